@@ -3,6 +3,7 @@ module github.com/shibbirmcc/github-issue-developer-mcp-server
 go 1.24.5
 
 require (
-	github.com/modelcontextprotocol/go-sdk v0.2.0 // indirect
-	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	github.com/modelcontextprotocol/go-sdk v0.2.0
+	github.com/yosida95/uritemplate/v3 v3.0.2
+	gopkg.in/yaml.v3 v3.0.1
 )
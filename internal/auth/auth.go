@@ -0,0 +1,178 @@
+// Package auth authenticates HTTP clients with bearer tokens, either static tokens
+// from a file or JWTs signed by a key in a local JWKS file, and decides from a
+// token's scopes which prompts, tools and resources it may use
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Scopes understood by the server. A prompt or tool can also be granted on its own
+// with "prompts:<name>" or "tools:<name>".
+const (
+	// ScopeAll grants everything
+	ScopeAll       = "*"
+	ScopePrompts   = "prompts"
+	ScopeResources = "resources"
+	// ScopeTools grants every tool, including those that change the repository or GitHub
+	ScopeTools = "tools"
+	// ScopeToolsRead grants the tools that only read
+	ScopeToolsRead = "tools:read"
+)
+
+// Kinds of things a scope grants
+const (
+	KindPrompt   = "prompt"
+	KindTool     = "tool"
+	KindResource = "resource"
+)
+
+// ErrInvalidToken is returned for tokens that are unknown, malformed, expired or
+// not signed by a trusted key
+var ErrInvalidToken = errors.New("invalid token")
+
+// scopePattern matches the scopes a token file may grant
+var scopePattern = regexp.MustCompile(`^(\*|prompts|resources|tools|(prompts|tools):[a-z0-9_-]+)$`)
+
+// Scopes are the scopes granted to a token
+type Scopes []string
+
+// Allows reports whether the scopes grant the prompt, tool or resource of kind named
+// name. readOnly tells whether a tool only reads.
+func (s Scopes) Allows(kind, name string, readOnly bool) bool {
+	if slices.Contains(s, ScopeAll) {
+		return true
+	}
+	switch kind {
+	case KindPrompt:
+		return slices.Contains(s, ScopePrompts) || slices.Contains(s, "prompts:"+name)
+	case KindTool:
+		return slices.Contains(s, ScopeTools) || slices.Contains(s, "tools:"+name) ||
+			(readOnly && slices.Contains(s, ScopeToolsRead))
+	case KindResource:
+		return slices.Contains(s, ScopeResources)
+	}
+	return false
+}
+
+// RequiredScope returns the broad scope that grants the prompt, tool or resource of
+// kind, as reported to clients that lack it
+func RequiredScope(kind string, readOnly bool) string {
+	switch {
+	case kind == KindPrompt:
+		return ScopePrompts
+	case kind == KindResource:
+		return ScopeResources
+	case readOnly:
+		return ScopeToolsRead
+	}
+	return ScopeTools
+}
+
+// SupportedScopes are the scopes advertised in the resource metadata
+var SupportedScopes = []string{ScopePrompts, ScopeResources, ScopeToolsRead, ScopeTools}
+
+// validScope reports whether scope is one the server understands
+func validScope(scope string) bool {
+	return scopePattern.MatchString(scope)
+}
+
+// Principal is an authenticated client
+type Principal struct {
+	// Subject is the name of a static token or the sub claim of a JWT
+	Subject string `json:"subject"`
+	Scopes  Scopes `json:"scopes"`
+	// Method is how the client authenticated: "token" or "jwt"
+	Method string `json:"method"`
+}
+
+// String names the principal for logs
+func (p *Principal) String() string {
+	return fmt.Sprintf("%s %s (%s)", p.Method, p.Subject, strings.Join(p.Scopes, " "))
+}
+
+// ID identifies the principal across sessions and restarts
+func (p *Principal) ID() string {
+	return p.Method + ":" + p.Subject
+}
+
+// contextKey is the key of the Principal in a context
+type contextKey struct{}
+
+// NewContext returns a context carrying p
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the principal carried by ctx. Requests that did not come
+// through the HTTP handler, such as those over stdio, carry none.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok
+}
+
+// Config locates the credentials the server accepts
+type Config struct {
+	// TokensFile is a YAML file of static tokens and their scopes
+	TokensFile string
+	// JWKSFile is a JSON Web Key Set whose keys sign accepted JWTs
+	JWKSFile string
+	// Issuer and Audience, if set, must match the iss and aud claims of JWTs
+	Issuer   string
+	Audience string
+}
+
+// Enabled reports whether any credentials are configured
+func (c Config) Enabled() bool {
+	return c.TokensFile != "" || c.JWKSFile != ""
+}
+
+// Verifier checks bearer tokens against the configured credentials
+type Verifier struct {
+	tokens []staticToken
+	jwt    *jwtVerifier
+}
+
+// NewVerifier loads the credentials of c
+func NewVerifier(c Config) (*Verifier, error) {
+	if !c.Enabled() {
+		return nil, errors.New("no tokens file or JWKS file configured")
+	}
+
+	v := &Verifier{}
+	if c.TokensFile != "" {
+		tokens, err := loadTokens(c.TokensFile)
+		if err != nil {
+			return nil, err
+		}
+		v.tokens = tokens
+	}
+	if c.JWKSFile != "" {
+		keys, err := loadJWKS(c.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.jwt = newJWTVerifier(keys, c.Issuer, c.Audience)
+	}
+	return v, nil
+}
+
+// Verify returns the principal a bearer token authenticates. Static tokens are tried
+// first, then the token is verified as a JWT.
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: empty token", ErrInvalidToken)
+	}
+	if p, ok := matchToken(v.tokens, token); ok {
+		return p, nil
+	}
+	if v.jwt != nil && strings.Count(token, ".") == 2 {
+		return v.jwt.verify(token)
+	}
+	return nil, fmt.Errorf("%w: unknown token", ErrInvalidToken)
+}
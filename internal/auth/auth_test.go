@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name in a temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestScopesAllows(t *testing.T) {
+	tests := []struct {
+		scopes   Scopes
+		kind     string
+		name     string
+		readOnly bool
+		want     bool
+	}{
+		{Scopes{ScopeAll}, KindTool, "commit_changes", false, true},
+		{Scopes{ScopePrompts}, KindPrompt, "development-workflow", false, true},
+		{Scopes{"prompts:work-on-issue"}, KindPrompt, "work-on-issue", false, true},
+		{Scopes{"prompts:work-on-issue"}, KindPrompt, "development-workflow", false, false},
+		{Scopes{ScopeToolsRead}, KindTool, "git_status", true, true},
+		{Scopes{ScopeToolsRead}, KindTool, "commit_changes", false, false},
+		{Scopes{ScopeToolsRead, "tools:commit_changes"}, KindTool, "commit_changes", false, true},
+		{Scopes{ScopeTools}, KindTool, "commit_changes", false, true},
+		{Scopes{ScopeTools}, KindPrompt, "work-on-issue", false, false},
+		{Scopes{ScopeResources}, KindResource, "issue://octo/hello/1", false, true},
+		{Scopes{ScopePrompts}, KindResource, "issue://octo/hello/1", false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.scopes.Allows(tt.kind, tt.name, tt.readOnly); got != tt.want {
+			t.Errorf("%v.Allows(%s, %s, %v) = %v, expected %v", tt.scopes, tt.kind, tt.name, tt.readOnly, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyStaticTokens(t *testing.T) {
+	digest := sha256.Sum256([]byte("bob-secret"))
+	path := writeFile(t, "tokens.yml", `tokens:
+  - name: alice
+    token: alice-secret
+    scopes: [prompts, tools:read]
+  - name: bob
+    sha256: `+hex.EncodeToString(digest[:])+`
+    scopes: ["*"]
+`)
+	v, err := NewVerifier(Config{TokensFile: path})
+	if err != nil {
+		t.Fatalf("NewVerifier returned error: %v", err)
+	}
+
+	p, err := v.Verify("alice-secret")
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if p.Subject != "alice" || p.Method != "token" || len(p.Scopes) != 2 {
+		t.Errorf("Unexpected principal %+v", p)
+	}
+	if p, err := v.Verify("bob-secret"); err != nil || p.Subject != "bob" {
+		t.Errorf("Expected bob's token to be matched by its digest, got %+v, %v", p, err)
+	}
+	for _, token := range []string{"", "alice", "a.b.c"} {
+		if _, err := v.Verify(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Verify(%q) returned %v, expected ErrInvalidToken", token, err)
+		}
+	}
+
+	if _, err := NewVerifier(Config{}); err == nil {
+		t.Error("Expected an error without credentials")
+	}
+}
+
+func TestLoadTokensNamesOffendingKeys(t *testing.T) {
+	path := writeFile(t, "tokens.yml", `tokens:
+  - name: alice
+    token: secret
+    scopes: [prompts, admin]
+  - name: alice
+    token: other
+    sha256: abc
+    scopes: [tools]
+  - name: carol
+    token: secret
+  - token: dave
+    scopes: [tools]
+`)
+	_, err := NewVerifier(Config{TokensFile: path})
+	if err == nil {
+		t.Fatal("Expected the tokens file to be rejected")
+	}
+	for _, want := range []string{
+		`tokens[0].scopes[1]: unknown scope "admin"`,
+		`tokens[1].name: duplicate "alice"`,
+		"tokens[1]: give either token or sha256, not both",
+		"tokens[2]: same token as an earlier entry",
+		"tokens[2].scopes: must list at least one scope",
+		"tokens[3].name: must not be empty",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in:\n%v", want, err)
+		}
+	}
+	if !errors.Is(err, ErrInvalidTokens) || !strings.Contains(err.Error(), path) {
+		t.Errorf("Expected ErrInvalidTokens naming the file, got %v", err)
+	}
+
+	path = writeFile(t, "tokens.yml", "tokens:\n  - name: alice\n    secret: x\n")
+	if _, err := NewVerifier(Config{TokensFile: path}); err == nil || !strings.Contains(err.Error(), "field secret not found") {
+		t.Errorf("Expected unknown keys to be rejected, got %v", err)
+	}
+}
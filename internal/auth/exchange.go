@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token exchange parameters (RFC 8693)
+const (
+	grantTokenExchange   = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+const (
+	// exchangeTimeout bounds a single token exchange
+	exchangeTimeout = 30 * time.Second
+
+	// defaultExchangeLifetime is how long a token is reused when the endpoint does
+	// not say when it expires
+	defaultExchangeLifetime = 5 * time.Minute
+
+	// exchangeRefreshMargin renews a token this long before it expires
+	exchangeRefreshMargin = time.Minute
+)
+
+// ErrExchangeFailed is returned when the token exchange endpoint does not issue a token
+var ErrExchangeFailed = errors.New("token exchange failed")
+
+// ExchangeConfig locates the OAuth token exchange endpoint that trades the bearer
+// token of a client for a token of another service
+type ExchangeConfig struct {
+	// URL is the token endpoint
+	URL string
+	// ClientID and ClientSecret authenticate the server at the endpoint, if set
+	ClientID     string
+	ClientSecret string
+	// Audience, if set, names the service the token is requested for
+	Audience string
+}
+
+// Exchanger trades bearer tokens at a token exchange endpoint (RFC 8693) and reuses
+// the issued tokens until shortly before they expire
+type Exchanger struct {
+	config     ExchangeConfig
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]exchangedToken
+
+	// now returns the current time; tests replace it
+	now func() time.Time
+}
+
+// exchangedToken is an issued token and when it is renewed
+type exchangedToken struct {
+	token   string
+	renewAt time.Time
+}
+
+// NewExchanger creates an exchanger for the endpoint of c
+func NewExchanger(c ExchangeConfig) (*Exchanger, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid token exchange URL %q: must be an http or https URL", c.URL)
+	}
+	return &Exchanger{
+		config:     c,
+		httpClient: &http.Client{Timeout: exchangeTimeout},
+		cache:      map[[sha256.Size]byte]exchangedToken{},
+		now:        time.Now,
+	}, nil
+}
+
+// Exchange returns a token issued for subjectToken
+func (e *Exchanger) Exchange(ctx context.Context, subjectToken string) (string, error) {
+	key := sha256.Sum256([]byte(subjectToken))
+	e.mu.Lock()
+	cached, ok := e.cache[key]
+	e.mu.Unlock()
+	if ok && e.now().Before(cached.renewAt) {
+		return cached.token, nil
+	}
+
+	token, lifetime, err := e.request(ctx, subjectToken)
+	if err != nil {
+		return "", err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.now()
+	for k, t := range e.cache {
+		if !now.Before(t.renewAt) {
+			delete(e.cache, k)
+		}
+	}
+	e.cache[key] = exchangedToken{token: token, renewAt: now.Add(lifetime - exchangeRefreshMargin)}
+	return token, nil
+}
+
+// request asks the endpoint for a token and returns it with its lifetime
+func (e *Exchanger) request(ctx context.Context, subjectToken string) (string, time.Duration, error) {
+	form := url.Values{
+		"grant_type":           {grantTokenExchange},
+		"subject_token":        {subjectToken},
+		"subject_token_type":   {tokenTypeAccessToken},
+		"requested_token_type": {tokenTypeAccessToken},
+	}
+	if e.config.Audience != "" {
+		form.Set("audience", e.config.Audience)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if e.config.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(e.config.ClientID), url.QueryEscape(e.config.ClientSecret))
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return "", 0, fmt.Errorf("%w: decoding response: %v", ErrExchangeFailed, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		reason := resp.Status
+		if body.Error != "" {
+			reason = strings.TrimSpace(body.Error + " " + body.ErrorDescription)
+		}
+		return "", 0, fmt.Errorf("%w: %s", ErrExchangeFailed, reason)
+	}
+
+	lifetime := defaultExchangeLifetime
+	if body.ExpiresIn > 0 {
+		lifetime = time.Duration(body.ExpiresIn) * time.Second
+	}
+	return body.AccessToken, lifetime, nil
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExchangeReusesTokensUntilTheyExpire(t *testing.T) {
+	var requests int
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("grant_type") != grantTokenExchange || r.PostForm.Get("subject_token_type") != tokenTypeAccessToken || r.PostForm.Get("audience") != "https://api.github.com" {
+			t.Errorf("Unexpected exchange request %v", r.PostForm)
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "mcp" || secret != "s3cret" {
+			t.Errorf("Expected client credentials, got %q %q", id, secret)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("subject_token") == "revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "subject token revoked"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "gho_" + r.PostForm.Get("subject_token"),
+			"expires_in":   600,
+		})
+	}))
+	defer endpoint.Close()
+
+	e, err := NewExchanger(ExchangeConfig{URL: endpoint.URL, ClientID: "mcp", ClientSecret: "s3cret", Audience: "https://api.github.com"})
+	if err != nil {
+		t.Fatalf("NewExchanger returned error: %v", err)
+	}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for _, subject := range []string{"alice", "alice", "bob"} {
+		token, err := e.Exchange(ctx, subject)
+		if err != nil || token != "gho_"+subject {
+			t.Errorf("Exchange(%s) = %q, %v", subject, token, err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected the token of alice to be reused, got %d requests", requests)
+	}
+
+	now = now.Add(9*time.Minute + time.Second)
+	if _, err := e.Exchange(ctx, "alice"); err != nil || requests != 3 {
+		t.Errorf("Expected the token to be renewed before it expires, got %d requests, %v", requests, err)
+	}
+
+	_, err = e.Exchange(ctx, "revoked")
+	if !errors.Is(err, ErrExchangeFailed) || !strings.Contains(err.Error(), "invalid_grant subject token revoked") {
+		t.Errorf("Expected the endpoint's error, got %v", err)
+	}
+
+	if _, err := NewExchanger(ExchangeConfig{URL: "idp.example.com/token"}); err == nil {
+		t.Error("Expected an error for a URL without a scheme")
+	}
+}
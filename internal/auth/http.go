@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// MetadataPath is where the OAuth protected resource metadata (RFC 9728) is served
+const MetadataPath = "/.well-known/oauth-protected-resource"
+
+// maxBodySize bounds the JSON-RPC messages read to check scopes
+const maxBodySize = 10 << 20
+
+// ResourceMetadata describes the server as an OAuth protected resource
+type ResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers,omitempty"`
+	ScopesSupported        []string `json:"scopes_supported"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported"`
+	ResourceName           string   `json:"resource_name,omitempty"`
+}
+
+// HandlerOptions configure the authenticating handler
+type HandlerOptions struct {
+	// Resource identifies the server in its metadata; by default the scheme and host
+	// of the request
+	Resource string
+
+	// AuthorizationServers issue the JWTs the server accepts, advertised to clients
+	AuthorizationServers []string
+
+	// ReadOnly reports whether a tool only reads, which the tools:read scope grants
+	ReadOnly func(tool string) bool
+}
+
+// Handler authenticates the bearer token of every request to next and passes the
+// principal on in the request context. Requests without a valid token are answered
+// with 401, and JSON-RPC calls of prompts, tools or resources the token's scopes do
+// not grant with 403. The WWW-Authenticate header points clients at the resource
+// metadata, which is served unauthenticated at MetadataPath.
+func (v *Verifier) Handler(next http.Handler, opts HandlerOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == MetadataPath {
+			serveMetadata(w, r, opts)
+			return
+		}
+
+		token, ok := BearerToken(r)
+		if !ok {
+			challenge(w, r, opts, http.StatusUnauthorized, "", "", "")
+			return
+		}
+		p, err := v.Verify(token)
+		if err != nil {
+			challenge(w, r, opts, http.StatusUnauthorized, "invalid_token", strings.TrimPrefix(err.Error(), ErrInvalidToken.Error()+": "), "")
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+			if err != nil {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			if kind, name, scope, denied := deniedCall(body, p.Scopes, opts.ReadOnly); denied {
+				log.Printf("Denied %s %s to %s", kind, name, p)
+				challenge(w, r, opts, http.StatusForbidden, "insufficient_scope", fmt.Sprintf("%s %s needs scope %s", kind, name, scope), scope)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+	})
+}
+
+// BearerToken returns the token of the Authorization: Bearer header of r
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// jsonRPCCall is the part of a JSON-RPC request that names what it uses
+type jsonRPCCall struct {
+	Method string `json:"method"`
+	Params struct {
+		Name string `json:"name"`
+		URI  string `json:"uri"`
+	} `json:"params"`
+}
+
+// deniedCall finds a call in a JSON-RPC message or batch that scopes do not grant
+// and returns what it calls and the scope it needs
+func deniedCall(body []byte, scopes Scopes, readOnly func(string) bool) (kind, name, scope string, denied bool) {
+	var calls []jsonRPCCall
+	if err := json.Unmarshal(body, &calls); err != nil {
+		var call jsonRPCCall
+		if json.Unmarshal(body, &call) != nil {
+			// Malformed messages are left to the transport to reject
+			return "", "", "", false
+		}
+		calls = []jsonRPCCall{call}
+	}
+
+	for _, call := range calls {
+		var kind, name string
+		switch call.Method {
+		case "prompts/get":
+			kind, name = KindPrompt, call.Params.Name
+		case "tools/call":
+			kind, name = KindTool, call.Params.Name
+		case "resources/read":
+			kind, name = KindResource, call.Params.URI
+		default:
+			continue
+		}
+		ro := kind == KindTool && readOnly != nil && readOnly(name)
+		if !scopes.Allows(kind, name, ro) {
+			return kind, name, RequiredScope(kind, ro), true
+		}
+	}
+	return "", "", "", false
+}
+
+// resource returns the resource identifier of the server
+func resource(r *http.Request, opts HandlerOptions) string {
+	if opts.Resource != "" {
+		return opts.Resource
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// metadataURL returns the URL of the resource metadata
+func metadataURL(r *http.Request, opts HandlerOptions) string {
+	res := resource(r, opts)
+	if i := strings.Index(res, "://"); i >= 0 {
+		if j := strings.IndexByte(res[i+3:], '/'); j >= 0 {
+			res = res[:i+3+j]
+		}
+	}
+	return res + MetadataPath
+}
+
+// serveMetadata writes the protected resource metadata
+func serveMetadata(w http.ResponseWriter, r *http.Request, opts HandlerOptions) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "unsupported method", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(ResourceMetadata{
+		Resource:               resource(r, opts),
+		AuthorizationServers:   opts.AuthorizationServers,
+		ScopesSupported:        SupportedScopes,
+		BearerMethodsSupported: []string{"header"},
+		ResourceName:           "github-issue-developer",
+	})
+}
+
+// challenge rejects a request with a Bearer WWW-Authenticate challenge (RFC 6750)
+// that names the resource metadata
+func challenge(w http.ResponseWriter, r *http.Request, opts HandlerOptions, status int, code, description, scope string) {
+	params := []string{fmt.Sprintf("resource_metadata=%q", metadataURL(r, opts))}
+	if code != "" {
+		params = append(params, fmt.Sprintf("error=%q", code))
+	}
+	if description != "" {
+		params = append(params, fmt.Sprintf("error_description=%q", strings.ReplaceAll(description, `"`, "'")))
+	}
+	if scope != "" {
+		params = append(params, fmt.Sprintf("scope=%q", scope))
+	}
+	w.Header().Set("WWW-Authenticate", "Bearer "+strings.Join(params, ", "))
+
+	message := http.StatusText(status)
+	if description != "" {
+		message += ": " + description
+	}
+	http.Error(w, message, status)
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestHandler serves a handler that echoes the principal of each request, behind
+// a verifier that accepts the token "reader" with the prompts and tools:read scopes
+func newTestHandler(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	path := writeFile(t, "tokens.yml", "tokens:\n  - name: alice\n    token: reader\n    scopes: [prompts, tools:read]\n")
+	v, err := NewVerifier(Config{TokensFile: path})
+	if err != nil {
+		t.Fatalf("NewVerifier returned error: %v", err)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := FromContext(r.Context())
+		_, _ = io.WriteString(w, p.String())
+	})
+	ts := httptest.NewServer(v.Handler(next, HandlerOptions{
+		AuthorizationServers: []string{"https://idp.example.com"},
+		ReadOnly:             func(tool string) bool { return tool == "git_status" },
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// post sends body to url with token as the bearer token
+func post(t *testing.T, url, token, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestHandlerChallenges(t *testing.T) {
+	ts := newTestHandler(t)
+	metadata := `resource_metadata="` + ts.URL + MetadataPath + `"`
+
+	resp := post(t, ts.URL+"/mcp", "", `{}`)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("WWW-Authenticate"); got != "Bearer "+metadata {
+		t.Errorf("Unexpected challenge %q", got)
+	}
+
+	resp = post(t, ts.URL+"/mcp", "writer", `{}`)
+	if got := resp.Header.Get("WWW-Authenticate"); resp.StatusCode != http.StatusUnauthorized || !strings.Contains(got, `error="invalid_token"`) {
+		t.Errorf("Expected 401 with invalid_token, got %d %q", resp.StatusCode, got)
+	}
+
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"%s","arguments":{}}}`
+	resp = post(t, ts.URL+"/mcp", "reader", strings.Replace(call, "%s", "commit_changes", 1))
+	got := resp.Header.Get("WWW-Authenticate")
+	if resp.StatusCode != http.StatusForbidden || !strings.Contains(got, `error="insufficient_scope"`) || !strings.Contains(got, `scope="tools"`) || !strings.Contains(got, metadata) {
+		t.Errorf("Expected 403 with insufficient_scope, got %d %q", resp.StatusCode, got)
+	}
+
+	batch := `[{"jsonrpc":"2.0","id":1,"method":"prompts/list"},{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"issue://octo/hello/1"}}]`
+	if resp := post(t, ts.URL+"/mcp", "reader", batch); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a batch with a denied call to be refused, got %d", resp.StatusCode)
+	}
+
+	resp = post(t, ts.URL+"/mcp", "reader", strings.Replace(call, "%s", "git_status", 1))
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(string(body), "token alice") {
+		t.Errorf("Expected the request to reach the handler as alice, got %d %q", resp.StatusCode, body)
+	}
+}
+
+func TestHandlerServesMetadata(t *testing.T) {
+	ts := newTestHandler(t)
+
+	resp, err := http.Get(ts.URL + MetadataPath)
+	if err != nil {
+		t.Fatalf("GET returned error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var metadata ResourceMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if metadata.Resource != ts.URL {
+		t.Errorf("Expected resource %s, got %s", ts.URL, metadata.Resource)
+	}
+	if len(metadata.AuthorizationServers) != 1 || metadata.AuthorizationServers[0] != "https://idp.example.com" {
+		t.Errorf("Unexpected authorization servers %v", metadata.AuthorizationServers)
+	}
+	if len(metadata.ScopesSupported) == 0 || metadata.BearerMethodsSupported[0] != "header" {
+		t.Errorf("Unexpected metadata %+v", metadata)
+	}
+}
@@ -0,0 +1,319 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is how far the exp and nbf claims of a JWT may be off
+const clockSkew = time.Minute
+
+// ErrInvalidJWKS is returned when a JWKS file cannot be parsed or holds unusable keys
+var ErrInvalidJWKS = errors.New("invalid JWKS file")
+
+// jwk is a JSON Web Key as found in a JWKS file
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey is a verification key and the algorithms it is used with
+type publicKey struct {
+	kid  string
+	algs []string
+	key  crypto.PublicKey
+}
+
+// loadJWKS reads the signing keys of a JWKS file. Keys for encryption are skipped.
+func loadJWKS(path string) ([]publicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWKS file: %w", err)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", path, ErrInvalidJWKS, err)
+	}
+
+	var keys []publicKey
+	var errs []error
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w: keys[%d]: %v", path, ErrInvalidJWKS, i, err))
+			continue
+		}
+		keys = append(keys, *key)
+	}
+	if len(keys) == 0 && len(errs) == 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: keys: must hold at least one signing key", path, ErrInvalidJWKS))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// publicKey decodes the key material of k
+func (k jwk) publicKey() (*publicKey, error) {
+	key := &publicKey{kid: k.Kid}
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("n: %v", err)
+		}
+		e, err := decodeInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("e: must be a small positive integer")
+		}
+		if n.BitLen() < 2048 {
+			return nil, fmt.Errorf("n: RSA keys must have at least 2048 bits, got %d", n.BitLen())
+		}
+		key.key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		key.algs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	case "EC":
+		ec, alg, err := ecKey(k.Crv, k.X, k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key.key, key.algs = ec, []string{alg}
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("crv: unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("x: must be a base64url Ed25519 public key")
+		}
+		key.key, key.algs = ed25519.PublicKey(x), []string{"EdDSA"}
+	default:
+		return nil, fmt.Errorf("kty: unsupported key type %q", k.Kty)
+	}
+
+	if k.Alg != "" {
+		if !slices.Contains(key.algs, k.Alg) {
+			return nil, fmt.Errorf("alg: %q cannot be used with this key", k.Alg)
+		}
+		key.algs = []string{k.Alg}
+	}
+	return key, nil
+}
+
+// ecKey decodes an elliptic curve key and returns it with its signing algorithm
+func ecKey(crv, xs, ys string) (*ecdsa.PublicKey, string, error) {
+	var curve elliptic.Curve
+	var check ecdh.Curve
+	var alg string
+	switch crv {
+	case "P-256":
+		curve, check, alg = elliptic.P256(), ecdh.P256(), "ES256"
+	case "P-384":
+		curve, check, alg = elliptic.P384(), ecdh.P384(), "ES384"
+	case "P-521":
+		curve, check, alg = elliptic.P521(), ecdh.P521(), "ES512"
+	default:
+		return nil, "", fmt.Errorf("crv: unsupported curve %q", crv)
+	}
+
+	size := (curve.Params().BitSize + 7) / 8
+	x, errX := base64.RawURLEncoding.DecodeString(xs)
+	y, errY := base64.RawURLEncoding.DecodeString(ys)
+	if errX != nil || errY != nil || len(x) != size || len(y) != size {
+		return nil, "", fmt.Errorf("x, y: must be base64url coordinates of %d bytes", size)
+	}
+	// The ecdh package rejects points that are not on the curve
+	if _, err := check.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+		return nil, "", errors.New("x, y: point is not on the curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, alg, nil
+}
+
+// decodeInt decodes a base64url big-endian integer
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("must be a base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// jwtVerifier verifies JWTs signed by one of its keys
+type jwtVerifier struct {
+	keys     []publicKey
+	issuer   string
+	audience string
+
+	// now returns the current time; tests replace it
+	now func() time.Time
+}
+
+// newJWTVerifier creates a verifier for keys that requires issuer and audience when
+// they are not empty
+func newJWTVerifier(keys []publicKey, issuer, audience string) *jwtVerifier {
+	return &jwtVerifier{keys: keys, issuer: issuer, audience: audience, now: time.Now}
+}
+
+// claims are the JWT claims the server reads
+type claims struct {
+	Issuer    string          `json:"iss"`
+	Subject   string          `json:"sub"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+	Scope     string          `json:"scope"`
+	Scp       json.RawMessage `json:"scp"`
+}
+
+// verify checks the signature and claims of a compact JWT
+func (v *jwtVerifier) verify(token string) (*Principal, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidToken, fmt.Sprintf(format, args...))
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalid("malformed JWT")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalid("malformed JWT header")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalid("malformed JWT signature")
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range v.keys {
+		if (header.Kid == "" || key.kid == header.Kid) && slices.Contains(key.algs, header.Alg) &&
+			verifySignature(key.key, header.Alg, signed, signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, invalid("JWT signature does not match a trusted key (alg %q, kid %q)", header.Alg, header.Kid)
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, invalid("malformed JWT claims")
+	}
+	now := v.now()
+	switch {
+	case c.ExpiresAt == nil:
+		return nil, invalid("JWT has no exp claim")
+	case now.After(unixTime(*c.ExpiresAt).Add(clockSkew)):
+		return nil, invalid("JWT expired at %s", unixTime(*c.ExpiresAt).UTC().Format(time.RFC3339))
+	case c.NotBefore != nil && now.Add(clockSkew).Before(unixTime(*c.NotBefore)):
+		return nil, invalid("JWT is not valid before %s", unixTime(*c.NotBefore).UTC().Format(time.RFC3339))
+	case c.Subject == "":
+		return nil, invalid("JWT has no sub claim")
+	case v.issuer != "" && c.Issuer != v.issuer:
+		return nil, invalid("JWT issuer %q is not %q", c.Issuer, v.issuer)
+	case v.audience != "" && !slices.Contains(stringOrList(c.Audience), v.audience):
+		return nil, invalid("JWT is not meant for audience %q", v.audience)
+	}
+
+	scopes := strings.Fields(c.Scope)
+	for _, scope := range stringOrList(c.Scp) {
+		scopes = append(scopes, strings.Fields(scope)...)
+	}
+	// Identity providers add scopes of their own, which grant nothing here
+	scopes = slices.DeleteFunc(scopes, func(s string) bool { return !validScope(s) })
+	return &Principal{Subject: c.Subject, Scopes: scopes, Method: "jwt"}, nil
+}
+
+// verifySignature checks a JWS signature made with alg
+func verifySignature(key crypto.PublicKey, alg string, signed, signature []byte) bool {
+	var hash crypto.Hash
+	switch {
+	case alg == "EdDSA":
+		k, ok := key.(ed25519.PublicKey)
+		return ok && ed25519.Verify(k, signed, signature)
+	case strings.HasSuffix(alg, "256"):
+		hash = crypto.SHA256
+	case strings.HasSuffix(alg, "384"):
+		hash = crypto.SHA384
+	case strings.HasSuffix(alg, "512"):
+		hash = crypto.SHA512
+	default:
+		return false
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if strings.HasPrefix(alg, "PS") {
+			return rsa.VerifyPSS(k, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+		return rsa.VerifyPKCS1v15(k, hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r, s := new(big.Int).SetBytes(signature[:size]), new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	}
+	return false
+}
+
+// decodeSegment decodes a base64url JSON segment of a JWT
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// stringOrList decodes a claim that is either a string or a list of strings
+func stringOrList(raw json.RawMessage) []string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return []string{s}
+	}
+	var list []string
+	_ = json.Unmarshal(raw, &list)
+	return list
+}
+
+// unixTime converts a NumericDate claim
+func unixTime(seconds float64) time.Time {
+	return time.Unix(int64(seconds), 0)
+}
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testKeys are the keys of the test JWKS
+type testKeys struct {
+	rsa     *rsa.PrivateKey
+	ec      *ecdsa.PrivateKey
+	ed25519 ed25519.PrivateKey
+}
+
+// newTestKeys generates keys and writes their public halves to a JWKS file, with an
+// encryption key that is skipped
+func newTestKeys(t *testing.T) (*testKeys, string) {
+	t.Helper()
+
+	k := &testKeys{}
+	var err error
+	if k.rsa, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		t.Fatal(err)
+	}
+	if k.ec, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	if _, k.ed25519, err = ed25519.GenerateKey(rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	ecPoint := func(n *big.Int) string { return b64(n.FillBytes(make([]byte, 32))) }
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(k.rsa.N.Bytes()), "e": b64(big.NewInt(int64(k.rsa.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": ecPoint(k.ec.X), "y": ecPoint(k.ec.Y)},
+		{"kty": "OKP", "kid": "ed-1", "crv": "Ed25519", "x": b64(k.ed25519.Public().(ed25519.PublicKey))},
+		{"kty": "oct", "use": "enc", "k": "c2VjcmV0"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k, writeFile(t, "jwks.json", string(jwks))
+}
+
+// signJWT signs claims with key as a compact JWT
+func signJWT(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	t.Helper()
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := b64(header) + "." + b64(payload)
+
+	var signature []byte
+	var err error
+	digest := sha256.Sum256([]byte(signed))
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, digest[:])
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signed))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + b64(signature)
+}
+
+func TestVerifyJWT(t *testing.T) {
+	keys, jwks := newTestKeys(t)
+	v, err := NewVerifier(Config{JWKSFile: jwks, Issuer: "https://idp.example.com", Audience: "https://mcp.example.com"})
+	if err != nil {
+		t.Fatalf("NewVerifier returned error: %v", err)
+	}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	v.jwt.now = func() time.Time { return now }
+
+	claims := func(extra map[string]any) map[string]any {
+		c := map[string]any{
+			"iss": "https://idp.example.com", "sub": "alice", "aud": []string{"https://mcp.example.com"},
+			"exp": now.Add(time.Hour).Unix(), "scope": "openid prompts tools:read",
+		}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+
+	for _, token := range []string{
+		signJWT(t, "RS256", "rsa-1", keys.rsa, claims(nil)),
+		signJWT(t, "ES256", "ec-1", keys.ec, claims(nil)),
+		signJWT(t, "EdDSA", "", keys.ed25519, claims(nil)),
+	} {
+		p, err := v.Verify(token)
+		if err != nil {
+			t.Errorf("Verify returned error: %v", err)
+			continue
+		}
+		if p.Subject != "alice" || p.Method != "jwt" || strings.Join(p.Scopes, " ") != "prompts tools:read" {
+			t.Errorf("Unexpected principal %+v", p)
+		}
+	}
+
+	p, err := v.Verify(signJWT(t, "RS256", "rsa-1", keys.rsa, claims(map[string]any{"scope": nil, "scp": []string{"tools", "resources"}, "aud": "https://mcp.example.com"})))
+	if err != nil || strings.Join(p.Scopes, " ") != "tools resources" {
+		t.Errorf("Expected scopes from scp, got %+v, %v", p, err)
+	}
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		signJWT(t, "RS256", "rsa-1", other, claims(nil)):                                              "signature does not match",
+		signJWT(t, "ES256", "rsa-1", keys.ec, claims(nil)):                                            "signature does not match",
+		signJWT(t, "RS256", "", keys.rsa, claims(map[string]any{"exp": now.Add(-time.Hour).Unix()})):  "expired",
+		signJWT(t, "RS256", "", keys.rsa, claims(map[string]any{"exp": nil})):                         "no exp claim",
+		signJWT(t, "RS256", "", keys.rsa, claims(map[string]any{"nbf": now.Add(time.Hour).Unix()})):   "not valid before",
+		signJWT(t, "RS256", "", keys.rsa, claims(map[string]any{"iss": "https://evil.example.com"})):  "issuer",
+		signJWT(t, "RS256", "", keys.rsa, claims(map[string]any{"aud": "https://other.example.com"})): "audience",
+		signJWT(t, "RS256", "", keys.rsa, claims(map[string]any{"sub": ""})):                          "no sub claim",
+	}
+	unsigned := strings.Split(signJWT(t, "RS256", "", keys.rsa, claims(nil)), ".")
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	tests[header+"."+unsigned[1]+"."] = "signature does not match"
+
+	for token, want := range tests {
+		_, err := v.Verify(token)
+		if !errors.Is(err, ErrInvalidToken) || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an invalid token error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestLoadJWKSRejectsUnusableKeys(t *testing.T) {
+	tests := map[string]string{
+		`{"keys": []}`: "keys: must hold at least one signing key",
+		`{"keys": [{"kty": "RSA", "n": "AQAB", "e": "AQAB"}]}`:                                                 "keys[0]: n: RSA keys must have at least 2048 bits",
+		`{"keys": [{"kty": "EC", "crv": "P-256", "x": "AAAA", "y": "AAAA"}]}`:                                  "keys[0]: x, y: must be base64url coordinates",
+		`{"keys": [{"kty": "OKP", "crv": "X25519", "x": "AAAA"}]}`:                                             `keys[0]: crv: unsupported curve "X25519"`,
+		`{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`:                                                          `keys[0]: kty: unsupported key type "oct"`,
+		`{"keys": [{"kty": "OKP", "crv": "Ed25519", "alg": "RS256", "x": "` + strings.Repeat("A", 43) + `"}]}`: `keys[0]: alg: "RS256" cannot be used`,
+		`not json`: "invalid character",
+	}
+	for content, want := range tests {
+		path := writeFile(t, "jwks.json", content)
+		_, err := NewVerifier(Config{JWKSFile: path})
+		if !errors.Is(err, ErrInvalidJWKS) || !strings.Contains(err.Error(), want) {
+			t.Errorf("JWKS %s: expected an error containing %q, got %v", content, want, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidTokens is returned when a tokens file cannot be parsed or validated
+var ErrInvalidTokens = errors.New("invalid tokens file")
+
+// tokensFile is the layout of a tokens file
+type tokensFile struct {
+	Tokens []tokenEntry `yaml:"tokens"`
+}
+
+// tokenEntry is a token in a tokens file. The token is given either as is or as
+// the hex SHA-256 digest of it, so that the file need not hold the secret.
+type tokenEntry struct {
+	Name   string   `yaml:"name"`
+	Token  string   `yaml:"token"`
+	SHA256 string   `yaml:"sha256"`
+	Scopes []string `yaml:"scopes"`
+}
+
+// staticToken is a loaded token, kept only as its digest
+type staticToken struct {
+	name   string
+	digest [sha256.Size]byte
+	scopes Scopes
+}
+
+// loadTokens reads and validates a tokens file. Errors name the offending key, e.g.
+// "tokens[1].scopes[0]".
+func loadTokens(path string) ([]staticToken, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading tokens file: %w", err)
+	}
+
+	var file tokensFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w: %v", path, ErrInvalidTokens, err)
+	}
+
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %w: %s: %s", path, ErrInvalidTokens, key, fmt.Sprintf(format, args...)))
+	}
+	if len(file.Tokens) == 0 {
+		invalid("tokens", "must list at least one token")
+	}
+
+	tokens := make([]staticToken, 0, len(file.Tokens))
+	names := map[string]bool{}
+	digests := map[[sha256.Size]byte]bool{}
+	for i, entry := range file.Tokens {
+		key := fmt.Sprintf("tokens[%d]", i)
+		token := staticToken{name: strings.TrimSpace(entry.Name), scopes: entry.Scopes}
+
+		switch {
+		case token.name == "":
+			invalid(key+".name", "must not be empty")
+		case names[token.name]:
+			invalid(key+".name", "duplicate %q", token.name)
+		}
+		names[token.name] = true
+
+		switch {
+		case entry.Token != "" && entry.SHA256 != "":
+			invalid(key, "give either token or sha256, not both")
+		case entry.Token != "":
+			token.digest = sha256.Sum256([]byte(entry.Token))
+		case entry.SHA256 != "":
+			digest, err := hex.DecodeString(entry.SHA256)
+			if err != nil || len(digest) != sha256.Size {
+				invalid(key+".sha256", "must be a hex SHA-256 digest")
+			}
+			copy(token.digest[:], digest)
+		default:
+			invalid(key, "must give token or sha256")
+		}
+		if digests[token.digest] {
+			invalid(key, "same token as an earlier entry")
+		}
+		digests[token.digest] = true
+
+		if len(entry.Scopes) == 0 {
+			invalid(key+".scopes", "must list at least one scope")
+		}
+		for j, scope := range entry.Scopes {
+			if !validScope(scope) {
+				invalid(fmt.Sprintf("%s.scopes[%d]", key, j), "unknown scope %q", scope)
+			}
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// matchToken finds the static token equal to token. Every entry is compared in
+// constant time so that the comparison does not reveal how much of a token matched.
+func matchToken(tokens []staticToken, token string) (*Principal, bool) {
+	digest := sha256.Sum256([]byte(token))
+	var match *staticToken
+	for i := range tokens {
+		if subtle.ConstantTimeCompare(tokens[i].digest[:], digest[:]) == 1 {
+			match = &tokens[i]
+		}
+	}
+	if match == nil {
+		return nil, false
+	}
+	return &Principal{Subject: match.name, Scopes: match.scopes, Method: "token"}, true
+}
@@ -0,0 +1,142 @@
+package branches
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// maxDescriptionWords keeps suggested descriptions concise even when the length limit allows more
+const maxDescriptionWords = 6
+
+// titleTag matches a leading "[Bug]" or "bug:" style tag in an issue title
+var titleTag = regexp.MustCompile(`^\s*(?:\[([^\]]+)\]|([A-Za-z]+)(?:\([^)]*\))?:)\s*`)
+
+// labelPrefixes maps issue labels and title tags to branch prefixes
+var labelPrefixes = map[string]string{
+	"bug":           "bugfix",
+	"defect":        "bugfix",
+	"fix":           "bugfix",
+	"regression":    "bugfix",
+	"hotfix":        "hotfix",
+	"critical":      "hotfix",
+	"feature":       "feature",
+	"feat":          "feature",
+	"enhancement":   "feature",
+	"documentation": "docs",
+	"docs":          "docs",
+	"chore":         "chore",
+	"maintenance":   "chore",
+	"refactor":      "chore",
+	"dependencies":  "chore",
+	"test":          "test",
+	"tests":         "test",
+	"testing":       "test",
+	"release":       "release",
+	"experiment":    "experiment",
+	"spike":         "experiment",
+}
+
+// stopWords are dropped from issue titles when deriving a branch description
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "can": true, "does": true, "for": true, "from": true, "has": true,
+	"have": true, "in": true, "into": true, "is": true, "it": true, "its": true,
+	"of": true, "on": true, "or": true, "should": true, "so": true, "that": true,
+	"the": true, "this": true, "to": true, "was": true, "when": true, "will": true,
+	"with": true,
+}
+
+// Suggestion is a branch name derived from an issue
+type Suggestion struct {
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+	Reason string `json:"reason"`
+}
+
+// Suggest derives a branch name of the form <type>/<issue-number>-<short-description>
+// from an issue. The type comes from the first label or title tag that maps to an
+// allowed prefix, such as bug to bugfix/, and defaults to feature/. The description
+// is the title slugified without stop words, capped in words and to the policy length.
+func Suggest(issueNumber int, title string, labels []string, pol *policy.Policy) Suggestion {
+	prefix, reason := "", ""
+	for _, label := range labels {
+		if p, ok := labelPrefix(label, pol); ok {
+			prefix, reason = p, fmt.Sprintf("label %q", label)
+			break
+		}
+	}
+
+	if match := titleTag.FindStringSubmatch(title); match != nil {
+		tag := match[1] + match[2]
+		if p, ok := labelPrefix(tag, pol); ok {
+			title = title[len(match[0]):]
+			if prefix == "" {
+				prefix, reason = p, fmt.Sprintf("title tag %q", tag)
+			}
+		}
+	}
+
+	if prefix == "" {
+		prefix, reason = defaultPrefix(pol), "default for issues without a matching label"
+	}
+
+	description := describe(title)
+	if description == "" {
+		description = "work"
+	}
+	if issueNumber > 0 {
+		description = strconv.Itoa(issueNumber) + "-" + description
+	}
+
+	return Suggestion{
+		Name:   truncate(prefix+"/"+description, pol.Branches.MaxLength),
+		Prefix: prefix,
+		Reason: fmt.Sprintf("%s/ from %s", prefix, reason),
+	}
+}
+
+// labelPrefix maps an issue label to an allowed branch prefix. Labels such as
+// "type: bug" or "kind/bug" are matched by their last word.
+func labelPrefix(label string, pol *policy.Policy) (string, bool) {
+	fields := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return r == ':' || r == '/' || r == ' '
+	})
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	key := fields[len(fields)-1]
+	if slices.Contains(pol.BranchPrefixes(), key) {
+		return key, true
+	}
+	prefix, ok := labelPrefixes[key]
+	if ok && slices.Contains(pol.BranchPrefixes(), prefix) {
+		return prefix, true
+	}
+	return "", false
+}
+
+// describe turns an issue title into a short hyphenated description without stop words
+func describe(title string) string {
+	title = strings.NewReplacer("'", "", "’", "").Replace(strings.ToLower(title))
+	words := strings.Fields(invalidCharacters.ReplaceAllString(title, " "))
+
+	var kept []string
+	for _, word := range words {
+		if !stopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) == 0 {
+		kept = words
+	}
+	if len(kept) > maxDescriptionWords {
+		kept = kept[:maxDescriptionWords]
+	}
+	return strings.Join(kept, "-")
+}
@@ -0,0 +1,67 @@
+package branches
+
+import (
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name   string
+		issue  int
+		title  string
+		labels []string
+		want   string
+	}{
+		{"bug label", 42, "Login fails when the password contains a quote", []string{"bug"}, "bugfix/42-login-fails-password-contains-quote"},
+		{"enhancement label", 7, "Add support for GitHub Enterprise", []string{"good first issue", "enhancement"}, "feature/7-add-support-github-enterprise"},
+		{"scoped label", 3, "Document the config file", []string{"type: documentation"}, "docs/3-document-config-file"},
+		{"no labels", 15, "Export reports as CSV", nil, "feature/15-export-reports-csv"},
+		{"title tag", 9, "[Bug] Crash on startup", nil, "bugfix/9-crash-startup"},
+		{"conventional title", 11, "fix(api): don't retry on 4xx", nil, "bugfix/11-dont-retry-4xx"},
+		{"word cap", 5, "Make one two three four five six seven eight", nil, "feature/5-make-one-two-three-four-five"},
+		{"only stop words", 8, "The", nil, "feature/8-the"},
+		{"empty title", 4, "", nil, "feature/4-work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion := Suggest(tt.issue, tt.title, tt.labels, policy.Default())
+			if suggestion.Name != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, suggestion.Name)
+			}
+			if suggestion.Reason == "" {
+				t.Error("Expected a reason for the prefix")
+			}
+			if result := Validate(suggestion.Name, policy.Default(), Options{IssueNumber: tt.issue}); !result.Valid {
+				t.Errorf("Expected suggestion to be valid, got %v", rules(result.Violations))
+			}
+		})
+	}
+}
+
+func TestSuggestCapsLength(t *testing.T) {
+	p := policy.Default()
+	p.Branches.MaxLength = 25
+
+	suggestion := Suggest(1234, "Refactor authentication middleware pipeline", nil, p)
+	if len(suggestion.Name) > 25 {
+		t.Errorf("Expected name within 25 characters, got %q", suggestion.Name)
+	}
+	if suggestion.Name != "feature/1234-refactor" {
+		t.Errorf("Expected cut at a word boundary, got %q", suggestion.Name)
+	}
+}
+
+func TestSuggestIgnoresPrefixesOutsidePolicy(t *testing.T) {
+	p := policy.Default()
+	p.Branches.Prefixes = []policy.Convention{{Name: "feature"}, {Name: "fix"}}
+
+	if suggestion := Suggest(2, "Broken link", []string{"bug"}, p); suggestion.Prefix != "feature" {
+		t.Errorf("Expected bugfix to be unavailable, got %q", suggestion.Prefix)
+	}
+	if suggestion := Suggest(2, "Broken link", []string{"fix"}, p); suggestion.Prefix != "fix" {
+		t.Errorf("Expected label matching a policy prefix to be used, got %q", suggestion.Prefix)
+	}
+}
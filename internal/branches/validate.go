@@ -0,0 +1,268 @@
+package branches
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// Rules checked by Validate
+const (
+	RuleEmpty       = "empty"
+	RuleProtected   = "protected"
+	RulePrefix      = "prefix"
+	RuleCase        = "case"
+	RuleCharacters  = "characters"
+	RuleHyphens     = "hyphens"
+	RuleSegments    = "segments"
+	RuleMaxLength   = "max-length"
+	RuleIssueNumber = "issue-number"
+)
+
+// maxSegments is the number of slash separated parts in <type>/<scope>/<short-description>
+const maxSegments = 3
+
+var (
+	// invalidCharacters matches everything that is not allowed in a branch name segment
+	invalidCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+
+	// repeatedHyphens matches runs of hyphens
+	repeatedHyphens = regexp.MustCompile(`-{2,}`)
+
+	// issuePrefix matches the <issue-number>- prefix of a short description
+	issuePrefix = regexp.MustCompile(`^(\d+)-`)
+)
+
+// prefixAliases maps commonly used branch prefixes to their conventional name
+var prefixAliases = map[string]string{
+	"feat":     "feature",
+	"features": "feature",
+	"fix":      "bugfix",
+	"bug":      "bugfix",
+	"doc":      "docs",
+	"tests":    "test",
+	"exp":      "experiment",
+	"spike":    "experiment",
+}
+
+// Branch is a branch name split into its conventional parts
+type Branch struct {
+	Prefix      string `json:"prefix"`
+	Scope       string `json:"scope,omitempty"`
+	IssueNumber int    `json:"issue_number,omitempty"`
+	Description string `json:"description"`
+}
+
+// Violation is a single naming rule a branch breaks, with a suggested fix
+type Violation struct {
+	Rule       string `json:"rule"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Options configures the checks that depend on the work on the branch
+type Options struct {
+	// IssueNumber, if set, must appear as the <issue-number>- prefix of the description
+	IssueNumber int
+}
+
+// Result is the outcome of validating a branch name
+type Result struct {
+	Valid         bool        `json:"valid"`
+	Branch        *Branch     `json:"branch,omitempty"`
+	Violations    []Violation `json:"violations"`
+	SuggestedName string      `json:"suggested_name,omitempty"`
+}
+
+// Validate checks a branch name against the naming rules of the policy:
+// <type>/<short-description>, <type>/<issue-number>-<short-description> or
+// <type>/<scope>/<short-description> with an allowed type and lowercase words
+// separated by hyphens
+func Validate(name string, pol *policy.Policy, opts Options) Result {
+	result := Result{Valid: true, Violations: []Violation{}}
+	add := func(rule, suggestion, format string, args ...any) {
+		result.Valid = false
+		result.Violations = append(result.Violations, Violation{
+			Rule:       rule,
+			Message:    fmt.Sprintf(format, args...),
+			Suggestion: suggestion,
+		})
+	}
+
+	if strings.TrimSpace(name) == "" {
+		add(RuleEmpty, "Use <type>/<issue-number>-<short-description>", "Branch name is empty")
+		return result
+	}
+	if pol.IsProtectedBranch(name) {
+		add(RuleProtected, "Create a feature branch instead", "%q is a protected branch and must not be worked on directly", name)
+		return result
+	}
+
+	segments := strings.Split(name, "/")
+	prefix := segments[0]
+	switch {
+	case len(segments) == 1:
+		add(RulePrefix, fmt.Sprintf("Start the name with one of: %s", prefixList(pol)), "Branch name has no <type>/ prefix")
+	case !slices.Contains(pol.BranchPrefixes(), prefix):
+		suggestion := fmt.Sprintf("Use one of: %s", prefixList(pol))
+		if alias, ok := prefixAlias(strings.ToLower(prefix), pol); ok {
+			suggestion = fmt.Sprintf("Use %q", alias+"/")
+		}
+		add(RulePrefix, suggestion, "Prefix %q is not an allowed branch type", prefix)
+	}
+	if len(segments) > maxSegments {
+		add(RuleSegments, "Use at most <type>/<scope>/<short-description>", "Branch name has %d segments, at most %d are allowed", len(segments), maxSegments)
+	}
+
+	if lower := strings.ToLower(name); lower != name {
+		add(RuleCase, fmt.Sprintf("Use %q", lower), "Branch name must be lowercase")
+	}
+	for _, segment := range segments {
+		if bad := invalidCharacters.FindAllString(strings.ToLower(segment), -1); len(bad) > 0 {
+			add(RuleCharacters, "Replace spaces, underscores and other characters with hyphens",
+				"Branch name contains invalid characters %q; use letters, digits and hyphens", strings.Join(bad, ""))
+			break
+		}
+	}
+	for _, segment := range segments {
+		if segment == "" || strings.HasPrefix(segment, "-") || strings.HasSuffix(segment, "-") || strings.Contains(segment, "--") {
+			add(RuleHyphens, "Separate words with single hyphens and do not leave empty segments",
+				"Segment %q has empty parts or leading, trailing or repeated hyphens", segment)
+			break
+		}
+	}
+
+	if limit := pol.Branches.MaxLength; limit > 0 && utf8.RuneCountInString(name) > limit {
+		add(RuleMaxLength, fmt.Sprintf("Shorten the description by %d characters", utf8.RuneCountInString(name)-limit),
+			"Branch name is %d characters, the limit is %d", utf8.RuneCountInString(name), limit)
+	}
+
+	branch := parse(segments)
+	if len(segments) > 1 {
+		result.Branch = branch
+	}
+	if opts.IssueNumber > 0 && branch.IssueNumber != opts.IssueNumber {
+		add(RuleIssueNumber, fmt.Sprintf("Start the description with %d-", opts.IssueNumber),
+			"Branch name does not reference issue #%d", opts.IssueNumber)
+	}
+
+	if !result.Valid {
+		if suggested := normalize(name, pol, opts); suggested != name {
+			result.SuggestedName = suggested
+		}
+	}
+	return result
+}
+
+// parse splits the segments of a branch name into its conventional parts
+func parse(segments []string) *Branch {
+	branch := &Branch{Prefix: segments[0]}
+	if len(segments) == 1 {
+		branch.Prefix = ""
+		branch.Description = segments[0]
+		return branch
+	}
+
+	branch.Description = segments[len(segments)-1]
+	if len(segments) > 2 {
+		branch.Scope = strings.Join(segments[1:len(segments)-1], "/")
+	}
+	if match := issuePrefix.FindStringSubmatch(branch.Description); match != nil {
+		branch.IssueNumber, _ = strconv.Atoi(match[1])
+		branch.Description = strings.TrimPrefix(branch.Description, match[0])
+	}
+	return branch
+}
+
+// normalize rewrites a branch name so that it follows the naming rules where that
+// can be done mechanically
+func normalize(name string, pol *policy.Policy, opts Options) string {
+	segments := strings.Split(strings.ToLower(name), "/")
+	prefix := defaultPrefix(pol)
+	if len(segments) > 1 {
+		prefix = segments[0]
+		segments = segments[1:]
+	}
+	if !slices.Contains(pol.BranchPrefixes(), prefix) {
+		if alias, ok := prefixAlias(prefix, pol); ok {
+			prefix = alias
+		} else {
+			prefix = defaultPrefix(pol)
+		}
+	}
+
+	var parts []string
+	for _, segment := range segments {
+		if slug := slugify(segment); slug != "" {
+			parts = append(parts, slug)
+		}
+	}
+	if len(parts) == 0 {
+		return prefix + "/"
+	}
+	if len(parts) > maxSegments-1 {
+		parts = []string{parts[0], strings.Join(parts[1:], "-")}
+	}
+
+	description := parts[len(parts)-1]
+	if opts.IssueNumber > 0 && !strings.HasPrefix(description, strconv.Itoa(opts.IssueNumber)+"-") {
+		description = strconv.Itoa(opts.IssueNumber) + "-" + issuePrefix.ReplaceAllString(description, "")
+	}
+	parts[len(parts)-1] = description
+
+	return truncate(prefix+"/"+strings.Join(parts, "/"), pol.Branches.MaxLength)
+}
+
+// slugify lowercases text and joins its words with single hyphens
+func slugify(text string) string {
+	slug := invalidCharacters.ReplaceAllString(strings.ToLower(text), "-")
+	return strings.Trim(repeatedHyphens.ReplaceAllString(slug, "-"), "-")
+}
+
+// truncate shortens a branch name to at most limit characters, cutting at a hyphen
+// where possible. A limit of zero means no limit.
+func truncate(name string, limit int) string {
+	if limit <= 0 || len(name) <= limit {
+		return name
+	}
+	cut := name[:limit]
+	if i := strings.LastIndex(cut, "-"); name[limit] != '-' && i > strings.LastIndex(cut, "/")+1 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, "-")
+}
+
+// prefixAlias returns the allowed prefix a mistyped prefix most likely means
+func prefixAlias(prefix string, pol *policy.Policy) (string, bool) {
+	if alias, ok := prefixAliases[prefix]; ok && slices.Contains(pol.BranchPrefixes(), alias) {
+		return alias, true
+	}
+	for _, allowed := range pol.BranchPrefixes() {
+		if prefix != "" && (strings.HasPrefix(allowed, prefix) || strings.HasPrefix(prefix, allowed)) {
+			return allowed, true
+		}
+	}
+	return "", false
+}
+
+// defaultPrefix returns the prefix used when none can be inferred
+func defaultPrefix(pol *policy.Policy) string {
+	if slices.Contains(pol.BranchPrefixes(), "feature") {
+		return "feature"
+	}
+	return pol.BranchPrefixes()[0]
+}
+
+// prefixList formats the allowed prefixes for messages
+func prefixList(pol *policy.Policy) string {
+	prefixes := pol.BranchPrefixes()
+	for i, prefix := range prefixes {
+		prefixes[i] = prefix + "/"
+	}
+	return strings.Join(prefixes, ", ")
+}
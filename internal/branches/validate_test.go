@@ -0,0 +1,104 @@
+package branches
+
+import (
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// rules returns the rules of the violations in order
+func rules(violations []Violation) []string {
+	var names []string
+	for _, v := range violations {
+		names = append(names, v.Rule)
+	}
+	return names
+}
+
+func TestValidateValidNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		branch Branch
+	}{
+		{"feature/add-user-auth", Branch{Prefix: "feature", Description: "add-user-auth"}},
+		{"bugfix/42-fix-login-error", Branch{Prefix: "bugfix", IssueNumber: 42, Description: "fix-login-error"}},
+		{"chore/deps/update-go", Branch{Prefix: "chore", Scope: "deps", Description: "update-go"}},
+	}
+
+	for _, tt := range tests {
+		result := Validate(tt.name, policy.Default(), Options{})
+		if !result.Valid {
+			t.Errorf("Expected %q to be valid, got %v", tt.name, rules(result.Violations))
+			continue
+		}
+		if result.Branch == nil || *result.Branch != tt.branch {
+			t.Errorf("%q: expected %+v, got %+v", tt.name, tt.branch, result.Branch)
+		}
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		rule      string
+		suggested string
+	}{
+		{"", Options{}, RuleEmpty, ""},
+		{"main", Options{}, RuleProtected, ""},
+		{"add-login", Options{}, RulePrefix, "feature/add-login"},
+		{"feat/add-login", Options{}, RulePrefix, "feature/add-login"},
+		{"feature/Add-Login", Options{}, RuleCase, "feature/add-login"},
+		{"feature/add_login page", Options{}, RuleCharacters, "feature/add-login-page"},
+		{"feature/add--login-", Options{}, RuleHyphens, "feature/add-login"},
+		{"feature/a/b/c", Options{}, RuleSegments, "feature/a/b-c"},
+		{"feature/add-login", Options{IssueNumber: 7}, RuleIssueNumber, "feature/7-add-login"},
+		{"feature/" + "very-long-description-that-keeps-going-well-past-the-length-limit", Options{}, RuleMaxLength,
+			"feature/very-long-description-that-keeps-going-well-past-the"},
+	}
+
+	for _, tt := range tests {
+		result := Validate(tt.name, policy.Default(), tt.opts)
+		if result.Valid {
+			t.Errorf("Expected %q to be invalid", tt.name)
+			continue
+		}
+
+		found := false
+		for _, v := range result.Violations {
+			if v.Rule == tt.rule {
+				found = true
+				if v.Message == "" || v.Suggestion == "" {
+					t.Errorf("%q: expected message and suggestion, got %+v", tt.name, v)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%q: expected %s violation, got %v", tt.name, tt.rule, rules(result.Violations))
+		}
+		if result.SuggestedName != tt.suggested {
+			t.Errorf("%q: expected suggested name %q, got %q", tt.name, tt.suggested, result.SuggestedName)
+		}
+		if tt.suggested != "" {
+			if again := Validate(tt.suggested, policy.Default(), tt.opts); !again.Valid {
+				t.Errorf("Expected suggested name %q to be valid, got %v", tt.suggested, rules(again.Violations))
+			}
+		}
+	}
+}
+
+func TestValidateUsesPolicyPrefixes(t *testing.T) {
+	p := policy.Default()
+	p.Branches.Prefixes = []policy.Convention{{Name: "story"}, {Name: "defect"}}
+	p.Branches.Protected = []string{"develop"}
+
+	if result := Validate("story/12-checkout", p, Options{}); !result.Valid {
+		t.Errorf("Expected policy prefix to be accepted, got %v", rules(result.Violations))
+	}
+	if result := Validate("feature/12-checkout", p, Options{}); result.Valid {
+		t.Error("Expected prefix outside the policy to be rejected")
+	}
+	if result := Validate("develop", p, Options{}); result.Valid {
+		t.Error("Expected protected branch from the policy to be rejected")
+	}
+}
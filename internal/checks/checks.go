@@ -0,0 +1,208 @@
+// Package checks follows the CI of a commit: the GitHub Actions workflow runs and the
+// check suites and check runs that GitHub Apps report for it
+package checks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+)
+
+// States of a Summary
+const (
+	StatePending = "pending"
+	StateSuccess = "success"
+	StateFailure = "failure"
+	// StateNone means no checks have been reported for the commit
+	StateNone = "none"
+)
+
+// statusCompleted is the status of finished suites and runs
+const statusCompleted = "completed"
+
+// actionsApp is the slug of the GitHub Actions app
+const actionsApp = "github-actions"
+
+// failingConclusions are the conclusions that fail a check; success, neutral and
+// skipped pass
+var failingConclusions = []string{"failure", "timed_out", "cancelled", "action_required", "startup_failure", "stale"}
+
+// Job is a check of the commit. For GitHub Actions it is a job of a workflow run and
+// ID is the job ID.
+type Job struct {
+	ID         int64  `json:"id,omitempty"`
+	Name       string `json:"name"`
+	Workflow   string `json:"workflow,omitempty"`
+	RunID      int64  `json:"run_id,omitempty"`
+	App        string `json:"app,omitempty"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Duration   string `json:"duration,omitempty"`
+}
+
+// Completed reports whether the job has finished
+func (j Job) Completed() bool {
+	return j.Status == statusCompleted
+}
+
+// Failed reports whether the job finished without passing
+func (j Job) Failed() bool {
+	return j.Completed() && FailingConclusion(j.Conclusion)
+}
+
+// FailingConclusion reports whether a check or job with conclusion failed
+func FailingConclusion(conclusion string) bool {
+	return slices.Contains(failingConclusions, conclusion)
+}
+
+// key identifies the job across polls
+func (j Job) key() string {
+	if j.ID != 0 {
+		return fmt.Sprint(j.ID)
+	}
+	return j.Workflow + "/" + j.Name
+}
+
+// title names the job with its workflow
+func (j Job) title() string {
+	if j.Workflow != "" && j.Workflow != j.Name {
+		return j.Workflow + " / " + j.Name
+	}
+	return j.Name
+}
+
+// Summary is the state of all checks of a commit
+type Summary struct {
+	SHA       string `json:"sha"`
+	State     string `json:"state"`
+	Jobs      []Job  `json:"jobs"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+	Passed    int    `json:"passed"`
+	Failed    int    `json:"failed"`
+}
+
+// Snapshot fetches the checks of commit sha. Workflows that are queued but have no
+// jobs yet are reported as a pending job named after the workflow.
+func Snapshot(ctx context.Context, client *github.Client, owner, repo, sha string) (*Summary, error) {
+	suites, err := client.ListCheckSuites(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, fmt.Errorf("listing check suites of %.12s: %w", sha, err)
+	}
+	runs, err := client.ListCheckRuns(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, fmt.Errorf("listing check runs of %.12s: %w", sha, err)
+	}
+	workflowRuns, err := client.ListWorkflowRuns(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, fmt.Errorf("listing workflow runs of %.12s: %w", sha, err)
+	}
+
+	workflows := map[int64]github.WorkflowRun{}
+	for _, run := range workflowRuns {
+		workflows[run.CheckSuiteID] = run
+	}
+
+	s := &Summary{SHA: sha, Jobs: []Job{}}
+	withRuns := map[int64]bool{}
+	for _, run := range runs {
+		job := Job{ID: run.ID, Name: run.Name, Status: run.Status, Conclusion: run.Conclusion, URL: run.HTMLURL}
+		if run.App != nil {
+			job.App = run.App.Slug
+		}
+		if run.CheckSuite != nil {
+			withRuns[run.CheckSuite.ID] = true
+			if workflow, ok := workflows[run.CheckSuite.ID]; ok {
+				job.Workflow, job.RunID = workflow.Name, workflow.ID
+			}
+		}
+		if run.StartedAt != nil && run.CompletedAt != nil {
+			job.Duration = run.CompletedAt.Sub(*run.StartedAt).Round(time.Second).String()
+		}
+		s.Jobs = append(s.Jobs, job)
+	}
+
+	// Other apps can leave suites queued forever, so only workflows count as pending without runs
+	for _, suite := range suites {
+		if withRuns[suite.ID] || suite.Status == statusCompleted || suite.App == nil || suite.App.Slug != actionsApp {
+			continue
+		}
+		job := Job{Name: fmt.Sprintf("check suite %d", suite.ID), App: suite.App.Slug, Status: suite.Status}
+		if workflow, ok := workflows[suite.ID]; ok {
+			job.Name, job.Workflow, job.RunID, job.URL = workflow.Name, workflow.Name, workflow.ID, workflow.HTMLURL
+		}
+		s.Jobs = append(s.Jobs, job)
+	}
+
+	s.summarize()
+	return s, nil
+}
+
+// summarize counts the jobs and derives the overall state
+func (s *Summary) summarize() {
+	s.Total, s.Completed, s.Passed, s.Failed = len(s.Jobs), 0, 0, 0
+	for _, job := range s.Jobs {
+		switch {
+		case job.Failed():
+			s.Completed++
+			s.Failed++
+		case job.Completed():
+			s.Completed++
+			s.Passed++
+		}
+	}
+
+	switch {
+	case s.Total == 0:
+		s.State = StateNone
+	case s.Completed < s.Total:
+		s.State = StatePending
+	case s.Failed > 0:
+		s.State = StateFailure
+	default:
+		s.State = StateSuccess
+	}
+}
+
+// Text is a per-job summary for the agent to read, failures first
+func (s *Summary) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Checks for %.12s: %s (%d passed, %d failed, %d pending)\n", s.SHA, s.State, s.Passed, s.Failed, s.Total-s.Completed)
+
+	jobs := slices.Clone(s.Jobs)
+	rank := func(j Job) int {
+		switch {
+		case j.Failed():
+			return 0
+		case !j.Completed():
+			return 1
+		default:
+			return 2
+		}
+	}
+	slices.SortStableFunc(jobs, func(a, b Job) int { return rank(a) - rank(b) })
+
+	for _, job := range jobs {
+		result := job.Status
+		switch {
+		case job.Failed():
+			result = "FAIL (" + job.Conclusion + ")"
+		case job.Completed():
+			result = "PASS (" + job.Conclusion + ")"
+		}
+		line := fmt.Sprintf("- %s: %s", job.title(), result)
+		if job.Duration != "" {
+			line += " in " + job.Duration
+		}
+		if job.URL != "" {
+			line += " " + job.URL
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,86 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+)
+
+const sha = "0123456789abcdef0123456789abcdef01234567"
+
+// checkRun is a check run of the CI workflow, whose check suite is 10
+func checkRun(id int, name, status, conclusion string) map[string]any {
+	return map[string]any{
+		"id": id, "name": name, "status": status, "conclusion": conclusion,
+		"html_url":     "https://github.com/octo/hello/actions/runs/100/job/" + name,
+		"started_at":   "2026-10-01T10:00:00Z",
+		"completed_at": "2026-10-01T10:01:30Z",
+		"check_suite":  map[string]any{"id": 10},
+		"app":          map[string]any{"slug": "github-actions"},
+	}
+}
+
+// serveChecks serves the checks of sha: suite 10 is the CI workflow with runs,
+// suite 11 a queued Release workflow without runs and suite 12 a stale suite of another app
+func serveChecks(s *githubtest.Server, runs ...map[string]any) {
+	prefix := "GET /repos/octo/hello/commits/" + sha
+	s.JSON(prefix+"/check-suites", http.StatusOK, map[string]any{"check_suites": []map[string]any{
+		{"id": 10, "status": "in_progress", "app": map[string]any{"slug": "github-actions"}},
+		{"id": 11, "status": "queued", "app": map[string]any{"slug": "github-actions"}},
+		{"id": 12, "status": "queued", "app": map[string]any{"slug": "some-bot"}},
+	}})
+	s.JSON(prefix+"/check-runs", http.StatusOK, map[string]any{"check_runs": runs})
+	s.JSON("GET /repos/octo/hello/actions/runs", http.StatusOK, map[string]any{"workflow_runs": []map[string]any{
+		{"id": 100, "name": "CI", "check_suite_id": 10},
+		{"id": 101, "name": "Release", "check_suite_id": 11, "html_url": "https://github.com/octo/hello/actions/runs/101"},
+	}})
+}
+
+func TestSnapshot(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveChecks(s, checkRun(1, "build", "completed", "success"), checkRun(2, "lint", "completed", "failure"), checkRun(3, "test", "in_progress", ""))
+
+	summary, err := Snapshot(context.Background(), s.Client(t), "octo", "hello", sha)
+	if err != nil {
+		t.Fatalf("Snapshot returned error: %v", err)
+	}
+	if summary.State != StatePending || summary.Total != 4 || summary.Passed != 1 || summary.Failed != 1 || summary.Completed != 2 {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+	if job := summary.Jobs[0]; job.Workflow != "CI" || job.RunID != 100 || job.Duration != "1m30s" || job.App != "github-actions" {
+		t.Errorf("Unexpected job %+v", job)
+	}
+	if job := summary.Jobs[3]; job.Name != "Release" || job.Status != "queued" || job.RunID != 101 {
+		t.Errorf("Expected the queued workflow as a pending job, got %+v", job)
+	}
+
+	text := summary.Text()
+	if !strings.HasPrefix(text, "Checks for 0123456789ab: pending (1 passed, 1 failed, 2 pending)\n- CI / lint: FAIL (failure)") {
+		t.Errorf("Expected failures first, got:\n%s", text)
+	}
+}
+
+func TestSummaryState(t *testing.T) {
+	tests := []struct {
+		name string
+		jobs []Job
+		want string
+	}{
+		{"none", nil, StateNone},
+		{"pending", []Job{{Status: "completed", Conclusion: "success"}, {Status: "queued"}}, StatePending},
+		{"success", []Job{{Status: "completed", Conclusion: "success"}, {Status: "completed", Conclusion: "skipped"}}, StateSuccess},
+		{"failure", []Job{{Status: "completed", Conclusion: "neutral"}, {Status: "completed", Conclusion: "timed_out"}}, StateFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Summary{Jobs: tt.jobs}
+			s.summarize()
+			if s.State != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, s.State)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+)
+
+// Backoff is how the interval between polls grows
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Factor  float64
+}
+
+// DefaultBackoff polls after 5 seconds, then ever less often up to every 30 seconds
+var DefaultBackoff = Backoff{Initial: 5 * time.Second, Max: 30 * time.Second, Factor: 1.5}
+
+// DefaultStartupGrace is how long Wait waits for the first checks of a freshly pushed commit
+const DefaultStartupGrace = 2 * time.Minute
+
+// WaitOptions configure Wait
+type WaitOptions struct {
+	// Timeout bounds the whole wait
+	Timeout time.Duration
+
+	// Backoff is the polling schedule; the zero value uses DefaultBackoff
+	Backoff Backoff
+
+	// StartupGrace is how long to keep polling while no checks have been reported,
+	// since CI takes a moment to start after a push
+	StartupGrace time.Duration
+
+	// OnUpdate, if set, is called after the first poll and whenever jobs have
+	// finished, with the jobs that finished since the previous call
+	OnUpdate func(s *Summary, finished []Job)
+}
+
+// Wait polls the checks of commit sha until all have completed, or none have been
+// reported within the startup grace, or the timeout expires. Transient GitHub errors,
+// such as server errors and rate limits, are retried on the polling schedule, or once
+// the rate limit resets, until the timeout; the last of them is returned if no poll
+// succeeded. It reports whether the timeout expired and returns the context's error
+// if it is cancelled.
+func Wait(ctx context.Context, client *github.Client, owner, repo, sha string, opts WaitOptions) (*Summary, bool, error) {
+	backoff := opts.Backoff
+	if backoff.Initial <= 0 {
+		backoff = DefaultBackoff
+	}
+	start := time.Now()
+	deadline := start.Add(opts.Timeout)
+
+	var last *Summary
+	finished := map[string]bool{}
+	delay := backoff.Initial
+	for polls := 0; ; {
+		wait := delay
+		s, err := Snapshot(ctx, client, owner, repo, sha)
+		if err != nil {
+			if !transient(err) {
+				return nil, false, err
+			}
+			var apiErr *github.Error
+			if errors.As(err, &apiErr) && !apiErr.RateLimitReset.IsZero() {
+				wait = max(wait, time.Until(apiErr.RateLimitReset))
+			}
+		} else {
+			last = s
+			var done []Job
+			for _, job := range s.Jobs {
+				if job.Completed() && !finished[job.key()] {
+					finished[job.key()] = true
+					done = append(done, job)
+				}
+			}
+			if opts.OnUpdate != nil && (polls == 0 || len(done) > 0) {
+				opts.OnUpdate(s, done)
+			}
+			polls++
+
+			switch {
+			case s.State == StateSuccess || s.State == StateFailure:
+				return s, false, nil
+			case s.State == StateNone && time.Since(start) >= opts.StartupGrace:
+				return s, false, nil
+			}
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if last == nil {
+				return nil, false, err
+			}
+			return last, true, nil
+		}
+		timer := time.NewTimer(min(wait, remaining))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, false, ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(time.Duration(float64(delay)*backoff.Factor), backoff.Max)
+	}
+}
+
+// transient reports whether err may go away by itself: a rate limit, a server error
+// or a network error
+func transient(err error) bool {
+	if errors.Is(err, github.ErrRateLimited) {
+		return true
+	}
+	var apiErr *github.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,150 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+)
+
+// fastBackoff keeps tests quick
+var fastBackoff = Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Factor: 2}
+
+// serveProgress serves check runs that finish one per poll
+func serveProgress(s *githubtest.Server, names ...string) {
+	var mu sync.Mutex
+	polls := 0
+	prefix := "GET /repos/octo/hello/commits/" + sha
+	s.JSON(prefix+"/check-suites", http.StatusOK, map[string]any{"check_suites": []any{}})
+	s.JSON("GET /repos/octo/hello/actions/runs", http.StatusOK, map[string]any{"workflow_runs": []any{}})
+	s.HandleFunc(prefix+"/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var runs []map[string]any
+		for i, name := range names {
+			if i < polls {
+				runs = append(runs, checkRun(i+1, name, "completed", "success"))
+			} else {
+				runs = append(runs, checkRun(i+1, name, "in_progress", ""))
+			}
+		}
+		polls++
+		githubtest.WriteJSON(w, http.StatusOK, map[string]any{"check_runs": runs})
+	})
+}
+
+func TestWaitUntilComplete(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveProgress(s, "build", "test")
+
+	var updates [][]Job
+	summary, timedOut, err := Wait(context.Background(), s.Client(t), "octo", "hello", sha, WaitOptions{
+		Timeout:  time.Minute,
+		Backoff:  fastBackoff,
+		OnUpdate: func(_ *Summary, finished []Job) { updates = append(updates, finished) },
+	})
+	if err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+	if timedOut || summary.State != StateSuccess || summary.Passed != 2 {
+		t.Errorf("Unexpected summary %+v (timed out %v)", summary, timedOut)
+	}
+	if len(updates) != 3 || len(updates[0]) != 0 || updates[1][0].Name != "build" || updates[2][0].Name != "test" {
+		t.Errorf("Expected an update for the start and each finished job, got %+v", updates)
+	}
+}
+
+func TestWaitTimeoutAndCancellation(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveChecks(s, checkRun(1, "build", "in_progress", ""))
+	client := s.Client(t)
+
+	summary, timedOut, err := Wait(context.Background(), client, "octo", "hello", sha, WaitOptions{Timeout: 20 * time.Millisecond, Backoff: fastBackoff})
+	if err != nil || !timedOut || summary.State != StatePending {
+		t.Errorf("Expected timeout with pending checks, got %+v, %v, %v", summary, timedOut, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, err = Wait(ctx, client, "octo", "hello", sha, WaitOptions{
+		Timeout:  time.Minute,
+		Backoff:  fastBackoff,
+		OnUpdate: func(*Summary, []Job) { cancel() },
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation error, got %v", err)
+	}
+}
+
+func TestWaitWithoutChecks(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveProgress(s)
+
+	summary, timedOut, err := Wait(context.Background(), s.Client(t), "octo", "hello", sha, WaitOptions{
+		Timeout:      time.Minute,
+		Backoff:      fastBackoff,
+		StartupGrace: 10 * time.Millisecond,
+	})
+	if err != nil || timedOut || summary.State != StateNone {
+		t.Errorf("Expected no checks after the startup grace, got %+v, %v, %v", summary, timedOut, err)
+	}
+}
+
+// serveFailures answers for the check suites of sha with each of statuses in turn,
+// and from then on reports a passed build
+func serveFailures(s *githubtest.Server, statuses ...int) {
+	var mu sync.Mutex
+	prefix := "GET /repos/octo/hello/commits/" + sha
+	s.JSON(prefix+"/check-runs", http.StatusOK, map[string]any{"check_runs": []any{checkRun(1, "build", "completed", "success")}})
+	s.JSON("GET /repos/octo/hello/actions/runs", http.StatusOK, map[string]any{"workflow_runs": []any{}})
+	s.HandleFunc(prefix+"/check-suites", func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(statuses) > 0 {
+			status := statuses[0]
+			statuses = statuses[1:]
+			if status == http.StatusTooManyRequests || status == http.StatusForbidden {
+				// A 403 with Retry-After is a secondary rate limit
+				w.Header().Set("Retry-After", "0")
+			}
+			githubtest.WriteJSON(w, status, map[string]any{"message": http.StatusText(status)})
+			return
+		}
+		githubtest.WriteJSON(w, http.StatusOK, map[string]any{"check_suites": []any{}})
+	})
+}
+
+func TestWaitRetriesTransientErrors(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveFailures(s, http.StatusBadGateway, http.StatusTooManyRequests, http.StatusForbidden, http.StatusServiceUnavailable)
+
+	summary, timedOut, err := Wait(context.Background(), s.Client(t), "octo", "hello", sha, WaitOptions{Timeout: time.Minute, Backoff: fastBackoff})
+	if err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+	if timedOut || summary.State != StateSuccess {
+		t.Errorf("Expected the checks to be waited for past the errors, got %+v (timed out %v)", summary, timedOut)
+	}
+
+	s = githubtest.NewServer(t)
+	serveFailures(s, http.StatusNotFound)
+	if _, _, err := Wait(context.Background(), s.Client(t), "octo", "hello", sha, WaitOptions{Timeout: time.Minute, Backoff: fastBackoff}); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("Expected a not found error at once, got %v", err)
+	}
+
+	s = githubtest.NewServer(t)
+	failures := make([]int, 1000)
+	for i := range failures {
+		failures[i] = http.StatusInternalServerError
+	}
+	serveFailures(s, failures...)
+	_, timedOut, err = Wait(context.Background(), s.Client(t), "octo", "hello", sha, WaitOptions{Timeout: 20 * time.Millisecond, Backoff: fastBackoff})
+	if err == nil || timedOut || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Expected the last error once the timeout expired, got %v (timed out %v)", err, timedOut)
+	}
+}
@@ -0,0 +1,170 @@
+package cilogs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/checks"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+)
+
+// JobFailure is what went wrong in a failed job
+type JobFailure struct {
+	JobID      int64     `json:"job_id"`
+	Job        string    `json:"job"`
+	Workflow   string    `json:"workflow,omitempty"`
+	RunID      int64     `json:"run_id"`
+	URL        string    `json:"url,omitempty"`
+	Step       string    `json:"step,omitempty"`
+	StepNumber int       `json:"step_number,omitempty"`
+	Failures   []Failure `json:"failures"`
+	Excerpt    string    `json:"excerpt"`
+}
+
+// Analyze downloads the logs of the failed jobs of workflow run runID, or of job jobID
+// only when it is not zero, and extracts their failures. The run's log archive is
+// used; if it cannot be downloaded, each job's log is fetched instead. Excerpts of
+// the failing steps are limited to excerptLines lines.
+func Analyze(ctx context.Context, client *github.Client, owner, repo string, runID, jobID int64, excerptLines int) ([]JobFailure, error) {
+	jobs, err := client.ListWorkflowJobs(ctx, owner, repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("listing jobs of run %d: %w", runID, err)
+	}
+
+	var selected []github.WorkflowJob
+	for _, job := range jobs {
+		if (jobID == 0 && checks.FailingConclusion(job.Conclusion)) || job.ID == jobID {
+			selected = append(selected, job)
+		}
+	}
+	if jobID != 0 && len(selected) == 0 {
+		return nil, fmt.Errorf("job %d is not part of run %d", jobID, runID)
+	}
+	results := []JobFailure{}
+	if len(selected) == 0 {
+		return results, nil
+	}
+
+	archive, archiveErr := downloadArchive(ctx, client, owner, repo, runID)
+	for _, job := range selected {
+		result := JobFailure{JobID: job.ID, Job: job.Name, Workflow: job.WorkflowName, RunID: runID, URL: job.HTMLURL}
+		step := failingStep(job.Steps)
+		if step != nil {
+			result.Step, result.StepNumber = step.Name, step.Number
+		}
+
+		section, full, err := jobLog(ctx, client, owner, repo, archive, job, step)
+		if err != nil {
+			if archiveErr != nil {
+				err = fmt.Errorf("%w (log archive: %v)", err, archiveErr)
+			}
+			return nil, fmt.Errorf("downloading logs of job %q: %w", job.Name, err)
+		}
+
+		result.Failures = Extract(section)
+		if len(result.Failures) == 0 && section != full {
+			result.Failures = Extract(full)
+		}
+		result.Excerpt = Tail(section, excerptLines)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// downloadArchive downloads and opens the log archive of a run
+func downloadArchive(ctx context.Context, client *github.Client, owner, repo string, runID int64) (*Archive, error) {
+	data, err := client.DownloadRunLogs(ctx, owner, repo, runID)
+	if err != nil {
+		return nil, err
+	}
+	return OpenArchive(data)
+}
+
+// jobLog returns the log of the failing step of a job and the whole job log, from the
+// archive if it has them and downloaded otherwise
+func jobLog(ctx context.Context, client *github.Client, owner, repo string, archive *Archive,
+	job github.WorkflowJob, step *github.WorkflowStep) (string, string, error) {
+	if archive != nil {
+		full, ok := archive.JobLog(job.Name)
+		if step != nil {
+			if text, ok := archive.StepLog(job.Name, step.Number); ok {
+				return text, full, nil
+			}
+		}
+		if ok {
+			return FailingSection(full), full, nil
+		}
+	}
+
+	data, err := client.DownloadJobLogs(ctx, owner, repo, job.ID)
+	if err != nil {
+		return "", "", err
+	}
+	full := Clean(string(data))
+	return FailingSection(full), full, nil
+}
+
+// failingStep returns the first step of a job that failed
+func failingStep(steps []github.WorkflowStep) *github.WorkflowStep {
+	for i := range steps {
+		if checks.FailingConclusion(steps[i].Conclusion) {
+			return &steps[i]
+		}
+	}
+	return nil
+}
+
+// Markdown renders job failures for the agent to read
+func Markdown(jobs []JobFailure) string {
+	if len(jobs) == 0 {
+		return "No failed jobs.\n"
+	}
+
+	var sb strings.Builder
+	for i, job := range jobs {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		title := job.Job
+		if job.Workflow != "" {
+			title = job.Workflow + " / " + job.Job
+		}
+		fmt.Fprintf(&sb, "## %s\n\n", title)
+		if job.Step != "" {
+			fmt.Fprintf(&sb, "- **Failed step:** %d. %s\n", job.StepNumber, job.Step)
+		}
+		if job.URL != "" {
+			fmt.Fprintf(&sb, "- **URL:** %s\n", job.URL)
+		}
+
+		if len(job.Failures) > 0 {
+			sb.WriteString("\n### Failures\n\n")
+		}
+		for _, f := range job.Failures {
+			sb.WriteString("- " + failureLine(f) + "\n")
+		}
+		fmt.Fprintf(&sb, "\n### Log\n\n```text\n%s\n```\n", job.Excerpt)
+	}
+	return sb.String()
+}
+
+// failureLine summarizes a failure on one line
+func failureLine(f Failure) string {
+	var parts []string
+	if len(f.Locations) > 0 {
+		parts = append(parts, f.Locations[0].String())
+	}
+	subject := f.Kind
+	switch {
+	case f.Test != "":
+		subject += " " + f.Test
+	case f.Linter != "":
+		subject += " (" + f.Linter + ")"
+	}
+	if f.Package != "" {
+		subject += " in " + f.Package
+	}
+	parts = append(parts, "**"+subject+"**", f.Message)
+	return strings.Join(parts, " ")
+}
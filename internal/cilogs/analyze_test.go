@@ -0,0 +1,92 @@
+package cilogs
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+)
+
+const testLog = `2026-10-01T10:00:00.0000000Z ##[group]Run go test ./...
+2026-10-01T10:00:01.0000000Z --- FAIL: TestUpload (0.01s)
+2026-10-01T10:00:01.0000000Z     upload_test.go:42: Expected 3 retries, got 1
+2026-10-01T10:00:01.0000000Z FAIL
+2026-10-01T10:00:01.0000000Z FAIL	example.com/hello/upload	0.123s
+2026-10-01T10:00:02.0000000Z ##[error]Process completed with exit code 1.
+2026-10-01T10:00:03.0000000Z ##[group]Post job cleanup.`
+
+// serveJobs serves run 100 with a passing build job and a failing test job
+func serveJobs(s *githubtest.Server) {
+	s.JSON("GET /repos/octo/hello/actions/runs/100/jobs", http.StatusOK, map[string]any{"jobs": []map[string]any{
+		{"id": 1, "run_id": 100, "name": "build", "workflow_name": "CI", "status": "completed", "conclusion": "success"},
+		{"id": 2, "run_id": 100, "name": "test", "workflow_name": "CI", "status": "completed", "conclusion": "failure",
+			"html_url": "https://github.com/octo/hello/actions/runs/100/job/2",
+			"steps": []map[string]any{
+				{"name": "Set up job", "number": 1, "conclusion": "success"},
+				{"name": "Run go test", "number": 3, "conclusion": "failure"},
+			}},
+	}})
+}
+
+func TestAnalyze(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveJobs(s)
+	archive := zipArchive(t, map[string]string{"1_build.txt": "ok", "2_test.txt": testLog})
+	s.HandleFunc("GET /repos/octo/hello/actions/runs/100/logs", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/api/v3/storage/logs.zip", http.StatusFound)
+	})
+	s.HandleFunc("GET /storage/logs.zip", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(archive)
+	})
+
+	jobs, err := Analyze(context.Background(), s.Client(t), "octo", "hello", 100, 0, 50)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("Expected only the failed job, got %+v", jobs)
+	}
+	job := jobs[0]
+	if job.JobID != 2 || job.Workflow != "CI" || job.Step != "Run go test" || job.StepNumber != 3 {
+		t.Errorf("Unexpected job %+v", job)
+	}
+	if len(job.Failures) != 1 || job.Failures[0].Test != "TestUpload" || job.Failures[0].Package != "example.com/hello/upload" {
+		t.Errorf("Unexpected failures %+v", job.Failures)
+	}
+	if !strings.HasPrefix(job.Excerpt, "##[group]Run go test") || strings.Contains(job.Excerpt, "cleanup") {
+		t.Errorf("Expected the excerpt of the failing step, got %q", job.Excerpt)
+	}
+
+	text := Markdown(jobs)
+	for _, want := range []string{"## CI / test", "3. Run go test", "upload_test.go:42 **test TestUpload in example.com/hello/upload**"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestAnalyzeFallsBackToJobLogs(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveJobs(s)
+	s.JSON("GET /repos/octo/hello/actions/runs/100/logs", http.StatusGone, map[string]any{"message": "Gone"})
+	s.HandleFunc("GET /repos/octo/hello/actions/jobs/2/logs", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(testLog))
+	})
+
+	jobs, err := Analyze(context.Background(), s.Client(t), "octo", "hello", 100, 2, 2)
+	if err != nil {
+		t.Fatalf("Analyze returned error: %v", err)
+	}
+	if len(jobs) != 1 || len(jobs[0].Failures) != 1 {
+		t.Fatalf("Unexpected jobs %+v", jobs)
+	}
+	if !strings.HasPrefix(jobs[0].Excerpt, "... (4 earlier lines omitted)") {
+		t.Errorf("Expected the excerpt limited to two lines, got %q", jobs[0].Excerpt)
+	}
+
+	if _, err := Analyze(context.Background(), s.Client(t), "octo", "hello", 100, 9, 50); err == nil {
+		t.Error("Expected an error for a job outside the run")
+	}
+}
@@ -0,0 +1,124 @@
+package cilogs
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxLogFile bounds the size of a single log file read from an archive
+	maxLogFile = 16 << 20
+	// maxArchive bounds the size of all the log files read from an archive together,
+	// so that a small archive cannot decompress into more memory than the server has
+	maxArchive = 256 << 20
+)
+
+// ErrArchiveTooLarge is returned for log archives whose logs decompress to more than
+// the server reads
+var ErrArchiveTooLarge = errors.New("log archive too large")
+
+var (
+	// logFilePattern matches the files of a log archive: "3_build.txt" holds a whole
+	// job and "build/4_Run go test.txt" one of its steps
+	logFilePattern = regexp.MustCompile(`^(\d+)_(.*)\.txt$`)
+
+	// nameCharacters are the characters compared when matching job names to archive paths
+	nameCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Archive is the log archive of a workflow run
+type Archive struct {
+	// jobs maps normalized job names to their whole logs
+	jobs map[string]string
+	// steps maps normalized job names to their step logs by step number
+	steps map[string]map[int]string
+}
+
+// OpenArchive reads a workflow run's zip log archive. Each log file is cut to 16 MiB,
+// and archives whose log files decompress to more than 256 MiB in all are refused.
+func OpenArchive(data []byte) (*Archive, error) {
+	return openArchive(data, maxArchive)
+}
+
+// openArchive is OpenArchive with limit bytes of logs at most
+func openArchive(data []byte, limit int64) (*Archive, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading log archive: %w", err)
+	}
+
+	a := &Archive{jobs: map[string]string{}, steps: map[string]map[int]string{}}
+	remaining := limit
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		dir, base := path.Split(file.Name)
+		match := logFilePattern.FindStringSubmatch(base)
+		if match == nil || strings.Count(dir, "/") > 1 {
+			continue
+		}
+
+		text, size, err := readLogFile(file, min(maxLogFile, remaining+1))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s from log archive: %w", file.Name, err)
+		}
+		if size > remaining {
+			return nil, fmt.Errorf("%w: its logs decompress to more than %d MiB", ErrArchiveTooLarge, limit>>20)
+		}
+		remaining -= size
+		if dir == "" {
+			a.jobs[normalizeName(match[2])] = text
+			continue
+		}
+		job := normalizeName(strings.TrimSuffix(dir, "/"))
+		if a.steps[job] == nil {
+			a.steps[job] = map[int]string{}
+		}
+		number, _ := strconv.Atoi(match[1])
+		a.steps[job][number] = text
+	}
+	return a, nil
+}
+
+// readLogFile reads and cleans at most limit bytes of a file of the archive, and
+// returns the number of bytes it read
+func readLogFile(file *zip.File, limit int64) (string, int64, error) {
+	r, err := file.Open()
+	if err != nil {
+		return "", 0, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, limit))
+	if err != nil {
+		return "", 0, err
+	}
+	return Clean(string(data)), int64(len(data)), nil
+}
+
+// JobLog returns the whole log of a job
+func (a *Archive) JobLog(job string) (string, bool) {
+	text, ok := a.jobs[normalizeName(job)]
+	return text, ok
+}
+
+// StepLog returns the log of step number of a job. Newer archives hold only whole
+// job logs.
+func (a *Archive) StepLog(job string, number int) (string, bool) {
+	text, ok := a.steps[normalizeName(job)][number]
+	return text, ok
+}
+
+// normalizeName folds a job name to the characters that survive in archive paths,
+// from which GitHub removes characters such as slashes and colons
+func normalizeName(name string) string {
+	return nameCharacters.ReplaceAllString(strings.ToLower(name), "")
+}
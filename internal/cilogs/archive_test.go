@@ -0,0 +1,73 @@
+package cilogs
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// zipArchive builds a log archive holding files
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, text := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Create returned error: %v", err)
+		}
+		if _, err := f.Write([]byte(text)); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpenArchive(t *testing.T) {
+	data := zipArchive(t, map[string]string{
+		"0_test (ubuntu-latest).txt":             "2026-10-01T10:00:00.0000000Z whole job\r\n",
+		"test (ubuntu-latest)/4_Run go test.txt": "2026-10-01T10:00:00.0000000Z \x1b[31mstep four\x1b[0m\n",
+		"test (ubuntu-latest)/README.md":         "ignored",
+	})
+
+	archive, err := OpenArchive(data)
+	if err != nil {
+		t.Fatalf("OpenArchive returned error: %v", err)
+	}
+	if text, ok := archive.JobLog("Test (ubuntu-latest)"); !ok || text != "whole job" {
+		t.Errorf("Expected the cleaned job log, got %q, %v", text, ok)
+	}
+	if text, ok := archive.StepLog("test (ubuntu-latest)", 4); !ok || text != "step four" {
+		t.Errorf("Expected the cleaned step log, got %q, %v", text, ok)
+	}
+	if _, ok := archive.StepLog("test (ubuntu-latest)", 3); ok {
+		t.Error("Expected no log for a missing step")
+	}
+
+	if _, err := OpenArchive([]byte("not a zip")); err == nil {
+		t.Error("Expected an error for an invalid archive")
+	}
+}
+
+func TestOpenArchiveLimitsDecompressedSize(t *testing.T) {
+	data := zipArchive(t, map[string]string{
+		"0_build.txt": strings.Repeat("a", 3<<20),
+		"1_test.txt":  strings.Repeat("b", 2<<20),
+	})
+	if len(data) > 64<<10 {
+		t.Fatalf("Expected a small archive, got %d bytes", len(data))
+	}
+
+	_, err := openArchive(data, 4<<20)
+	if !errors.Is(err, ErrArchiveTooLarge) || !strings.Contains(err.Error(), "more than 4 MiB") {
+		t.Errorf("Expected the archive to be refused, got %v", err)
+	}
+	if _, err := openArchive(data, 5<<20); err != nil {
+		t.Errorf("Expected logs of exactly the limit to be read, got %v", err)
+	}
+}
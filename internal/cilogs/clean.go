@@ -0,0 +1,64 @@
+// Package cilogs turns GitHub Actions logs into the failures an agent needs to fix:
+// it finds the failing step of each failed job and recognizes Go test failures,
+// compile errors, golangci-lint issues and panics with their file and line
+package cilogs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// timestampPattern matches the timestamp GitHub Actions puts before every log line
+	timestampPattern = regexp.MustCompile(`^\x{FEFF}?\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?Z ?`)
+
+	// ansiPattern matches ANSI escape sequences such as colors
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+)
+
+// Clean removes timestamps and ANSI escape sequences from a log and normalizes line endings
+func Clean(log string) string {
+	lines := strings.Split(strings.ReplaceAll(log, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = ansiPattern.ReplaceAllString(timestampPattern.ReplaceAllString(line, ""), "")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// FailingSection returns the part of a job log that failed: the lines from the start
+// of the step group containing the first error up to that error. A log without an
+// error is returned whole.
+func FailingSection(log string) string {
+	lines := strings.Split(log, "\n")
+
+	failed := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "##[error]") {
+			failed = i
+			break
+		}
+	}
+	if failed < 0 {
+		return log
+	}
+
+	start := 0
+	for i := failed; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "##[group]") {
+			start = i
+			break
+		}
+	}
+	return strings.Join(lines[start:failed+1], "\n")
+}
+
+// Tail returns the last max lines of text, noting how many were left out
+func Tail(text string, max int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if max <= 0 || len(lines) <= max {
+		return strings.Join(lines, "\n")
+	}
+	omitted := len(lines) - max
+	return fmt.Sprintf("... (%d earlier lines omitted)\n", omitted) + strings.Join(lines[omitted:], "\n")
+}
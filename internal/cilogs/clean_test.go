@@ -0,0 +1,34 @@
+package cilogs
+
+import "testing"
+
+func TestClean(t *testing.T) {
+	log := "\ufeff2026-10-01T10:00:00.1234567Z ##[group]Run go test ./...\r\n" +
+		"2026-10-01T10:00:01.0000000Z \x1b[36;1mgo test ./...\x1b[0m\r\n" +
+		"2026-10-01T10:00:02.0000000Z \r\n"
+	want := "##[group]Run go test ./...\ngo test ./..."
+	if got := Clean(log); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestFailingSection(t *testing.T) {
+	log := "##[group]Run actions/checkout@v4\ncheckout\n##[endgroup]\n##[group]Run go test ./...\n##[endgroup]\n" +
+		"--- FAIL: TestX\n##[error]Process completed with exit code 1.\n##[group]Post job cleanup.\ncleanup"
+	want := "##[group]Run go test ./...\n##[endgroup]\n--- FAIL: TestX\n##[error]Process completed with exit code 1."
+	if got := FailingSection(log); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := FailingSection("no errors"); got != "no errors" {
+		t.Errorf("Expected the whole log without errors, got %q", got)
+	}
+}
+
+func TestTail(t *testing.T) {
+	if got := Tail("a\nb\nc\nd\n", 2); got != "... (2 earlier lines omitted)\nc\nd" {
+		t.Errorf("Unexpected tail %q", got)
+	}
+	if got := Tail("a\nb", 5); got != "a\nb" {
+		t.Errorf("Expected short text unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,273 @@
+package cilogs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kinds of Failure
+const (
+	KindTest    = "test"
+	KindCompile = "compile"
+	KindLint    = "lint"
+	KindPanic   = "panic"
+	KindError   = "error"
+)
+
+// maxStackFrames bounds the stack trace kept in the excerpt of a panic
+const maxStackFrames = 8
+
+var (
+	// testFailPattern matches the start of a failed Go test, "--- FAIL: TestName (0.01s)"
+	testFailPattern = regexp.MustCompile(`^\s*--- FAIL: (\S+)`)
+
+	// testOutputPattern matches a t.Error or t.Fatal line, "    file_test.go:12: message"
+	testOutputPattern = regexp.MustCompile(`^\s+([\w./-]+\.go):(\d+): (.*)$`)
+
+	// testRunPattern matches the lines go test -v prints when a test starts or resumes
+	// output, "=== RUN   TestName"
+	testRunPattern = regexp.MustCompile(`^=== (?:RUN|CONT|NAME|PAUSE)\s+(\S+)`)
+
+	// testEndPattern matches lines that end the output of a test
+	testEndPattern = regexp.MustCompile(`^\s*(?:--- (?:FAIL|PASS|SKIP)|=== |FAIL\b|ok\s|PASS$|panic: )`)
+
+	// packageFailPattern matches the summary of a failed package, "FAIL	example.com/pkg	0.01s"
+	packageFailPattern = regexp.MustCompile(`^FAIL\s+(\S+)`)
+
+	// positionPattern matches a compiler or linter message, "./file.go:12:5: message"
+	positionPattern = regexp.MustCompile(`^(?:##\[error\]|Error: )?(?:\./)?([\w./-]+\.go):(\d+)(?::(\d+))?: (.+)$`)
+
+	// linterPattern matches the linter name golangci-lint puts after its messages
+	linterPattern = regexp.MustCompile(`\s\(([a-z][a-z0-9-]*)\)$`)
+
+	// buildHeaderPattern matches the package header go build prints before errors, "# example.com/pkg"
+	buildHeaderPattern = regexp.MustCompile(`^# (\S+)`)
+
+	// panicPattern matches the first line of a panic
+	panicPattern = regexp.MustCompile(`^panic: (.+)$`)
+
+	// framePattern matches the file line of a stack frame, "	/path/file.go:123 +0x1d"
+	framePattern = regexp.MustCompile(`^\s+(\S+\.go):(\d+)(?: \+0x[0-9a-f]+)?$`)
+
+	// exitCodePattern matches the error GitHub Actions reports for a failed command
+	exitCodePattern = regexp.MustCompile(`^Process completed with exit code \d+\.?$`)
+)
+
+// Location is a position in a source file
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column,omitempty"`
+}
+
+// String formats the location as file:line[:column]
+func (l Location) String() string {
+	if l.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+	}
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// Failure is a failure recognized in a log
+type Failure struct {
+	Kind      string     `json:"kind"`
+	Message   string     `json:"message"`
+	Test      string     `json:"test,omitempty"`
+	Package   string     `json:"package,omitempty"`
+	Linter    string     `json:"linter,omitempty"`
+	Locations []Location `json:"locations,omitempty"`
+	Excerpt   string     `json:"excerpt,omitempty"`
+}
+
+// Extract recognizes Go test failures, compile errors, golangci-lint issues, panics
+// and errors reported by GitHub Actions in a cleaned log. A failed command's exit
+// code is reported only when nothing more specific is found.
+func Extract(log string) []Failure {
+	lines := strings.Split(log, "\n")
+	failures := []Failure{}
+	seen := map[string]bool{}
+	add := func(f Failure) bool {
+		key := f.Kind + "\x00" + f.Test + "\x00" + f.Message
+		if len(f.Locations) > 0 {
+			key += "\x00" + f.Locations[0].String()
+		}
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+		failures = append(failures, f)
+		return true
+	}
+
+	var exitCode, pkg string
+	// tests holds the failed tests whose package summary has not been seen yet
+	var tests []int
+	// output holds what go test -v printed for each test before its result
+	output := map[string][]string{}
+	var running string
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if match := testRunPattern.FindStringSubmatch(line); match != nil {
+			running = match[1]
+			continue
+		}
+
+		if match := buildHeaderPattern.FindStringSubmatch(line); match != nil {
+			pkg = match[1]
+			continue
+		}
+
+		if match := testFailPattern.FindStringSubmatch(line); match != nil {
+			f, end := testFailure(lines, i, match[1], output[match[1]])
+			if add(f) {
+				tests = append(tests, len(failures)-1)
+			}
+			running = ""
+			i = end - 1
+			continue
+		}
+
+		if running != "" && testOutputPattern.MatchString(line) {
+			output[running] = append(output[running], line)
+			continue
+		}
+
+		if match := packageFailPattern.FindStringSubmatch(line); match != nil {
+			// The summary line names the package of the tests reported before it
+			for _, j := range tests {
+				failures[j].Package = match[1]
+			}
+			tests, running = nil, ""
+			continue
+		}
+
+		if match := panicPattern.FindStringSubmatch(line); match != nil {
+			f, end := panicFailure(lines, i, match[1])
+			add(f)
+			i = end - 1
+			continue
+		}
+
+		if match := positionPattern.FindStringSubmatch(line); match != nil {
+			add(positionFailure(match, pkg))
+			continue
+		}
+
+		if message, ok := strings.CutPrefix(line, "##[error]"); ok {
+			message = strings.TrimSpace(message)
+			if exitCodePattern.MatchString(message) {
+				exitCode = message
+			} else if message != "" {
+				add(Failure{Kind: KindError, Message: message})
+			}
+		}
+	}
+
+	if len(failures) == 0 && exitCode != "" {
+		failures = append(failures, Failure{Kind: KindError, Message: exitCode})
+	}
+	return dropParentTests(failures)
+}
+
+// testFailure collects the output of the failed test starting at line i, after the
+// output go test -v printed before it, and returns it with the index of the line
+// after it
+func testFailure(lines []string, i int, name string, before []string) (Failure, int) {
+	f := Failure{Kind: KindTest, Test: name}
+	end := i + 1
+	for end < len(lines) && !testEndPattern.MatchString(lines[end]) {
+		end++
+	}
+	output := append(append([]string{}, before...), lines[i+1:end]...)
+
+	var messages []string
+	for _, line := range output {
+		if match := testOutputPattern.FindStringSubmatch(line); match != nil {
+			n, _ := strconv.Atoi(match[2])
+			f.Locations = append(f.Locations, Location{File: match[1], Line: n})
+			messages = append(messages, match[3])
+		}
+	}
+
+	f.Message = "test failed"
+	if len(messages) > 0 {
+		f.Message = messages[0]
+	}
+	excerpt := append([]string{strings.TrimSpace(lines[i])}, output...)
+	f.Excerpt = strings.TrimRight(strings.Join(excerpt, "\n"), "\n")
+	return f, end
+}
+
+// panicFailure reads the panic starting at line i and its stack trace, locating the
+// panic at the first frame outside the Go runtime and testing package
+func panicFailure(lines []string, i int, message string) (Failure, int) {
+	f := Failure{Kind: KindPanic, Message: strings.TrimSuffix(message, " [recovered]")}
+	excerpt := []string{lines[i]}
+	frames := 0
+	end := i + 1
+	for ; end < len(lines); end++ {
+		line := lines[end]
+		if strings.TrimSpace(line) == "" && frames > 0 {
+			break
+		}
+		if testEndPattern.MatchString(line) && !strings.HasPrefix(strings.TrimSpace(line), "panic: ") {
+			break
+		}
+		if frames < maxStackFrames {
+			excerpt = append(excerpt, line)
+		}
+		if match := framePattern.FindStringSubmatch(line); match != nil {
+			frames++
+			if len(f.Locations) == 0 && !isRuntimeFrame(match[1]) {
+				n, _ := strconv.Atoi(match[2])
+				f.Locations = append(f.Locations, Location{File: match[1], Line: n})
+			}
+		}
+	}
+	f.Excerpt = strings.Join(excerpt, "\n")
+	return f, end
+}
+
+// isRuntimeFrame reports whether a stack frame is in the Go runtime or testing package
+func isRuntimeFrame(file string) bool {
+	return strings.Contains(file, "/src/runtime/") || strings.Contains(file, "/src/testing/")
+}
+
+// positionFailure turns a file:line:column message into a lint issue when it names
+// a linter, and a compile error otherwise
+func positionFailure(match []string, pkg string) Failure {
+	line, _ := strconv.Atoi(match[2])
+	column, _ := strconv.Atoi(match[3])
+	f := Failure{Kind: KindCompile, Message: match[4], Package: pkg, Locations: []Location{{File: match[1], Line: line, Column: column}}}
+	if linter := linterPattern.FindStringSubmatch(match[4]); linter != nil {
+		f.Kind, f.Linter, f.Package = KindLint, linter[1], ""
+		f.Message = strings.TrimSuffix(match[4], linter[0])
+	}
+	return f
+}
+
+// dropParentTests removes tests that failed only because a subtest failed and say
+// nothing themselves
+func dropParentTests(failures []Failure) []Failure {
+	kept := make([]Failure, 0, len(failures))
+	for _, f := range failures {
+		if f.Kind == KindTest && len(f.Locations) == 0 && hasSubtest(failures, f.Test) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// hasSubtest reports whether a subtest of test failed
+func hasSubtest(failures []Failure, test string) bool {
+	for _, f := range failures {
+		if f.Kind == KindTest && strings.HasPrefix(f.Test, test+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package cilogs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractGoTestFailures(t *testing.T) {
+	log := `=== RUN   TestUpload
+    upload_test.go:42: Expected 3 retries, got 1
+    upload_test.go:43: Expected error
+--- FAIL: TestUpload (0.01s)
+=== RUN   TestParse
+--- FAIL: TestParse (0.00s)
+    --- FAIL: TestParse/empty (0.00s)
+        parse_test.go:17: Expected error for empty input
+FAIL
+FAIL	example.com/hello/upload	0.123s
+ok  	example.com/hello/parse	0.010s`
+
+	failures := Extract(log)
+	if len(failures) != 2 {
+		t.Fatalf("Expected two failures without the parent test, got %+v", failures)
+	}
+	want := Failure{
+		Kind:      KindTest,
+		Message:   "Expected 3 retries, got 1",
+		Test:      "TestUpload",
+		Package:   "example.com/hello/upload",
+		Locations: []Location{{File: "upload_test.go", Line: 42}, {File: "upload_test.go", Line: 43}},
+		Excerpt:   "--- FAIL: TestUpload (0.01s)\n    upload_test.go:42: Expected 3 retries, got 1\n    upload_test.go:43: Expected error",
+	}
+	if !reflect.DeepEqual(failures[0], want) {
+		t.Errorf("Expected %+v, got %+v", want, failures[0])
+	}
+	if f := failures[1]; f.Test != "TestParse/empty" || f.Locations[0].String() != "parse_test.go:17" || f.Package != "example.com/hello/upload" {
+		t.Errorf("Unexpected subtest failure %+v", f)
+	}
+}
+
+func TestExtractCompileAndLint(t *testing.T) {
+	log := `# example.com/hello/upload
+./upload.go:12:2: undefined: retries
+./upload.go:12:2: undefined: retries
+upload.go:30:9: Error return value of ` + "`f.Close`" + ` is not checked (errcheck)
+##[error]main.go:7:1: exported function Run should have comment (revive)
+##[error]Process completed with exit code 1.`
+
+	failures := Extract(log)
+	if len(failures) != 3 {
+		t.Fatalf("Expected duplicate errors and the exit code to be dropped, got %+v", failures)
+	}
+	if f := failures[0]; f.Kind != KindCompile || f.Package != "example.com/hello/upload" || f.Locations[0] != (Location{"upload.go", 12, 2}) {
+		t.Errorf("Unexpected compile error %+v", f)
+	}
+	if f := failures[1]; f.Kind != KindLint || f.Linter != "errcheck" || strings.HasSuffix(f.Message, ")") || f.Locations[0].Line != 30 {
+		t.Errorf("Unexpected lint issue %+v", f)
+	}
+	if f := failures[2]; f.Kind != KindLint || f.Linter != "revive" || f.Locations[0].File != "main.go" {
+		t.Errorf("Unexpected annotated lint issue %+v", f)
+	}
+}
+
+func TestExtractPanic(t *testing.T) {
+	log := `--- FAIL: TestServe (0.00s)
+panic: runtime error: invalid memory address or nil pointer dereference [recovered]
+	panic: runtime error: invalid memory address or nil pointer dereference
+
+goroutine 7 [running]:
+testing.tRunner.func1.2({0x5d2f40, 0x8a3b10})
+	/opt/hostedtoolcache/go/1.24.5/x64/src/testing/testing.go:1734 +0x21c
+panic({0x5d2f40?, 0x8a3b10?})
+	/opt/hostedtoolcache/go/1.24.5/x64/src/runtime/panic.go:792 +0x132
+example.com/hello/server.(*Server).Serve(0x0)
+	/home/runner/work/hello/hello/server/server.go:88 +0x1d
+example.com/hello/server.TestServe(0xc000102e00)
+	/home/runner/work/hello/hello/server/server_test.go:12 +0x25
+FAIL	example.com/hello/server	0.015s`
+
+	failures := Extract(log)
+	if len(failures) != 2 {
+		t.Fatalf("Expected the test and the panic, got %+v", failures)
+	}
+	f := failures[1]
+	if f.Kind != KindPanic || !strings.HasPrefix(f.Message, "runtime error: invalid memory address") {
+		t.Errorf("Unexpected panic %+v", f)
+	}
+	if len(f.Locations) != 1 || f.Locations[0] != (Location{"/home/runner/work/hello/hello/server/server.go", 88, 0}) {
+		t.Errorf("Expected the first frame outside the runtime, got %+v", f.Locations)
+	}
+	if failures[0].Package != "example.com/hello/server" {
+		t.Errorf("Expected package of the panicking test, got %+v", failures[0])
+	}
+}
+
+func TestExtractFallsBackToExitCode(t *testing.T) {
+	failures := Extract("npm ERR! something\n##[error]Process completed with exit code 2.")
+	if len(failures) != 1 || failures[0].Kind != KindError || failures[0].Message != "Process completed with exit code 2." {
+		t.Errorf("Expected the exit code, got %+v", failures)
+	}
+	if failures := Extract("all good"); len(failures) != 0 {
+		t.Errorf("Expected no failures, got %+v", failures)
+	}
+}
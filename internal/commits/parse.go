@@ -0,0 +1,172 @@
+package commits
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidHeader is returned when a commit header is not of the form
+// <type>[(scope)][!]: <description>
+var ErrInvalidHeader = errors.New("invalid commit header")
+
+var (
+	// headerPattern matches a Conventional Commits header
+	headerPattern = regexp.MustCompile(`^(\w[\w-]*)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+	// footerPattern matches the first line of a footer: "Token: value" or "Token #value"
+	footerPattern = regexp.MustCompile(`^(BREAKING CHANGE|BREAKING-CHANGE|[A-Za-z][A-Za-z-]*)(: | #)(.*)$`)
+
+	// issueReferencePattern matches issue and pull request references such as #42 or owner/repo#42
+	issueReferencePattern = regexp.MustCompile(`(?:[\w.-]+/[\w.-]+)?#(\d+)\b`)
+)
+
+// Commit is a commit message parsed according to the Conventional Commits specification
+type Commit struct {
+	Header      string   `json:"header"`
+	Type        string   `json:"type"`
+	Scope       string   `json:"scope,omitempty"`
+	Breaking    bool     `json:"breaking"`
+	Description string   `json:"description"`
+	Body        string   `json:"body,omitempty"`
+	Footers     []Footer `json:"footers"`
+}
+
+// Footer is a single git trailer style footer such as "Refs: #42"
+type Footer struct {
+	Token string `json:"token"`
+	Value string `json:"value"`
+
+	// separator is ": " or " " as written in the message
+	separator string
+}
+
+// String formats the footer as it appears in a commit message
+func (f Footer) String() string {
+	separator := f.separator
+	if separator == "" {
+		separator = ": "
+		if strings.HasPrefix(f.Value, "#") {
+			separator = " "
+		}
+	}
+	return f.Token + separator + f.Value
+}
+
+// IsBreaking reports whether the footer announces a breaking change
+func (f Footer) IsBreaking() bool {
+	return f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE"
+}
+
+// Parse splits a commit message into its header, body and footers. Comment lines
+// starting with "#" and trailing whitespace are ignored, as git does.
+func Parse(message string) (*Commit, error) {
+	lines := messageLines(message)
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) == "" {
+		return nil, fmt.Errorf("%w: message is empty", ErrInvalidHeader)
+	}
+
+	commit := &Commit{Header: lines[0], Footers: []Footer{}}
+	match := headerPattern.FindStringSubmatch(lines[0])
+	if match == nil {
+		return nil, fmt.Errorf("%w: %q does not match <type>[(scope)][!]: <description>", ErrInvalidHeader, lines[0])
+	}
+	commit.Type = match[1]
+	commit.Scope = match[2]
+	commit.Breaking = match[3] == "!"
+	commit.Description = strings.TrimSpace(match[4])
+	if commit.Description == "" {
+		return nil, fmt.Errorf("%w: description is empty", ErrInvalidHeader)
+	}
+
+	paragraphs := splitParagraphs(lines[1:])
+	if n := len(paragraphs); n > 0 {
+		if footers, ok := parseFooters(paragraphs[n-1]); ok {
+			commit.Footers = footers
+			paragraphs = paragraphs[:n-1]
+		}
+	}
+	commit.Body = strings.Join(paragraphs, "\n\n")
+
+	for _, footer := range commit.Footers {
+		if footer.IsBreaking() {
+			commit.Breaking = true
+		}
+	}
+	return commit, nil
+}
+
+// IssueReferences returns the issue numbers referenced in the commit footers
+func (c *Commit) IssueReferences() []int {
+	var refs []int
+	for _, footer := range c.Footers {
+		for _, match := range issueReferencePattern.FindAllStringSubmatch(footer.Value, -1) {
+			if n, err := strconv.Atoi(match[1]); err == nil && n > 0 {
+				refs = append(refs, n)
+			}
+		}
+	}
+	return refs
+}
+
+// messageLines normalises line endings and drops comment lines and trailing blank lines
+func messageLines(message string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitParagraphs groups lines into blank-line separated paragraphs
+func splitParagraphs(lines []string) []string {
+	var (
+		paragraphs []string
+		current    []string
+	)
+	for _, line := range lines {
+		if line == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, strings.Join(current, "\n"))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, strings.Join(current, "\n"))
+	}
+	return paragraphs
+}
+
+// parseFooters parses a paragraph as footers. Lines that do not start a new footer
+// continue the value of the previous one. It reports false if the paragraph does not
+// start with a footer.
+func parseFooters(paragraph string) ([]Footer, bool) {
+	var footers []Footer
+	for _, line := range strings.Split(paragraph, "\n") {
+		if match := footerPattern.FindStringSubmatch(line); match != nil {
+			footer := Footer{Token: match[1], Value: match[3], separator: match[2]}
+			if match[2] == " #" {
+				footer.Value = "#" + footer.Value
+				footer.separator = " "
+			}
+			footers = append(footers, footer)
+			continue
+		}
+		if len(footers) == 0 {
+			return nil, false
+		}
+		footers[len(footers)-1].Value += "\n" + line
+	}
+	return footers, true
+}
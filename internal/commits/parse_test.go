@@ -0,0 +1,80 @@
+package commits
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	message := `feat(auth)!: add token refresh
+
+Refresh access tokens before they expire so long running
+sessions are not logged out.
+
+Second paragraph of the body.
+
+BREAKING CHANGE: the login response no longer includes
+the refresh token
+Refs: #42, owner/repo#7
+Closes #8
+# Please enter the commit message for your changes.
+`
+
+	commit, err := Parse(message)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if commit.Type != "feat" || commit.Scope != "auth" || !commit.Breaking {
+		t.Errorf("Unexpected header fields: %+v", commit)
+	}
+	if commit.Description != "add token refresh" {
+		t.Errorf("Unexpected description %q", commit.Description)
+	}
+	wantBody := "Refresh access tokens before they expire so long running\nsessions are not logged out.\n\nSecond paragraph of the body."
+	if commit.Body != wantBody {
+		t.Errorf("Unexpected body %q", commit.Body)
+	}
+
+	if len(commit.Footers) != 3 {
+		t.Fatalf("Expected 3 footers, got %+v", commit.Footers)
+	}
+	if !commit.Footers[0].IsBreaking() || commit.Footers[0].Value != "the login response no longer includes\nthe refresh token" {
+		t.Errorf("Unexpected breaking change footer %+v", commit.Footers[0])
+	}
+	if commit.Footers[2].String() != "Closes #8" {
+		t.Errorf("Expected footer to keep its separator, got %q", commit.Footers[2].String())
+	}
+	if refs := commit.IssueReferences(); !slices.Equal(refs, []int{42, 7, 8}) {
+		t.Errorf("Expected issue references [42 7 8], got %v", refs)
+	}
+}
+
+func TestParseBreakingFooterOnly(t *testing.T) {
+	commit, err := Parse("fix: drop legacy flag\n\nBREAKING-CHANGE: --legacy is gone")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if !commit.Breaking || commit.Body != "" {
+		t.Errorf("Expected breaking commit without body, got %+v", commit)
+	}
+}
+
+func TestParseBodyWithoutFooters(t *testing.T) {
+	commit, err := Parse("docs: explain setup\n\nSee the README for details.")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if commit.Body != "See the README for details." || len(commit.Footers) != 0 {
+		t.Errorf("Expected body without footers, got %+v", commit)
+	}
+}
+
+func TestParseInvalidHeader(t *testing.T) {
+	for _, message := range []string{"", "# only a comment", "Added login page", "feat:missing space", "feat: ", "feat(api: unclosed scope"} {
+		if _, err := Parse(message); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("Parse(%q): expected ErrInvalidHeader, got %v", message, err)
+		}
+	}
+}
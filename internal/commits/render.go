@@ -0,0 +1,43 @@
+package commits
+
+import "strings"
+
+// Message holds the parts of a commit message before they are rendered
+type Message struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+	Body        string
+	Footers     []Footer
+}
+
+// Render assembles the message as <type>[(scope)][!]: <description>, a blank line,
+// the body wrapped at bodyWrap columns and the footers as the last paragraph. A
+// bodyWrap of zero leaves the body as is.
+func (m Message) Render(bodyWrap int) string {
+	var sb strings.Builder
+	sb.WriteString(m.Type)
+	if m.Scope != "" {
+		sb.WriteString("(" + m.Scope + ")")
+	}
+	if m.Breaking {
+		sb.WriteString("!")
+	}
+	sb.WriteString(": " + m.Description)
+
+	if body := strings.TrimSpace(m.Body); body != "" {
+		if bodyWrap > 0 {
+			body = wrapText(body, bodyWrap)
+		}
+		sb.WriteString("\n\n" + body)
+	}
+
+	if len(m.Footers) > 0 {
+		sb.WriteString("\n")
+		for _, footer := range m.Footers {
+			sb.WriteString("\n" + footer.String())
+		}
+	}
+	return sb.String()
+}
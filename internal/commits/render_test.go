@@ -0,0 +1,50 @@
+package commits
+
+import (
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		message Message
+		want    string
+	}{
+		{
+			name:    "header only",
+			message: Message{Type: "fix", Description: "handle empty input"},
+			want:    "fix: handle empty input",
+		},
+		{
+			name:    "scope and breaking",
+			message: Message{Type: "feat", Scope: "api", Breaking: true, Description: "drop v1 endpoints"},
+			want:    "feat(api)!: drop v1 endpoints",
+		},
+		{
+			name: "body and footers",
+			message: Message{
+				Type:        "feat",
+				Description: "add login",
+				Body:        "Users can sign in with their GitHub account instead of creating a separate password.",
+				Footers:     []Footer{{Token: "Refs", Value: "#42"}, {Token: "Reviewed-by", Value: "Alice"}},
+			},
+			want: "feat: add login\n\n" +
+				"Users can sign in with their GitHub account instead of creating a\nseparate password.\n\n" +
+				"Refs #42\nReviewed-by: Alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.message.Render(72)
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+			if result := Validate(got, policy.Default(), Options{}); !result.Valid {
+				t.Errorf("Expected rendered message to be valid, got %+v", result.Violations)
+			}
+		})
+	}
+}
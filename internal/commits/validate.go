@@ -0,0 +1,290 @@
+package commits
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// Severity describes whether a violation makes a commit message invalid
+type Severity string
+
+const (
+	// SeverityError violations make the message invalid
+	SeverityError Severity = "error"
+	// SeverityWarning violations come from heuristics and do not invalidate the message
+	SeverityWarning Severity = "warning"
+)
+
+// Rules checked by Validate
+const (
+	RuleHeaderFormat      = "header-format"
+	RuleTypeCase          = "type-case"
+	RuleTypeEnum          = "type-enum"
+	RuleScopeEnum         = "scope-enum"
+	RuleSubjectMaxLength  = "subject-max-length"
+	RuleSubjectFullStop   = "subject-full-stop"
+	RuleSubjectImperative = "subject-imperative"
+	RuleBodyLeadingBlank  = "body-leading-blank"
+	RuleBodyMaxLineLength = "body-max-line-length"
+	RuleIssueReference    = "issue-reference"
+)
+
+// typeAliases maps commonly mistyped commit types to their conventional name
+var typeAliases = map[string]string{
+	"feature":       "feat",
+	"features":      "feat",
+	"bug":           "fix",
+	"bugfix":        "fix",
+	"hotfix":        "fix",
+	"doc":           "docs",
+	"documentation": "docs",
+	"tests":         "test",
+	"testing":       "test",
+	"refactoring":   "refactor",
+	"performance":   "perf",
+	"chores":        "chore",
+	"deps":          "chore",
+}
+
+// Violation is a single rule a commit message breaks, with a suggested fix
+type Violation struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+
+	// Line is the 1-based line of the message, ignoring comment lines, or 0 when
+	// the violation concerns the message as a whole
+	Line       int    `json:"line"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Options configures the checks that depend on the work being committed
+type Options struct {
+	// IssueNumber, if set, must be referenced in a footer such as "Refs #42"
+	IssueNumber int
+}
+
+// Result is the outcome of validating a commit message
+type Result struct {
+	Valid            bool        `json:"valid"`
+	Commit           *Commit     `json:"commit,omitempty"`
+	Violations       []Violation `json:"violations"`
+	SuggestedMessage string      `json:"suggested_message,omitempty"`
+}
+
+// Validate parses message and checks it against the commit rules of the policy.
+// The message is valid if it has no error violations. When a violation can be
+// fixed mechanically, the suggested message has the fix applied.
+func Validate(message string, pol *policy.Policy, opts Options) Result {
+	v := &validator{policy: pol, opts: opts, lines: messageLines(message)}
+	v.run()
+
+	result := Result{
+		Valid:      true,
+		Commit:     v.commit,
+		Violations: v.violations,
+	}
+	for _, violation := range v.violations {
+		if violation.Severity == SeverityError {
+			result.Valid = false
+		}
+	}
+	if len(v.violations) > 0 && v.commit != nil {
+		if suggested := v.suggest(); suggested != strings.Join(v.lines, "\n") {
+			result.SuggestedMessage = suggested
+		}
+	}
+	return result
+}
+
+// validator accumulates the violations of a single commit message
+type validator struct {
+	policy     *policy.Policy
+	opts       Options
+	lines      []string
+	commit     *Commit
+	violations []Violation
+}
+
+// add records a violation
+func (v *validator) add(rule string, severity Severity, line int, suggestion, format string, args ...any) {
+	v.violations = append(v.violations, Violation{
+		Rule:       rule,
+		Severity:   severity,
+		Line:       line,
+		Message:    fmt.Sprintf(format, args...),
+		Suggestion: suggestion,
+	})
+}
+
+// run checks the header, then the body layout, then the footers
+func (v *validator) run() {
+	v.violations = []Violation{}
+
+	commit, err := Parse(strings.Join(v.lines, "\n"))
+	if err != nil {
+		v.add(RuleHeaderFormat, SeverityError, 1,
+			fmt.Sprintf("Use the form <type>[(scope)]: <description> with a type from: %s", strings.Join(v.policy.CommitTypes(), ", ")),
+			"%v", err)
+	} else {
+		v.commit = commit
+		v.checkHeader()
+	}
+
+	v.checkBody()
+
+	if commit != nil && v.opts.IssueNumber > 0 && !slices.Contains(commit.IssueReferences(), v.opts.IssueNumber) {
+		v.add(RuleIssueReference, SeverityError, 0,
+			fmt.Sprintf("Add a footer: Refs #%d", v.opts.IssueNumber),
+			"Message does not reference issue #%d in a footer", v.opts.IssueNumber)
+	}
+}
+
+// checkHeader checks the type, scope and subject line
+func (v *validator) checkHeader() {
+	c := v.commit
+
+	if lower := strings.ToLower(c.Type); lower != c.Type {
+		v.add(RuleTypeCase, SeverityError, 1, fmt.Sprintf("Use %q", lower), "Type %q must be lowercase", c.Type)
+	}
+	if typ := strings.ToLower(c.Type); !slices.Contains(v.policy.CommitTypes(), typ) {
+		suggestion := fmt.Sprintf("Use one of: %s", strings.Join(v.policy.CommitTypes(), ", "))
+		if alias, ok := v.typeAlias(typ); ok {
+			suggestion = fmt.Sprintf("Use %q", alias)
+		}
+		v.add(RuleTypeEnum, SeverityError, 1, suggestion, "Type %q is not an allowed commit type", c.Type)
+	}
+	if c.Scope != "" && !v.policy.AllowsScope(c.Scope) {
+		v.add(RuleScopeEnum, SeverityError, 1,
+			fmt.Sprintf("Use one of: %s", strings.Join(v.policy.Commits.Scopes, ", ")),
+			"Scope %q is not an allowed scope", c.Scope)
+	}
+
+	if length, max := utf8.RuneCountInString(c.Header), v.policy.Commits.SubjectMaxLength; length > max {
+		v.add(RuleSubjectMaxLength, SeverityError, 1,
+			fmt.Sprintf("Shorten the description by %d characters and move details into the body", length-max),
+			"Subject line is %d characters, the limit is %d", length, max)
+	}
+	if strings.HasSuffix(c.Description, ".") {
+		v.add(RuleSubjectFullStop, SeverityError, 1, "Remove the trailing period", "Subject line must not end with a period")
+	}
+
+	word, _, _ := strings.Cut(c.Description, " ")
+	if verb, ok := imperativeOf(word); ok {
+		v.add(RuleSubjectImperative, SeverityWarning, 1,
+			fmt.Sprintf("Use %q instead of %q", verb, word),
+			"Subject should use the imperative mood (%q, not %q)", verb, word)
+	}
+}
+
+// checkBody checks that the body is separated from the subject and wrapped
+func (v *validator) checkBody() {
+	if len(v.lines) > 1 && v.lines[1] != "" {
+		v.add(RuleBodyLeadingBlank, SeverityError, 2, "Insert a blank line after the subject line",
+			"Subject line must be separated from the body by a blank line")
+	}
+
+	wrap := v.policy.Commits.BodyWrap
+	for i := 1; i < len(v.lines); i++ {
+		line := v.lines[i]
+		if length := utf8.RuneCountInString(line); length > wrap && !unbreakable(line) {
+			v.add(RuleBodyMaxLineLength, SeverityError, i+1,
+				fmt.Sprintf("Wrap the line at %d characters", wrap),
+				"Line %d is %d characters, wrap the body at %d", i+1, length, wrap)
+		}
+	}
+}
+
+// typeAlias returns the allowed commit type a mistyped type most likely means
+func (v *validator) typeAlias(typ string) (string, bool) {
+	alias, ok := typeAliases[typ]
+	if ok && slices.Contains(v.policy.CommitTypes(), alias) {
+		return alias, true
+	}
+	for _, allowed := range v.policy.CommitTypes() {
+		if strings.HasPrefix(typ, allowed) || strings.HasPrefix(allowed, typ) {
+			return allowed, true
+		}
+	}
+	return "", false
+}
+
+// suggest rebuilds the message with every mechanical fix applied
+func (v *validator) suggest() string {
+	c := v.commit
+
+	typ := strings.ToLower(c.Type)
+	if alias, ok := v.typeAlias(typ); ok && !slices.Contains(v.policy.CommitTypes(), typ) {
+		typ = alias
+	}
+
+	description := strings.TrimRight(c.Description, ".")
+	if word, rest, _ := strings.Cut(description, " "); word != "" {
+		if verb, ok := imperativeOf(word); ok {
+			description = strings.TrimSpace(matchCase(verb, word) + " " + rest)
+		}
+	}
+
+	footers := slices.Clone(c.Footers)
+	if v.opts.IssueNumber > 0 && !slices.Contains(c.IssueReferences(), v.opts.IssueNumber) {
+		footers = append(footers, Footer{Token: "Refs", Value: fmt.Sprintf("#%d", v.opts.IssueNumber)})
+	}
+
+	return Message{
+		Type:        typ,
+		Scope:       c.Scope,
+		Breaking:    strings.Contains(c.Header, "!:"),
+		Description: description,
+		Body:        c.Body,
+		Footers:     footers,
+	}.Render(v.policy.Commits.BodyWrap)
+}
+
+// wrapText wraps each line longer than width at word boundaries. Lines that are
+// already short enough are kept as written, and list items keep their indentation.
+func wrapText(text string, width int) string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		if utf8.RuneCountInString(line) <= width || unbreakable(line) {
+			out = append(out, line)
+			continue
+		}
+
+		indent := ""
+		if trimmed := strings.TrimLeft(line, " "); strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+			indent = strings.Repeat(" ", len(line)-len(trimmed)+2)
+		}
+
+		current := ""
+		for _, word := range strings.Fields(line) {
+			switch {
+			case current == "":
+				current = strings.Repeat(" ", len(line)-len(strings.TrimLeft(line, " "))) + word
+			case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > width:
+				out = append(out, current)
+				current = indent + word
+			default:
+				current += " " + word
+			}
+		}
+		out = append(out, current)
+	}
+	return strings.Join(out, "\n")
+}
+
+// unbreakable reports whether a line is a single token, such as a URL, that cannot be wrapped
+func unbreakable(line string) bool {
+	return !strings.Contains(strings.TrimSpace(line), " ")
+}
+
+// matchCase returns word with the capitalisation of the first letter of like
+func matchCase(word, like string) string {
+	if like != "" && like[0] >= 'A' && like[0] <= 'Z' {
+		return strings.ToUpper(word[:1]) + word[1:]
+	}
+	return word
+}
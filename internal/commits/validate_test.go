@@ -0,0 +1,141 @@
+package commits
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// rules returns the rules of the violations in order
+func rules(violations []Violation) []string {
+	var names []string
+	for _, v := range violations {
+		names = append(names, v.Rule)
+	}
+	return names
+}
+
+func TestValidateValidMessage(t *testing.T) {
+	message := "feat(auth): add token refresh\n\nRefresh tokens before they expire.\n\nRefs #42"
+
+	result := Validate(message, policy.Default(), Options{IssueNumber: 42})
+	if !result.Valid || len(result.Violations) != 0 {
+		t.Errorf("Expected valid message, got %+v", result.Violations)
+	}
+	if result.Commit == nil || result.Commit.Scope != "auth" {
+		t.Errorf("Expected parsed commit, got %+v", result.Commit)
+	}
+	if result.SuggestedMessage != "" {
+		t.Errorf("Expected no suggestion for a valid message, got %q", result.SuggestedMessage)
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		opts    Options
+		rule    string
+		line    int
+	}{
+		{"header format", "Added the login page", Options{}, RuleHeaderFormat, 1},
+		{"type case", "Fix: handle nil user", Options{}, RuleTypeCase, 1},
+		{"type enum", "feature: add login", Options{}, RuleTypeEnum, 1},
+		{"subject length", "feat: add a very long description that goes on and on and on", Options{}, RuleSubjectMaxLength, 1},
+		{"full stop", "fix: handle nil user.", Options{}, RuleSubjectFullStop, 1},
+		{"imperative", "fix: fixed nil user", Options{}, RuleSubjectImperative, 1},
+		{"leading blank", "fix: handle nil user\nThe user can be nil.", Options{}, RuleBodyLeadingBlank, 2},
+		{"line length", "fix: handle nil user\n\n" + strings.Repeat("word ", 20), Options{}, RuleBodyMaxLineLength, 3},
+		{"issue reference", "fix: handle nil user", Options{IssueNumber: 9}, RuleIssueReference, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.message, policy.Default(), tt.opts)
+			for _, v := range result.Violations {
+				if v.Rule != tt.rule {
+					continue
+				}
+				if v.Line != tt.line {
+					t.Errorf("Expected line %d, got %d", tt.line, v.Line)
+				}
+				if v.Suggestion == "" {
+					t.Error("Expected a suggested fix")
+				}
+				if v.Severity == SeverityError && result.Valid {
+					t.Error("Expected message with an error to be invalid")
+				}
+				return
+			}
+			t.Errorf("Expected %s violation, got %v", tt.rule, rules(result.Violations))
+		})
+	}
+}
+
+func TestValidateScopeFromPolicy(t *testing.T) {
+	p := policy.Default()
+	p.Commits.Scopes = []string{"api"}
+
+	if result := Validate("fix(ui): align buttons", p, Options{}); result.Valid {
+		t.Error("Expected scope outside the policy to be rejected")
+	}
+	if result := Validate("fix(api): handle timeouts", p, Options{}); !result.Valid {
+		t.Errorf("Expected allowed scope to be accepted, got %v", rules(result.Violations))
+	}
+}
+
+func TestValidateImperativeIsWarning(t *testing.T) {
+	result := Validate("feat: adds login", policy.Default(), Options{})
+	if !result.Valid {
+		t.Errorf("Expected imperative mood heuristic not to invalidate the message, got %v", rules(result.Violations))
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Severity != SeverityWarning {
+		t.Errorf("Expected a single warning, got %+v", result.Violations)
+	}
+}
+
+func TestValidateSuggestedMessage(t *testing.T) {
+	message := "Feature(api)!: Updated the retry logic.\n" +
+		"The client now retries requests that fail with a 503 status using exponential backoff with jitter.\n\n" +
+		"Fixes: #12"
+
+	result := Validate(message, policy.Default(), Options{IssueNumber: 40})
+
+	want := "feat(api)!: Update the retry logic\n\n" +
+		"The client now retries requests that fail with a 503 status using\n" +
+		"exponential backoff with jitter.\n\n" +
+		"Fixes: #12\n" +
+		"Refs #40"
+	if result.SuggestedMessage != want {
+		t.Errorf("Unexpected suggested message:\n%s\nwant:\n%s", result.SuggestedMessage, want)
+	}
+
+	again := Validate(result.SuggestedMessage, policy.Default(), Options{IssueNumber: 40})
+	if !again.Valid {
+		t.Errorf("Expected suggested message to be valid, got %v", rules(again.Violations))
+	}
+}
+
+func TestImperativeOf(t *testing.T) {
+	tests := map[string]string{
+		"added":    "add",
+		"Fixes":    "fix",
+		"updating": "update",
+		"dropped":  "drop",
+		"stopping": "stop",
+		"applied":  "apply",
+		"wrote":    "write",
+	}
+	for word, want := range tests {
+		if got, ok := imperativeOf(word); !ok || got != want {
+			t.Errorf("imperativeOf(%q) = %q, %v; want %q", word, got, ok, want)
+		}
+	}
+
+	for _, word := range []string{"add", "address", "process", "bring"} {
+		if got, ok := imperativeOf(word); ok {
+			t.Errorf("Expected %q to be accepted, got %q", word, got)
+		}
+	}
+}
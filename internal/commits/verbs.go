@@ -0,0 +1,87 @@
+package commits
+
+import "strings"
+
+// imperativeVerbs are the verbs commonly used to start commit subjects. Their past
+// tense, gerund and third person forms are recognised as non-imperative.
+var imperativeVerbs = []string{
+	"add", "adjust", "allow", "apply", "avoid", "build", "bump", "change", "check",
+	"clean", "clarify", "configure", "convert", "correct", "create", "debug", "delete",
+	"deprecate", "disable", "document", "drop", "enable", "ensure", "expose", "extend",
+	"extract", "fix", "format", "handle", "hide", "implement", "improve", "include",
+	"increase", "initialize", "inline", "install", "integrate", "introduce", "limit",
+	"load", "log", "merge", "migrate", "move", "optimize", "parse", "polish", "prepare",
+	"prevent", "reduce", "refactor", "release", "remove", "rename", "reorder",
+	"replace", "resolve", "restore", "restructure", "return", "revert", "rewrite",
+	"run", "simplify", "skip", "sort", "split", "stop", "support", "switch", "test",
+	"tidy", "track", "tweak", "update", "upgrade", "use", "validate", "verify", "wrap",
+	"write",
+}
+
+// irregularVerbs maps irregular non-imperative forms to their imperative
+var irregularVerbs = map[string]string{
+	"built":     "build",
+	"ran":       "run",
+	"rewrote":   "rewrite",
+	"rewritten": "rewrite",
+	"wrote":     "write",
+	"written":   "write",
+}
+
+// nonImperative maps inflected verb forms to their imperative
+var nonImperative = buildInflections()
+
+// buildInflections generates the past tense, gerund and third person forms of the
+// imperative verbs
+func buildInflections() map[string]string {
+	forms := make(map[string]string, len(imperativeVerbs)*4)
+	for _, verb := range imperativeVerbs {
+		for _, form := range inflect(verb) {
+			if form != verb {
+				forms[form] = verb
+			}
+		}
+	}
+	for form, verb := range irregularVerbs {
+		if form != verb {
+			forms[form] = verb
+		}
+	}
+	return forms
+}
+
+// inflect returns the regular inflections of a verb, including the doubled final
+// consonant spellings such as "dropped" and "stopping"
+func inflect(verb string) []string {
+	last := verb[len(verb)-1]
+	stem := verb[:len(verb)-1]
+
+	var forms []string
+	switch {
+	case strings.HasSuffix(verb, "e"):
+		forms = append(forms, verb+"d", stem+"ing", verb+"s")
+	case last == 'y' && !isVowel(verb[len(verb)-2]):
+		forms = append(forms, stem+"ied", verb+"ing", stem+"ies")
+	case strings.HasSuffix(verb, "s") || strings.HasSuffix(verb, "x") || strings.HasSuffix(verb, "z") ||
+		strings.HasSuffix(verb, "ch") || strings.HasSuffix(verb, "sh"):
+		forms = append(forms, verb+"ed", verb+"ing", verb+"es")
+	default:
+		forms = append(forms, verb+"ed", verb+"ing", verb+"s")
+		if len(verb) >= 3 && !isVowel(last) && last != 'w' && last != 'y' && isVowel(verb[len(verb)-2]) && !isVowel(verb[len(verb)-3]) {
+			forms = append(forms, verb+string(last)+"ed", verb+string(last)+"ing")
+		}
+	}
+	return forms
+}
+
+// isVowel reports whether b is a lowercase vowel
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) >= 0
+}
+
+// imperativeOf returns the imperative form of word if word is a recognised
+// non-imperative verb form
+func imperativeOf(word string) (string, bool) {
+	verb, ok := nonImperative[strings.ToLower(word)]
+	return verb, ok
+}
@@ -0,0 +1,203 @@
+// Package config holds the server configuration, layered from built-in defaults, a
+// YAML configuration file, environment variables and command-line flags, each
+// overriding the one before
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// Version is the version the server reports. Release builds set it with
+// -ldflags "-X github.com/shibbirmcc/github-issue-developer-mcp-server/internal/config.Version=v1.2.3".
+var Version = "1.0.0"
+
+// Defaults of the settings that have one
+const (
+	DefaultName              = "github-issue-developer"
+	DefaultStreamablePath    = "/mcp"
+	DefaultSSEPath           = "/"
+	DefaultReadHeaderTimeout = 10 * time.Second
+	DefaultIdleTimeout       = 2 * time.Minute
+	DefaultShutdownTimeout   = 30 * time.Second
+	DefaultGitHubTokenHeader = "X-GitHub-Token"
+	DefaultPollInterval      = 2 * time.Second
+)
+
+// ErrInvalidConfig is returned for configuration that cannot be used
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// Config is the configuration of the server. Every field is a setting with a key in
+// the configuration file, an environment variable and, except for secrets, a flag.
+type Config struct {
+	Server    Server    `yaml:"server"`
+	Transport Transport `yaml:"transport"`
+	TLS       TLS       `yaml:"tls"`
+	Auth      Auth      `yaml:"auth"`
+	GitHub    GitHub    `yaml:"github"`
+	Git       Git       `yaml:"git"`
+	Prompts   Prompts   `yaml:"prompts"`
+	Policy    Policy    `yaml:"policy"`
+	State     State     `yaml:"state"`
+	Audit     Audit     `yaml:"audit"`
+
+	// file is the configuration file that was loaded, if any, and sources records
+	// where each key that is not a default was set
+	file    string
+	sources map[string]string
+}
+
+// Server identifies the server to clients and bounds its shutdown
+type Server struct {
+	Name            string        `yaml:"name"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// Transport selects stdio or the HTTP transports and configures the HTTP server
+type Transport struct {
+	// Mode is stdio, http, streamable or sse. Without it, it is http when Address
+	// is set and stdio otherwise.
+	Mode              string        `yaml:"mode"`
+	Address           string        `yaml:"address"`
+	StreamablePath    string        `yaml:"streamable_path"`
+	SSEPath           string        `yaml:"sse_path"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+}
+
+// TLS turns on HTTPS and, with a client CA, mutual TLS
+type TLS struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	ClientCAFile string `yaml:"client_ca_file"`
+}
+
+// Auth holds the credentials HTTP clients authenticate with and the resource
+// metadata advertised to them
+type Auth struct {
+	TokensFile           string   `yaml:"tokens_file"`
+	JWKSFile             string   `yaml:"jwks_file"`
+	Issuer               string   `yaml:"issuer"`
+	Audience             string   `yaml:"audience"`
+	Resource             string   `yaml:"resource"`
+	AuthorizationServers []string `yaml:"authorization_servers"`
+}
+
+// GitHub configures the GitHub API and whose credentials sessions act with
+type GitHub struct {
+	Token         string        `yaml:"token"`
+	APIURL        string        `yaml:"api_url"`
+	Credentials   string        `yaml:"credentials"`
+	TokenHeader   string        `yaml:"token_header"`
+	TokenExchange TokenExchange `yaml:"token_exchange"`
+}
+
+// TokenExchange is the endpoint GitHub tokens are exchanged at
+type TokenExchange struct {
+	URL          string `yaml:"url"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	Audience     string `yaml:"audience"`
+}
+
+// Git limits where the git tools work
+type Git struct {
+	// Workspaces are the directories client roots must lie within. The git tools are
+	// disabled on the HTTP transports without any.
+	Workspaces []string `yaml:"workspaces"`
+}
+
+// Prompts configures the prompt library
+type Prompts struct {
+	Dir            string        `yaml:"dir"`
+	RepositoryRoot string        `yaml:"repository_root"`
+	PollInterval   time.Duration `yaml:"poll_interval"`
+}
+
+// Policy configures the conventions policy
+type Policy struct {
+	File string `yaml:"file"`
+}
+
+// State configures where work is saved across restarts
+type State struct {
+	File string `yaml:"file"`
+}
+
+// Audit configures the audit log
+type Audit struct {
+	Log string `yaml:"log"`
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		Server: Server{Name: DefaultName, ShutdownTimeout: DefaultShutdownTimeout},
+		Transport: Transport{
+			StreamablePath:    DefaultStreamablePath,
+			SSEPath:           DefaultSSEPath,
+			ReadHeaderTimeout: DefaultReadHeaderTimeout,
+			IdleTimeout:       DefaultIdleTimeout,
+		},
+		GitHub:  GitHub{TokenHeader: DefaultGitHubTokenHeader},
+		Prompts: Prompts{PollInterval: DefaultPollInterval},
+	}
+}
+
+// Source returns where the setting named key was set: "default", "file PATH:LINE",
+// "env NAME" or "flag --NAME"
+func (c *Config) Source(key string) string {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	return "default"
+}
+
+// Describe names the setting key in errors, with where it was set unless it is a default
+func (c *Config) Describe(key string) string {
+	if source, ok := c.sources[key]; ok {
+		return fmt.Sprintf("%s (%s)", key, source)
+	}
+	return key
+}
+
+// Validate checks the values that are wrong whatever else is configured. The
+// combinations of settings and the files they name are checked by the server.
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s: %s", ErrInvalidConfig, c.Describe(key), fmt.Sprintf(format, args...)))
+	}
+
+	if c.Server.Name == "" {
+		invalid("server.name", "must not be empty")
+	}
+	for _, s := range settings {
+		d, ok := s.field(c).(*time.Duration)
+		if !ok {
+			continue
+		}
+		switch {
+		case *d < 0:
+			invalid(s.key, "must not be negative, got %s", *d)
+		case *d == 0 && s.positive:
+			invalid(s.key, "must be positive")
+		}
+	}
+	for i, server := range c.Auth.AuthorizationServers {
+		if server == "" {
+			invalid(fmt.Sprintf("auth.authorization_servers[%d]", i), "must not be empty")
+		}
+	}
+	for i, dir := range c.Git.Workspaces {
+		if !filepath.IsAbs(dir) {
+			invalid(fmt.Sprintf("git.workspaces[%d]", i), "must be an absolute path, got %q", dir)
+		}
+	}
+
+	return errors.Join(errs...)
+}
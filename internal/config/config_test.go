@@ -0,0 +1,230 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile writes content to name in a temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// env returns a LookupEnv reading from vars
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+// parseFlags registers the configuration flags and parses args
+func parseFlags(t *testing.T, args ...string) *Flags {
+	t.Helper()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	return flags
+}
+
+func TestLoadLayersFileEnvAndFlags(t *testing.T) {
+	path := writeFile(t, "config.yml", `server:
+  name: team-developer
+transport:
+  mode: http
+  address: ":8080"
+  idle_timeout: 1m
+auth:
+  authorization_servers:
+    - https://idp.example.com
+github:
+  token_exchange:
+    url: https://idp.example.com/token
+prompts:
+  poll_interval: 5s
+`)
+	cfg, err := Load(Options{
+		Flags:     parseFlags(t, "--config", path, "--transport.address", ":9090"),
+		LookupEnv: env(map[string]string{"MCP_HTTP_ADDR": ":8443", "MCP_HTTP_IDLE_TIMEOUT": "90s", "MCP_TRANSPORT": "", "GH_TOKEN": "gh-token"}),
+	})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.Server.Name != "team-developer" || cfg.Transport.Mode != "http" || cfg.Prompts.PollInterval != 5*time.Second {
+		t.Errorf("Expected settings from the file, got %+v", cfg)
+	}
+	if cfg.Transport.IdleTimeout != 90*time.Second || cfg.GitHub.Token != "gh-token" {
+		t.Errorf("Expected the environment to override the file, got %s, %q", cfg.Transport.IdleTimeout, cfg.GitHub.Token)
+	}
+	if cfg.Transport.Address != ":9090" {
+		t.Errorf("Expected flags to override the environment, got %q", cfg.Transport.Address)
+	}
+	if cfg.Transport.StreamablePath != DefaultStreamablePath || cfg.Server.ShutdownTimeout != DefaultShutdownTimeout {
+		t.Errorf("Expected defaults for unset settings, got %q, %s", cfg.Transport.StreamablePath, cfg.Server.ShutdownTimeout)
+	}
+	if !slices.Equal(cfg.Auth.AuthorizationServers, []string{"https://idp.example.com"}) {
+		t.Errorf("Expected a list from the file, got %v", cfg.Auth.AuthorizationServers)
+	}
+
+	for key, want := range map[string]string{
+		"server.name":                     "file " + path + ":2",
+		"github.token_exchange.url":       "file " + path + ":12",
+		"transport.mode":                  "file " + path + ":4",
+		"transport.idle_timeout":          "env MCP_HTTP_IDLE_TIMEOUT",
+		"github.token":                    "env GH_TOKEN",
+		"transport.address":               "flag --transport.address",
+		"transport.streamable_path":       "default",
+		"github.token_exchange.client_id": "default",
+	} {
+		if got := cfg.Source(key); got != want {
+			t.Errorf("Source(%s) = %q, expected %q", key, got, want)
+		}
+	}
+	if cfg.File() != path {
+		t.Errorf("Expected the file to be recorded, got %q", cfg.File())
+	}
+}
+
+func TestLoadNamesOffendingKeys(t *testing.T) {
+	path := writeFile(t, "config.yml", `transport:
+  adress: ":8080"
+  mode: [http]
+  read_timeout: -1s
+github:
+  token_exchange: https://idp.example.com/token
+prompts:
+  poll_interval: 0s
+`)
+	vars := map[string]string{
+		"MCP_HTTP_IDLE_TIMEOUT": "2 minutes",
+		"MCP_AUTH_SERVERS":      "https://a.example.com,,https://b.example.com",
+		"MCP_GIT_WORKSPACES":    "/srv/work,checkouts",
+	}
+	_, err := Load(Options{
+		Flags:     parseFlags(t, "--config", path, "--server.shutdown-timeout", "soon"),
+		LookupEnv: env(vars),
+	})
+	if err == nil {
+		t.Fatal("Expected the configuration to be rejected")
+	}
+	for _, want := range []string{
+		"transport.adress (file " + path + ":2): unknown key",
+		"transport.mode (file " + path + ":3): must be a single value",
+		"transport.read_timeout (file " + path + ":4): must not be negative, got -1s",
+		"github.token_exchange (file " + path + ":6): must be a mapping of keys",
+		"prompts.poll_interval (file " + path + ":8): must be positive",
+		`transport.idle_timeout (env MCP_HTTP_IDLE_TIMEOUT): must be a duration such as 30s or 2m, got "2 minutes"`,
+		`server.shutdown_timeout (flag --server.shutdown-timeout): must be a duration such as 30s or 2m, got "soon"`,
+		`git.workspaces[1]: must be an absolute path, got "checkouts"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in:\n%v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "authorization_servers") {
+		t.Errorf("Expected empty list entries from the environment to be skipped, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+
+	if _, err := Load(Options{LookupEnv: env(map[string]string{FileEnv: filepath.Join(t.TempDir(), "missing.yml")})}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected a missing configuration file to be rejected, got %v", err)
+	}
+	if _, err := Load(Options{Flags: parseFlags(t, "--config", writeFile(t, "config.yml", "transport: [\n"))}); err == nil {
+		t.Error("Expected malformed YAML to be rejected")
+	}
+}
+
+func TestLoadSkipsMissingDefaultFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := Load(Options{})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.File() != "" || cfg.Server.Name != DefaultName {
+		t.Errorf("Expected the defaults without a configuration file, got %q, %+v", cfg.File(), cfg)
+	}
+}
+
+func TestWriteRedactsSecretsAndRoundTrips(t *testing.T) {
+	cfg, err := Load(Options{
+		Flags: parseFlags(t, "--transport.address", ":8080", "--auth.authorization-servers", "https://a.example.com,https://b.example.com"),
+		LookupEnv: env(map[string]string{
+			"GITHUB_TOKEN": "ghp_secret",
+			"MCP_GITHUB_TOKEN_EXCHANGE_CLIENT_SECRET": "client-secret",
+			"MCP_SHUTDOWN_TIMEOUT":                    "1m",
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := cfg.Write(&out, true); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	printed := out.String()
+	if strings.Contains(printed, "ghp_secret") || strings.Contains(printed, "client-secret") {
+		t.Errorf("Expected secrets to be redacted:\n%s", printed)
+	}
+	for _, want := range []string{
+		"token: " + redacted + " # from env GITHUB_TOKEN",
+		"address: :8080 # from flag --transport.address",
+		"shutdown_timeout: 1m0s # from env MCP_SHUTDOWN_TIMEOUT",
+		"streamable_path: /mcp\n",
+	} {
+		if !strings.Contains(printed, want) {
+			t.Errorf("Expected %q in:\n%s", want, printed)
+		}
+	}
+
+	out.Reset()
+	cfg.GitHub.Token, cfg.GitHub.TokenExchange.ClientSecret = "", ""
+	if err := cfg.Write(&out, false); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if strings.Contains(out.String(), "#") {
+		t.Errorf("Expected no sources:\n%s", out.String())
+	}
+	reloaded, err := Load(Options{Flags: parseFlags(t, "--config", writeFile(t, "config.yml", out.String()))})
+	if err != nil {
+		t.Fatalf("Load returned error for the printed configuration: %v", err)
+	}
+	if reloaded.Server != cfg.Server || reloaded.Transport != cfg.Transport || reloaded.GitHub != cfg.GitHub ||
+		!slices.Equal(reloaded.Auth.AuthorizationServers, cfg.Auth.AuthorizationServers) {
+		t.Errorf("Expected the printed configuration to load back unchanged, got %+v", reloaded)
+	}
+}
+
+func TestSecretsHaveNoFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+	for _, s := range settings {
+		if got := fs.Lookup(s.flag) != nil && s.flag != ""; got == s.secret {
+			t.Errorf("Setting %s: secret %v but flag %q", s.key, s.secret, s.flag)
+		}
+	}
+	if fs.Lookup("github.token") != nil || fs.Lookup("transport.sse-path") == nil {
+		t.Error("Expected flags for every setting but secrets")
+	}
+}
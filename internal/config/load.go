@@ -0,0 +1,235 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the configuration file when the --config flag is not given
+const FileEnv = "MCP_CONFIG_FILE"
+
+// Flags are the command-line flags of the configuration: --config and one flag per
+// setting, named after its key, e.g. --transport.address
+type Flags struct {
+	file   string
+	values map[string]string
+}
+
+// RegisterFlags defines the configuration flags on fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{values: map[string]string{}}
+	fs.StringVar(&f.file, "config", "", "configuration file (default $"+FileEnv+" or config.yml in the user configuration directory)")
+	for _, s := range settings {
+		if s.flag == "" {
+			continue
+		}
+		key := s.key
+		fs.Func(s.flag, s.usage, func(value string) error {
+			f.values[key] = value
+			return nil
+		})
+	}
+	return f
+}
+
+// Options are where Load reads the layers above the defaults from
+type Options struct {
+	// Flags are the parsed command-line flags, if any
+	Flags *Flags
+
+	// LookupEnv reads environment variables, usually os.LookupEnv; nil reads none
+	LookupEnv func(string) (string, bool)
+}
+
+// Load returns the defaults overlaid with the configuration file, the environment
+// and the flags, in that order, and validates the result. The configuration file is
+// the --config flag, MCP_CONFIG_FILE or, if it exists, DefaultFile. Errors name the
+// offending key and where it was set.
+func Load(opts Options) (*Config, error) {
+	c := Default()
+	c.sources = map[string]string{}
+	lookupEnv := opts.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = func(string) (string, bool) { return "", false }
+	}
+
+	path, required := DefaultFile(), false
+	if opts.Flags != nil && opts.Flags.file != "" {
+		path, required = opts.Flags.file, true
+	} else if file, ok := lookupEnv(FileEnv); ok && file != "" {
+		path, required = file, true
+	}
+
+	var errs []error
+	if path != "" {
+		if err := c.loadFile(path, required); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, c.loadEnv(lookupEnv)...)
+	if opts.Flags != nil {
+		errs = append(errs, c.loadFlags(opts.Flags)...)
+	}
+	if err := errors.Join(append(errs, c.Validate())...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DefaultFile returns config.yml in the user configuration directory, or an empty
+// string if that directory cannot be determined
+func DefaultFile() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "github-issue-developer", "config.yml")
+}
+
+// File returns the configuration file that was loaded, if any
+func (c *Config) File() string {
+	return c.file
+}
+
+// invalidAt reports an invalid value of the setting key set at source
+func invalidAt(key, source string, format string, args ...any) error {
+	return fmt.Errorf("%w: %s (%s): %s", ErrInvalidConfig, key, source, fmt.Sprintf(format, args...))
+}
+
+// loadFile overlays the YAML configuration file at path. A missing file is only an
+// error when it is required.
+func (c *Config) loadFile(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !required && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("%w: reading configuration file: %v", ErrInvalidConfig, err)
+	}
+	c.file = path
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	var errs []error
+	c.loadSection(path, "", doc.Content[0], &errs)
+	return errors.Join(errs...)
+}
+
+// loadSection overlays the settings of the mapping node under the key prefix
+func (c *Config) loadSection(path, prefix string, node *yaml.Node, errs *[]error) {
+	if node.Kind != yaml.MappingNode {
+		name := prefix
+		if name == "" {
+			name = "configuration"
+		}
+		*errs = append(*errs, invalidAt(name, fmt.Sprintf("file %s:%d", path, node.Line), "must be a mapping of keys"))
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		key := k.Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		source := fmt.Sprintf("file %s:%d", path, k.Line)
+		if v.Kind == yaml.ScalarNode && v.Tag == "!!null" {
+			continue
+		}
+
+		s, ok := lookup(key)
+		switch {
+		case ok:
+			if err := s.setNode(c, v); err != nil {
+				*errs = append(*errs, invalidAt(key, source, "%v", err))
+				continue
+			}
+			c.sources[key] = source
+		case isSection(key):
+			c.loadSection(path, key, v, errs)
+		default:
+			*errs = append(*errs, invalidAt(key, source, "unknown key"))
+		}
+	}
+}
+
+// isSection reports whether key groups other settings, e.g. "github.token_exchange"
+func isSection(key string) bool {
+	for _, s := range settings {
+		if strings.HasPrefix(s.key, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// setNode sets the setting from a YAML value. Lists may be sequences or
+// comma-separated strings.
+func (s setting) setNode(c *Config, node *yaml.Node) error {
+	if list, ok := s.field(c).(*[]string); ok && node.Kind == yaml.SequenceNode {
+		values := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return fmt.Errorf("must be a list of strings")
+			}
+			values = append(values, strings.TrimSpace(item.Value))
+		}
+		*list = values
+		return nil
+	}
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("must be a single value")
+	}
+	return s.set(c, node.Value)
+}
+
+// loadEnv overlays the settings whose environment variables are set and not empty
+func (c *Config) loadEnv(lookupEnv func(string) (string, bool)) []error {
+	var errs []error
+	for _, s := range settings {
+		for _, name := range s.env {
+			value, ok := lookupEnv(name)
+			if !ok || value == "" {
+				continue
+			}
+			source := "env " + name
+			if err := s.set(c, value); err != nil {
+				errs = append(errs, invalidAt(s.key, source, "%v", err))
+			} else {
+				c.sources[s.key] = source
+			}
+			break
+		}
+	}
+	return errs
+}
+
+// loadFlags overlays the settings given as flags
+func (c *Config) loadFlags(f *Flags) []error {
+	var errs []error
+	for _, s := range settings {
+		value, ok := f.values[s.key]
+		if !ok {
+			continue
+		}
+		source := "flag --" + s.flag
+		if err := s.set(c, value); err != nil {
+			errs = append(errs, invalidAt(s.key, source, "%v", err))
+		} else {
+			c.sources[s.key] = source
+		}
+	}
+	return errs
+}
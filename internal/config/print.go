@@ -0,0 +1,66 @@
+package config
+
+import (
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets when the configuration is printed
+const redacted = "<redacted>"
+
+// Write writes c as a YAML configuration file with secrets redacted. With sources,
+// every setting that is not a default is annotated with where it was set.
+func (c *Config) Write(w io.Writer, sources bool) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, s := range settings {
+		parent := root
+		path := strings.Split(s.key, ".")
+		for _, name := range path[:len(path)-1] {
+			parent = section(parent, name)
+		}
+
+		value := s.node(c)
+		if source, ok := c.sources[s.key]; ok && sources {
+			value.LineComment = "from " + source
+		}
+		parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[len(path)-1]}, value)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// section returns the mapping named name in parent, adding it if needed
+func section(parent *yaml.Node, name string) *yaml.Node {
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == name {
+			return parent.Content[i+1]
+		}
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, child)
+	return child
+}
+
+// node returns the value of the setting in c as a YAML node
+func (s setting) node(c *Config) *yaml.Node {
+	if list, ok := s.field(c).(*[]string); ok {
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, value := range *list {
+			seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+		}
+		return seq
+	}
+
+	value := s.format(c)
+	if s.secret && value != "" {
+		value = redacted
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// setting is a configuration key and the environment variables and flag that set it
+type setting struct {
+	// key is the dotted path of the setting in the configuration file
+	key string
+	// env lists the environment variables of the setting; the first one set wins
+	env []string
+	// flag is the command-line flag of the setting; secrets have none so that they
+	// do not show up in process listings
+	flag  string
+	usage string
+	// secret settings are redacted when the configuration is printed
+	secret bool
+	// positive durations must not be zero
+	positive bool
+	// field returns a pointer to the setting in c: a *string, *time.Duration or *[]string
+	field func(c *Config) any
+}
+
+// settings lists every setting in the order they are printed
+var settings = []setting{
+	{key: "server.name", env: []string{"MCP_SERVER_NAME"}, usage: "name the server reports to clients",
+		field: func(c *Config) any { return &c.Server.Name }},
+	{key: "server.shutdown_timeout", env: []string{"MCP_SHUTDOWN_TIMEOUT"}, positive: true, usage: "how long running calls may take to finish on shutdown",
+		field: func(c *Config) any { return &c.Server.ShutdownTimeout }},
+
+	{key: "transport.mode", env: []string{"MCP_TRANSPORT"}, usage: "transport to serve: stdio, http, streamable or sse",
+		field: func(c *Config) any { return &c.Transport.Mode }},
+	{key: "transport.address", env: []string{"MCP_HTTP_ADDR"}, usage: "address the HTTP transports listen at",
+		field: func(c *Config) any { return &c.Transport.Address }},
+	{key: "transport.streamable_path", env: []string{"MCP_STREAMABLE_PATH"}, usage: "path of the streamable HTTP transport",
+		field: func(c *Config) any { return &c.Transport.StreamablePath }},
+	{key: "transport.sse_path", env: []string{"MCP_SSE_PATH"}, usage: "path of the SSE transport",
+		field: func(c *Config) any { return &c.Transport.SSEPath }},
+	{key: "transport.read_header_timeout", env: []string{"MCP_HTTP_READ_HEADER_TIMEOUT"}, usage: "time to read request headers; 0 for none",
+		field: func(c *Config) any { return &c.Transport.ReadHeaderTimeout }},
+	{key: "transport.read_timeout", env: []string{"MCP_HTTP_READ_TIMEOUT"}, usage: "time to read whole requests; 0 for none",
+		field: func(c *Config) any { return &c.Transport.ReadTimeout }},
+	{key: "transport.write_timeout", env: []string{"MCP_HTTP_WRITE_TIMEOUT"}, usage: "time to write whole responses; 0 for none",
+		field: func(c *Config) any { return &c.Transport.WriteTimeout }},
+	{key: "transport.idle_timeout", env: []string{"MCP_HTTP_IDLE_TIMEOUT"}, usage: "time idle connections are kept open; 0 for none",
+		field: func(c *Config) any { return &c.Transport.IdleTimeout }},
+
+	{key: "tls.cert_file", env: []string{"MCP_TLS_CERT_FILE"}, usage: "certificate the server presents, turning on HTTPS",
+		field: func(c *Config) any { return &c.TLS.CertFile }},
+	{key: "tls.key_file", env: []string{"MCP_TLS_KEY_FILE"}, usage: "private key of the certificate",
+		field: func(c *Config) any { return &c.TLS.KeyFile }},
+	{key: "tls.client_ca_file", env: []string{"MCP_TLS_CLIENT_CA_FILE"}, usage: "CAs that client certificates must be signed by",
+		field: func(c *Config) any { return &c.TLS.ClientCAFile }},
+
+	{key: "auth.tokens_file", env: []string{"MCP_AUTH_TOKENS_FILE"}, usage: "static bearer tokens of HTTP clients",
+		field: func(c *Config) any { return &c.Auth.TokensFile }},
+	{key: "auth.jwks_file", env: []string{"MCP_AUTH_JWKS_FILE"}, usage: "keys that JWT bearer tokens are signed with",
+		field: func(c *Config) any { return &c.Auth.JWKSFile }},
+	{key: "auth.issuer", env: []string{"MCP_AUTH_ISSUER"}, usage: "required iss claim of JWTs",
+		field: func(c *Config) any { return &c.Auth.Issuer }},
+	{key: "auth.audience", env: []string{"MCP_AUTH_AUDIENCE"}, usage: "required aud claim of JWTs",
+		field: func(c *Config) any { return &c.Auth.Audience }},
+	{key: "auth.resource", env: []string{"MCP_AUTH_RESOURCE"}, usage: "resource identifier advertised in the resource metadata",
+		field: func(c *Config) any { return &c.Auth.Resource }},
+	{key: "auth.authorization_servers", env: []string{"MCP_AUTH_SERVERS"}, usage: "comma-separated authorization servers advertised in the resource metadata",
+		field: func(c *Config) any { return &c.Auth.AuthorizationServers }},
+
+	{key: "github.token", env: []string{"GITHUB_TOKEN", "GH_TOKEN"}, secret: true,
+		field: func(c *Config) any { return &c.GitHub.Token }},
+	{key: "github.api_url", env: []string{"GITHUB_API_URL"}, usage: "GitHub REST API endpoint or GitHub Enterprise Server host",
+		field: func(c *Config) any { return &c.GitHub.APIURL }},
+	{key: "github.credentials", env: []string{"MCP_GITHUB_CREDENTIALS"}, usage: "GitHub credentials of HTTP sessions: server, forward or exchange",
+		field: func(c *Config) any { return &c.GitHub.Credentials }},
+	{key: "github.token_header", env: []string{"MCP_GITHUB_TOKEN_HEADER"}, usage: "header clients send their GitHub token in",
+		field: func(c *Config) any { return &c.GitHub.TokenHeader }},
+	{key: "github.token_exchange.url", env: []string{"MCP_GITHUB_TOKEN_EXCHANGE_URL"}, usage: "token endpoint GitHub tokens are exchanged at",
+		field: func(c *Config) any { return &c.GitHub.TokenExchange.URL }},
+	{key: "github.token_exchange.client_id", env: []string{"MCP_GITHUB_TOKEN_EXCHANGE_CLIENT_ID"}, usage: "client ID at the token endpoint",
+		field: func(c *Config) any { return &c.GitHub.TokenExchange.ClientID }},
+	{key: "github.token_exchange.client_secret", env: []string{"MCP_GITHUB_TOKEN_EXCHANGE_CLIENT_SECRET"}, secret: true,
+		field: func(c *Config) any { return &c.GitHub.TokenExchange.ClientSecret }},
+	{key: "github.token_exchange.audience", env: []string{"MCP_GITHUB_TOKEN_EXCHANGE_AUDIENCE"}, usage: "audience requested at the token endpoint",
+		field: func(c *Config) any { return &c.GitHub.TokenExchange.Audience }},
+
+	{key: "git.workspaces", env: []string{"MCP_GIT_WORKSPACES"}, usage: "comma-separated directories client roots must lie within; required for the git tools over HTTP",
+		field: func(c *Config) any { return &c.Git.Workspaces }},
+
+	{key: "prompts.dir", env: []string{"MCP_PROMPTS_DIR"}, usage: "directory of prompt files overriding the built-in library",
+		field: func(c *Config) any { return &c.Prompts.Dir }},
+	{key: "prompts.repository_root", env: []string{"MCP_REPOSITORY_ROOT"}, usage: "repository used until a client reports its roots",
+		field: func(c *Config) any { return &c.Prompts.RepositoryRoot }},
+	{key: "prompts.poll_interval", env: []string{"MCP_PROMPTS_POLL_INTERVAL"}, positive: true, usage: "how often prompt directories are checked for changes",
+		field: func(c *Config) any { return &c.Prompts.PollInterval }},
+
+	{key: "policy.file", env: []string{"MCP_POLICY_FILE"}, usage: "conventions policy overriding the built-in defaults",
+		field: func(c *Config) any { return &c.Policy.File }},
+	{key: "state.file", env: []string{"MCP_STATE_FILE"}, usage: `file work is saved in across restarts, or "off"`,
+		field: func(c *Config) any { return &c.State.File }},
+	{key: "audit.log", env: []string{"MCP_AUDIT_LOG"}, usage: "file audit entries are appended to",
+		field: func(c *Config) any { return &c.Audit.Log }},
+}
+
+func init() {
+	for i := range settings {
+		if !settings[i].secret {
+			settings[i].flag = strings.ReplaceAll(settings[i].key, "_", "-")
+		}
+	}
+}
+
+// lookup returns the setting named key
+func lookup(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// set parses raw into the setting's field of c. Lists are comma-separated.
+func (s setting) set(c *Config, raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch field := s.field(c).(type) {
+	case *string:
+		*field = raw
+	case *time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 30s or 2m, got %q", raw)
+		}
+		*field = d
+	case *[]string:
+		var values []string
+		for value := range strings.SplitSeq(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		*field = values
+	}
+	return nil
+}
+
+// format returns the field of the setting in c as a string
+func (s setting) format(c *Config) string {
+	switch field := s.field(c).(type) {
+	case *string:
+		return *field
+	case *time.Duration:
+		return field.String()
+	case *[]string:
+		return strings.Join(*field, ",")
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidBranchName is returned for names git does not accept as branch names,
+// including names that git would read as options
+var ErrInvalidBranchName = errors.New("invalid branch name")
+
+// CheckBranchName fails unless name is a valid branch name according to
+// git check-ref-format --branch
+func (r *Repository) CheckBranchName(ctx context.Context, name string) error {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("%w: %q", ErrInvalidBranchName, name)
+	}
+	if _, err := r.run(ctx, "check-ref-format", "--branch", name); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidBranchName, name)
+	}
+	return nil
+}
+
+// RevParse returns the commit SHA that ref points at
+func (r *Repository) RevParse(ctx context.Context, ref string) (string, error) {
+	out, err := r.run(ctx, "rev-parse", "--verify", "-q", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// BranchExists reports whether the local branch exists
+func (r *Repository) BranchExists(ctx context.Context, branch string) bool {
+	_, err := r.run(ctx, "rev-parse", "--verify", "-q", "--end-of-options", "refs/heads/"+branch)
+	return err == nil
+}
+
+// RemoteBranchExists reports whether the remote-tracking branch remote/branch exists
+func (r *Repository) RemoteBranchExists(ctx context.Context, remote, branch string) bool {
+	_, err := r.run(ctx, "rev-parse", "--verify", "-q", "--end-of-options", "refs/remotes/"+remote+"/"+branch)
+	return err == nil
+}
+
+// RemoteBranchHead asks remote itself, rather than its remote-tracking branches,
+// which commit branch points at. It returns "" if remote has no such branch.
+func (r *Repository) RemoteBranchHead(ctx context.Context, remote, branch string) (string, error) {
+	out, err := r.run(ctx, "ls-remote", "--heads", "--", remote, "refs/heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+		if sha, ref, ok := strings.Cut(line, "\t"); ok && ref == "refs/heads/"+branch {
+			return sha, nil
+		}
+	}
+	return "", nil
+}
+
+// Fetch updates the remote-tracking branches of remote. With branches, only those
+// branches are fetched. Neither is ever read as an option.
+func (r *Repository) Fetch(ctx context.Context, remote string, branches ...string) error {
+	_, err := r.run(ctx, append([]string{"fetch", "--quiet", "--", remote}, branches...)...)
+	return err
+}
+
+// FastForward fetches branch from remote and fast-forwards the local branch to it,
+// whether or not it is checked out. It fails if the branches have diverged and
+// reports whether the local branch moved.
+func (r *Repository) FastForward(ctx context.Context, remote, branch string) (bool, error) {
+	before, err := r.RevParse(ctx, "refs/heads/"+branch)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := r.CurrentBranch(ctx)
+	if err != nil {
+		return false, err
+	}
+	if current == branch {
+		if err := r.Fetch(ctx, remote, branch); err != nil {
+			return false, err
+		}
+		if _, err := r.run(ctx, "merge", "--ff-only", "--quiet", "--end-of-options", "refs/remotes/"+remote+"/"+branch); err != nil {
+			return false, err
+		}
+	} else {
+		// Fetching into the local branch refuses updates that are not fast-forwards
+		if err := r.Fetch(ctx, remote, "refs/heads/"+branch+":refs/heads/"+branch, "refs/heads/"+branch+":refs/remotes/"+remote+"/"+branch); err != nil {
+			return false, err
+		}
+	}
+
+	after, err := r.RevParse(ctx, "refs/heads/"+branch)
+	if err != nil {
+		return false, err
+	}
+	return before != after, nil
+}
+
+// Switch checks out an existing local branch
+func (r *Repository) Switch(ctx context.Context, branch string) error {
+	_, err := r.run(ctx, "switch", "--quiet", "--", branch)
+	return err
+}
+
+// CreateBranch creates branch at startPoint without tracking it and checks it out
+func (r *Repository) CreateBranch(ctx context.Context, branch, startPoint string) error {
+	_, err := r.run(ctx, "switch", "--quiet", "--no-track", "-c", branch, "--", startPoint)
+	return err
+}
+
+// TrackBranch creates a local branch tracking remote/branch and checks it out
+func (r *Repository) TrackBranch(ctx context.Context, remote, branch string) error {
+	_, err := r.run(ctx, "switch", "--quiet", "--track", "-c", branch, "--", remote+"/"+branch)
+	return err
+}
@@ -0,0 +1,157 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+)
+
+// pushUpstreamCommit adds a commit to main on remote from a separate clone
+func pushUpstreamCommit(t *testing.T, remote, subject string) string {
+	t.Helper()
+	other := gittest.Clone(t, remote)
+	gittest.Run(t, other, "commit", "-q", "--allow-empty", "-m", subject)
+	gittest.Run(t, other, "push", "-q", "origin", "main")
+	return gittest.Run(t, other, "rev-parse", "HEAD")
+}
+
+func TestFastForward(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	remote := gittest.NewRemote(t, dir)
+	repo := &Repository{Dir: dir}
+	ctx := context.Background()
+
+	moved, err := repo.FastForward(ctx, "origin", "main")
+	if err != nil || moved {
+		t.Fatalf("Expected up to date main, got %v, %v", moved, err)
+	}
+
+	upstream := pushUpstreamCommit(t, remote, "feat: upstream change")
+	moved, err = repo.FastForward(ctx, "origin", "main")
+	if err != nil || !moved {
+		t.Fatalf("Expected checked out main to move, got %v, %v", moved, err)
+	}
+	if head, _ := repo.RevParse(ctx, "HEAD"); head != upstream {
+		t.Errorf("Expected HEAD %s, got %s", upstream, head)
+	}
+
+	gittest.Run(t, dir, "switch", "-q", "-c", "feature/1-work")
+	upstream = pushUpstreamCommit(t, remote, "feat: another change")
+	moved, err = repo.FastForward(ctx, "origin", "main")
+	if err != nil || !moved {
+		t.Fatalf("Expected main to move while not checked out, got %v, %v", moved, err)
+	}
+	if sha, _ := repo.RevParse(ctx, "main"); sha != upstream {
+		t.Errorf("Expected main %s, got %s", upstream, sha)
+	}
+	if branch, _ := repo.CurrentBranch(ctx); branch != "feature/1-work" {
+		t.Errorf("Expected to stay on feature branch, got %q", branch)
+	}
+}
+
+func TestFastForwardDiverged(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	remote := gittest.NewRemote(t, dir)
+	repo := &Repository{Dir: dir}
+
+	pushUpstreamCommit(t, remote, "feat: upstream change")
+	gittest.Run(t, dir, "commit", "-q", "--allow-empty", "-m", "feat: local change")
+	gittest.Run(t, dir, "switch", "-q", "-c", "feature/2-work")
+
+	if _, err := repo.FastForward(context.Background(), "origin", "main"); err == nil {
+		t.Error("Expected diverged branches not to fast-forward")
+	}
+}
+
+func TestCreateSwitchAndTrackBranches(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	remote := gittest.NewRemote(t, dir)
+	repo := &Repository{Dir: dir}
+	ctx := context.Background()
+
+	if err := repo.CreateBranch(ctx, "feature/3-new", "main"); err != nil {
+		t.Fatalf("CreateBranch returned error: %v", err)
+	}
+	if !repo.BranchExists(ctx, "feature/3-new") || repo.BranchExists(ctx, "feature/missing") {
+		t.Error("Unexpected BranchExists results")
+	}
+	if upstream := gittest.Run(t, dir, "for-each-ref", "--format=%(upstream)", "refs/heads/feature/3-new"); upstream != "" {
+		t.Errorf("Expected new branch not to track its start point, got %q", upstream)
+	}
+
+	if err := repo.Switch(ctx, "main"); err != nil {
+		t.Fatalf("Switch returned error: %v", err)
+	}
+
+	other := gittest.Clone(t, remote)
+	gittest.Run(t, other, "switch", "-q", "-c", "feature/4-shared")
+	gittest.Run(t, other, "push", "-q", "origin", "feature/4-shared")
+	if err := repo.Fetch(ctx, "origin"); err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if !repo.RemoteBranchExists(ctx, "origin", "feature/4-shared") {
+		t.Fatal("Expected fetched remote branch to exist")
+	}
+	if err := repo.TrackBranch(ctx, "origin", "feature/4-shared"); err != nil {
+		t.Fatalf("TrackBranch returned error: %v", err)
+	}
+	if branch, _ := repo.CurrentBranch(ctx); branch != "feature/4-shared" {
+		t.Errorf("Expected tracked branch to be checked out, got %q", branch)
+	}
+}
+
+func TestRefsAreNeverOptions(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.NewRemote(t, dir)
+	repo := &Repository{Dir: dir}
+	ctx := context.Background()
+
+	pwned := filepath.Join(t.TempDir(), "pwned")
+	option := "--upload-pack=touch " + pwned
+	for _, name := range []string{option, "-b", "", "feature/a..b"} {
+		if err := repo.CheckBranchName(ctx, name); !errors.Is(err, ErrInvalidBranchName) {
+			t.Errorf("Expected %q to be an invalid branch name, got %v", name, err)
+		}
+	}
+	if err := repo.CheckBranchName(ctx, "feature/1-work"); err != nil {
+		t.Errorf("CheckBranchName returned error: %v", err)
+	}
+
+	if err := repo.Fetch(ctx, "origin", option); err == nil {
+		t.Error("Expected fetching an option to fail")
+	}
+	if _, err := repo.FastForward(ctx, "origin", option); err == nil {
+		t.Error("Expected fast-forwarding an option to fail")
+	}
+	if _, err := repo.RemoteBranchHead(ctx, option, "main"); err == nil {
+		t.Error("Expected asking an option for its branches to fail")
+	}
+	if err := repo.Switch(ctx, "--detach"); err == nil {
+		t.Error("Expected switching to an option to fail")
+	}
+	if _, err := os.Stat(pwned); err == nil {
+		t.Error("Expected the upload-pack command not to run")
+	}
+}
+
+func TestRemoteBranchHeadAsksTheRemote(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	remote := gittest.NewRemote(t, dir)
+	repo := &Repository{Dir: dir}
+	ctx := context.Background()
+
+	upstream := pushUpstreamCommit(t, remote, "feat: upstream change")
+	if head, err := repo.RemoteBranchHead(ctx, "origin", "main"); err != nil || head != upstream {
+		t.Errorf("Expected the unfetched head %s, got %q, %v", upstream, head, err)
+	}
+	if head, err := repo.RemoteBranchHead(ctx, "origin", "feature/missing"); err != nil || head != "" {
+		t.Errorf("Expected no head for a missing branch, got %q, %v", head, err)
+	}
+	if _, err := repo.RemoteBranchHead(ctx, "missing", "main"); err == nil {
+		t.Error("Expected an unknown remote to fail")
+	}
+}
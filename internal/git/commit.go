@@ -0,0 +1,27 @@
+package git
+
+import (
+	"context"
+	"strings"
+)
+
+// Add stages the current contents of paths, including deletions
+func (r *Repository) Add(ctx context.Context, paths ...string) error {
+	_, err := r.run(ctx, append([]string{"add", "--"}, paths...)...)
+	return err
+}
+
+// Commit records a commit with message, exactly as given, and returns its SHA. With
+// paths, the working tree contents of those paths are committed, as git commit --only
+// does; other staged changes stay staged. Hooks run as they would for a commit made
+// on the command line.
+func (r *Repository) Commit(ctx context.Context, message string, paths ...string) (string, error) {
+	args := []string{"commit", "--quiet", "--cleanup=verbatim", "--file=-"}
+	if len(paths) > 0 {
+		args = append(append(args, "--only", "--"), paths...)
+	}
+	if _, err := r.runWithInput(ctx, strings.NewReader(message), args...); err != nil {
+		return "", err
+	}
+	return r.RevParse(ctx, "HEAD")
+}
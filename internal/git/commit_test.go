@@ -0,0 +1,67 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+)
+
+func TestCommitOnlyListedPaths(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	repo := &Repository{Dir: dir}
+	ctx := context.Background()
+
+	gittest.WriteFile(t, dir, "main.go", "package main\n")
+	gittest.WriteFile(t, dir, "notes.txt", "todo\n")
+	if err := repo.Add(ctx, "main.go", "notes.txt"); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	message := "feat: add entry point\n\nFollows the layout\n#42 asked for.\n\n# Usage\n\nRefs #3\n"
+	sha, err := repo.Commit(ctx, message, "main.go")
+	if err != nil {
+		t.Fatalf("Commit returned error: %v", err)
+	}
+	if head, _ := repo.RevParse(ctx, "HEAD"); head != sha {
+		t.Errorf("Expected SHA %s to be HEAD, got %s", sha, head)
+	}
+	if got := gittest.Run(t, dir, "log", "-1", "--format=%B"); got != strings.TrimSpace(message) {
+		t.Errorf("Unexpected commit message %q", got)
+	}
+	if files := gittest.Run(t, dir, "show", "--name-only", "--format=", "HEAD"); files != "main.go" {
+		t.Errorf("Expected only main.go to be committed, got %q", files)
+	}
+
+	status, err := repo.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if len(status.Staged) != 1 || status.Staged[0].Path != "notes.txt" {
+		t.Errorf("Expected notes.txt to stay staged, got %+v", status.Staged)
+	}
+}
+
+func TestCommitHookFailure(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	repo := &Repository{Dir: dir}
+	ctx := context.Background()
+
+	hook := filepath.Join(dir, ".git", "hooks", "pre-commit")
+	gittest.WriteFile(t, dir, filepath.Join(".git", "hooks", "pre-commit"), "#!/bin/sh\necho lint failed >&2\nexit 1\n")
+	if err := os.Chmod(hook, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gittest.WriteFile(t, dir, "main.go", "package main\n")
+	if err := repo.Add(ctx, "main.go"); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	_, err := repo.Commit(ctx, "feat: add entry point")
+	if err == nil || !strings.Contains(err.Error(), "lint failed") {
+		t.Fatalf("Expected failing pre-commit hook to abort the commit, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRepository is returned when a path is not inside a Git working tree
+var ErrNotRepository = errors.New("not a git repository")
+
+// CommandError is returned when a git command exits with an error
+type CommandError struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+// Error describes the failed command and what git printed
+func (e *CommandError) Error() string {
+	msg := strings.TrimSpace(e.Stderr)
+	if msg == "" {
+		msg = e.Err.Error()
+	}
+	return fmt.Sprintf("git %s: %s", strings.Join(e.Args, " "), msg)
+}
+
+// Unwrap returns the underlying process error
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Repository is a Git working tree
+type Repository struct {
+	// Dir is the top-level directory of the working tree
+	Dir string
+}
+
+// Open returns the repository containing path
+func Open(ctx context.Context, path string) (*Repository, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		path = filepath.Dir(path)
+	}
+
+	out, err := (&Repository{Dir: path}).run(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) && strings.Contains(cmdErr.Stderr, "not a git repository") {
+			return nil, fmt.Errorf("%w: %s", ErrNotRepository, path)
+		}
+		return nil, err
+	}
+	return &Repository{Dir: filepath.Clean(strings.TrimSpace(out))}, nil
+}
+
+// run executes git in the repository and returns its standard output. Prompts for
+// credentials are disabled and messages are not localized so that output can be parsed.
+func (r *Repository) run(ctx context.Context, args ...string) (string, error) {
+	return r.runWithInput(ctx, nil, args...)
+}
+
+// runWithInput is run with stdin connected to input
+func (r *Repository) runWithInput(ctx context.Context, input io.Reader, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", r.Dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	cmd.Stdin = input
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", &CommandError{Args: args, Stderr: stderr.String(), Err: err}
+	}
+	return stdout.String(), nil
+}
+
+// FindRoot returns the closest ancestor of dir, including dir itself, that contains
+// a .git entry, or an empty string if dir is not inside a repository
+func FindRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// FileURIPath converts a file:// URI, such as an MCP client root, to a local path
+func FileURIPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+)
+
+func TestOpen(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	nested := filepath.Join(dir, "internal", "git")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := Open(context.Background(), nested)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if repo.Dir != dir {
+		t.Errorf("Expected repository root %q, got %q", dir, repo.Dir)
+	}
+
+	if _, err := Open(context.Background(), t.TempDir()); !errors.Is(err, ErrNotRepository) {
+		t.Errorf("Expected ErrNotRepository outside a repository, got %v", err)
+	}
+	if _, err := Open(context.Background(), filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected error for missing path")
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	repo := &Repository{Dir: gittest.NewRepository(t)}
+
+	_, err := repo.run(context.Background(), "rev-parse", "--verify", "no-such-ref")
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected CommandError, got %v", err)
+	}
+	if cmdErr.Error() == "" || cmdErr.Unwrap() == nil {
+		t.Errorf("Expected message and underlying error, got %+v", cmdErr)
+	}
+}
+
+func TestFileURIPath(t *testing.T) {
+	tests := []struct {
+		uri  string
+		path string
+		ok   bool
+	}{
+		{"file:///home/dev/project", "/home/dev/project", true},
+		{"file:///home/dev/my%20project", "/home/dev/my project", true},
+		{"https://example.com/repo", "", false},
+		{"file://", "", false},
+	}
+
+	for _, tt := range tests {
+		path, ok := FileURIPath(tt.uri)
+		if ok != tt.ok || path != filepath.FromSlash(tt.path) {
+			t.Errorf("FileURIPath(%q) = %q, %v; want %q, %v", tt.uri, path, ok, tt.path, tt.ok)
+		}
+	}
+}
+
+func TestFindRoot(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(repo, "internal", "server")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if root := FindRoot(nested); root != repo {
+		t.Errorf("Expected repository root %q, got %q", repo, root)
+	}
+
+	if root := FindRoot(t.TempDir()); root != "" {
+		t.Errorf("Expected no repository root outside a repository, got %q", root)
+	}
+}
@@ -0,0 +1,89 @@
+// Package gittest creates temporary Git repositories for tests
+package gittest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// env isolates test repositories from the user's Git configuration
+var env = []string{
+	"GIT_CONFIG_GLOBAL=" + os.DevNull,
+	"GIT_CONFIG_NOSYSTEM=1",
+	"GIT_AUTHOR_NAME=Test Author",
+	"GIT_AUTHOR_EMAIL=author@example.com",
+	"GIT_COMMITTER_NAME=Test Author",
+	"GIT_COMMITTER_EMAIL=author@example.com",
+	"LC_ALL=C",
+}
+
+// NewRepository creates a repository in a temporary directory with one commit on
+// main containing README.md and returns its directory
+func NewRepository(t *testing.T) string {
+	t.Helper()
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	Run(t, dir, "init", "-q", "-b", "main")
+	// Commits made by the code under test do not see env, so set an identity locally
+	Run(t, dir, "config", "user.name", "Test Author")
+	Run(t, dir, "config", "user.email", "author@example.com")
+	WriteFile(t, dir, "README.md", "# Test\n")
+	Run(t, dir, "add", "README.md")
+	Run(t, dir, "commit", "-q", "-m", "chore: initial commit")
+	return dir
+}
+
+// NewRemote creates a bare repository cloned from dir and adds it to dir as origin
+func NewRemote(t *testing.T, dir string) string {
+	t.Helper()
+
+	remote := filepath.Join(t.TempDir(), "origin.git")
+	Run(t, dir, "clone", "-q", "--bare", dir, remote)
+	Run(t, dir, "remote", "add", "origin", remote)
+	Run(t, dir, "fetch", "-q", "origin")
+	return remote
+}
+
+// Clone clones remote into a temporary directory and returns the working tree
+func Clone(t *testing.T, remote string) string {
+	t.Helper()
+
+	parent, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	Run(t, parent, "clone", "-q", remote, "clone")
+	return filepath.Join(parent, "clone")
+}
+
+// WriteFile writes content to the file name relative to dir, creating parent directories
+func WriteFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Run runs git in dir and returns its trimmed output, failing the test on error
+func Run(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Commit is a single entry of the commit log
+type Commit struct {
+	SHA         string `json:"sha"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Date        string `json:"date"`
+	Subject     string `json:"subject"`
+}
+
+// Stash is a single entry of the stash list
+type Stash struct {
+	Ref     string `json:"ref"`
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+}
+
+// Log returns up to limit commits reachable from HEAD, newest first. A repository
+// without commits has an empty log.
+func (r *Repository) Log(ctx context.Context, limit int) ([]Commit, error) {
+	if !r.hasCommits(ctx) {
+		return []Commit{}, nil
+	}
+
+	out, err := r.run(ctx, "log", fmt.Sprintf("--max-count=%d", limit), "--format=%H%x00%an%x00%ae%x00%aI%x00%s%x1e")
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []Commit{}
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x00")
+		if len(fields) != 5 {
+			continue
+		}
+		commits = append(commits, Commit{
+			SHA:         fields[0],
+			AuthorName:  fields[1],
+			AuthorEmail: fields[2],
+			Date:        fields[3],
+			Subject:     fields[4],
+		})
+	}
+	return commits, nil
+}
+
+// Stashes returns the stash list, newest first
+func (r *Repository) Stashes(ctx context.Context) ([]Stash, error) {
+	out, err := r.run(ctx, "stash", "list", "--format=%gd%x00%H%x00%gs")
+	if err != nil {
+		return nil, err
+	}
+
+	stashes := []Stash{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 3 {
+			continue
+		}
+		stashes = append(stashes, Stash{Ref: fields[0], SHA: fields[1], Message: fields[2]})
+	}
+	return stashes, nil
+}
+
+// hasCommits reports whether HEAD points at a commit
+func (r *Repository) hasCommits(ctx context.Context) bool {
+	_, err := r.run(ctx, "rev-parse", "--verify", "-q", "HEAD")
+	return err == nil
+}
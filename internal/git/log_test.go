@@ -0,0 +1,59 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+)
+
+func TestLog(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.Run(t, dir, "commit", "-q", "--allow-empty", "-m", "feat: second\n\nWith a body.")
+	gittest.Run(t, dir, "commit", "-q", "--allow-empty", "-m", "fix: third")
+
+	commits, err := (&Repository{Dir: dir}).Log(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Log returned error: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %+v", commits)
+	}
+	if commits[0].Subject != "fix: third" || commits[1].Subject != "feat: second" {
+		t.Errorf("Expected newest first, got %+v", commits)
+	}
+	if len(commits[0].SHA) != 40 || commits[0].AuthorEmail != "author@example.com" || commits[0].Date == "" {
+		t.Errorf("Expected commit metadata, got %+v", commits[0])
+	}
+}
+
+func TestLogWithoutCommits(t *testing.T) {
+	dir := t.TempDir()
+	gittest.Run(t, dir, "init", "-q")
+
+	commits, err := (&Repository{Dir: dir}).Log(context.Background(), 10)
+	if err != nil || len(commits) != 0 {
+		t.Errorf("Expected empty log, got %+v, %v", commits, err)
+	}
+}
+
+func TestStashes(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	repo := &Repository{Dir: dir}
+
+	stashes, err := repo.Stashes(context.Background())
+	if err != nil || len(stashes) != 0 {
+		t.Fatalf("Expected no stashes, got %+v, %v", stashes, err)
+	}
+
+	gittest.WriteFile(t, dir, "README.md", "# Changed\n")
+	gittest.Run(t, dir, "stash", "push", "-q", "-m", "work in progress")
+
+	stashes, err = repo.Stashes(context.Background())
+	if err != nil {
+		t.Fatalf("Stashes returned error: %v", err)
+	}
+	if len(stashes) != 1 || stashes[0].Ref != "stash@{0}" || stashes[0].Message != "On main: work in progress" {
+		t.Errorf("Unexpected stash list %+v", stashes)
+	}
+}
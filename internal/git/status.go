@@ -0,0 +1,188 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FileChange is a changed path in the index or working tree
+type FileChange struct {
+	Path         string `json:"path"`
+	OriginalPath string `json:"original_path,omitempty"`
+	Status       string `json:"status"`
+}
+
+// Status is the state of the working tree, index and current branch
+type Status struct {
+	Branch     string       `json:"branch,omitempty"`
+	Detached   bool         `json:"detached"`
+	Head       string       `json:"head,omitempty"`
+	Upstream   string       `json:"upstream,omitempty"`
+	Ahead      int          `json:"ahead"`
+	Behind     int          `json:"behind"`
+	Staged     []FileChange `json:"staged"`
+	Unstaged   []FileChange `json:"unstaged"`
+	Untracked  []string     `json:"untracked"`
+	Conflicted []string     `json:"conflicted"`
+	Clean      bool         `json:"clean"`
+}
+
+// changeNames maps the porcelain status letters to readable names
+var changeNames = map[byte]string{
+	'M': "modified",
+	'T': "type-changed",
+	'A': "added",
+	'D': "deleted",
+	'R': "renamed",
+	'C': "copied",
+}
+
+// Status returns the branch, ahead/behind counts and changed files of the repository.
+// When the branch has no upstream, it is compared with origin/<branch> if that exists.
+func (r *Repository) Status(ctx context.Context) (*Status, error) {
+	out, err := r.run(ctx, "status", "--porcelain=v2", "--branch", "--untracked-files=all", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := parseStatus(out)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.Upstream == "" && status.Branch != "" && status.Head != "" {
+		remote := "origin/" + status.Branch
+		if _, err := r.run(ctx, "rev-parse", "--verify", "-q", "--end-of-options", "refs/remotes/"+remote); err == nil {
+			ahead, behind, err := r.aheadBehind(ctx, "HEAD", remote)
+			if err != nil {
+				return nil, err
+			}
+			status.Upstream, status.Ahead, status.Behind = remote, ahead, behind
+		}
+	}
+	return status, nil
+}
+
+// CurrentBranch returns the checked out branch, or an empty string if HEAD is detached
+func (r *Repository) CurrentBranch(ctx context.Context) (string, error) {
+	out, err := r.run(ctx, "branch", "--show-current")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// RemoteURL returns the URL of the named remote, or an empty string if there is no such remote
+func (r *Repository) RemoteURL(ctx context.Context, name string) (string, error) {
+	out, err := r.run(ctx, "remote", "get-url", name)
+	if err != nil {
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) && strings.Contains(cmdErr.Stderr, "No such remote") {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// aheadBehind counts the commits reachable from local but not remote and vice versa
+func (r *Repository) aheadBehind(ctx context.Context, local, remote string) (int, int, error) {
+	out, err := r.run(ctx, "rev-list", "--left-right", "--count", "--end-of-options", local+"..."+remote)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", out)
+	}
+	ahead, _ := strconv.Atoi(fields[0])
+	behind, _ := strconv.Atoi(fields[1])
+	return ahead, behind, nil
+}
+
+// parseStatus parses the output of git status --porcelain=v2 --branch -z
+func parseStatus(out string) (*Status, error) {
+	status := &Status{
+		Staged:     []FileChange{},
+		Unstaged:   []FileChange{},
+		Untracked:  []string{},
+		Conflicted: []string{},
+	}
+
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if entry == "" {
+			continue
+		}
+
+		switch entry[0] {
+		case '#':
+			parseBranchHeader(status, entry)
+		case '1':
+			fields := strings.SplitN(entry, " ", 9)
+			if len(fields) != 9 {
+				return nil, fmt.Errorf("unexpected status entry %q", entry)
+			}
+			addChange(status, fields[1], FileChange{Path: fields[8]})
+		case '2':
+			fields := strings.SplitN(entry, " ", 10)
+			if len(fields) != 10 || i+1 >= len(entries) {
+				return nil, fmt.Errorf("unexpected status entry %q", entry)
+			}
+			i++
+			addChange(status, fields[1], FileChange{Path: fields[9], OriginalPath: entries[i]})
+		case 'u':
+			fields := strings.SplitN(entry, " ", 11)
+			if len(fields) != 11 {
+				return nil, fmt.Errorf("unexpected status entry %q", entry)
+			}
+			status.Conflicted = append(status.Conflicted, fields[10])
+		case '?':
+			status.Untracked = append(status.Untracked, entry[2:])
+		}
+	}
+
+	status.Clean = len(status.Staged) == 0 && len(status.Unstaged) == 0 &&
+		len(status.Untracked) == 0 && len(status.Conflicted) == 0
+	return status, nil
+}
+
+// parseBranchHeader records a "# branch.*" header line
+func parseBranchHeader(status *Status, header string) {
+	key, value, _ := strings.Cut(strings.TrimPrefix(header, "# "), " ")
+	switch key {
+	case "branch.oid":
+		if value != "(initial)" {
+			status.Head = value
+		}
+	case "branch.head":
+		if value == "(detached)" {
+			status.Detached = true
+		} else {
+			status.Branch = value
+		}
+	case "branch.upstream":
+		status.Upstream = value
+	case "branch.ab":
+		_, _ = fmt.Sscanf(value, "+%d -%d", &status.Ahead, &status.Behind)
+	}
+}
+
+// addChange records the index and working tree sides of a changed entry
+func addChange(status *Status, xy string, change FileChange) {
+	if name, ok := changeNames[xy[0]]; ok {
+		staged := change
+		staged.Status = name
+		status.Staged = append(status.Staged, staged)
+	}
+	if name, ok := changeNames[xy[1]]; ok {
+		unstaged := change
+		unstaged.Status = name
+		status.Unstaged = append(status.Unstaged, unstaged)
+	}
+}
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+)
+
+func TestStatus(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.WriteFile(t, dir, "main.go", "package main\n")
+	gittest.WriteFile(t, dir, "old name.txt", "content\n")
+	gittest.Run(t, dir, "add", ".")
+	gittest.Run(t, dir, "commit", "-q", "-m", "feat: add files")
+
+	gittest.Run(t, dir, "mv", "old name.txt", "new name.txt")
+	gittest.WriteFile(t, dir, "staged.go", "package main\n")
+	gittest.Run(t, dir, "add", "staged.go")
+	gittest.WriteFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	gittest.WriteFile(t, dir, "notes/todo.md", "- [ ] test\n")
+
+	status, err := (&Repository{Dir: dir}).Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+
+	if status.Branch != "main" || status.Detached || status.Head == "" {
+		t.Errorf("Unexpected branch state %+v", status)
+	}
+	wantStaged := []FileChange{
+		{Path: "new name.txt", OriginalPath: "old name.txt", Status: "renamed"},
+		{Path: "staged.go", Status: "added"},
+	}
+	if !slices.Equal(status.Staged, wantStaged) {
+		t.Errorf("Expected staged %+v, got %+v", wantStaged, status.Staged)
+	}
+	if !slices.Equal(status.Unstaged, []FileChange{{Path: "main.go", Status: "modified"}}) {
+		t.Errorf("Unexpected unstaged files %+v", status.Unstaged)
+	}
+	if !slices.Equal(status.Untracked, []string{"notes/todo.md"}) {
+		t.Errorf("Unexpected untracked files %v", status.Untracked)
+	}
+	if status.Clean {
+		t.Error("Expected dirty working tree")
+	}
+}
+
+func TestStatusAheadBehindOrigin(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.NewRemote(t, dir)
+	repo := &Repository{Dir: dir}
+
+	gittest.Run(t, dir, "checkout", "-q", "-b", "feature/1-login")
+	gittest.Run(t, dir, "push", "-q", "origin", "feature/1-login")
+	gittest.Run(t, dir, "commit", "-q", "--allow-empty", "-m", "feat: one")
+	gittest.Run(t, dir, "commit", "-q", "--allow-empty", "-m", "feat: two")
+
+	status, err := repo.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if status.Upstream != "origin/feature/1-login" || status.Ahead != 2 || status.Behind != 0 {
+		t.Errorf("Expected 2 ahead of origin without tracking, got %+v", status)
+	}
+
+	gittest.Run(t, dir, "branch", "-q", "--set-upstream-to=origin/feature/1-login")
+	gittest.Run(t, dir, "reset", "-q", "--hard", "HEAD~2")
+	gittest.Run(t, dir, "commit", "-q", "--allow-empty", "-m", "feat: other")
+
+	status, err = repo.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if status.Ahead != 1 || status.Behind != 0 || !status.Clean {
+		t.Errorf("Expected 1 ahead of tracked upstream, got %+v", status)
+	}
+}
+
+func TestStatusDetachedAndEmpty(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.Run(t, dir, "checkout", "-q", "--detach")
+
+	status, err := (&Repository{Dir: dir}).Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if !status.Detached || status.Branch != "" {
+		t.Errorf("Expected detached HEAD, got %+v", status)
+	}
+
+	empty := t.TempDir()
+	gittest.Run(t, empty, "init", "-q", "-b", "main")
+	status, err = (&Repository{Dir: empty}).Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if status.Branch != "main" || status.Head != "" || !status.Clean {
+		t.Errorf("Expected clean unborn main branch, got %+v", status)
+	}
+}
+
+func TestCurrentBranchAndRemoteURL(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	repo := &Repository{Dir: dir}
+	ctx := context.Background()
+
+	if branch, err := repo.CurrentBranch(ctx); err != nil || branch != "main" {
+		t.Errorf("Expected main, got %q, %v", branch, err)
+	}
+	if url, err := repo.RemoteURL(ctx, "origin"); err != nil || url != "" {
+		t.Errorf("Expected no origin, got %q, %v", url, err)
+	}
+
+	remote := gittest.NewRemote(t, dir)
+	if url, err := repo.RemoteURL(ctx, "origin"); err != nil || url != remote {
+		t.Errorf("Expected origin %q, got %q, %v", remote, url, err)
+	}
+}
+
+func TestParseStatusUnmerged(t *testing.T) {
+	out := "# branch.oid abc\x00# branch.head topic\x00# branch.upstream origin/topic\x00# branch.ab +3 -4\x00" +
+		"u UU N... 100644 100644 100644 100644 h1 h2 h3 conflict.go\x00"
+
+	status, err := parseStatus(out)
+	if err != nil {
+		t.Fatalf("parseStatus returned error: %v", err)
+	}
+	if status.Ahead != 3 || status.Behind != 4 || status.Upstream != "origin/topic" {
+		t.Errorf("Unexpected branch header parsing %+v", status)
+	}
+	if !slices.Equal(status.Conflicted, []string{"conflict.go"}) || status.Clean {
+		t.Errorf("Expected conflicted file, got %+v", status)
+	}
+
+	if _, err := parseStatus("1 M. short\x00"); err == nil {
+		t.Error("Expected error for malformed entry")
+	}
+}
@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxLogArchive bounds the size of a downloaded log archive
+const maxLogArchive = 64 << 20
+
+// WorkflowStep is a step of a GitHub Actions job
+type WorkflowStep struct {
+	Number      int        `json:"number"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// WorkflowJob is a job of a workflow run
+type WorkflowJob struct {
+	ID           int64          `json:"id"`
+	RunID        int64          `json:"run_id"`
+	WorkflowName string         `json:"workflow_name"`
+	Name         string         `json:"name"`
+	HeadSHA      string         `json:"head_sha"`
+	Status       string         `json:"status"`
+	Conclusion   string         `json:"conclusion"`
+	HTMLURL      string         `json:"html_url"`
+	Steps        []WorkflowStep `json:"steps"`
+}
+
+// GetWorkflowJob fetches a job of a workflow run
+func (c *Client) GetWorkflowJob(ctx context.Context, owner, repo string, jobID int64) (*WorkflowJob, error) {
+	path, err := repoPath(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	var job WorkflowJob
+	if err := c.Get(ctx, fmt.Sprintf("%s/actions/jobs/%d", path, jobID), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListWorkflowJobs fetches the jobs of the latest attempt of a workflow run
+func (c *Client) ListWorkflowJobs(ctx context.Context, owner, repo string, runID int64) ([]WorkflowJob, error) {
+	path, err := repoPath(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return ListField[WorkflowJob](ctx, c, fmt.Sprintf("%s/actions/runs/%d/jobs", path, runID), "jobs", 0)
+}
+
+// DownloadRunLogs downloads the zip archive of a workflow run's logs
+func (c *Client) DownloadRunLogs(ctx context.Context, owner, repo string, runID int64) ([]byte, error) {
+	path, err := repoPath(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return c.Download(ctx, fmt.Sprintf("%s/actions/runs/%d/logs", path, runID), maxLogArchive)
+}
+
+// DownloadJobLogs downloads the plain text log of a job
+func (c *Client) DownloadJobLogs(ctx context.Context, owner, repo string, jobID int64) ([]byte, error) {
+	path, err := repoPath(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return c.Download(ctx, fmt.Sprintf("%s/actions/jobs/%d/logs", path, jobID), maxLogArchive)
+}
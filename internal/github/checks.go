@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// App is the GitHub App that reports checks, such as github-actions
+type App struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// CheckSuite groups the check runs one app reports for a commit. For GitHub Actions
+// each workflow run is a check suite.
+type CheckSuite struct {
+	ID                   int64  `json:"id"`
+	HeadSHA              string `json:"head_sha"`
+	Status               string `json:"status"`
+	Conclusion           string `json:"conclusion"`
+	App                  *App   `json:"app,omitempty"`
+	LatestCheckRunsCount int    `json:"latest_check_runs_count"`
+}
+
+// CheckRun is a single check of a commit. For GitHub Actions each job is a check run
+// whose ID is the job ID.
+type CheckRun struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	HeadSHA     string     `json:"head_sha"`
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion"`
+	HTMLURL     string     `json:"html_url"`
+	DetailsURL  string     `json:"details_url"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CheckSuite  *struct {
+		ID int64 `json:"id"`
+	} `json:"check_suite,omitempty"`
+	App *App `json:"app,omitempty"`
+}
+
+// WorkflowRun is a run of a GitHub Actions workflow
+type WorkflowRun struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	HeadSHA      string `json:"head_sha"`
+	HeadBranch   string `json:"head_branch"`
+	Event        string `json:"event"`
+	Status       string `json:"status"`
+	Conclusion   string `json:"conclusion"`
+	HTMLURL      string `json:"html_url"`
+	RunAttempt   int    `json:"run_attempt"`
+	CheckSuiteID int64  `json:"check_suite_id"`
+}
+
+// Commit is a commit as returned by the commits API. Only its identity is decoded.
+type Commit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+}
+
+// commitPath returns the API path of the commit that ref points at
+func commitPath(owner, repo, ref string) (string, error) {
+	path, err := repoPath(owner, repo)
+	if err != nil {
+		return "", err
+	}
+	segment, err := pathSegment(ref)
+	if err != nil {
+		return "", err
+	}
+	return path + "/commits/" + segment, nil
+}
+
+// GetCommit fetches the commit that ref, a SHA, branch or tag, points at
+func (c *Client) GetCommit(ctx context.Context, owner, repo, ref string) (*Commit, error) {
+	path, err := commitPath(owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	var commit Commit
+	if err := c.Get(ctx, path, &commit); err != nil {
+		return nil, err
+	}
+	return &commit, nil
+}
+
+// GetPullRequest fetches a pull request
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	path, err := pullPath(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	var pr PullRequest
+	if err := c.Get(ctx, path, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// ListCheckSuites fetches the check suites of a commit
+func (c *Client) ListCheckSuites(ctx context.Context, owner, repo, sha string) ([]CheckSuite, error) {
+	path, err := commitPath(owner, repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	return ListField[CheckSuite](ctx, c, path+"/check-suites", "check_suites", 0)
+}
+
+// ListCheckRuns fetches the latest check runs of a commit
+func (c *Client) ListCheckRuns(ctx context.Context, owner, repo, sha string) ([]CheckRun, error) {
+	path, err := commitPath(owner, repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	return ListField[CheckRun](ctx, c, path+"/check-runs", "check_runs", 0)
+}
+
+// ListWorkflowRuns fetches the GitHub Actions workflow runs of a commit
+func (c *Client) ListWorkflowRuns(ctx context.Context, owner, repo, sha string) ([]WorkflowRun, error) {
+	path, err := repoPath(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return ListField[WorkflowRun](ctx, c, path+"/actions/runs?head_sha="+url.QueryEscape(sha), "workflow_runs", 0)
+}
@@ -0,0 +1,253 @@
+// Package github is a small client for the GitHub REST API, including GitHub
+// Enterprise Server
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the REST API endpoint of github.com
+	DefaultBaseURL = "https://api.github.com/"
+
+	// apiVersion is the REST API version requested with every call
+	apiVersion = "2022-11-28"
+
+	// defaultUserAgent identifies the server to GitHub, which rejects requests without one
+	defaultUserAgent = "github-issue-developer-mcp-server"
+
+	// defaultTimeout bounds a single request when no HTTP client is configured
+	defaultTimeout = 30 * time.Second
+)
+
+// Config holds the settings of a Client
+type Config struct {
+	// Token authenticates requests; without one only public data is available
+	// and rate limits are much lower
+	Token string
+
+	// BaseURL is the REST API endpoint. A GitHub Enterprise Server host such as
+	// https://github.example.com resolves to its /api/v3/ endpoint. Defaults to
+	// DefaultBaseURL.
+	BaseURL string
+
+	// HTTPClient sends the requests. Defaults to a client with a 30 second timeout.
+	HTTPClient *http.Client
+
+	// UserAgent is sent with every request
+	UserAgent string
+}
+
+// ConfigFromEnv reads the token from GITHUB_TOKEN or GH_TOKEN and the base URL
+// from GITHUB_API_URL, as set by GitHub Actions
+func ConfigFromEnv() Config {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	return Config{Token: token, BaseURL: os.Getenv("GITHUB_API_URL")}
+}
+
+// Client calls the GitHub REST API
+type Client struct {
+	baseURL    *url.URL
+	token      string
+	httpClient *http.Client
+	userAgent  string
+}
+
+// NewClient creates a client from cfg
+func NewClient(cfg Config) (*Client, error) {
+	baseURL, err := parseBaseURL(cfg.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		baseURL:    baseURL,
+		token:      strings.TrimSpace(cfg.Token),
+		httpClient: cfg.HTTPClient,
+		userAgent:  cfg.UserAgent,
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if c.userAgent == "" {
+		c.userAgent = defaultUserAgent
+	}
+	return c, nil
+}
+
+// parseBaseURL validates the API endpoint and makes sure it ends with a slash so
+// that relative paths resolve below it
+func parseBaseURL(raw string) (*url.URL, error) {
+	if raw == "" {
+		raw = DefaultBaseURL
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub base URL %q: %w", raw, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid GitHub base URL %q: must be an http or https URL", raw)
+	}
+
+	// GitHub Enterprise Server serves the REST API below /api/v3/ on the web host
+	if u.Host != "api.github.com" && strings.Trim(u.Path, "/") == "" {
+		u.Path = "/api/v3/"
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
+
+// BaseURL returns the REST API endpoint requests are sent to
+func (c *Client) BaseURL() string {
+	return c.baseURL.String()
+}
+
+// Authenticated reports whether the client has a token
+func (c *Client) Authenticated() bool {
+	return c.token != ""
+}
+
+// NewRequest creates a request for path, which is relative to the base URL unless
+// it is an absolute URL such as a pagination link. A non-nil body is sent as JSON.
+// The request is authenticated with the token source of ctx, if it has one.
+func (c *Client) NewRequest(ctx context.Context, method, path string, body any) (*http.Request, error) {
+	token, err := c.requestToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, err := c.baseURL.Parse(strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" && u.Host == c.baseURL.Host {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// Response is a GitHub API response with its pagination links
+type Response struct {
+	*http.Response
+
+	// NextPage is the URL of the next page of a list, or empty on the last page
+	NextPage string
+}
+
+// Do sends req and decodes a successful JSON response into v, if v is not nil.
+// Responses outside the 2xx range are returned as an *Error.
+func (c *Client) Do(req *http.Request, v any) (*Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := &Response{Response: resp, NextPage: nextPage(resp.Header.Get("Link"))}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return response, newError(req, resp)
+	}
+
+	if v != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil && err != io.EOF {
+			return response, fmt.Errorf("decoding %s %s: %w", req.Method, req.URL.Path, err)
+		}
+	}
+	return response, nil
+}
+
+// Get fetches path and decodes the response into v
+func (c *Client) Get(ctx context.Context, path string, v any) error {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.Do(req, v)
+	return err
+}
+
+// Post sends body to path and decodes the response into v
+func (c *Client) Post(ctx context.Context, path string, body, v any) error {
+	return c.send(ctx, http.MethodPost, path, body, v)
+}
+
+// Patch sends body to path and decodes the response into v
+func (c *Client) Patch(ctx context.Context, path string, body, v any) error {
+	return c.send(ctx, http.MethodPatch, path, body, v)
+}
+
+// Download fetches path, following redirects to storage, and returns the raw
+// response body. Bodies larger than limit bytes are an error.
+func (c *Client) Download(ctx context.Context, path string, limit int64) ([]byte, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newError(req, resp)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", req.URL.Path, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("downloading %s: larger than %d bytes", req.URL.Path, limit)
+	}
+	return data, nil
+}
+
+// send issues a request with a JSON body
+func (c *Client) send(ctx context.Context, method, path string, body, v any) error {
+	req, err := c.NewRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	_, err = c.Do(req, v)
+	return err
+}
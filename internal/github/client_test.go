@@ -0,0 +1,273 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for a fake GitHub API served by handler
+func newTestClient(t *testing.T, token string, handler http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(Config{Token: token, BaseURL: server.URL + "/api/v3"})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	return client
+}
+
+func TestParseBaseURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"", "https://api.github.com/"},
+		{"https://api.github.com", "https://api.github.com/"},
+		{"https://github.example.com", "https://github.example.com/api/v3/"},
+		{"https://github.example.com/", "https://github.example.com/api/v3/"},
+		{"https://github.example.com/api/v3", "https://github.example.com/api/v3/"},
+		{"http://localhost:8080/github/", "http://localhost:8080/github/"},
+	}
+	for _, tt := range tests {
+		client, err := NewClient(Config{BaseURL: tt.raw})
+		if err != nil {
+			t.Fatalf("NewClient(%q) returned error: %v", tt.raw, err)
+		}
+		if got := client.BaseURL(); got != tt.want {
+			t.Errorf("NewClient(%q): expected base URL %q, got %q", tt.raw, tt.want, got)
+		}
+	}
+
+	for _, raw := range []string{"github.example.com", "ftp://github.example.com", "://bad"} {
+		if _, err := NewClient(Config{BaseURL: raw}); err == nil {
+			t.Errorf("Expected error for base URL %q", raw)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "gh-token")
+	t.Setenv("GITHUB_API_URL", "https://github.example.com/api/v3")
+
+	cfg := ConfigFromEnv()
+	if cfg.Token != "gh-token" || cfg.BaseURL != "https://github.example.com/api/v3" {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	if cfg := ConfigFromEnv(); cfg.Token != "github-token" {
+		t.Errorf("Expected GITHUB_TOKEN to take precedence, got %q", cfg.Token)
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	client := newTestClient(t, "secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/octo/hello/issues/1" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected bearer token, got %q", got)
+		}
+		if got := r.Header.Get("Accept"); got != "application/vnd.github+json" {
+			t.Errorf("Unexpected Accept header %q", got)
+		}
+		if r.Header.Get("X-GitHub-Api-Version") == "" || r.Header.Get("User-Agent") == "" {
+			t.Errorf("Expected API version and user agent headers, got %v", r.Header)
+		}
+		_, _ = fmt.Fprint(w, `{"number": 1, "title": "Crash on start"}`)
+	}))
+
+	var issue struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}
+	if err := client.Get(context.Background(), "/repos/octo/hello/issues/1", &issue); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if issue.Number != 1 || issue.Title != "Crash on start" {
+		t.Errorf("Unexpected issue %+v", issue)
+	}
+}
+
+func TestUnauthenticatedRequest(t *testing.T) {
+	client := newTestClient(t, "", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Expected no Authorization header, got %q", got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if client.Authenticated() {
+		t.Error("Expected client without token to be unauthenticated")
+	}
+	if err := client.Get(context.Background(), "user", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+}
+
+func TestTokenSourceOverridesClientToken(t *testing.T) {
+	var got []string
+	client := newTestClient(t, "server-token", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	ctx := context.Background()
+	for _, ctx := range []context.Context{ctx, WithTokenSource(ctx, StaticToken("alice-token")), WithTokenSource(ctx, StaticToken(""))} {
+		if err := client.Get(ctx, "user", nil); err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+	}
+	want := []string{"Bearer server-token", "Bearer alice-token", ""}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected Authorization headers %q, got %q", want, got)
+	}
+
+	failing := WithTokenSource(ctx, func(context.Context) (string, error) { return "", errors.New("exchange refused") })
+	if err := client.Get(failing, "user", nil); err == nil || err.Error() != "GitHub credentials: exchange refused" {
+		t.Errorf("Expected the token source error, got %v", err)
+	}
+}
+
+func TestPostSendsJSON(t *testing.T) {
+	client := newTestClient(t, "secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON POST, got %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"id": 7}`)
+	}))
+
+	var comment struct {
+		ID int `json:"id"`
+	}
+	err := client.Post(context.Background(), "repos/octo/hello/issues/1/comments", map[string]string{"body": "Done"}, &comment)
+	if err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	if comment.ID != 7 {
+		t.Errorf("Expected comment 7, got %+v", comment)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	client := newTestClient(t, "secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/logs" {
+			http.Redirect(w, r, "/storage/logs", http.StatusFound)
+			return
+		}
+		_, _ = fmt.Fprint(w, "0123456789")
+	}))
+
+	data, err := client.Download(context.Background(), "repos/octo/hello/actions/runs/1/logs", 10)
+	if err != nil {
+		t.Fatalf("Download returned error: %v", err)
+	}
+	if string(data) != "0123456789" {
+		t.Errorf("Expected the redirected body, got %q", data)
+	}
+	if _, err := client.Download(context.Background(), "repos/octo/hello/actions/runs/1/logs", 9); err == nil {
+		t.Error("Expected an error for a body over the limit")
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		headers map[string]string
+		want    error
+	}{
+		{http.StatusUnauthorized, `{"message": "Bad credentials"}`, nil, ErrUnauthorized},
+		{http.StatusForbidden, `{"message": "Resource not accessible by integration"}`, nil, ErrForbidden},
+		{http.StatusNotFound, `{"message": "Not Found"}`, nil, ErrNotFound},
+		{
+			http.StatusUnprocessableEntity,
+			`{"message": "Validation Failed", "errors": [{"resource": "PullRequest", "field": "head", "code": "invalid"}]}`,
+			nil, ErrValidation,
+		},
+		{
+			http.StatusForbidden, `{"message": "API rate limit exceeded"}`,
+			map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+			ErrRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			client := newTestClient(t, "secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = fmt.Fprint(w, tt.body)
+			}))
+
+			err := client.Get(context.Background(), "repos/octo/hello", nil)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, err)
+			}
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Message == "" {
+				t.Errorf("Expected *Error with status %d and message, got %#v", tt.status, err)
+			}
+		})
+	}
+}
+
+func TestSecondaryRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		body    string
+		wait    time.Duration
+	}{
+		{"retry after", map[string]string{"Retry-After": "30", "X-RateLimit-Remaining": "4999",
+			"X-RateLimit-Reset": strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)}, `{"message": "Slow down"}`, 30 * time.Second},
+		{"no headers", nil, `{"message": "You have exceeded a secondary rate limit."}`, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, "secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = fmt.Fprint(w, tt.body)
+			}))
+
+			start := time.Now()
+			err := client.Get(context.Background(), "repos/octo/hello", nil)
+			var apiErr *Error
+			if !errors.Is(err, ErrRateLimited) || !errors.As(err, &apiErr) {
+				t.Fatalf("Expected ErrRateLimited, got %v", err)
+			}
+			if wait := apiErr.RateLimitReset.Sub(start); wait < tt.wait-time.Second || wait > tt.wait+time.Second {
+				t.Errorf("Expected to wait %s, got %s", tt.wait, wait)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	client := newTestClient(t, "secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = fmt.Fprint(w, `{"message": "Validation Failed", "errors": [{"message": "A pull request already exists"}]}`)
+	}))
+
+	err := client.Post(context.Background(), "repos/octo/hello/pulls", map[string]string{}, nil)
+	want := "github: POST " + client.BaseURL() + "repos/octo/hello/pulls: 422 Validation Failed: A pull request already exists"
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,38 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+// TokenSource returns the token a request is authenticated with. An empty token
+// sends the request unauthenticated.
+type TokenSource func(ctx context.Context) (string, error)
+
+// tokenSourceKey is the key of the TokenSource in a context
+type tokenSourceKey struct{}
+
+// WithTokenSource returns a context whose requests are authenticated with the
+// tokens of source rather than the client's own token, so that one client can act
+// for several users
+func WithTokenSource(ctx context.Context, source TokenSource) context.Context {
+	return context.WithValue(ctx, tokenSourceKey{}, source)
+}
+
+// StaticToken returns a source that always returns token
+func StaticToken(token string) TokenSource {
+	return func(context.Context) (string, error) { return token, nil }
+}
+
+// requestToken returns the token for a request made with ctx
+func (c *Client) requestToken(ctx context.Context) (string, error) {
+	source, ok := ctx.Value(tokenSourceKey{}).(TokenSource)
+	if !ok {
+		return c.token, nil
+	}
+	token, err := source(ctx)
+	if err != nil {
+		return "", fmt.Errorf("GitHub credentials: %w", err)
+	}
+	return token, nil
+}
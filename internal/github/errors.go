@@ -0,0 +1,125 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors matched by errors.Is against an *Error with the corresponding status
+var (
+	// ErrUnauthorized is a 401: the token is missing, invalid or expired
+	ErrUnauthorized = errors.New("github: unauthorized")
+	// ErrForbidden is a 403: the token lacks a permission or scope
+	ErrForbidden = errors.New("github: forbidden")
+	// ErrNotFound is a 404, which GitHub also returns for private resources the token cannot see
+	ErrNotFound = errors.New("github: not found")
+	// ErrValidation is a 422: the request was understood but its content was rejected
+	ErrValidation = errors.New("github: validation failed")
+	// ErrRateLimited is a 403 or 429 caused by an exhausted primary or secondary rate limit
+	ErrRateLimited = errors.New("github: rate limit exceeded")
+)
+
+const (
+	// maxErrorBody bounds how much of an error response is read
+	maxErrorBody = 1 << 20
+	// secondaryRateLimitWait is how long to wait when a rate limit says not until when
+	secondaryRateLimitWait = time.Minute
+)
+
+// Error is a response from the GitHub API outside the 2xx range
+type Error struct {
+	Method     string
+	URL        string
+	StatusCode int
+
+	// Message and DocumentationURL come from the response body
+	Message          string       `json:"message"`
+	DocumentationURL string       `json:"documentation_url"`
+	Errors           []FieldError `json:"errors"`
+
+	// RateLimitReset is when the rate limit resets if the request was rate limited
+	RateLimitReset time.Time
+}
+
+// FieldError is a single problem reported with a 422 response
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// String describes the problem, preferring GitHub's own message
+func (e FieldError) String() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", e.Resource, e.Field, e.Code))
+}
+
+// Error describes the failed request and what GitHub reported
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	for i, fieldErr := range e.Errors {
+		if i == 0 {
+			msg += ": "
+		} else {
+			msg += "; "
+		}
+		msg += fieldErr.String()
+	}
+	if !e.RateLimitReset.IsZero() {
+		msg += fmt.Sprintf(" (resets at %s)", e.RateLimitReset.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("github: %s %s: %d %s", e.Method, e.URL, e.StatusCode, msg)
+}
+
+// Is matches the sentinel error for the status code
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return !e.RateLimitReset.IsZero()
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// newError builds an *Error from an unsuccessful response
+func newError(req *http.Request, resp *http.Response) *Error {
+	e := &Error{Method: req.Method, URL: req.URL.Redacted(), StatusCode: resp.StatusCode}
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody)); err == nil && len(data) > 0 {
+		_ = json.Unmarshal(data, e)
+	}
+
+	// Secondary rate limits are 403s with Retry-After while requests remain; GitHub
+	// asks to wait at least a minute when it sends neither header
+	retryAfter := resp.Header.Get("Retry-After")
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && (resp.Header.Get("X-RateLimit-Remaining") == "0" || retryAfter != "" ||
+			strings.Contains(strings.ToLower(e.Message), "secondary rate limit")))
+	if limited {
+		e.RateLimitReset = time.Now().Add(secondaryRateLimitWait)
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			e.RateLimitReset = time.Now().Add(time.Duration(seconds) * time.Second)
+		} else if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			e.RateLimitReset = time.Unix(reset, 0)
+		}
+	}
+	return e
+}
@@ -0,0 +1,83 @@
+// Package githubtest serves a fake GitHub REST API for tests
+package githubtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+)
+
+// Token is the token clients returned by Server.Client authenticate with
+const Token = "test-token"
+
+// Server is a fake GitHub API. Routes are registered with the method and path
+// patterns of http.ServeMux, relative to the API root, e.g.
+// "GET /repos/{owner}/{repo}/issues/{number}". GraphQL calls arrive at
+// "POST /graphql". Unregistered routes return 404.
+type Server struct {
+	*httptest.Server
+	mux *http.ServeMux
+}
+
+// NewServer starts a fake GitHub API that is closed when the test ends
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+
+	s := &Server{mux: http.NewServeMux()}
+	root := http.NewServeMux()
+	root.Handle("/api/v3/", http.StripPrefix("/api/v3", s.mux))
+	root.Handle("/api/graphql", http.StripPrefix("/api", s.mux))
+	s.Server = httptest.NewServer(root)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Client returns a GitHub client for the fake API, authenticated with Token
+func (s *Server) Client(t *testing.T) *github.Client {
+	t.Helper()
+
+	client, err := github.NewClient(github.Config{Token: Token, BaseURL: s.URL})
+	if err != nil {
+		t.Fatalf("github.NewClient returned error: %v", err)
+	}
+	return client
+}
+
+// HandleFunc registers handler for pattern
+func (s *Server) HandleFunc(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// JSON registers a route that responds with status and body encoded as JSON
+func (s *Server) JSON(pattern string, status int, body any) {
+	s.HandleFunc(pattern, func(w http.ResponseWriter, _ *http.Request) {
+		WriteJSON(w, status, body)
+	})
+}
+
+// GraphQL registers handler for GraphQL calls. It receives the query and variables
+// of each call and returns the data of the response.
+func (s *Server) GraphQL(t *testing.T, handler func(query string, variables map[string]any) any) {
+	s.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Decoding GraphQL request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		WriteJSON(w, http.StatusOK, map[string]any{"data": handler(req.Query, req.Variables)})
+	})
+}
+
+// WriteJSON writes body as a JSON response with status
+func WriteJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// graphQLRequest is the body of a GraphQL call
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLResponse is the body GitHub answers a GraphQL call with
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors GraphQLErrors   `json:"errors"`
+}
+
+// GraphQLError is an error GitHub reports in the body of a GraphQL response, which
+// is sent with status 200
+type GraphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Path    []any  `json:"path"`
+}
+
+// GraphQLErrors are the errors of a GraphQL response. They match ErrNotFound,
+// ErrForbidden and ErrRateLimited with errors.Is when GitHub reports that type.
+type GraphQLErrors []GraphQLError
+
+// Error joins the messages of the errors
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Message)
+	}
+	return "github: graphql: " + strings.Join(messages, "; ")
+}
+
+// Is matches the sentinel error for the type of any of the errors
+func (e GraphQLErrors) Is(target error) bool {
+	for _, err := range e {
+		switch {
+		case err.Type == "NOT_FOUND" && target == ErrNotFound,
+			err.Type == "FORBIDDEN" && target == ErrForbidden,
+			err.Type == "RATE_LIMITED" && target == ErrRateLimited,
+			err.Type == "UNPROCESSABLE" && target == ErrValidation:
+			return true
+		}
+	}
+	return false
+}
+
+// graphQLURL returns the GraphQL endpoint: /graphql below the REST endpoint on
+// github.com and /api/graphql next to /api/v3/ on GitHub Enterprise Server
+func (c *Client) graphQLURL() string {
+	u := *c.baseURL
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		u.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+	} else {
+		u.Path += "graphql"
+	}
+	return u.String()
+}
+
+// GraphQL runs a query or mutation with variables and decodes its data into v.
+// Errors in the response body are returned as GraphQLErrors, even if data came
+// back with them.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, v any) error {
+	req, err := c.NewRequest(ctx, http.MethodPost, c.graphQLURL(), graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	var resp graphQLResponse
+	if _, err := c.Do(req, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return resp.Errors
+	}
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return errors.New("github: graphql: response has no data")
+	}
+	if v != nil {
+		if err := json.Unmarshal(resp.Data, v); err != nil {
+			return fmt.Errorf("decoding graphql response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGraphQL(t *testing.T) {
+	client := newTestClient(t, "secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/graphql" {
+			t.Errorf("Expected POST /api/graphql, got %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected the token, got %q", got)
+		}
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Variables["number"] == float64(404) {
+			_, _ = w.Write([]byte(`{"data": {"repository": {"pullRequest": null}}, ` +
+				`"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a PullRequest with the number of 404."}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"viewer": {"login": "octocat"}}}`))
+	}))
+
+	var data struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+	}
+	if err := client.GraphQL(context.Background(), "query { viewer { login } }", nil, &data); err != nil {
+		t.Fatalf("GraphQL returned error: %v", err)
+	}
+	if data.Viewer.Login != "octocat" {
+		t.Errorf("Expected octocat, got %+v", data)
+	}
+
+	err := client.GraphQL(context.Background(), "query", map[string]any{"number": 404}, &data)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err == nil || err.Error() != "github: graphql: Could not resolve to a PullRequest with the number of 404." {
+		t.Errorf("Unexpected error message %v", err)
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                  "https://api.github.com/graphql",
+		"https://github.example.com":        "https://github.example.com/api/graphql",
+		"https://github.example.com/api/v3": "https://github.example.com/api/graphql",
+	}
+	for baseURL, want := range tests {
+		client, err := NewClient(Config{BaseURL: baseURL})
+		if err != nil {
+			t.Fatalf("NewClient returned error: %v", err)
+		}
+		if got := client.graphQLURL(); got != want {
+			t.Errorf("graphQLURL() for %q = %q, expected %q", baseURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// User is a GitHub account
+type User struct {
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url,omitempty"`
+}
+
+// Label is an issue label
+type Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Milestone is the milestone an issue is planned for
+type Milestone struct {
+	Number  int        `json:"number"`
+	Title   string     `json:"title"`
+	State   string     `json:"state"`
+	DueOn   *time.Time `json:"due_on,omitempty"`
+	HTMLURL string     `json:"html_url,omitempty"`
+}
+
+// Repository identifies a repository in API responses
+type Repository struct {
+	FullName string `json:"full_name"`
+	HTMLURL  string `json:"html_url,omitempty"`
+}
+
+// PullRequestRef is present on issues that are pull requests
+type PullRequestRef struct {
+	HTMLURL  string     `json:"html_url"`
+	MergedAt *time.Time `json:"merged_at,omitempty"`
+}
+
+// Issue is a GitHub issue, or a pull request seen through the issues API
+type Issue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	State       string          `json:"state"`
+	StateReason string          `json:"state_reason,omitempty"`
+	HTMLURL     string          `json:"html_url"`
+	User        User            `json:"user"`
+	Labels      []Label         `json:"labels"`
+	Assignees   []User          `json:"assignees"`
+	Milestone   *Milestone      `json:"milestone,omitempty"`
+	Comments    int             `json:"comments"`
+	PullRequest *PullRequestRef `json:"pull_request,omitempty"`
+	Repository  *Repository     `json:"repository,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	ClosedAt    *time.Time      `json:"closed_at,omitempty"`
+}
+
+// Comment is a comment on an issue or pull request conversation
+type Comment struct {
+	ID                int64     `json:"id"`
+	User              User      `json:"user"`
+	Body              string    `json:"body"`
+	HTMLURL           string    `json:"html_url"`
+	AuthorAssociation string    `json:"author_association,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TimelineEvent is an entry of an issue's timeline. Only the fields used to find
+// pull requests that reference the issue are decoded.
+type TimelineEvent struct {
+	Event  string `json:"event"`
+	Source *struct {
+		Type  string `json:"type"`
+		Issue *Issue `json:"issue"`
+	} `json:"source,omitempty"`
+}
+
+// issuePath returns the API path of an issue
+func issuePath(owner, repo string, number int) (string, error) {
+	path, err := repoPath(owner, repo)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/issues/%d", path, number), nil
+}
+
+// GetIssue fetches an issue
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+	path, err := issuePath(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	var issue Issue
+	if err := c.Get(ctx, path, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// ListIssueComments fetches every comment on an issue, oldest first
+func (c *Client) ListIssueComments(ctx context.Context, owner, repo string, number int) ([]Comment, error) {
+	path, err := issuePath(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return List[Comment](ctx, c, path+"/comments", 0)
+}
+
+// ListIssueTimeline fetches every event on an issue's timeline, oldest first
+func (c *Client) ListIssueTimeline(ctx context.Context, owner, repo string, number int) ([]TimelineEvent, error) {
+	path, err := issuePath(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return List[TimelineEvent](ctx, c, path+"/timeline", 0)
+}
+
+// CreateIssueComment adds a comment to an issue
+func (c *Client) CreateIssueComment(ctx context.Context, owner, repo string, number int, body string) (*Comment, error) {
+	path, err := issuePath(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	var comment Comment
+	if err := c.Post(ctx, path+"/comments", map[string]string{"body": body}, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// UpdateIssueComment replaces the body of an issue comment
+func (c *Client) UpdateIssueComment(ctx context.Context, owner, repo string, id int64, body string) (*Comment, error) {
+	path, err := repoPath(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	path = fmt.Sprintf("%s/issues/comments/%d", path, id)
+	var comment Comment
+	if err := c.Patch(ctx, path, map[string]string{"body": body}, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
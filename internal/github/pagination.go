@@ -0,0 +1,100 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// perPage is the largest page size the REST API allows
+const perPage = 100
+
+// List fetches every page of the list at path and returns the items. A limit
+// greater than zero stops after that many items.
+func List[T any](ctx context.Context, c *Client, path string, limit int) ([]T, error) {
+	return listPages(ctx, c, path, limit, func(page *[]T) ([]T, error) { return *page, nil })
+}
+
+// ListField is List for lists the API wraps in an object, such as
+// {"total_count": 2, "check_runs": [...]}, returning the items under field
+func ListField[T any](ctx context.Context, c *Client, path, field string, limit int) ([]T, error) {
+	return listPages(ctx, c, path, limit, func(page *map[string]json.RawMessage) ([]T, error) {
+		var items []T
+		if raw, ok := (*page)[field]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, fmt.Errorf("decoding %s of %s: %w", field, path, err)
+			}
+		}
+		return items, nil
+	})
+}
+
+// listPages fetches the pages of a list, decoding each into a P and taking its items
+func listPages[P, T any](ctx context.Context, c *Client, path string, limit int, items func(*P) ([]T, error)) ([]T, error) {
+	next, err := withPageSize(path, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []T
+	for next != "" {
+		req, err := c.NewRequest(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page P
+		resp, err := c.Do(req, &page)
+		if err != nil {
+			return nil, err
+		}
+		pageItems, err := items(&page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, pageItems...)
+		if limit > 0 && len(all) >= limit {
+			return all[:limit], nil
+		}
+		next = resp.NextPage
+	}
+	return all, nil
+}
+
+// withPageSize adds per_page to path unless it already sets one
+func withPageSize(path string, limit int) (string, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	if query.Get("per_page") == "" {
+		size := perPage
+		if limit > 0 && limit < perPage {
+			size = limit
+		}
+		query.Set("per_page", strconv.Itoa(size))
+		u.RawQuery = query.Encode()
+	}
+	return u.String(), nil
+}
+
+// nextPage returns the rel="next" URL of a Link header
+func nextPage(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+		if !ok {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+	return ""
+}
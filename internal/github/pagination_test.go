@@ -0,0 +1,89 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestListFollowsNextLinks(t *testing.T) {
+	var client *Client
+	client = newTestClient(t, "secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected token on every page, got %q", got)
+		}
+		if r.URL.Query().Get("per_page") == "" {
+			t.Error("Expected per_page to be set")
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			next := client.BaseURL() + "repos/octo/hello/labels?per_page=100&page=2"
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next", <%s>; rel="last"`, next, next))
+			_, _ = fmt.Fprint(w, `[{"name": "bug"}, {"name": "docs"}]`)
+		case "2":
+			_, _ = fmt.Fprint(w, `[{"name": "enhancement"}]`)
+		default:
+			t.Errorf("Unexpected page %q", r.URL.Query().Get("page"))
+		}
+	}))
+
+	type label struct {
+		Name string `json:"name"`
+	}
+	labels, err := List[label](context.Background(), client, "repos/octo/hello/labels", 0)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(labels) != 3 || labels[2].Name != "enhancement" {
+		t.Errorf("Expected labels from both pages, got %+v", labels)
+	}
+
+	labels, err = List[label](context.Background(), client, "repos/octo/hello/labels", 1)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(labels) != 1 {
+		t.Errorf("Expected limit to stop after one label, got %+v", labels)
+	}
+}
+
+func TestListFieldUnwrapsPages(t *testing.T) {
+	var client *Client
+	client = newTestClient(t, "", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, client.BaseURL()+"repos/octo/hello/commits/abc/check-runs?page=2"))
+			_, _ = fmt.Fprint(w, `{"total_count": 2, "check_runs": [{"name": "build"}]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"total_count": 2, "check_runs": [{"name": "lint"}]}`)
+	}))
+
+	type run struct {
+		Name string `json:"name"`
+	}
+	runs, err := ListField[run](context.Background(), client, "repos/octo/hello/commits/abc/check-runs", "check_runs", 0)
+	if err != nil {
+		t.Fatalf("ListField returned error: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Name != "build" || runs[1].Name != "lint" {
+		t.Errorf("Expected runs from both pages, got %+v", runs)
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"", ""},
+		{`<https://api.github.com/x?page=3>; rel="next", <https://api.github.com/x?page=5>; rel="last"`, "https://api.github.com/x?page=3"},
+		{`<https://api.github.com/x?page=1>; rel="prev", <https://api.github.com/x?page=1>; rel="first"`, ""},
+	}
+	for _, tt := range tests {
+		if got := nextPage(tt.link); got != tt.want {
+			t.Errorf("nextPage(%q): expected %q, got %q", tt.link, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PullRequestBranch is the head or base of a pull request
+type PullRequestBranch struct {
+	Label string      `json:"label"`
+	Ref   string      `json:"ref"`
+	SHA   string      `json:"sha"`
+	Repo  *Repository `json:"repo,omitempty"`
+}
+
+// Team is a GitHub team, as requested for review
+type Team struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// PullRequest is a GitHub pull request
+type PullRequest struct {
+	Number             int               `json:"number"`
+	Title              string            `json:"title"`
+	Body               string            `json:"body"`
+	State              string            `json:"state"`
+	Draft              bool              `json:"draft"`
+	Merged             bool              `json:"merged"`
+	HTMLURL            string            `json:"html_url"`
+	User               User              `json:"user"`
+	Head               PullRequestBranch `json:"head"`
+	Base               PullRequestBranch `json:"base"`
+	RequestedReviewers []User            `json:"requested_reviewers"`
+	RequestedTeams     []Team            `json:"requested_teams"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// NewPullRequest is the request body for opening a pull request
+type NewPullRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body"`
+	Draft bool   `json:"draft"`
+}
+
+// pullPath returns the API path of a repository's pull requests, or of one of them
+func pullPath(owner, repo string, number int) (string, error) {
+	path, err := repoPath(owner, repo)
+	if err != nil {
+		return "", err
+	}
+	path += "/pulls"
+	if number > 0 {
+		path += fmt.Sprintf("/%d", number)
+	}
+	return path, nil
+}
+
+// CreatePullRequest opens a pull request
+func (c *Client) CreatePullRequest(ctx context.Context, owner, repo string, pr NewPullRequest) (*PullRequest, error) {
+	path, err := pullPath(owner, repo, 0)
+	if err != nil {
+		return nil, err
+	}
+	var created PullRequest
+	if err := c.Post(ctx, path, pr, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// RequestReviewers asks users and teams, by slug, to review a pull request
+func (c *Client) RequestReviewers(ctx context.Context, owner, repo string, number int, reviewers, teams []string) (*PullRequest, error) {
+	path, err := pullPath(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string][]string{}
+	if len(reviewers) > 0 {
+		body["reviewers"] = reviewers
+	}
+	if len(teams) > 0 {
+		body["team_reviewers"] = teams
+	}
+	var pr PullRequest
+	if err := c.Post(ctx, path+"/requested_reviewers", body, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
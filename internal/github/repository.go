@@ -0,0 +1,62 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidRepository is returned for owners and repository names that are not
+// valid on GitHub, such as ".." or "a/b"
+var ErrInvalidRepository = errors.New("github: invalid repository")
+
+// repositoryName matches owner and repository names; "." and ".." match too but
+// are rejected separately
+var repositoryName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// repoPath returns the API path of a repository. Owners and names are validated
+// rather than escaped: "." and ".." survive escaping and would let a request, and
+// the token sent with it, reach other paths of the host.
+func repoPath(owner, repo string) (string, error) {
+	for _, name := range []string{owner, repo} {
+		if !repositoryName.MatchString(name) || name == "." || name == ".." {
+			return "", fmt.Errorf("%w: %q/%q", ErrInvalidRepository, owner, repo)
+		}
+	}
+	return "repos/" + owner + "/" + repo, nil
+}
+
+// pathSegment escapes value, such as a ref or SHA, as a single segment of an API
+// path, rejecting the values that escaping leaves as "." or ".."
+func pathSegment(value string) (string, error) {
+	if value == "" || value == "." || value == ".." {
+		return "", fmt.Errorf("github: invalid path segment %q", value)
+	}
+	return url.PathEscape(value), nil
+}
+
+// ParseRemoteURL extracts the owner and repository name from a Git remote URL such
+// as https://github.com/owner/repo.git, git@github.com:owner/repo.git or
+// ssh://git@github.example.com/owner/repo
+func ParseRemoteURL(remote string) (owner, repo string, ok bool) {
+	remote = strings.TrimSpace(remote)
+
+	var path string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		path = u.Path
+	} else if _, after, found := strings.Cut(remote, ":"); found && !strings.Contains(remote, "://") {
+		// scp-like syntax: [user@]host:owner/repo
+		path = after
+	} else {
+		return "", "", false
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
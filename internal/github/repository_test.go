@@ -0,0 +1,64 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		owner  string
+		repo   string
+		ok     bool
+	}{
+		{"https://github.com/octo/hello.git", "octo", "hello", true},
+		{"https://github.com/octo/hello", "octo", "hello", true},
+		{"git@github.com:octo/hello.git", "octo", "hello", true},
+		{"ssh://git@github.example.com/octo/hello.git", "octo", "hello", true},
+		{"https://token@github.example.com/octo/hello/", "octo", "hello", true},
+		{"/srv/git/hello.git", "", "", false},
+		{"https://github.com/octo", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		owner, repo, ok := ParseRemoteURL(tt.remote)
+		if owner != tt.owner || repo != tt.repo || ok != tt.ok {
+			t.Errorf("ParseRemoteURL(%q) = %q, %q, %v; expected %q, %q, %v", tt.remote, owner, repo, ok, tt.owner, tt.repo, tt.ok)
+		}
+	}
+}
+
+func TestRepoPathRejectsOtherPaths(t *testing.T) {
+	if path, err := repoPath("octo", "hello.go-2_x"); err != nil || path != "repos/octo/hello.go-2_x" {
+		t.Errorf("repoPath returned %q, %v", path, err)
+	}
+	for _, name := range []string{"", ".", "..", "a/b", "%2e%2e", "a b", "a?b"} {
+		if _, err := repoPath(name, "hello"); !errors.Is(err, ErrInvalidRepository) {
+			t.Errorf("Expected owner %q to be rejected, got %v", name, err)
+		}
+		if _, err := repoPath("octo", name); !errors.Is(err, ErrInvalidRepository) {
+			t.Errorf("Expected repository %q to be rejected, got %v", name, err)
+		}
+	}
+
+	requests := 0
+	client := newTestClient(t, "token", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	ctx := context.Background()
+	if _, err := client.GetIssue(ctx, "..", "..", 1); !errors.Is(err, ErrInvalidRepository) {
+		t.Errorf("Expected GetIssue to reject \"..\", got %v", err)
+	}
+	if _, err := client.UpdateIssueComment(ctx, "octo", "..", 1, "body"); !errors.Is(err, ErrInvalidRepository) {
+		t.Errorf("Expected UpdateIssueComment to reject \"..\", got %v", err)
+	}
+	if _, err := client.GetCommit(ctx, "octo", "hello", ".."); err == nil {
+		t.Error("Expected GetCommit to reject the ref \"..\"")
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+}
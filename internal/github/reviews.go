@@ -0,0 +1,359 @@
+package github
+
+import (
+	"context"
+	"time"
+)
+
+// reviewThreadsQuery lists a page of the review threads of a pull request
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 50, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          ` + reviewThreadFields + `
+        }
+      }
+    }
+  }
+}`
+
+// reviewThreadFields are the fields of a review thread read by every query
+const reviewThreadFields = `id isResolved isOutdated path line startLine originalLine originalStartLine diffSide
+          resolvedBy { login }
+          comments(first: 100) {
+            totalCount
+            nodes { ` + reviewCommentFields + ` }
+          }`
+
+// reviewCommentFields are the fields of a review comment read by every query
+const reviewCommentFields = `id databaseId author { login } body url diffHunk createdAt`
+
+// reviewStatusQuery reads the review decision, latest reviews and pending review
+// requests of a pull request
+const reviewStatusQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      url
+      reviewDecision
+      latestOpinionatedReviews(first: 100) { nodes { ` + reviewFields + ` } }
+      latestReviews(first: 100) { nodes { ` + reviewFields + ` } }
+      reviewRequests(first: 100) {
+        nodes {
+          requestedReviewer {
+            ... on User { login }
+            ... on Bot { login }
+            ... on Mannequin { login }
+            ... on Team { slug organization { login } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// reviewFields are the fields of a review
+const reviewFields = `author { login } state submittedAt url`
+
+// replyMutation replies to a review thread
+const replyMutation = `mutation($thread: ID!, $body: String!) {
+  addPullRequestReviewThreadReply(input: {pullRequestReviewThreadId: $thread, body: $body}) {
+    comment { ` + reviewCommentFields + ` }
+  }
+}`
+
+// resolveMutation marks a review thread as resolved
+const resolveMutation = `mutation($thread: ID!) {
+  resolveReviewThread(input: {threadId: $thread}) {
+    thread {
+      ` + reviewThreadFields + `
+    }
+  }
+}`
+
+// Review states of a pull request review
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+	ReviewDismissed        = "DISMISSED"
+	ReviewPending          = "PENDING"
+)
+
+// ReviewComment is a comment in a review thread
+type ReviewComment struct {
+	ID         string    `json:"id"`
+	DatabaseID int64     `json:"database_id"`
+	Author     string    `json:"author"`
+	Body       string    `json:"body"`
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReviewThread is a thread of review comments on a line or lines of a pull request's diff
+type ReviewThread struct {
+	ID       string `json:"id"`
+	Path     string `json:"path"`
+	DiffSide string `json:"diff_side,omitempty"`
+
+	// Line and StartLine are where the thread is in the current diff; they are zero
+	// when the thread is outdated
+	Line      int `json:"line,omitempty"`
+	StartLine int `json:"start_line,omitempty"`
+
+	// OriginalLine and OriginalStartLine are where the thread was first placed
+	OriginalLine      int `json:"original_line,omitempty"`
+	OriginalStartLine int `json:"original_start_line,omitempty"`
+
+	Resolved      bool            `json:"resolved"`
+	Outdated      bool            `json:"outdated"`
+	ResolvedBy    string          `json:"resolved_by,omitempty"`
+	DiffHunk      string          `json:"diff_hunk,omitempty"`
+	Comments      []ReviewComment `json:"comments"`
+	TotalComments int             `json:"total_comments"`
+}
+
+// PullRequestReview is a review submitted on a pull request
+type PullRequestReview struct {
+	Author      string     `json:"author"`
+	State       string     `json:"state"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+	URL         string     `json:"url,omitempty"`
+}
+
+// ReviewStatus is the state of the reviews of a pull request
+type ReviewStatus struct {
+	URL string `json:"url"`
+
+	// Decision is APPROVED, CHANGES_REQUESTED or REVIEW_REQUIRED when the base branch
+	// requires reviews, and empty otherwise
+	Decision string `json:"decision,omitempty"`
+
+	// Opinionated are the latest approving or change requesting review of each reviewer
+	Opinionated []PullRequestReview `json:"opinionated"`
+
+	// Latest are the latest review of each reviewer, whatever its state
+	Latest []PullRequestReview `json:"latest"`
+
+	// Requested are the users and teams, as org/slug, asked for a review that have not
+	// given one
+	Requested []string `json:"requested"`
+}
+
+// actor is the author of a comment or review, which is null for deleted accounts
+type actor struct {
+	Login string `json:"login"`
+}
+
+// loginOf returns the login of a possibly deleted actor
+func loginOf(a *actor) string {
+	if a == nil {
+		return "ghost"
+	}
+	return a.Login
+}
+
+// reviewCommentNode is a review comment as returned by GraphQL
+type reviewCommentNode struct {
+	ID         string    `json:"id"`
+	DatabaseID int64     `json:"databaseId"`
+	Author     *actor    `json:"author"`
+	Body       string    `json:"body"`
+	URL        string    `json:"url"`
+	DiffHunk   string    `json:"diffHunk"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// comment converts the node
+func (n reviewCommentNode) comment() ReviewComment {
+	return ReviewComment{ID: n.ID, DatabaseID: n.DatabaseID, Author: loginOf(n.Author), Body: n.Body, URL: n.URL, CreatedAt: n.CreatedAt}
+}
+
+// reviewThreadNode is a review thread as returned by GraphQL
+type reviewThreadNode struct {
+	ID                string `json:"id"`
+	IsResolved        bool   `json:"isResolved"`
+	IsOutdated        bool   `json:"isOutdated"`
+	Path              string `json:"path"`
+	Line              *int   `json:"line"`
+	StartLine         *int   `json:"startLine"`
+	OriginalLine      *int   `json:"originalLine"`
+	OriginalStartLine *int   `json:"originalStartLine"`
+	DiffSide          string `json:"diffSide"`
+	ResolvedBy        *actor `json:"resolvedBy"`
+	Comments          struct {
+		TotalCount int                 `json:"totalCount"`
+		Nodes      []reviewCommentNode `json:"nodes"`
+	} `json:"comments"`
+}
+
+// thread converts the node
+func (n reviewThreadNode) thread() ReviewThread {
+	t := ReviewThread{
+		ID:                n.ID,
+		Path:              n.Path,
+		DiffSide:          n.DiffSide,
+		Line:              intValue(n.Line),
+		StartLine:         intValue(n.StartLine),
+		OriginalLine:      intValue(n.OriginalLine),
+		OriginalStartLine: intValue(n.OriginalStartLine),
+		Resolved:          n.IsResolved,
+		Outdated:          n.IsOutdated,
+		Comments:          make([]ReviewComment, 0, len(n.Comments.Nodes)),
+		TotalComments:     n.Comments.TotalCount,
+	}
+	if n.ResolvedBy != nil {
+		t.ResolvedBy = n.ResolvedBy.Login
+	}
+	for _, c := range n.Comments.Nodes {
+		t.Comments = append(t.Comments, c.comment())
+	}
+	if len(n.Comments.Nodes) > 0 {
+		t.DiffHunk = n.Comments.Nodes[0].DiffHunk
+	}
+	return t
+}
+
+// intValue returns the value of a nullable number, or zero
+func intValue(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+// reviewNode is a review as returned by GraphQL
+type reviewNode struct {
+	Author      *actor     `json:"author"`
+	State       string     `json:"state"`
+	SubmittedAt *time.Time `json:"submittedAt"`
+	URL         string     `json:"url"`
+}
+
+// reviews converts review nodes
+func reviews(nodes []reviewNode) []PullRequestReview {
+	out := make([]PullRequestReview, 0, len(nodes))
+	for _, n := range nodes {
+		out = append(out, PullRequestReview{Author: loginOf(n.Author), State: n.State, SubmittedAt: n.SubmittedAt, URL: n.URL})
+	}
+	return out
+}
+
+// pullRequestVariables are the variables that select a pull request
+func pullRequestVariables(owner, repo string, number int) map[string]any {
+	return map[string]any{"owner": owner, "repo": repo, "number": number}
+}
+
+// ListReviewThreads fetches every review thread of a pull request with up to 100
+// comments each
+func (c *Client) ListReviewThreads(ctx context.Context, owner, repo string, number int) ([]ReviewThread, error) {
+	variables := pullRequestVariables(owner, repo, number)
+	threads := []ReviewThread{}
+	for {
+		var data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []reviewThreadNode `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		if err := c.GraphQL(ctx, reviewThreadsQuery, variables, &data); err != nil {
+			return nil, err
+		}
+
+		page := data.Repository.PullRequest.ReviewThreads
+		for _, node := range page.Nodes {
+			threads = append(threads, node.thread())
+		}
+		if !page.PageInfo.HasNextPage {
+			return threads, nil
+		}
+		variables["cursor"] = page.PageInfo.EndCursor
+	}
+}
+
+// GetReviewStatus fetches the review decision, latest reviews and pending review
+// requests of a pull request
+func (c *Client) GetReviewStatus(ctx context.Context, owner, repo string, number int) (*ReviewStatus, error) {
+	var data struct {
+		Repository struct {
+			PullRequest struct {
+				URL                      string `json:"url"`
+				ReviewDecision           string `json:"reviewDecision"`
+				LatestOpinionatedReviews struct {
+					Nodes []reviewNode `json:"nodes"`
+				} `json:"latestOpinionatedReviews"`
+				LatestReviews struct {
+					Nodes []reviewNode `json:"nodes"`
+				} `json:"latestReviews"`
+				ReviewRequests struct {
+					Nodes []struct {
+						RequestedReviewer *struct {
+							Login        string `json:"login"`
+							Slug         string `json:"slug"`
+							Organization actor  `json:"organization"`
+						} `json:"requestedReviewer"`
+					} `json:"nodes"`
+				} `json:"reviewRequests"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	if err := c.GraphQL(ctx, reviewStatusQuery, pullRequestVariables(owner, repo, number), &data); err != nil {
+		return nil, err
+	}
+
+	pr := data.Repository.PullRequest
+	status := &ReviewStatus{
+		URL:         pr.URL,
+		Decision:    pr.ReviewDecision,
+		Opinionated: reviews(pr.LatestOpinionatedReviews.Nodes),
+		Latest:      reviews(pr.LatestReviews.Nodes),
+		Requested:   []string{},
+	}
+	for _, request := range pr.ReviewRequests.Nodes {
+		switch reviewer := request.RequestedReviewer; {
+		case reviewer == nil:
+		case reviewer.Slug != "":
+			status.Requested = append(status.Requested, reviewer.Organization.Login+"/"+reviewer.Slug)
+		default:
+			status.Requested = append(status.Requested, reviewer.Login)
+		}
+	}
+	return status, nil
+}
+
+// ReplyToReviewThread adds a comment to a review thread, given by its node ID
+func (c *Client) ReplyToReviewThread(ctx context.Context, threadID, body string) (*ReviewComment, error) {
+	var data struct {
+		Reply struct {
+			Comment reviewCommentNode `json:"comment"`
+		} `json:"addPullRequestReviewThreadReply"`
+	}
+	if err := c.GraphQL(ctx, replyMutation, map[string]any{"thread": threadID, "body": body}, &data); err != nil {
+		return nil, err
+	}
+	comment := data.Reply.Comment.comment()
+	return &comment, nil
+}
+
+// ResolveReviewThread marks a review thread, given by its node ID, as resolved and
+// returns it
+func (c *Client) ResolveReviewThread(ctx context.Context, threadID string) (*ReviewThread, error) {
+	var data struct {
+		Resolve struct {
+			Thread reviewThreadNode `json:"thread"`
+		} `json:"resolveReviewThread"`
+	}
+	if err := c.GraphQL(ctx, resolveMutation, map[string]any{"thread": threadID}, &data); err != nil {
+		return nil, err
+	}
+	thread := data.Resolve.Thread.thread()
+	return &thread, nil
+}
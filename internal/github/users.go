@@ -0,0 +1,12 @@
+package github
+
+import "context"
+
+// GetAuthenticatedUser fetches the user the request is authenticated as
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.Get(ctx, "user", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
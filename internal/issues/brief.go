@@ -0,0 +1,147 @@
+package issues
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/branches"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+var (
+	// scopeLabelPattern matches labels that name an area of the code, such as "scope: api" or "area/cli"
+	scopeLabelPattern = regexp.MustCompile(`(?i)^(?:scope|area|component|module)\s*[:/]\s*(.+)$`)
+
+	// titleScopePattern matches a Conventional Commits style title such as "fix(api): ..."
+	titleScopePattern = regexp.MustCompile(`^\s*\w+\(([^()]+)\)!?:`)
+
+	// scopeCharacters are replaced when a label is turned into a scope
+	scopeCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// prefixCommitTypes maps branch prefixes to the commit type of the work done on them
+var prefixCommitTypes = map[string]string{
+	"feature":    "feat",
+	"bugfix":     "fix",
+	"hotfix":     "fix",
+	"docs":       "docs",
+	"test":       "test",
+	"chore":      "chore",
+	"release":    "chore",
+	"experiment": "feat",
+}
+
+// Brief is everything an agent needs to start implementing an issue
+type Brief struct {
+	Issue              *Issue      `json:"issue"`
+	AcceptanceCriteria []Criterion `json:"acceptance_criteria"`
+	Branch             string      `json:"branch"`
+	BranchReason       string      `json:"branch_reason"`
+	CommitType         string      `json:"commit_type"`
+	Scope              string      `json:"scope,omitempty"`
+	ScopeReason        string      `json:"scope_reason"`
+	Checklist          []string    `json:"checklist"`
+}
+
+// NewBrief derives the acceptance criteria, branch name, commit type and scope and a
+// step checklist for issue from the conventions policy
+func NewBrief(issue *Issue, pol *policy.Policy) *Brief {
+	suggestion := branches.Suggest(issue.Number, issue.Title, issue.Labels, pol)
+
+	b := &Brief{
+		Issue:              issue,
+		AcceptanceCriteria: AcceptanceCriteria(issue.Body),
+		Branch:             suggestion.Name,
+		BranchReason:       suggestion.Reason,
+		CommitType:         commitType(suggestion.Prefix, pol),
+	}
+	b.Scope, b.ScopeReason = guessScope(issue, pol)
+	b.Checklist = b.checklist(pol)
+	return b
+}
+
+// commitType returns the commit type matching a branch prefix, falling back to the
+// first type of the policy
+func commitType(prefix string, pol *policy.Policy) string {
+	types := pol.CommitTypes()
+	if typ, ok := prefixCommitTypes[prefix]; ok && slices.Contains(types, typ) {
+		return typ
+	}
+	if slices.Contains(types, prefix) {
+		return prefix
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return "feat"
+}
+
+// guessScope picks a commit scope from an area label, a scope in a Conventional
+// Commits style title or an allowed scope named in the title
+func guessScope(issue *Issue, pol *policy.Policy) (string, string) {
+	for _, label := range issue.Labels {
+		if match := scopeLabelPattern.FindStringSubmatch(label); match != nil {
+			if scope := scopeName(match[1]); scope != "" && pol.AllowsScope(scope) {
+				return scope, fmt.Sprintf("from label %q", label)
+			}
+		}
+	}
+
+	if match := titleScopePattern.FindStringSubmatch(issue.Title); match != nil {
+		if scope := scopeName(match[1]); scope != "" && pol.AllowsScope(scope) {
+			return scope, "from the issue title"
+		}
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(issue.Title), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '-')
+	})
+	for _, scope := range pol.Commits.Scopes {
+		if slices.Contains(words, scope) {
+			return scope, fmt.Sprintf("allowed scope %q named in the issue title", scope)
+		}
+	}
+	for _, label := range issue.Labels {
+		if scope := scopeName(label); slices.Contains(pol.Commits.Scopes, scope) {
+			return scope, fmt.Sprintf("allowed scope %q used as a label", scope)
+		}
+	}
+	return "", "no scope found; pick the area of the codebase the change touches"
+}
+
+// scopeName normalizes a label to a commit scope
+func scopeName(text string) string {
+	return strings.Trim(scopeCharacters.ReplaceAllString(strings.ToLower(text), "-"), "-")
+}
+
+// checklist lists the steps of the development workflow applied to the issue
+func (b *Brief) checklist(pol *policy.Policy) []string {
+	number := b.Issue.Number
+	header := b.CommitType
+	if b.Scope != "" {
+		header += "(" + b.Scope + ")"
+	}
+
+	steps := []string{
+		"Check the working tree is clean; commit or stash unrelated changes",
+		fmt.Sprintf("Switch to `%s`, created from an up to date `%s`, never working on %s",
+			b.Branch, pol.DefaultBranch, strings.Join(pol.Branches.Protected, "/")),
+	}
+	if len(b.AcceptanceCriteria) == 0 {
+		steps = append(steps, "Agree on acceptance criteria with the user, then implement them")
+	}
+	for _, criterion := range b.AcceptanceCriteria {
+		if !criterion.Done {
+			steps = append(steps, "Implement: "+criterion.Text)
+		}
+	}
+	return append(steps,
+		fmt.Sprintf("Write tests reaching %d%% coverage and run them locally", pol.CoverageThreshold),
+		fmt.Sprintf("Commit as `%s: <description>` with a `Refs #%d` footer", header, number),
+		"Push the branch and wait for every CI check to pass, fixing failures",
+		fmt.Sprintf("Open a pull request whose description says `Fixes #%d`", number),
+		"Address every review comment and wait for the user to approve the pull request",
+	)
+}
@@ -0,0 +1,75 @@
+package issues
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+func TestNewBrief(t *testing.T) {
+	issue := &Issue{
+		Owner:  "octo",
+		Repo:   "hello",
+		Number: 42,
+		Title:  "Crash when the config file is missing",
+		Body:   "## Acceptance Criteria\n\n- [ ] Starts without a config file\n- [x] Logs a warning\n",
+		Labels: []string{"bug", "area/Config Loader"},
+	}
+
+	brief := NewBrief(issue, policy.Default())
+
+	if brief.Branch != "bugfix/42-crash-config-file-missing" || brief.CommitType != "fix" {
+		t.Errorf("Unexpected branch %q or commit type %q", brief.Branch, brief.CommitType)
+	}
+	if brief.Scope != "config-loader" || !strings.Contains(brief.ScopeReason, "area/Config Loader") {
+		t.Errorf("Unexpected scope %q (%s)", brief.Scope, brief.ScopeReason)
+	}
+	if len(brief.AcceptanceCriteria) != 2 {
+		t.Errorf("Expected two acceptance criteria, got %+v", brief.AcceptanceCriteria)
+	}
+
+	checklist := strings.Join(brief.Checklist, "\n")
+	for _, want := range []string{
+		"Switch to `bugfix/42-crash-config-file-missing`",
+		"Implement: Starts without a config file",
+		"100% coverage",
+		"`fix(config-loader): <description>` with a `Refs #42` footer",
+		"`Fixes #42`",
+	} {
+		if !strings.Contains(checklist, want) {
+			t.Errorf("Expected checklist to contain %q, got:\n%s", want, checklist)
+		}
+	}
+	if strings.Contains(checklist, "Logs a warning") {
+		t.Errorf("Expected completed criteria to be left out of the checklist, got:\n%s", checklist)
+	}
+}
+
+func TestGuessScope(t *testing.T) {
+	restricted := policy.Default()
+	restricted.Commits.Scopes = []string{"api", "cli"}
+
+	tests := []struct {
+		name   string
+		title  string
+		labels []string
+		pol    *policy.Policy
+		want   string
+	}{
+		{"scope label", "Add retries", []string{"scope: API"}, policy.Default(), "api"},
+		{"title scope", "feat(cli): add --verbose", nil, policy.Default(), "cli"},
+		{"disallowed label", "Add retries", []string{"area/web"}, restricted, ""},
+		{"allowed scope in title", "Retry failed API calls", nil, restricted, "api"},
+		{"allowed scope as label", "Add retries", []string{"cli"}, restricted, "cli"},
+		{"nothing", "Add retries", []string{"bug"}, policy.Default(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := guessScope(&Issue{Title: tt.title, Labels: tt.labels}, tt.pol)
+			if got != tt.want {
+				t.Errorf("Expected scope %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package issues
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChecklistMarker identifies the comment holding an issue's acceptance criteria checklist
+const ChecklistMarker = "<!-- acceptance-criteria-checklist -->"
+
+// Checklist renders criteria as a Markdown task list under an "Acceptance Criteria"
+// heading, preceded by ChecklistMarker so that the comment can be found and updated
+func Checklist(criteria []Criterion) string {
+	var sb strings.Builder
+	sb.WriteString(ChecklistMarker + "\n## Acceptance Criteria\n\n")
+	if len(criteria) == 0 {
+		sb.WriteString("_No acceptance criteria found._\n")
+	}
+	done := 0
+	for _, c := range criteria {
+		box := " "
+		if c.Done {
+			box = "x"
+			done++
+		}
+		fmt.Fprintf(&sb, "- [%s] %s\n", box, c.Text)
+	}
+	if len(criteria) > 0 {
+		fmt.Fprintf(&sb, "\n%d of %d done\n", done, len(criteria))
+	}
+	return sb.String()
+}
+
+// ChecklistComment returns the most recent comment by author holding a checklist, if
+// any. Checklists posted by anyone else are ignored so that they cannot tick off
+// criteria.
+func ChecklistComment(comments []Comment, author string) (Comment, bool) {
+	for i := len(comments) - 1; i >= 0; i-- {
+		if author != "" && strings.EqualFold(comments[i].Author, author) && strings.Contains(comments[i].Body, ChecklistMarker) {
+			return comments[i], true
+		}
+	}
+	return Comment{}, false
+}
+
+// MergeProgress marks the criteria that are done in previous, matched by text, as done
+func MergeProgress(criteria, previous []Criterion) []Criterion {
+	done := map[string]bool{}
+	for _, c := range previous {
+		if c.Done {
+			done[normalizeCriterion(c.Text)] = true
+		}
+	}
+
+	merged := make([]Criterion, len(criteria))
+	for i, c := range criteria {
+		c.Done = c.Done || done[normalizeCriterion(c.Text)]
+		merged[i] = c
+	}
+	return merged
+}
+
+// normalizeCriterion folds case and whitespace so that criteria match across edits
+func normalizeCriterion(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
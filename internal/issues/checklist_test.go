@@ -0,0 +1,53 @@
+package issues
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChecklistRoundTrip(t *testing.T) {
+	criteria := []Criterion{{"Retries three times", true}, {"Logs each attempt", false}}
+	text := Checklist(criteria)
+
+	if !strings.HasPrefix(text, ChecklistMarker+"\n") || !strings.Contains(text, "- [x] Retries three times\n- [ ] Logs each attempt\n") {
+		t.Errorf("Unexpected checklist:\n%s", text)
+	}
+	if !strings.Contains(text, "1 of 2 done") {
+		t.Errorf("Expected progress line, got:\n%s", text)
+	}
+	if got := AcceptanceCriteria(text); !reflect.DeepEqual(got, criteria) {
+		t.Errorf("Expected checklist to parse back to %+v, got %+v", criteria, got)
+	}
+}
+
+func TestChecklistComment(t *testing.T) {
+	comments := []Comment{
+		{ID: 1, Author: "agent", Body: ChecklistMarker + "\nold"},
+		{ID: 2, Author: "alice", Body: "LGTM"},
+		{ID: 3, Author: "Agent", Body: ChecklistMarker + "\nnew"},
+		{ID: 4, Author: "mallory", Body: ChecklistMarker + "\n- [x] everything"},
+	}
+	if c, ok := ChecklistComment(comments, "agent"); !ok || c.ID != 3 {
+		t.Errorf("Expected the latest checklist comment of the author, got %+v, %v", c, ok)
+	}
+	if _, ok := ChecklistComment(comments[1:2], "alice"); ok {
+		t.Error("Expected no checklist comment")
+	}
+	if _, ok := ChecklistComment(comments, ""); ok {
+		t.Error("Expected no checklist comment without an author")
+	}
+}
+
+func TestMergeProgress(t *testing.T) {
+	criteria := []Criterion{{"Retries three times", false}, {"Logs each attempt", false}, {"Documents retries", true}}
+	previous := []Criterion{{"retries  THREE times", true}, {"Logs each attempt", false}}
+
+	want := []Criterion{{"Retries three times", true}, {"Logs each attempt", false}, {"Documents retries", true}}
+	if got := MergeProgress(criteria, previous); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if criteria[0].Done {
+		t.Error("Expected MergeProgress not to modify its input")
+	}
+}
@@ -0,0 +1,135 @@
+package issues
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// headingPattern matches a Markdown ATX heading
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+	// labelPattern matches a line used as a heading, such as "**Acceptance criteria**" or "Acceptance criteria:"
+	labelPattern = regexp.MustCompile(`^(?:\*\*|__)?([A-Za-z][A-Za-z ]+?):?(?:\*\*|__)?:?\s*$`)
+
+	// criteriaHeadingPattern matches the titles of sections listing acceptance criteria
+	criteriaHeadingPattern = regexp.MustCompile(`(?i)^(acceptance criteria|definition of done)$`)
+
+	// listItemPattern matches a bullet, numbered or task list item
+	listItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[([ xX])\]\s+)?(.+)$`)
+
+	// taskItemPattern matches a task list item
+	taskItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.+)$`)
+)
+
+// Criterion is a single acceptance criterion of an issue
+type Criterion struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// AcceptanceCriteria extracts the acceptance criteria from an issue body. The items
+// of an "Acceptance Criteria" or "Definition of Done" section, or of a section titled
+// with one of headings, are used if there is one; otherwise every task list item in
+// the body is a criterion. Issue forms give the headings of their criteria fields.
+func AcceptanceCriteria(body string, headings ...string) []Criterion {
+	lines := bodyLines(body)
+
+	if section, ok := criteriaSection(lines, headings); ok {
+		return sectionCriteria(section)
+	}
+
+	criteria := []Criterion{}
+	for _, line := range lines {
+		if match := taskItemPattern.FindStringSubmatch(line); match != nil {
+			criteria = append(criteria, Criterion{Text: strings.TrimSpace(match[2]), Done: match[1] != " "})
+		}
+	}
+	return criteria
+}
+
+// bodyLines splits body into lines, blanking the contents of fenced code blocks so
+// that examples are not mistaken for criteria
+func bodyLines(body string) []string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	fenced := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			lines[i] = ""
+			continue
+		}
+		if fenced {
+			lines[i] = ""
+		}
+	}
+	return lines
+}
+
+// criteriaSection returns the lines below the acceptance criteria heading, up to the
+// next heading of the same or a higher level
+func criteriaSection(lines, headings []string) ([]string, bool) {
+	for i, line := range lines {
+		level, title, ok := heading(line)
+		if !ok || !isCriteriaHeading(title, headings) {
+			continue
+		}
+
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if next, _, ok := heading(lines[j]); ok && next <= level {
+				end = j
+				break
+			}
+		}
+		return lines[i+1 : end], true
+	}
+	return nil, false
+}
+
+// isCriteriaHeading reports whether title introduces acceptance criteria
+func isCriteriaHeading(title string, headings []string) bool {
+	if criteriaHeadingPattern.MatchString(title) {
+		return true
+	}
+	for _, h := range headings {
+		if strings.EqualFold(title, strings.TrimSpace(h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// heading reports whether line is a heading and returns its level and title. Lines
+// that only hold a bold label or a label ending in a colon count as level 6 headings.
+func heading(line string) (int, string, bool) {
+	line = strings.TrimSpace(line)
+	if match := headingPattern.FindStringSubmatch(line); match != nil {
+		return len(match[1]), strings.TrimSpace(strings.Trim(match[2], "*_:")), true
+	}
+	if match := labelPattern.FindStringSubmatch(line); match != nil && line != match[1] {
+		return 6, strings.TrimSpace(match[1]), true
+	}
+	return 0, "", false
+}
+
+// sectionCriteria turns the list items of a section into criteria. A section without
+// list items, such as an issue form text area, contributes one criterion per line.
+func sectionCriteria(section []string) []Criterion {
+	criteria := []Criterion{}
+	var plain []Criterion
+	for _, line := range section {
+		if match := listItemPattern.FindStringSubmatch(line); match != nil {
+			criteria = append(criteria, Criterion{Text: strings.TrimSpace(match[2]), Done: match[1] == "x" || match[1] == "X"})
+			continue
+		}
+		if text := strings.TrimSpace(line); text != "" && text != "_No response_" {
+			plain = append(plain, Criterion{Text: text})
+		}
+	}
+	if len(criteria) == 0 && plain != nil {
+		return plain
+	}
+	return criteria
+}
@@ -0,0 +1,62 @@
+package issues
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAcceptanceCriteria(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Criterion
+	}{
+		{
+			name: "heading section",
+			body: "The server crashes.\n\n## Acceptance Criteria\n\n- [ ] Starts without a config file\n- [x] Logs a warning\n" +
+				"* Documents the default\n\n## Notes\n\n- [ ] Not a criterion",
+			want: []Criterion{{"Starts without a config file", false}, {"Logs a warning", true}, {"Documents the default", false}},
+		},
+		{
+			name: "bold label with numbered items",
+			body: "**Acceptance criteria:**\n1. Returns 404 for unknown ids\n2) Returns 200 otherwise\n",
+			want: []Criterion{{"Returns 404 for unknown ids", false}, {"Returns 200 otherwise", false}},
+		},
+		{
+			name: "definition of done plain lines",
+			body: "### Definition of Done\n\nTests pass\nDocs updated\n",
+			want: []Criterion{{"Tests pass", false}, {"Docs updated", false}},
+		},
+		{
+			name: "task list fallback",
+			body: "Tasks:\n\n- [ ] Add flag\n- [X] Update help\n\n```\n- [ ] not in code\n```\n",
+			want: []Criterion{{"Add flag", false}, {"Update help", true}},
+		},
+		{
+			name: "none",
+			body: "Just a description.\n\n- a plain bullet",
+			want: []Criterion{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AcceptanceCriteria(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAcceptanceCriteriaFormHeadings(t *testing.T) {
+	body := "### Summary\n\nRetry failed uploads\n\n### Done when\n\n- [X] Retries three times\n- [ ] Logs each attempt\n\n" +
+		"### Checks\n\n- [x] I searched existing issues"
+
+	want := []Criterion{{"Retries three times", true}, {"Logs each attempt", false}}
+	if got := AcceptanceCriteria(body, "Done when"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if got := AcceptanceCriteria(body); len(got) != 3 {
+		t.Errorf("Expected every task list item without form headings, got %+v", got)
+	}
+}
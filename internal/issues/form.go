@@ -0,0 +1,114 @@
+package issues
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IssueFormDir is where GitHub looks for issue templates and forms, relative to the
+// repository root
+const IssueFormDir = ".github/ISSUE_TEMPLATE"
+
+// criteriaFieldPattern matches the ids and labels of issue form fields that ask for
+// acceptance criteria
+var criteriaFieldPattern = regexp.MustCompile(`(?i)acceptance|criteria|definition[ _-]of[ _-]done`)
+
+// IssueForm is a GitHub issue form. Only the fields needed to read back the responses
+// are decoded.
+type IssueForm struct {
+	Name string      `yaml:"name"`
+	Body []FormField `yaml:"body"`
+}
+
+// FormField is an element of an issue form body
+type FormField struct {
+	Type       string `yaml:"type"`
+	ID         string `yaml:"id"`
+	Attributes struct {
+		Label string `yaml:"label"`
+	} `yaml:"attributes"`
+}
+
+// ParseIssueForm parses the YAML definition of an issue form
+func ParseIssueForm(data []byte) (*IssueForm, error) {
+	var form IssueForm
+	if err := yaml.Unmarshal(data, &form); err != nil {
+		return nil, err
+	}
+	if form.Name == "" || len(form.Body) == 0 {
+		return nil, errors.New("not an issue form: name and body are required")
+	}
+	return &form, nil
+}
+
+// CriteriaHeadings returns the labels of the form's fields that ask for acceptance
+// criteria. GitHub renders each response under a "### <label>" heading in the issue body.
+func (f *IssueForm) CriteriaHeadings() []string {
+	var headings []string
+	for _, field := range f.Body {
+		label := strings.TrimSpace(field.Attributes.Label)
+		if field.Type == "markdown" || label == "" {
+			continue
+		}
+		if criteriaFieldPattern.MatchString(field.ID) || criteriaFieldPattern.MatchString(label) {
+			headings = append(headings, label)
+		}
+	}
+	return headings
+}
+
+// LoadIssueForms reads the issue forms of the repository at dir. A repository without
+// issue forms has none; Markdown templates and the chooser config are ignored, and
+// files that are not valid forms are skipped and logged.
+func LoadIssueForms(dir string) ([]*IssueForm, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, IssueFormDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var forms []*IssueForm
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if entry.IsDir() || (ext != ".yml" && ext != ".yaml") || strings.TrimSuffix(name, ext) == "config" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, IssueFormDir, name))
+		if err != nil {
+			return nil, err
+		}
+		form, err := ParseIssueForm(bytes.TrimSpace(data))
+		if err != nil {
+			log.Printf("Skipping issue form %s: %v", filepath.ToSlash(filepath.Join(IssueFormDir, name)), err)
+			continue
+		}
+		forms = append(forms, form)
+	}
+	return forms, nil
+}
+
+// FormCriteriaHeadings returns the criteria headings of all forms, once each
+func FormCriteriaHeadings(forms []*IssueForm) []string {
+	var headings []string
+	for _, form := range forms {
+		for _, h := range form.CriteriaHeadings() {
+			if !slices.Contains(headings, h) {
+				headings = append(headings, h)
+			}
+		}
+	}
+	return headings
+}
@@ -0,0 +1,76 @@
+package issues
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+)
+
+const featureForm = `name: Feature request
+description: Suggest an improvement
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for the suggestion!
+  - type: textarea
+    id: summary
+    attributes:
+      label: Summary
+  - type: textarea
+    id: acceptance-criteria
+    attributes:
+      label: Done when
+  - type: checkboxes
+    id: dod
+    attributes:
+      label: Definition of done
+      options:
+        - label: Documented
+`
+
+func TestIssueFormCriteriaHeadings(t *testing.T) {
+	form, err := ParseIssueForm([]byte(featureForm))
+	if err != nil {
+		t.Fatalf("ParseIssueForm returned error: %v", err)
+	}
+	if want := []string{"Done when", "Definition of done"}; !reflect.DeepEqual(form.CriteriaHeadings(), want) {
+		t.Errorf("Expected %v, got %v", want, form.CriteriaHeadings())
+	}
+
+	if _, err := ParseIssueForm([]byte("blank_issues_enabled: false\n")); err == nil {
+		t.Error("Expected error for a file that is not an issue form")
+	}
+}
+
+func TestLoadIssueForms(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	if forms, err := LoadIssueForms(dir); err != nil || forms != nil {
+		t.Fatalf("Expected no forms without a template directory, got %v, %v", forms, err)
+	}
+
+	gittest.WriteFile(t, dir, IssueFormDir+"/feature.yml", featureForm)
+	gittest.WriteFile(t, dir, IssueFormDir+"/bug.yaml", strings.Replace(featureForm, "Done when", "Acceptance criteria", 1))
+	gittest.WriteFile(t, dir, IssueFormDir+"/config.yml", "blank_issues_enabled: false\n")
+	gittest.WriteFile(t, dir, IssueFormDir+"/question.md", "---\nname: Question\n---\n")
+
+	forms, err := LoadIssueForms(dir)
+	if err != nil {
+		t.Fatalf("LoadIssueForms returned error: %v", err)
+	}
+	want := []string{"Acceptance criteria", "Definition of done", "Done when"}
+	if got := FormCriteriaHeadings(forms); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	gittest.WriteFile(t, dir, IssueFormDir+"/broken.yml", "name: [")
+	gittest.WriteFile(t, dir, IssueFormDir+"/labels.yml", "- name: bug\n  color: d73a4a\n")
+	forms, err = LoadIssueForms(dir)
+	if err != nil {
+		t.Fatalf("Expected files that are not forms to be skipped, got %v", err)
+	}
+	if got := FormCriteriaHeadings(forms); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
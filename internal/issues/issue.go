@@ -0,0 +1,149 @@
+// Package issues assembles a GitHub issue with its discussion and linked pull
+// requests into the form agents work from
+package issues
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+)
+
+// Pull request states reported in LinkedPullRequest.State
+const (
+	PullRequestOpen   = "open"
+	PullRequestClosed = "closed"
+	PullRequestMerged = "merged"
+)
+
+// Issue is an issue together with its comment thread and linked pull requests
+type Issue struct {
+	Owner              string              `json:"owner"`
+	Repo               string              `json:"repo"`
+	Number             int                 `json:"number"`
+	Title              string              `json:"title"`
+	State              string              `json:"state"`
+	URL                string              `json:"url"`
+	Author             string              `json:"author"`
+	Body               string              `json:"body"`
+	Labels             []string            `json:"labels"`
+	Assignees          []string            `json:"assignees"`
+	Milestone          *Milestone          `json:"milestone,omitempty"`
+	LinkedPullRequests []LinkedPullRequest `json:"linked_pull_requests"`
+	Comments           []Comment           `json:"comments"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	ClosedAt           *time.Time          `json:"closed_at,omitempty"`
+}
+
+// Milestone is the milestone an issue is planned for
+type Milestone struct {
+	Title string     `json:"title"`
+	State string     `json:"state"`
+	DueOn *time.Time `json:"due_on,omitempty"`
+}
+
+// LinkedPullRequest is a pull request that references the issue
+type LinkedPullRequest struct {
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	State      string `json:"state"`
+	URL        string `json:"url"`
+}
+
+// Comment is a comment in the issue's thread
+type Comment struct {
+	ID        int64     `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Load fetches issue number of owner/repo with its comments and the pull requests
+// that reference it
+func Load(ctx context.Context, client *github.Client, owner, repo string, number int) (*Issue, error) {
+	issue, err := client.GetIssue(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("loading issue %s/%s#%d: %w", owner, repo, number, err)
+	}
+
+	out := &Issue{
+		Owner:              owner,
+		Repo:               repo,
+		Number:             issue.Number,
+		Title:              issue.Title,
+		State:              issue.State,
+		URL:                issue.HTMLURL,
+		Author:             issue.User.Login,
+		Body:               issue.Body,
+		Labels:             []string{},
+		Assignees:          []string{},
+		LinkedPullRequests: []LinkedPullRequest{},
+		Comments:           []Comment{},
+		CreatedAt:          issue.CreatedAt,
+		UpdatedAt:          issue.UpdatedAt,
+		ClosedAt:           issue.ClosedAt,
+	}
+	for _, label := range issue.Labels {
+		out.Labels = append(out.Labels, label.Name)
+	}
+	for _, assignee := range issue.Assignees {
+		out.Assignees = append(out.Assignees, assignee.Login)
+	}
+	if m := issue.Milestone; m != nil {
+		out.Milestone = &Milestone{Title: m.Title, State: m.State, DueOn: m.DueOn}
+	}
+
+	if issue.Comments > 0 {
+		comments, err := client.ListIssueComments(ctx, owner, repo, number)
+		if err != nil {
+			return nil, fmt.Errorf("loading comments of %s/%s#%d: %w", owner, repo, number, err)
+		}
+		for _, c := range comments {
+			out.Comments = append(out.Comments, Comment{ID: c.ID, Author: c.User.Login, Body: c.Body, URL: c.HTMLURL, CreatedAt: c.CreatedAt})
+		}
+	}
+
+	events, err := client.ListIssueTimeline(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("loading timeline of %s/%s#%d: %w", owner, repo, number, err)
+	}
+	out.LinkedPullRequests = linkedPullRequests(owner, repo, events)
+	return out, nil
+}
+
+// linkedPullRequests returns the pull requests that cross-reference the issue, once each
+func linkedPullRequests(owner, repo string, events []github.TimelineEvent) []LinkedPullRequest {
+	links := []LinkedPullRequest{}
+	seen := map[string]bool{}
+	for _, event := range events {
+		if event.Event != "cross-referenced" || event.Source == nil || event.Source.Issue == nil {
+			continue
+		}
+		pr := event.Source.Issue
+		if pr.PullRequest == nil || seen[pr.HTMLURL] {
+			continue
+		}
+		seen[pr.HTMLURL] = true
+
+		repository := owner + "/" + repo
+		if pr.Repository != nil && pr.Repository.FullName != "" {
+			repository = pr.Repository.FullName
+		}
+		state := pr.State
+		if pr.PullRequest.MergedAt != nil {
+			state = PullRequestMerged
+		}
+		links = append(links, LinkedPullRequest{
+			Repository: repository,
+			Number:     pr.Number,
+			Title:      pr.Title,
+			State:      state,
+			URL:        pr.HTMLURL,
+		})
+	}
+	return links
+}
@@ -0,0 +1,123 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+)
+
+// serveIssue registers issue 42 of octo/hello with a comment and a linked pull request
+func serveIssue(s *githubtest.Server) {
+	s.JSON("GET /repos/octo/hello/issues/42", http.StatusOK, map[string]any{
+		"number":     42,
+		"title":      "Crash when the config file is missing",
+		"body":       "Steps to reproduce:\n\n1. Delete config.yml\n2. Start the server",
+		"state":      "open",
+		"html_url":   "https://github.com/octo/hello/issues/42",
+		"user":       map[string]any{"login": "alice"},
+		"labels":     []map[string]any{{"name": "bug"}, {"name": "config"}},
+		"assignees":  []map[string]any{{"login": "bob"}},
+		"milestone":  map[string]any{"number": 3, "title": "v1.2", "state": "open", "due_on": "2026-11-01T07:00:00Z"},
+		"comments":   1,
+		"created_at": "2026-10-01T09:30:00Z",
+		"updated_at": "2026-10-02T10:00:00Z",
+	})
+	s.JSON("GET /repos/octo/hello/issues/42/comments", http.StatusOK, []map[string]any{{
+		"id":         1,
+		"user":       map[string]any{"login": "bob"},
+		"body":       "I can reproduce this on Linux.",
+		"html_url":   "https://github.com/octo/hello/issues/42#issuecomment-1",
+		"created_at": "2026-10-01T12:00:00Z",
+	}})
+	pr := map[string]any{
+		"number":       57,
+		"title":        "Fix crash without config",
+		"state":        "closed",
+		"html_url":     "https://github.com/octo/hello/pull/57",
+		"pull_request": map[string]any{"html_url": "https://github.com/octo/hello/pull/57", "merged_at": "2026-10-03T08:00:00Z"},
+		"repository":   map[string]any{"full_name": "octo/hello"},
+	}
+	s.JSON("GET /repos/octo/hello/issues/42/timeline", http.StatusOK, []map[string]any{
+		{"event": "labeled"},
+		{"event": "cross-referenced", "source": map[string]any{"type": "issue", "issue": pr}},
+		{"event": "cross-referenced", "source": map[string]any{"type": "issue", "issue": pr}},
+		{"event": "cross-referenced", "source": map[string]any{"type": "issue", "issue": map[string]any{"number": 9, "title": "Related issue"}}},
+	})
+}
+
+func TestLoad(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveIssue(s)
+
+	issue, err := Load(context.Background(), s.Client(t), "octo", "hello", 42)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if issue.Title != "Crash when the config file is missing" || issue.Author != "alice" || issue.State != "open" {
+		t.Errorf("Unexpected issue %+v", issue)
+	}
+	if strings.Join(issue.Labels, ",") != "bug,config" || strings.Join(issue.Assignees, ",") != "bob" {
+		t.Errorf("Unexpected labels %v or assignees %v", issue.Labels, issue.Assignees)
+	}
+	if issue.Milestone == nil || issue.Milestone.Title != "v1.2" {
+		t.Errorf("Unexpected milestone %+v", issue.Milestone)
+	}
+	if len(issue.Comments) != 1 || issue.Comments[0].Author != "bob" {
+		t.Errorf("Unexpected comments %+v", issue.Comments)
+	}
+	want := LinkedPullRequest{Repository: "octo/hello", Number: 57, Title: "Fix crash without config", State: PullRequestMerged,
+		URL: "https://github.com/octo/hello/pull/57"}
+	if len(issue.LinkedPullRequests) != 1 || issue.LinkedPullRequests[0] != want {
+		t.Errorf("Expected one merged pull request, got %+v", issue.LinkedPullRequests)
+	}
+}
+
+func TestLoadNotFound(t *testing.T) {
+	s := githubtest.NewServer(t)
+
+	_, err := Load(context.Background(), s.Client(t), "octo", "hello", 404)
+	if !errors.Is(err, github.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "octo/hello#404") {
+		t.Errorf("Expected error to name the issue, got %v", err)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveIssue(s)
+
+	issue, err := Load(context.Background(), s.Client(t), "octo", "hello", 42)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	markdown := issue.Markdown()
+	for _, want := range []string{
+		"# Crash when the config file is missing (#42)\n",
+		"- **Labels:** bug, config\n",
+		"- **Assignees:** @bob\n",
+		"- **Milestone:** v1.2 (due 2026-11-01)\n",
+		"## Description\n\nSteps to reproduce:",
+		"- octo/hello#57 Fix crash without config (merged) https://github.com/octo/hello/pull/57\n",
+		"## Comments (1)\n\n### @bob, 2026-10-01 12:00 UTC\n\nI can reproduce this on Linux.\n",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Expected Markdown to contain %q, got:\n%s", want, markdown)
+		}
+	}
+
+	empty := (&Issue{Owner: "octo", Repo: "hello", Number: 1, Title: "Empty", State: "open"}).Markdown()
+	for _, want := range []string{"- **Labels:** none\n", "_No description provided._", "_No comments._"} {
+		if !strings.Contains(empty, want) {
+			t.Errorf("Expected Markdown to contain %q, got:\n%s", want, empty)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package issues
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeFormat is how dates are shown in Markdown
+const timeFormat = "2006-01-02 15:04 UTC"
+
+// Markdown renders the issue for an agent to read: a summary of its metadata, the
+// description, linked pull requests and the comment thread
+func (i *Issue) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s (#%d)\n\n", i.Title, i.Number)
+
+	fmt.Fprintf(&sb, "- **Repository:** %s/%s\n", i.Owner, i.Repo)
+	fmt.Fprintf(&sb, "- **State:** %s\n", i.State)
+	if i.Author != "" {
+		fmt.Fprintf(&sb, "- **Author:** @%s\n", i.Author)
+	}
+	fmt.Fprintf(&sb, "- **Labels:** %s\n", listOrNone(i.Labels, ""))
+	fmt.Fprintf(&sb, "- **Assignees:** %s\n", listOrNone(i.Assignees, "@"))
+	if m := i.Milestone; m != nil {
+		milestone := m.Title
+		if m.DueOn != nil {
+			milestone += fmt.Sprintf(" (due %s)", m.DueOn.UTC().Format(time.DateOnly))
+		}
+		fmt.Fprintf(&sb, "- **Milestone:** %s\n", milestone)
+	}
+	if i.URL != "" {
+		fmt.Fprintf(&sb, "- **URL:** %s\n", i.URL)
+	}
+
+	sb.WriteString("\n## Description\n\n")
+	if body := strings.TrimSpace(i.Body); body != "" {
+		sb.WriteString(body + "\n")
+	} else {
+		sb.WriteString("_No description provided._\n")
+	}
+
+	if len(i.LinkedPullRequests) > 0 {
+		sb.WriteString("\n## Linked Pull Requests\n\n")
+		for _, pr := range i.LinkedPullRequests {
+			fmt.Fprintf(&sb, "- %s#%d %s (%s) %s\n", pr.Repository, pr.Number, pr.Title, pr.State, pr.URL)
+		}
+	}
+
+	fmt.Fprintf(&sb, "\n## Comments (%d)\n", len(i.Comments))
+	if len(i.Comments) == 0 {
+		sb.WriteString("\n_No comments._\n")
+	}
+	for _, c := range i.Comments {
+		fmt.Fprintf(&sb, "\n### @%s, %s\n\n%s\n", c.Author, c.CreatedAt.UTC().Format(timeFormat), strings.TrimSpace(c.Body))
+	}
+	return sb.String()
+}
+
+// listOrNone joins items with commas, each with prefix, or returns "none"
+func listOrNone(items []string, prefix string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	prefixed := make([]string, len(items))
+	for i, item := range items {
+		prefixed[i] = prefix + item
+	}
+	return strings.Join(prefixed, ", ")
+}
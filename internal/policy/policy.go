@@ -0,0 +1,213 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepositoryPolicyFile is the path, relative to a repository root, of the
+// repository-specific conventions policy
+var RepositoryPolicyFile = filepath.Join(".github", "mcp-policy.yml")
+
+// ErrInvalidPolicy is returned when a policy file cannot be parsed or validated
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+// namePattern restricts branch prefixes, commit types and scopes to lowercase words
+var namePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Policy is the single declarative source of the conventions that prompts describe
+// and that validation tools enforce
+type Policy struct {
+	DefaultBranch     string       `yaml:"default_branch" json:"default_branch"`
+	License           string       `yaml:"license" json:"license"`
+	CoverageThreshold int          `yaml:"coverage_threshold" json:"coverage_threshold"`
+	Branches          BranchPolicy `yaml:"branches" json:"branches"`
+	Commits           CommitPolicy `yaml:"commits" json:"commits"`
+}
+
+// BranchPolicy describes how branches are named and which ones are protected
+type BranchPolicy struct {
+	Prefixes  []Convention `yaml:"prefixes" json:"prefixes"`
+	Protected []string     `yaml:"protected" json:"protected"`
+	MaxLength int          `yaml:"max_length" json:"max_length"`
+}
+
+// CommitPolicy describes the Conventional Commits rules for commit messages
+type CommitPolicy struct {
+	Types            []Convention `yaml:"types" json:"types"`
+	Scopes           []string     `yaml:"scopes" json:"scopes,omitempty"`
+	SubjectMaxLength int          `yaml:"subject_max_length" json:"subject_max_length"`
+	BodyWrap         int          `yaml:"body_wrap" json:"body_wrap"`
+}
+
+// Convention is a named branch prefix or commit type with its meaning
+type Convention struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description" json:"description"`
+}
+
+// Default returns the conventions the server ships with
+func Default() *Policy {
+	return &Policy{
+		DefaultBranch:     "main",
+		License:           "MIT",
+		CoverageThreshold: 100,
+		Branches: BranchPolicy{
+			Prefixes: []Convention{
+				{Name: "feature", Description: "New features or enhancements"},
+				{Name: "bugfix", Description: "Bug fixes"},
+				{Name: "hotfix", Description: "Critical fixes for production"},
+				{Name: "release", Description: "Release preparation branches"},
+				{Name: "chore", Description: "Maintenance tasks, refactoring"},
+				{Name: "docs", Description: "Documentation updates"},
+				{Name: "test", Description: "Test-related changes"},
+				{Name: "experiment", Description: "Experimental or proof-of-concept work"},
+			},
+			Protected: []string{"master", "main"},
+			MaxLength: 60,
+		},
+		Commits: CommitPolicy{
+			Types: []Convention{
+				{Name: "feat", Description: "A new feature for the user"},
+				{Name: "fix", Description: "A bug fix for the user"},
+				{Name: "docs", Description: "Documentation changes"},
+				{Name: "style", Description: "Code style changes (formatting, missing semicolons, etc.)"},
+				{Name: "refactor", Description: "Code refactoring without changing functionality"},
+				{Name: "perf", Description: "Performance improvements"},
+				{Name: "test", Description: "Adding or updating tests"},
+				{Name: "chore", Description: "Maintenance tasks, dependency updates"},
+				{Name: "ci", Description: "CI/CD configuration changes"},
+				{Name: "build", Description: "Build system or external dependency changes"},
+				{Name: "revert", Description: "Reverting a previous commit"},
+			},
+			SubjectMaxLength: 50,
+			BodyWrap:         72,
+		},
+	}
+}
+
+// Load returns the default policy overlaid with each of the given policy files in
+// order. Keys present in a later file replace those of earlier ones; lists are
+// replaced as a whole.
+func Load(paths ...string) (*Policy, error) {
+	p := Default()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading policy file: %w", err)
+		}
+		if err := p.overlay(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// overlay decodes a YAML policy document on top of p
+func (p *Policy) overlay(data []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(p); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return nil
+}
+
+// Validate checks that the policy is internally consistent. Errors name the
+// offending key, e.g. "branches.prefixes[2].name".
+func (p *Policy) Validate() error {
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s: %s", ErrInvalidPolicy, key, fmt.Sprintf(format, args...)))
+	}
+
+	if p.DefaultBranch == "" {
+		invalid("default_branch", "must not be empty")
+	}
+	if p.CoverageThreshold < 0 || p.CoverageThreshold > 100 {
+		invalid("coverage_threshold", "must be between 0 and 100, got %d", p.CoverageThreshold)
+	}
+
+	if len(p.Branches.Prefixes) == 0 {
+		invalid("branches.prefixes", "must list at least one prefix")
+	}
+	validateConventions("branches.prefixes", p.Branches.Prefixes, invalid)
+	if p.Branches.MaxLength < 0 {
+		invalid("branches.max_length", "must not be negative")
+	}
+
+	if len(p.Commits.Types) == 0 {
+		invalid("commits.types", "must list at least one type")
+	}
+	validateConventions("commits.types", p.Commits.Types, invalid)
+	for i, scope := range p.Commits.Scopes {
+		if !namePattern.MatchString(scope) {
+			invalid(fmt.Sprintf("commits.scopes[%d]", i), "%q must be lowercase words separated by hyphens", scope)
+		}
+	}
+	if p.Commits.SubjectMaxLength <= 0 {
+		invalid("commits.subject_max_length", "must be positive")
+	}
+	if p.Commits.BodyWrap <= 0 {
+		invalid("commits.body_wrap", "must be positive")
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateConventions checks that conventions have unique, well-formed names
+func validateConventions(key string, conventions []Convention, invalid func(key, format string, args ...any)) {
+	seen := make(map[string]bool, len(conventions))
+	for i, c := range conventions {
+		itemKey := fmt.Sprintf("%s[%d].name", key, i)
+		switch {
+		case !namePattern.MatchString(c.Name):
+			invalid(itemKey, "%q must be lowercase words separated by hyphens", c.Name)
+		case seen[c.Name]:
+			invalid(itemKey, "duplicate %q", c.Name)
+		}
+		seen[c.Name] = true
+	}
+}
+
+// BranchPrefixes returns the names of the allowed branch prefixes
+func (p *Policy) BranchPrefixes() []string {
+	return conventionNames(p.Branches.Prefixes)
+}
+
+// CommitTypes returns the names of the allowed commit types
+func (p *Policy) CommitTypes() []string {
+	return conventionNames(p.Commits.Types)
+}
+
+// IsProtectedBranch reports whether work must not happen directly on branch
+func (p *Policy) IsProtectedBranch(branch string) bool {
+	return branch == p.DefaultBranch || slices.Contains(p.Branches.Protected, branch)
+}
+
+// AllowsScope reports whether scope may be used in a commit message. Any scope is
+// allowed when the policy does not restrict them.
+func (p *Policy) AllowsScope(scope string) bool {
+	return len(p.Commits.Scopes) == 0 || slices.Contains(p.Commits.Scopes, scope)
+}
+
+// conventionNames returns the names of the conventions in order
+func conventionNames(conventions []Convention) []string {
+	names := make([]string, 0, len(conventions))
+	for _, c := range conventions {
+		names = append(names, c.Name)
+	}
+	return names
+}
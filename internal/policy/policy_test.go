@@ -0,0 +1,122 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writePolicyFile writes a policy document to a temporary file and returns its path
+func writePolicyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultIsValid(t *testing.T) {
+	p := Default()
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Default policy is invalid: %v", err)
+	}
+
+	if p.Commits.SubjectMaxLength != 50 || p.Commits.BodyWrap != 72 {
+		t.Errorf("Expected 50/72 commit limits, got %d/%d", p.Commits.SubjectMaxLength, p.Commits.BodyWrap)
+	}
+	if !slices.Contains(p.BranchPrefixes(), "bugfix") {
+		t.Errorf("Expected bugfix prefix, got %v", p.BranchPrefixes())
+	}
+	if !slices.Contains(p.CommitTypes(), "feat") {
+		t.Errorf("Expected feat commit type, got %v", p.CommitTypes())
+	}
+}
+
+func TestLoadOverlaysFilesInOrder(t *testing.T) {
+	user := writePolicyFile(t, "default_branch: main\ncoverage_threshold: 90\ncommits:\n  scopes: [api, auth]\n")
+	repo := writePolicyFile(t, "coverage_threshold: 80\nbranches:\n  protected: [main, release]\n")
+
+	p, err := Load(user, repo)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if p.DefaultBranch != "main" {
+		t.Errorf("Expected default branch from the first file, got %q", p.DefaultBranch)
+	}
+	if p.CoverageThreshold != 80 {
+		t.Errorf("Expected the later file to win, got %d", p.CoverageThreshold)
+	}
+	if !slices.Equal(p.Branches.Protected, []string{"main", "release"}) {
+		t.Errorf("Expected protected list to be replaced, got %v", p.Branches.Protected)
+	}
+	if len(p.Branches.Prefixes) != len(Default().Branches.Prefixes) {
+		t.Errorf("Expected unset keys to keep their defaults, got %v", p.Branches.Prefixes)
+	}
+	if p.Commits.SubjectMaxLength != 50 {
+		t.Errorf("Expected nested defaults to be kept, got %d", p.Commits.SubjectMaxLength)
+	}
+}
+
+func TestLoadRejectsInvalidPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+	}{
+		{"unknown key", "coverage: 90\n", "coverage"},
+		{"coverage out of range", "coverage_threshold: 120\n", "coverage_threshold"},
+		{"bad prefix", "branches:\n  prefixes:\n    - name: Feature\n", "branches.prefixes[0].name"},
+		{"duplicate type", "commits:\n  types:\n    - name: feat\n    - name: feat\n", "commits.types[1].name"},
+		{"bad scope", "commits:\n  scopes: [API]\n", "commits.scopes[0]"},
+		{"no subject length", "commits:\n  subject_max_length: 0\n", "commits.subject_max_length"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writePolicyFile(t, tt.content))
+			if !errors.Is(err, ErrInvalidPolicy) {
+				t.Fatalf("Expected ErrInvalidPolicy, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("Expected error to name %q, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yml")); err == nil {
+		t.Error("Expected error for missing policy file")
+	}
+}
+
+func TestIsProtectedBranch(t *testing.T) {
+	p := Default()
+	p.DefaultBranch = "develop"
+
+	for _, branch := range []string{"develop", "main", "master"} {
+		if !p.IsProtectedBranch(branch) {
+			t.Errorf("Expected %q to be protected", branch)
+		}
+	}
+	if p.IsProtectedBranch("feature/42-login") {
+		t.Error("Expected feature branch not to be protected")
+	}
+}
+
+func TestAllowsScope(t *testing.T) {
+	p := Default()
+	if !p.AllowsScope("anything") {
+		t.Error("Expected any scope to be allowed without a scope list")
+	}
+
+	p.Commits.Scopes = []string{"api"}
+	if !p.AllowsScope("api") || p.AllowsScope("ui") {
+		t.Error("Expected only listed scopes to be allowed")
+	}
+}
@@ -0,0 +1,125 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ArgumentType describes how the value of a prompt argument is validated
+type ArgumentType string
+
+const (
+	// ArgumentString accepts any text value
+	ArgumentString ArgumentType = "string"
+	// ArgumentInteger accepts a positive whole number such as an issue number
+	ArgumentInteger ArgumentType = "integer"
+	// ArgumentPercentage accepts a whole number between 0 and 100
+	ArgumentPercentage ArgumentType = "percentage"
+)
+
+var (
+	// ErrMissingArgument is returned when a required argument is not supplied
+	ErrMissingArgument = errors.New("missing required argument")
+	// ErrInvalidArgument is returned when an argument value does not match its type
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrUnknownArgument is returned when an argument is not declared by the prompt
+	ErrUnknownArgument = errors.New("unknown argument")
+)
+
+// Argument describes a single typed argument accepted by a prompt
+type Argument struct {
+	Name        string       `yaml:"name"`
+	Description string       `yaml:"description"`
+	Type        ArgumentType `yaml:"type"`
+	Required    bool         `yaml:"required"`
+	Default     string       `yaml:"default"`
+}
+
+// MCPArguments converts the prompt arguments into their MCP protocol representation
+func (p Prompt) MCPArguments() []*mcp.PromptArgument {
+	if len(p.Arguments) == 0 {
+		return nil
+	}
+
+	args := make([]*mcp.PromptArgument, 0, len(p.Arguments))
+	for _, arg := range p.Arguments {
+		description := arg.Description
+		if arg.Default != "" {
+			description = fmt.Sprintf("%s (default: %s)", description, arg.Default)
+		}
+		args = append(args, &mcp.PromptArgument{
+			Name:        arg.Name,
+			Description: description,
+			Required:    arg.Required,
+		})
+	}
+	return args
+}
+
+// resolveArguments validates the supplied values against the declared arguments and
+// returns the template data. Every declared argument is present in the result so that
+// templates can test optional values with {{if}}.
+func resolveArguments(declared []Argument, supplied map[string]string) (map[string]any, error) {
+	known := make(map[string]bool, len(declared))
+	for _, arg := range declared {
+		known[arg.Name] = true
+	}
+
+	var unknown []string
+	for name := range supplied {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownArgument, strings.Join(unknown, ", "))
+	}
+
+	data := make(map[string]any, len(declared))
+	for _, arg := range declared {
+		raw := strings.TrimSpace(supplied[arg.Name])
+		if raw == "" {
+			if arg.Required {
+				return nil, fmt.Errorf("%w: %s", ErrMissingArgument, arg.Name)
+			}
+			raw = arg.Default
+		}
+
+		value, err := convertArgument(arg, raw)
+		if err != nil {
+			return nil, err
+		}
+		data[arg.Name] = value
+	}
+	return data, nil
+}
+
+// convertArgument converts a raw value to the Go type matching the argument type
+func convertArgument(arg Argument, raw string) (any, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	switch arg.Type {
+	case ArgumentInteger:
+		n, err := strconv.Atoi(strings.TrimPrefix(raw, "#"))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: %s must be a positive integer, got %q", ErrInvalidArgument, arg.Name, raw)
+		}
+		return n, nil
+	case ArgumentPercentage:
+		n, err := strconv.Atoi(strings.TrimSuffix(raw, "%"))
+		if err != nil || n < 0 || n > 100 {
+			return nil, fmt.Errorf("%w: %s must be a percentage between 0 and 100, got %q", ErrInvalidArgument, arg.Name, raw)
+		}
+		return n, nil
+	default:
+		return raw, nil
+	}
+}
@@ -0,0 +1,117 @@
+package prompts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	issueNumberArgument = Argument{Name: "issue_number", Type: ArgumentInteger}
+	baseBranchArgument  = Argument{Name: "base_branch", Type: ArgumentString, Default: "main"}
+	scopeArgument       = Argument{Name: "scope", Type: ArgumentString}
+
+	coverageThresholdArgument = Argument{Name: "coverage_threshold", Type: ArgumentPercentage, Default: "100"}
+)
+
+func TestResolveArgumentsDefaults(t *testing.T) {
+	declared := []Argument{baseBranchArgument, issueNumberArgument, coverageThresholdArgument}
+
+	data, err := resolveArguments(declared, nil)
+	if err != nil {
+		t.Fatalf("resolveArguments returned error: %v", err)
+	}
+
+	if data["base_branch"] != "main" {
+		t.Errorf("Expected default base_branch 'main', got %v", data["base_branch"])
+	}
+
+	if data["coverage_threshold"] != 100 {
+		t.Errorf("Expected default coverage_threshold 100, got %v", data["coverage_threshold"])
+	}
+
+	value, ok := data["issue_number"]
+	if !ok {
+		t.Fatal("Expected optional argument to be present in template data")
+	}
+	if value != "" {
+		t.Errorf("Expected empty issue_number, got %v", value)
+	}
+}
+
+func TestResolveArgumentsConversion(t *testing.T) {
+	declared := []Argument{issueNumberArgument, coverageThresholdArgument, scopeArgument}
+
+	data, err := resolveArguments(declared, map[string]string{
+		"issue_number":       "#42",
+		"coverage_threshold": "85%",
+		"scope":              " auth ",
+	})
+	if err != nil {
+		t.Fatalf("resolveArguments returned error: %v", err)
+	}
+
+	if data["issue_number"] != 42 {
+		t.Errorf("Expected issue_number 42, got %v", data["issue_number"])
+	}
+	if data["coverage_threshold"] != 85 {
+		t.Errorf("Expected coverage_threshold 85, got %v", data["coverage_threshold"])
+	}
+	if data["scope"] != "auth" {
+		t.Errorf("Expected scope 'auth', got %v", data["scope"])
+	}
+}
+
+func TestResolveArgumentsErrors(t *testing.T) {
+	required := Argument{Name: "repository", Type: ArgumentString, Required: true}
+
+	tests := []struct {
+		name     string
+		declared []Argument
+		supplied map[string]string
+		wantErr  error
+	}{
+		{"missing required", []Argument{required}, nil, ErrMissingArgument},
+		{"blank required", []Argument{required}, map[string]string{"repository": "  "}, ErrMissingArgument},
+		{"non-numeric issue", []Argument{issueNumberArgument}, map[string]string{"issue_number": "abc"}, ErrInvalidArgument},
+		{"zero issue", []Argument{issueNumberArgument}, map[string]string{"issue_number": "0"}, ErrInvalidArgument},
+		{"coverage above 100", []Argument{coverageThresholdArgument}, map[string]string{"coverage_threshold": "120"}, ErrInvalidArgument},
+		{"undeclared argument", []Argument{scopeArgument}, map[string]string{"colour": "blue"}, ErrUnknownArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveArguments(tt.declared, tt.supplied)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMCPArguments(t *testing.T) {
+	prompt := Prompt{
+		Name: "example",
+		Arguments: []Argument{
+			{Name: "repository", Description: "Repository name", Required: true},
+			baseBranchArgument,
+		},
+	}
+
+	args := prompt.MCPArguments()
+	if len(args) != 2 {
+		t.Fatalf("Expected 2 arguments, got %d", len(args))
+	}
+
+	if args[0].Name != "repository" || !args[0].Required {
+		t.Errorf("Expected required 'repository' argument, got %+v", args[0])
+	}
+
+	if !strings.Contains(args[1].Description, "(default: main)") {
+		t.Errorf("Expected default to be advertised in description, got %q", args[1].Description)
+	}
+
+	if (Prompt{}).MCPArguments() != nil {
+		t.Error("Expected nil arguments for prompt without arguments")
+	}
+}
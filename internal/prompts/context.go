@@ -0,0 +1,135 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// ContextBrief is the context holding the implementation brief (issues.Brief) of the
+// GitHub issue named by the prompt's issue, owner and repo arguments
+const ContextBrief = "brief"
+
+// ErrContextUnavailable is returned when a prompt needs context the server cannot provide
+var ErrContextUnavailable = errors.New("prompt context unavailable")
+
+// ContextProvider computes the value of a context from the resolved prompt arguments
+// when a prompt that declares the context is requested
+type ContextProvider func(ctx context.Context, args map[string]any) (any, error)
+
+// contextSpec describes a context that prompts may declare in their front matter
+type contextSpec struct {
+	// arguments must be declared, with these types, by prompts using the context
+	arguments map[string]ArgumentType
+
+	// sample returns a value of the context's type to validate templates against
+	sample func(pol *policy.Policy) any
+}
+
+// contexts are the contexts prompts may declare
+var contexts = map[string]contextSpec{
+	ContextBrief: {
+		arguments: map[string]ArgumentType{"issue": ArgumentInteger, "owner": ArgumentString, "repo": ArgumentString},
+		sample: func(pol *policy.Policy) any {
+			return issues.NewBrief(&issues.Issue{
+				Owner:  "octo",
+				Repo:   "hello",
+				Number: 1,
+				Title:  "Sample issue",
+				Body:   "- [ ] Sample criterion",
+				Labels: []string{"bug"},
+			}, pol)
+		},
+	},
+}
+
+// environment is shared by the prompts of a manager and supplies what they need while
+// rendering: context providers and the other prompts, for include
+type environment struct {
+	mu        sync.RWMutex
+	providers map[string]ContextProvider
+	lookup    func(name string) (Prompt, bool)
+}
+
+// SetContextProvider installs the provider of a context declared by prompts, such as
+// ContextBrief. Prompts declaring a context without a provider fail when requested.
+func (pm *PromptManager) SetContextProvider(name string, provider ContextProvider) {
+	pm.env.mu.Lock()
+	defer pm.env.mu.Unlock()
+	pm.env.providers[name] = provider
+}
+
+// clone returns an environment with the same context providers that looks up other
+// prompts with lookup
+func (e *environment) clone(lookup func(name string) (Prompt, bool)) *environment {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return &environment{providers: maps.Clone(e.providers), lookup: lookup}
+}
+
+// context computes the named context for the resolved arguments
+func (e *environment) context(ctx context.Context, name string, args map[string]any) (any, error) {
+	if e == nil {
+		return nil, fmt.Errorf("%w: %s", ErrContextUnavailable, name)
+	}
+	e.mu.RLock()
+	provider := e.providers[name]
+	e.mu.RUnlock()
+
+	if provider == nil {
+		return nil, fmt.Errorf("%w: %s", ErrContextUnavailable, name)
+	}
+	return provider(ctx, args)
+}
+
+// include returns the template function that renders another prompt with arguments
+// given as name and value pairs, e.g. {{include "development-workflow" "scope" .scope}}.
+// Empty values are left out so that the included prompt's defaults apply, and so are
+// arguments the included prompt does not declare, so that overrides of it may drop
+// arguments. Included prompts cannot include further prompts.
+func (e *environment) include(ctx context.Context) func(name string, pairs ...any) (string, error) {
+	return func(name string, pairs ...any) (string, error) {
+		if e == nil || e.lookup == nil {
+			return "", fmt.Errorf("include %q: no other prompts available", name)
+		}
+		prompt, ok := e.lookup(name)
+		if !ok {
+			return "", fmt.Errorf("include %q: no such prompt", name)
+		}
+		if len(pairs)%2 != 0 {
+			return "", fmt.Errorf("include %q: arguments must be name and value pairs", name)
+		}
+
+		args := map[string]string{}
+		for i := 0; i < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok {
+				return "", fmt.Errorf("include %q: argument name %v is not a string", name, pairs[i])
+			}
+			declared := slices.ContainsFunc(prompt.Arguments, func(arg Argument) bool { return arg.Name == key })
+			if value := fmt.Sprint(pairs[i+1]); declared && value != "" && pairs[i+1] != nil {
+				args[key] = value
+			}
+		}
+
+		result, err := renderPrompt(ctx, prompt, args, false)
+		if err != nil {
+			return "", fmt.Errorf("include %q: %w", name, err)
+		}
+		texts := make([]string, 0, len(result.Messages))
+		for _, message := range result.Messages {
+			if text, ok := message.Content.(*mcp.TextContent); ok {
+				texts = append(texts, text.Text)
+			}
+		}
+		return strings.Join(texts, "\n\n"), nil
+	}
+}
@@ -0,0 +1,99 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// sampleBriefProvider returns the brief of a fixed issue with the number requested
+func sampleBriefProvider(_ context.Context, args map[string]any) (any, error) {
+	return issues.NewBrief(&issues.Issue{
+		Owner:  "octo",
+		Repo:   "hello",
+		Number: args["issue"].(int),
+		Title:  "Crash when the config file is missing",
+		Body:   "## Acceptance Criteria\n\n- [ ] Starts without a config file",
+		State:  "open",
+		Labels: []string{"bug", "area/config"},
+	}, policy.Default()), nil
+}
+
+func TestWorkOnIssuePrompt(t *testing.T) {
+	pm := NewPromptManager()
+	var received map[string]any
+	pm.SetContextProvider(ContextBrief, func(ctx context.Context, args map[string]any) (any, error) {
+		received = args
+		return sampleBriefProvider(ctx, args)
+	})
+
+	result, err := promptHandler(t, pm, "work-on-issue")(context.Background(), nil, &mcp.GetPromptParams{
+		Arguments: map[string]string{"issue": "42", "owner": "octo", "repo": "hello"},
+	})
+	if err != nil {
+		t.Fatalf("work-on-issue handler returned error: %v", err)
+	}
+	if received["issue"] != 42 || received["owner"] != "octo" || received["repo"] != "hello" {
+		t.Errorf("Expected provider to receive the resolved arguments, got %v", received)
+	}
+
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+	for _, want := range []string{
+		"You are implementing octo/hello#42: Crash when the config file is missing",
+		"- [ ] Starts without a config file",
+		"`bugfix/42-crash-config-file-missing` (bugfix/ from label \"bug\")",
+		"Use the `fix` type with the `config` scope",
+		"- [ ] Implement: Starts without a config file",
+		"# Crash when the config file is missing (#42)",
+		"## WORKFLOW RULES",
+		"You are implementing issue #42 in the config area.",
+		"PRE-PROCESSING CHECKS",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected brief to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestWorkOnIssueWithoutProvider(t *testing.T) {
+	pm := NewPromptManager()
+
+	_, err := promptHandler(t, pm, "work-on-issue")(context.Background(), nil, &mcp.GetPromptParams{
+		Arguments: map[string]string{"issue": "42"},
+	})
+	if !errors.Is(err, ErrContextUnavailable) {
+		t.Errorf("Expected ErrContextUnavailable, got %v", err)
+	}
+}
+
+func TestIncludeFollowsOverrides(t *testing.T) {
+	dir := t.TempDir()
+	override := "---\nname: development-workflow\ndescription: Team workflow\narguments:\n  - name: issue_number\n    type: integer\n" +
+		"---\nTeam rules for #{{.issue_number}}.\n"
+	if err := os.WriteFile(filepath.Join(dir, "development-workflow.md"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pm, err := LoadPromptManager(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptManager returned error: %v", err)
+	}
+	pm.SetContextProvider(ContextBrief, sampleBriefProvider)
+
+	result, err := promptHandler(t, pm, "work-on-issue")(context.Background(), nil, &mcp.GetPromptParams{
+		Arguments: map[string]string{"issue": "7"},
+	})
+	if err != nil {
+		t.Fatalf("work-on-issue handler returned error: %v", err)
+	}
+	if text := result.Messages[0].Content.(*mcp.TextContent).Text; !strings.Contains(text, "Team rules for #7.") {
+		t.Errorf("Expected the overridden development-workflow to be included, got:\n%s", text)
+	}
+}
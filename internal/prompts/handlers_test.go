@@ -8,18 +8,28 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// promptHandler returns the handler of the named prompt, failing the test if it does not exist
+func promptHandler(t *testing.T, pm *PromptManager, name string) mcp.PromptHandler {
+	t.Helper()
+	prompt, ok := pm.GetPrompt(name)
+	if !ok {
+		t.Fatalf("Prompt %s not found", name)
+	}
+	return prompt.Handler
+}
+
 func TestGitBestPracticesHandler(t *testing.T) {
 	pm := NewPromptManager()
 	ctx := context.Background()
 	params := &mcp.GetPromptParams{}
 
-	result, err := pm.gitBestPracticesHandler(ctx, nil, params)
+	result, err := promptHandler(t, pm, "git-best-practices")(ctx, nil, params)
 	if err != nil {
-		t.Fatalf("gitBestPracticesHandler returned error: %v", err)
+		t.Fatalf("git-best-practices handler returned error: %v", err)
 	}
 
 	if result == nil {
-		t.Fatal("gitBestPracticesHandler returned nil result")
+		t.Fatal("git-best-practices handler returned nil result")
 	}
 
 	if result.Description == "" {
@@ -65,13 +75,13 @@ func TestGithubWorkflowHandler(t *testing.T) {
 	ctx := context.Background()
 	params := &mcp.GetPromptParams{}
 
-	result, err := pm.githubWorkflowHandler(ctx, nil, params)
+	result, err := promptHandler(t, pm, "github-workflow")(ctx, nil, params)
 	if err != nil {
-		t.Fatalf("githubWorkflowHandler returned error: %v", err)
+		t.Fatalf("github-workflow handler returned error: %v", err)
 	}
 
 	if result == nil {
-		t.Fatal("githubWorkflowHandler returned nil result")
+		t.Fatal("github-workflow handler returned nil result")
 	}
 
 	textContent, ok := result.Messages[0].Content.(*mcp.TextContent)
@@ -99,13 +109,13 @@ func TestCodeReviewGuidelinesHandler(t *testing.T) {
 	ctx := context.Background()
 	params := &mcp.GetPromptParams{}
 
-	result, err := pm.codeReviewGuidelinesHandler(ctx, nil, params)
+	result, err := promptHandler(t, pm, "code-review-guidelines")(ctx, nil, params)
 	if err != nil {
-		t.Fatalf("codeReviewGuidelinesHandler returned error: %v", err)
+		t.Fatalf("code-review-guidelines handler returned error: %v", err)
 	}
 
 	if result == nil {
-		t.Fatal("codeReviewGuidelinesHandler returned nil result")
+		t.Fatal("code-review-guidelines handler returned nil result")
 	}
 
 	textContent, ok := result.Messages[0].Content.(*mcp.TextContent)
@@ -135,13 +145,13 @@ func TestCommitMessageFormatHandler(t *testing.T) {
 	ctx := context.Background()
 	params := &mcp.GetPromptParams{}
 
-	result, err := pm.commitMessageFormatHandler(ctx, nil, params)
+	result, err := promptHandler(t, pm, "commit-message-format")(ctx, nil, params)
 	if err != nil {
-		t.Fatalf("commitMessageFormatHandler returned error: %v", err)
+		t.Fatalf("commit-message-format handler returned error: %v", err)
 	}
 
 	if result == nil {
-		t.Fatal("commitMessageFormatHandler returned nil result")
+		t.Fatal("commit-message-format handler returned nil result")
 	}
 
 	textContent, ok := result.Messages[0].Content.(*mcp.TextContent)
@@ -170,13 +180,13 @@ func TestBranchNamingConventionHandler(t *testing.T) {
 	ctx := context.Background()
 	params := &mcp.GetPromptParams{}
 
-	result, err := pm.branchNamingConventionHandler(ctx, nil, params)
+	result, err := promptHandler(t, pm, "branch-naming-convention")(ctx, nil, params)
 	if err != nil {
-		t.Fatalf("branchNamingConventionHandler returned error: %v", err)
+		t.Fatalf("branch-naming-convention handler returned error: %v", err)
 	}
 
 	if result == nil {
-		t.Fatal("branchNamingConventionHandler returned nil result")
+		t.Fatal("branch-naming-convention handler returned nil result")
 	}
 
 	textContent, ok := result.Messages[0].Content.(*mcp.TextContent)
@@ -206,13 +216,13 @@ func TestDevelopmentWorkflowHandler(t *testing.T) {
 	ctx := context.Background()
 	params := &mcp.GetPromptParams{}
 
-	result, err := pm.developmentWorkflowHandler(ctx, nil, params)
+	result, err := promptHandler(t, pm, "development-workflow")(ctx, nil, params)
 	if err != nil {
-		t.Fatalf("developmentWorkflowHandler returned error: %v", err)
+		t.Fatalf("development-workflow handler returned error: %v", err)
 	}
 
 	if result == nil {
-		t.Fatal("developmentWorkflowHandler returned nil result")
+		t.Fatal("development-workflow handler returned nil result")
 	}
 
 	textContent, ok := result.Messages[0].Content.(*mcp.TextContent)
@@ -243,52 +253,47 @@ func TestDevelopmentWorkflowHandler(t *testing.T) {
 
 func TestAllHandlersReturnValidStructure(t *testing.T) {
 	pm := NewPromptManager()
+	pm.SetContextProvider(ContextBrief, sampleBriefProvider)
 	ctx := context.Background()
-	params := &mcp.GetPromptParams{}
 
-	handlers := []struct {
-		name    string
-		handler mcp.PromptHandler
-	}{
-		{"gitBestPracticesHandler", pm.gitBestPracticesHandler},
-		{"githubWorkflowHandler", pm.githubWorkflowHandler},
-		{"codeReviewGuidelinesHandler", pm.codeReviewGuidelinesHandler},
-		{"commitMessageFormatHandler", pm.commitMessageFormatHandler},
-		{"branchNamingConventionHandler", pm.branchNamingConventionHandler},
-		{"developmentWorkflowHandler", pm.developmentWorkflowHandler},
-	}
-
-	for _, h := range handlers {
-		t.Run(h.name, func(t *testing.T) {
-			result, err := h.handler(ctx, nil, params)
+	for _, prompt := range pm.GetAllPrompts() {
+		t.Run(prompt.Name, func(t *testing.T) {
+			params := &mcp.GetPromptParams{Arguments: map[string]string{}}
+			for _, arg := range prompt.Arguments {
+				if arg.Required {
+					params.Arguments[arg.Name] = "1"
+				}
+			}
+
+			result, err := prompt.Handler(ctx, nil, params)
 			if err != nil {
-				t.Fatalf("%s returned error: %v", h.name, err)
+				t.Fatalf("%s returned error: %v", prompt.Name, err)
 			}
 
 			if result == nil {
-				t.Fatalf("%s returned nil result", h.name)
+				t.Fatalf("%s returned nil result", prompt.Name)
 			}
 
 			if result.Description == "" {
-				t.Errorf("%s: expected non-empty description", h.name)
+				t.Errorf("%s: expected non-empty description", prompt.Name)
 			}
 
 			if len(result.Messages) == 0 {
-				t.Errorf("%s: expected at least one message", h.name)
+				t.Errorf("%s: expected at least one message", prompt.Name)
 			}
 
 			for i, message := range result.Messages {
 				if message.Role == "" {
-					t.Errorf("%s: message %d has empty role", h.name, i)
+					t.Errorf("%s: message %d has empty role", prompt.Name, i)
 				}
 
 				if message.Content == nil {
-					t.Errorf("%s: message %d has nil content", h.name, i)
+					t.Errorf("%s: message %d has nil content", prompt.Name, i)
 				}
 
 				if textContent, ok := message.Content.(*mcp.TextContent); ok {
 					if textContent.Text == "" {
-						t.Errorf("%s: message %d has empty text", h.name, i)
+						t.Errorf("%s: message %d has empty text", prompt.Name, i)
 					}
 				}
 			}
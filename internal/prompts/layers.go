@@ -0,0 +1,167 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Source identifies the layer a prompt definition was loaded from. Layers are
+// merged in the order built-in < user < repository, so a prompt defined in a
+// later layer replaces the prompt with the same name from an earlier one.
+type Source string
+
+const (
+	// SourceBuiltin is the prompt library embedded in the server binary
+	SourceBuiltin Source = "built-in"
+	// SourceUser is the user-global or deployment-wide prompt directory
+	SourceUser Source = "user"
+	// SourceRepository is the prompt directory of the client's repository
+	SourceRepository Source = "repository"
+)
+
+// RepositoryPromptDir is the directory, relative to a repository root, that holds
+// repository-specific prompt overrides
+var RepositoryPromptDir = filepath.Join(".github", "mcp-prompts")
+
+// promptLayer is a single source of prompt files. It remembers the last prompt that
+// was successfully parsed from each file so that a malformed edit does not drop it.
+type promptLayer struct {
+	fsys   fs.FS
+	source Source
+	dir    string
+	files  map[string]Prompt
+
+	// optional layers treat a missing directory as empty instead of an error
+	optional bool
+
+	// loaded is the signature of the files at the time of the last load
+	loaded string
+}
+
+// newPromptLayer creates a layer for the prompt files in the root of fsys.
+// dir is the directory fsys was opened from and is only used to report prompt paths.
+func newPromptLayer(fsys fs.FS, source Source, dir string) *promptLayer {
+	return &promptLayer{fsys: fsys, source: source, dir: dir, files: make(map[string]Prompt)}
+}
+
+// newDirLayer creates a layer for the prompt files in a directory on disk
+func newDirLayer(dir string, source Source, optional bool) *promptLayer {
+	layer := newPromptLayer(os.DirFS(dir), source, dir)
+	layer.optional = optional
+	return layer
+}
+
+// load parses every prompt file in the layer. Files that fail to parse keep their
+// previous good version, if any, and their errors are returned joined together.
+func (l *promptLayer) load() ([]Prompt, error) {
+	l.loaded = l.signature()
+
+	names, err := promptFileNames(l.fsys)
+	if err != nil {
+		if l.optional && errors.Is(err, fs.ErrNotExist) {
+			l.files = make(map[string]Prompt)
+			return nil, nil
+		}
+		return l.sorted(), fmt.Errorf("reading prompt directory: %w", err)
+	}
+
+	var errs []error
+	next := make(map[string]Prompt, len(names))
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		prompt, err := l.loadFile(name, seen)
+		if err != nil {
+			errs = append(errs, err)
+			previous, ok := l.files[name]
+			if !ok || seen[previous.Name] != "" {
+				continue
+			}
+			prompt = previous
+		}
+		next[name] = prompt
+		seen[prompt.Name] = name
+	}
+
+	l.files = next
+	return l.sorted(), errors.Join(errs...)
+}
+
+// loadFile parses a single prompt file, rejecting names already defined by another file
+func (l *promptLayer) loadFile(name string, seen map[string]string) (Prompt, error) {
+	data, err := fs.ReadFile(l.fsys, name)
+	if err != nil {
+		return Prompt{}, fmt.Errorf("reading prompt file %s: %w", name, err)
+	}
+
+	prompt, err := ParsePrompt(data)
+	if err != nil {
+		return Prompt{}, fmt.Errorf("%s: %w", filepath.Join(l.dir, name), err)
+	}
+
+	if other, ok := seen[prompt.Name]; ok {
+		return Prompt{}, fmt.Errorf("%w: %s: prompt %q is already defined in %s", ErrInvalidPromptFile, name, prompt.Name, other)
+	}
+
+	prompt.Source = l.source
+	prompt.Path = filepath.Join(l.dir, name)
+	return prompt, nil
+}
+
+// sorted returns the prompts of the layer ordered by file name
+func (l *promptLayer) sorted() []Prompt {
+	names := make([]string, 0, len(l.files))
+	for name := range l.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	prompts := make([]Prompt, 0, len(names))
+	for _, name := range names {
+		prompts = append(prompts, l.files[name])
+	}
+	return prompts
+}
+
+// signature summarises the names, sizes and modification times of the prompt files
+// in the layer so that pollers can cheaply detect changes
+func (l *promptLayer) signature() string {
+	names, err := promptFileNames(l.fsys)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+
+	var sb strings.Builder
+	for _, name := range names {
+		info, err := fs.Stat(l.fsys, name)
+		if err != nil {
+			fmt.Fprintf(&sb, "%s:missing;", name)
+			continue
+		}
+		fmt.Fprintf(&sb, "%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return sb.String()
+}
+
+// promptFileNames lists the prompt files in the root of fsys in sorted order
+func promptFileNames(fsys fs.FS) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != promptFileExtension {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,142 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+func TestPromptSources(t *testing.T) {
+	userDir := t.TempDir()
+	writePromptFile(t, userDir, "commit-message-format", "Team commit rules", "Use the team template.")
+	writePromptFile(t, userDir, "release-notes", "Release notes", "Summarise changes.")
+
+	repo := t.TempDir()
+	repoDir := filepath.Join(repo, RepositoryPromptDir)
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writePromptFile(t, repoDir, "release-notes", "Repository release notes", "Follow CHANGELOG.md.")
+
+	pm, err := LoadPromptManager(userDir)
+	if err != nil {
+		t.Fatalf("LoadPromptManager returned error: %v", err)
+	}
+
+	changes, err := pm.SetRepository(repo)
+	if err != nil {
+		t.Fatalf("SetRepository returned error: %v", err)
+	}
+	if len(changes.Updated) != 1 || changes.Updated[0].Name != "release-notes" {
+		t.Errorf("Expected release-notes to be updated, got %+v", changes)
+	}
+	if pm.Repository() != repo {
+		t.Errorf("Expected repository %q, got %q", repo, pm.Repository())
+	}
+
+	tests := []struct {
+		name   string
+		source Source
+		path   string
+	}{
+		{"git-best-practices", SourceBuiltin, "git-best-practices.md"},
+		{"commit-message-format", SourceUser, filepath.Join(userDir, "commit-message-format.md")},
+		{"release-notes", SourceRepository, filepath.Join(repoDir, "release-notes.md")},
+	}
+	for _, tt := range tests {
+		prompt, ok := pm.GetPrompt(tt.name)
+		if !ok {
+			t.Fatalf("Prompt %s not found", tt.name)
+		}
+		if prompt.Source != tt.source {
+			t.Errorf("%s: expected source %q, got %q", tt.name, tt.source, prompt.Source)
+		}
+		if prompt.Path != tt.path {
+			t.Errorf("%s: expected path %q, got %q", tt.name, tt.path, prompt.Path)
+		}
+	}
+
+	changes, err = pm.SetRepository("")
+	if err != nil {
+		t.Fatalf("SetRepository returned error: %v", err)
+	}
+	if len(changes.Updated) != 1 {
+		t.Errorf("Expected release-notes to revert to the user version, got %+v", changes)
+	}
+	if prompt, _ := pm.GetPrompt("release-notes"); prompt.Source != SourceUser {
+		t.Errorf("Expected user source after removing repository, got %q", prompt.Source)
+	}
+}
+
+func TestSetRepositoryWithoutPromptDirectory(t *testing.T) {
+	pm := NewPromptManager()
+	repo := t.TempDir()
+
+	changes, err := pm.SetRepository(repo)
+	if err != nil {
+		t.Fatalf("SetRepository returned error: %v", err)
+	}
+	if !changes.Empty() {
+		t.Errorf("Expected no changes for repository without prompts, got %+v", changes)
+	}
+
+	repoDir := filepath.Join(repo, RepositoryPromptDir)
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writePromptFile(t, repoDir, "git-best-practices", "Repository git rules", "Rebase, never merge.")
+
+	if !pm.modified() {
+		t.Fatal("Expected newly created repository prompt directory to be detected")
+	}
+
+	changes, err = pm.Reload()
+	if err != nil {
+		t.Fatalf("Reload returned error: %v", err)
+	}
+	if len(changes.Updated) != 1 || changes.Updated[0].Source != SourceRepository {
+		t.Errorf("Expected git-best-practices to be overridden by the repository, got %+v", changes)
+	}
+
+	if changes, _ := pm.SetRepository(repo); !changes.Empty() {
+		t.Errorf("Expected setting the same repository to be a no-op, got %+v", changes)
+	}
+}
+
+func TestForRepositoryLeavesManagerUnchanged(t *testing.T) {
+	userDir := t.TempDir()
+	writePromptFile(t, userDir, "release-notes", "User release notes", "Summarise changes.")
+	pm, err := LoadPromptManager(userDir)
+	if err != nil {
+		t.Fatalf("LoadPromptManager returned error: %v", err)
+	}
+
+	repo := t.TempDir()
+	repoDir := filepath.Join(repo, RepositoryPromptDir)
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writePromptFile(t, repoDir, "git-best-practices", "Repository git rules", "Rebase, never merge.")
+	p := policy.Default()
+	p.DefaultBranch = "trunk"
+
+	scoped, err := pm.ForRepository(repo, p)
+	if err != nil {
+		t.Fatalf("ForRepository returned error: %v", err)
+	}
+	if scoped.Repository() != repo || scoped.Policy() != p {
+		t.Errorf("Expected the repository and its policy, got %q, %+v", scoped.Repository(), scoped.Policy())
+	}
+	if prompt, _ := scoped.GetPrompt("git-best-practices"); prompt.Source != SourceRepository {
+		t.Errorf("Expected the repository prompt, got %q", prompt.Source)
+	}
+	if prompt, _ := scoped.GetPrompt("release-notes"); prompt.Source != SourceUser {
+		t.Errorf("Expected the user prompt to be kept, got %q", prompt.Source)
+	}
+
+	if prompt, _ := pm.GetPrompt("git-best-practices"); prompt.Source != SourceBuiltin || pm.Repository() != "" || pm.Policy().DefaultBranch == "trunk" {
+		t.Errorf("Expected the original manager to be unchanged, got %q, %q", prompt.Source, pm.Repository())
+	}
+}
@@ -0,0 +1,20 @@
+package prompts
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// library holds the built-in prompt definitions shipped with the server
+//
+//go:embed library/*.md
+var library embed.FS
+
+// DefaultLibrary returns the built-in prompt definitions as a file system
+func DefaultLibrary() fs.FS {
+	sub, err := fs.Sub(library, "library")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
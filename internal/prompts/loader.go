@@ -0,0 +1,211 @@
+package prompts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+	"gopkg.in/yaml.v3"
+)
+
+// promptFileExtension is the extension of prompt definition files
+const promptFileExtension = ".md"
+
+// frontMatterDelimiter separates the YAML front matter from the Markdown body
+const frontMatterDelimiter = "---"
+
+var (
+	// promptNamePattern restricts prompt names to lowercase words separated by hyphens
+	promptNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	// argumentNamePattern restricts argument names to identifiers usable in templates
+	argumentNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ErrInvalidPromptFile is returned when a prompt file cannot be parsed or validated
+var ErrInvalidPromptFile = errors.New("invalid prompt file")
+
+// promptFile is the YAML front matter of a prompt definition file
+type promptFile struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Role        string     `yaml:"role"`
+	Arguments   []Argument `yaml:"arguments"`
+	Context     []string   `yaml:"context"`
+	Messages    []Message  `yaml:"messages"`
+}
+
+// LoadPrompts reads every Markdown prompt definition in the root of fsys.
+// Prompts are returned sorted by file name; duplicate names are an error.
+func LoadPrompts(fsys fs.FS) ([]Prompt, error) {
+	prompts, err := newPromptLayer(fsys, "", "").load()
+	if err != nil {
+		return nil, err
+	}
+	return prompts, nil
+}
+
+// ParsePrompt parses a Markdown prompt definition with YAML front matter.
+// The Markdown body becomes the first message, sent with the front matter role,
+// followed by any messages listed in the front matter.
+func ParsePrompt(data []byte) (Prompt, error) {
+	header, body, err := splitFrontMatter(data)
+	if err != nil {
+		return Prompt{}, err
+	}
+
+	var file promptFile
+	decoder := yaml.NewDecoder(bytes.NewReader(header))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return Prompt{}, fmt.Errorf("%w: front matter: %v", ErrInvalidPromptFile, err)
+	}
+
+	prompt := Prompt{
+		Name:        strings.TrimSpace(file.Name),
+		Description: strings.TrimSpace(file.Description),
+		Arguments:   file.Arguments,
+		Context:     file.Context,
+	}
+
+	for i := range prompt.Arguments {
+		if prompt.Arguments[i].Type == "" {
+			prompt.Arguments[i].Type = ArgumentString
+		}
+	}
+
+	if body = strings.TrimSpace(body); body != "" {
+		role := file.Role
+		if role == "" {
+			role = "user"
+		}
+		prompt.Messages = append(prompt.Messages, Message{Role: role, Content: body})
+	}
+	prompt.Messages = append(prompt.Messages, file.Messages...)
+
+	if err := validatePrompt(prompt); err != nil {
+		return Prompt{}, err
+	}
+	return prompt, nil
+}
+
+// splitFrontMatter separates the YAML header from the Markdown body
+func splitFrontMatter(data []byte) ([]byte, string, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, frontMatterDelimiter+"\n") {
+		return nil, "", fmt.Errorf("%w: missing front matter", ErrInvalidPromptFile)
+	}
+
+	rest := text[len(frontMatterDelimiter)+1:]
+	end := strings.Index(rest, "\n"+frontMatterDelimiter+"\n")
+	switch {
+	case strings.HasPrefix(rest, frontMatterDelimiter+"\n"):
+		return nil, rest[len(frontMatterDelimiter)+1:], nil
+	case end >= 0:
+		return []byte(rest[:end]), rest[end+len(frontMatterDelimiter)+2:], nil
+	case strings.HasSuffix(rest, "\n"+frontMatterDelimiter):
+		return []byte(strings.TrimSuffix(rest, "\n"+frontMatterDelimiter)), "", nil
+	default:
+		return nil, "", fmt.Errorf("%w: unterminated front matter", ErrInvalidPromptFile)
+	}
+}
+
+// validatePrompt checks a parsed prompt for structural errors and verifies that its
+// templates render against sample values for every declared argument, context and the
+// default conventions policy. The argument name "policy" is reserved for the policy
+// itself, and context names cannot be used as argument names.
+func validatePrompt(prompt Prompt) error {
+	if !promptNamePattern.MatchString(prompt.Name) {
+		return fmt.Errorf("%w: name %q must be lowercase words separated by hyphens", ErrInvalidPromptFile, prompt.Name)
+	}
+	if prompt.Description == "" {
+		return fmt.Errorf("%w: prompt %q has no description", ErrInvalidPromptFile, prompt.Name)
+	}
+	if len(prompt.Messages) == 0 {
+		return fmt.Errorf("%w: prompt %q has no message body", ErrInvalidPromptFile, prompt.Name)
+	}
+
+	bound, err := prompt.withPolicy(policy.Default())
+	if err != nil {
+		return fmt.Errorf("%w: prompt %q: %v", ErrInvalidPromptFile, prompt.Name, err)
+	}
+
+	sample := map[string]any{policyKey: bound.policy}
+	for _, arg := range bound.Arguments {
+		if !argumentNamePattern.MatchString(arg.Name) || arg.Name == policyKey {
+			return fmt.Errorf("%w: prompt %q: invalid argument name %q", ErrInvalidPromptFile, prompt.Name, arg.Name)
+		}
+		if _, ok := sample[arg.Name]; ok {
+			return fmt.Errorf("%w: prompt %q: duplicate argument %q", ErrInvalidPromptFile, prompt.Name, arg.Name)
+		}
+		switch arg.Type {
+		case ArgumentString:
+			sample[arg.Name] = "sample"
+		case ArgumentInteger, ArgumentPercentage:
+			sample[arg.Name] = 1
+		default:
+			return fmt.Errorf("%w: prompt %q: argument %q has unknown type %q", ErrInvalidPromptFile, prompt.Name, arg.Name, arg.Type)
+		}
+
+		if _, err := convertArgument(arg, arg.Default); err != nil {
+			return fmt.Errorf("%w: prompt %q: default: %v", ErrInvalidPromptFile, prompt.Name, err)
+		}
+	}
+
+	if err := validateContext(prompt, sample); err != nil {
+		return err
+	}
+
+	for i, message := range prompt.Messages {
+		if message.Role != "user" && message.Role != "assistant" {
+			return fmt.Errorf("%w: prompt %q: message %d has invalid role %q", ErrInvalidPromptFile, prompt.Name, i, message.Role)
+		}
+		if strings.TrimSpace(message.Content) == "" {
+			return fmt.Errorf("%w: prompt %q: message %d is empty", ErrInvalidPromptFile, prompt.Name, i)
+		}
+		if _, err := renderTemplate(prompt.Name, message.Content, sample, template.FuncMap{"include": sampleInclude}); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPromptFile, err)
+		}
+	}
+	return nil
+}
+
+// validateContext checks the contexts a prompt declares and adds sample values for
+// them to sample
+func validateContext(prompt Prompt, sample map[string]any) error {
+	for _, name := range prompt.Context {
+		spec, ok := contexts[name]
+		if !ok {
+			return fmt.Errorf("%w: prompt %q: unknown context %q", ErrInvalidPromptFile, prompt.Name, name)
+		}
+		if _, ok := sample[name]; ok {
+			return fmt.Errorf("%w: prompt %q: context %q is declared twice or clashes with an argument", ErrInvalidPromptFile, prompt.Name, name)
+		}
+
+		for _, argName := range slices.Sorted(maps.Keys(spec.arguments)) {
+			argType := spec.arguments[argName]
+			i := slices.IndexFunc(prompt.Arguments, func(arg Argument) bool { return arg.Name == argName })
+			if i < 0 || prompt.Arguments[i].Type != argType {
+				return fmt.Errorf("%w: prompt %q: context %q needs a %s argument %q",
+					ErrInvalidPromptFile, prompt.Name, name, argType, argName)
+			}
+		}
+		sample[name] = spec.sample(policy.Default())
+	}
+	return nil
+}
+
+// sampleInclude stands in for include while validating, when the other prompts are not known
+func sampleInclude(name string, pairs ...any) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("include %q: arguments must be name and value pairs", name)
+	}
+	return "", nil
+}
@@ -0,0 +1,138 @@
+package prompts
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+const examplePromptFile = `---
+name: release-checklist
+description: Checklist for preparing a release
+arguments:
+  - name: version
+    description: Version being released
+    required: true
+  - name: issue_number
+    type: integer
+messages:
+  - role: assistant
+    content: I will prepare release {{.version}}.
+---
+Prepare release {{.version}}{{if .issue_number}} tracked in #{{.issue_number}}{{end}}.
+`
+
+func TestParsePrompt(t *testing.T) {
+	prompt, err := ParsePrompt([]byte(examplePromptFile))
+	if err != nil {
+		t.Fatalf("ParsePrompt returned error: %v", err)
+	}
+
+	if prompt.Name != "release-checklist" {
+		t.Errorf("Expected name 'release-checklist', got %q", prompt.Name)
+	}
+
+	if prompt.Description != "Checklist for preparing a release" {
+		t.Errorf("Unexpected description %q", prompt.Description)
+	}
+
+	if len(prompt.Arguments) != 2 {
+		t.Fatalf("Expected 2 arguments, got %d", len(prompt.Arguments))
+	}
+	if prompt.Arguments[0].Type != ArgumentString || !prompt.Arguments[0].Required {
+		t.Errorf("Expected required string argument, got %+v", prompt.Arguments[0])
+	}
+
+	if len(prompt.Messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(prompt.Messages))
+	}
+	if prompt.Messages[0].Role != "user" {
+		t.Errorf("Expected body message role 'user', got %q", prompt.Messages[0].Role)
+	}
+	if prompt.Messages[1].Role != "assistant" {
+		t.Errorf("Expected front matter message role 'assistant', got %q", prompt.Messages[1].Role)
+	}
+}
+
+func TestParsePromptErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"missing front matter", "Just some text"},
+		{"unterminated front matter", "---\nname: example\n"},
+		{"malformed yaml", "---\nname: [example\n---\nBody"},
+		{"unknown field", "---\nname: example\ndescription: Example\ncolour: blue\n---\nBody"},
+		{"missing name", "---\ndescription: Example\n---\nBody"},
+		{"invalid name", "---\nname: Example Prompt\ndescription: Example\n---\nBody"},
+		{"missing description", "---\nname: example\n---\nBody"},
+		{"no messages", "---\nname: example\ndescription: Example\n---\n"},
+		{"invalid role", "---\nname: example\ndescription: Example\nrole: system\n---\nBody"},
+		{"unknown argument type", "---\nname: example\ndescription: Example\narguments:\n  - name: count\n    type: float\n---\nBody"},
+		{"invalid default", "---\nname: example\ndescription: Example\narguments:\n  - name: count\n    type: integer\n    default: many\n---\nBody"},
+		{"duplicate argument", "---\nname: example\ndescription: Example\narguments:\n  - name: scope\n  - name: scope\n---\nBody"},
+		{"undeclared template reference", "---\nname: example\ndescription: Example\n---\nHello {{.who}}"},
+		{"undeclared reference in branch", "---\nname: example\ndescription: Example\narguments:\n  - name: scope\n---\n{{if .scope}}{{.who}}{{end}}"},
+		{"template syntax error", "---\nname: example\ndescription: Example\n---\n{{if}}"},
+		{"reserved argument name", "---\nname: example\ndescription: Example\narguments:\n  - name: policy\n---\nBody"},
+		{"unknown policy field", "---\nname: example\ndescription: Example\n---\n{{.policy.Colour}}"},
+		{"invalid policy default", "---\nname: example\ndescription: Example\narguments:\n  - name: base\n    default: \"{{.policy.Trunk}}\"\n---\nBody"},
+		{"unknown context", "---\nname: example\ndescription: Example\ncontext: [weather]\n---\nBody"},
+		{"context without arguments", "---\nname: example\ndescription: Example\ncontext: [brief]\n---\n{{.brief.Branch}}"},
+		{"context argument of wrong type", "---\nname: example\ndescription: Example\ncontext: [brief]\narguments:\n  - name: issue\n" +
+			"  - name: owner\n  - name: repo\n---\nBody"},
+		{"context clashes with argument", "---\nname: example\ndescription: Example\ncontext: [brief]\narguments:\n  - name: brief\n---\nBody"},
+		{"unknown context field", "---\nname: example\ndescription: Example\ncontext: [brief]\narguments:\n  - name: issue\n    type: integer\n" +
+			"  - name: owner\n  - name: repo\n---\n{{.brief.Colour}}"},
+		{"odd include arguments", "---\nname: example\ndescription: Example\n---\n{{include \"git-best-practices\" \"scope\"}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePrompt([]byte(tt.data))
+			if !errors.Is(err, ErrInvalidPromptFile) {
+				t.Fatalf("Expected ErrInvalidPromptFile, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadPrompts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"release-checklist.md": {Data: []byte(examplePromptFile)},
+		"README.txt":           {Data: []byte("not a prompt")},
+		"drafts/ignored.md":    {Data: []byte("not loaded from subdirectories")},
+	}
+
+	prompts, err := LoadPrompts(fsys)
+	if err != nil {
+		t.Fatalf("LoadPrompts returned error: %v", err)
+	}
+
+	if len(prompts) != 1 || prompts[0].Name != "release-checklist" {
+		t.Fatalf("Expected only release-checklist to be loaded, got %+v", prompts)
+	}
+}
+
+func TestLoadPromptsDuplicateName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.md": {Data: []byte(examplePromptFile)},
+		"b.md": {Data: []byte(examplePromptFile)},
+	}
+
+	_, err := LoadPrompts(fsys)
+	if !errors.Is(err, ErrInvalidPromptFile) {
+		t.Fatalf("Expected ErrInvalidPromptFile for duplicate prompt names, got %v", err)
+	}
+}
+
+func TestDefaultLibraryIsValid(t *testing.T) {
+	prompts, err := LoadPrompts(DefaultLibrary())
+	if err != nil {
+		t.Fatalf("built-in prompt library is invalid: %v", err)
+	}
+
+	if len(prompts) == 0 {
+		t.Fatal("Expected built-in prompt library to contain prompts")
+	}
+}
@@ -1,65 +1,186 @@
 package prompts
 
 import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
 )
 
-// Prompt represents a single prompt with its handler
+// Prompt represents a single prompt with its arguments, message templates and handler
 type Prompt struct {
 	Name        string
 	Description string
+	Arguments   []Argument
+	Messages    []Message
 	Handler     mcp.PromptHandler
+
+	// Context names the data computed when the prompt is requested, such as ContextBrief
+	Context []string
+
+	// Source is the layer the prompt was loaded from and Path the file that defined it
+	Source Source
+	Path   string
+
+	// policy is the conventions policy the prompt renders against
+	policy *policy.Policy
+
+	// env supplies context providers and other prompts while rendering
+	env *environment
 }
 
-// PromptManager manages all available prompts
+// Message is a single templated prompt message
+type Message struct {
+	Role    string `yaml:"role"`
+	Content string `yaml:"content"`
+}
+
+// PromptManager manages all available prompts. It is safe for concurrent use;
+// Reload atomically replaces the prompt set.
 type PromptManager struct {
-	prompts []Prompt
+	mu         sync.RWMutex
+	layers     []*promptLayer
+	repository string
+	policy     *policy.Policy
+	env        *environment
+	prompts    []Prompt
 }
 
-// NewPromptManager creates a new prompt manager with all available prompts
+// NewPromptManager creates a new prompt manager with the built-in prompt library
 func NewPromptManager() *PromptManager {
-	pm := &PromptManager{}
-	pm.initializePrompts()
+	pm, err := newPromptManager(newPromptLayer(DefaultLibrary(), SourceBuiltin, ""))
+	if err != nil {
+		panic(fmt.Sprintf("loading built-in prompt library: %v", err))
+	}
 	return pm
 }
 
+// LoadPromptManager creates a prompt manager with the built-in prompt library
+// overridden by the prompt definitions found in the user prompt directory dir
+func LoadPromptManager(dir string) (*PromptManager, error) {
+	return newPromptManager(
+		newPromptLayer(DefaultLibrary(), SourceBuiltin, ""),
+		newDirLayer(dir, SourceUser, false),
+	)
+}
+
+// newPromptManager creates a prompt manager from layers in increasing precedence
+func newPromptManager(layers ...*promptLayer) (*PromptManager, error) {
+	pm := &PromptManager{layers: layers, policy: policy.Default()}
+	pm.env = &environment{providers: map[string]ContextProvider{}, lookup: pm.GetPrompt}
+	if _, err := pm.Reload(); err != nil {
+		return nil, err
+	}
+	return pm, nil
+}
+
+// SetRepository layers the prompt overrides of the repository rooted at root over the
+// built-in and user prompts, replacing any previously configured repository. An empty
+// root removes the repository layer. A repository without a prompt directory
+// contributes no prompts, and one may be created later while the server is running.
+func (pm *PromptManager) SetRepository(root string) (PromptChanges, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if root == pm.repository {
+		return PromptChanges{}, nil
+	}
+
+	layers := slices.DeleteFunc(pm.layers, func(l *promptLayer) bool {
+		return l.source == SourceRepository
+	})
+	if root != "" {
+		layers = append(layers, newDirLayer(filepath.Join(root, RepositoryPromptDir), SourceRepository, true))
+	}
+	pm.layers = layers
+	pm.repository = root
+
+	return pm.reload()
+}
+
+// ForRepository returns a new manager with the built-in and user prompts of pm, the
+// prompt overrides of the repository rooted at root and the conventions policy p. pm
+// is left unchanged, so sessions working in different repositories each get their
+// own manager. Files that fail to parse are left out and their errors returned
+// alongside the manager.
+func (pm *PromptManager) ForRepository(root string, p *policy.Policy) (*PromptManager, error) {
+	pm.mu.RLock()
+	var layers []*promptLayer
+	for _, l := range pm.layers {
+		if l.source == SourceRepository {
+			continue
+		}
+		layer := newPromptLayer(l.fsys, l.source, l.dir)
+		layer.optional = l.optional
+		layers = append(layers, layer)
+	}
+	pm.mu.RUnlock()
+	if root != "" {
+		layers = append(layers, newDirLayer(filepath.Join(root, RepositoryPromptDir), SourceRepository, true))
+	}
+
+	next := &PromptManager{layers: layers, repository: root, policy: p}
+	next.env = pm.env.clone(next.GetPrompt)
+	_, err := next.Reload()
+	return next, err
+}
+
+// Repository returns the root of the repository whose prompts are layered over the
+// built-in and user prompts, or an empty string if there is none
+func (pm *PromptManager) Repository() string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.repository
+}
+
+// SetPolicy replaces the conventions policy that prompts render against and argument
+// defaults are resolved from. Prompts whose definition depends on the policy are
+// reported as updated.
+func (pm *PromptManager) SetPolicy(p *policy.Policy) (PromptChanges, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.policy = p
+	return pm.reload()
+}
+
+// Policy returns the conventions policy prompts are rendered against
+func (pm *PromptManager) Policy() *policy.Policy {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.policy
+}
+
 // GetAllPrompts returns all registered prompts
 func (pm *PromptManager) GetAllPrompts() []Prompt {
-	return pm.prompts
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return slices.Clone(pm.prompts)
+}
+
+// GetPrompt returns the prompt with the given name
+func (pm *PromptManager) GetPrompt(name string) (Prompt, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	for _, prompt := range pm.prompts {
+		if prompt.Name == name {
+			return prompt, true
+		}
+	}
+	return Prompt{}, false
 }
 
-// initializePrompts sets up all available prompts
-func (pm *PromptManager) initializePrompts() {
-	pm.prompts = []Prompt{
-		{
-			Name:        "git-best-practices",
-			Description: "Provides Git best practices for development workflow",
-			Handler:     pm.gitBestPracticesHandler,
-		},
-		{
-			Name:        "github-workflow",
-			Description: "Provides GitHub workflow best practices",
-			Handler:     pm.githubWorkflowHandler,
-		},
-		{
-			Name:        "code-review-guidelines",
-			Description: "Provides code review guidelines and best practices",
-			Handler:     pm.codeReviewGuidelinesHandler,
-		},
-		{
-			Name:        "commit-message-format",
-			Description: "Provides commit message formatting guidelines",
-			Handler:     pm.commitMessageFormatHandler,
-		},
-		{
-			Name:        "branch-naming-convention",
-			Description: "Provides branch naming convention guidelines",
-			Handler:     pm.branchNamingConventionHandler,
-		},
-		{
-			Name:        "development-workflow",
-			Description: "Comprehensive development workflow with Git, GitHub, and CI/CD best practices",
-			Handler:     pm.developmentWorkflowHandler,
-		},
+// mergePrompt adds a prompt to the set, replacing any prompt with the same name in place
+func mergePrompt(prompts []Prompt, prompt Prompt) []Prompt {
+	prompt.Handler = newPromptHandler(prompt)
+	for i := range prompts {
+		if prompts[i].Name == prompt.Name {
+			prompts[i] = prompt
+			return prompts
+		}
 	}
+	return append(prompts, prompt)
 }
@@ -1,7 +1,13 @@
 package prompts
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func TestNewPromptManager(t *testing.T) {
@@ -23,6 +29,7 @@ func TestNewPromptManager(t *testing.T) {
 		"commit-message-format",
 		"branch-naming-convention",
 		"development-workflow",
+		"work-on-issue",
 	}
 
 	if len(prompts) != len(expectedPrompts) {
@@ -73,6 +80,7 @@ func TestPromptDescriptions(t *testing.T) {
 		"commit-message-format":    "Provides commit message formatting guidelines",
 		"branch-naming-convention": "Provides branch naming convention guidelines",
 		"development-workflow":     "Comprehensive development workflow with Git, GitHub, and CI/CD best practices",
+		"work-on-issue":            "Implementation brief for a GitHub issue with its acceptance criteria, branch name, commit scope and step checklist",
 	}
 
 	for _, prompt := range prompts {
@@ -88,3 +96,60 @@ func TestPromptDescriptions(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadPromptManagerOverridesBuiltins(t *testing.T) {
+	dir := t.TempDir()
+
+	override := "---\nname: commit-message-format\ndescription: Team commit rules\n---\nUse the team commit template.\n"
+	if err := os.WriteFile(filepath.Join(dir, "commit-message-format.md"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	extra := "---\nname: release-notes\ndescription: Release note guidelines\n---\nSummarise user-facing changes.\n"
+	if err := os.WriteFile(filepath.Join(dir, "release-notes.md"), []byte(extra), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pm, err := LoadPromptManager(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptManager returned error: %v", err)
+	}
+
+	if len(pm.GetAllPrompts()) != 8 {
+		t.Fatalf("Expected 8 prompts, got %d", len(pm.GetAllPrompts()))
+	}
+
+	prompt, ok := pm.GetPrompt("commit-message-format")
+	if !ok {
+		t.Fatal("Expected commit-message-format prompt")
+	}
+	if prompt.Description != "Team commit rules" {
+		t.Errorf("Expected overridden description, got %q", prompt.Description)
+	}
+
+	result, err := prompt.Handler(context.Background(), nil, &mcp.GetPromptParams{})
+	if err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+	if text := result.Messages[0].Content.(*mcp.TextContent).Text; text != "Use the team commit template." {
+		t.Errorf("Expected overridden text, got %q", text)
+	}
+
+	if _, ok := pm.GetPrompt("release-notes"); !ok {
+		t.Error("Expected additional release-notes prompt")
+	}
+}
+
+func TestLoadPromptManagerInvalidDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.md"), []byte("no front matter"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadPromptManager(dir); !errors.Is(err, ErrInvalidPromptFile) {
+		t.Fatalf("Expected ErrInvalidPromptFile, got %v", err)
+	}
+
+	if _, err := LoadPromptManager(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("Expected error for missing prompt directory")
+	}
+}
@@ -0,0 +1,107 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// policyKey is the template data key under which the conventions policy is exposed,
+// so prompts can render rules such as {{.policy.Commits.SubjectMaxLength}}
+const policyKey = "policy"
+
+// templateFuncs are the helper functions available to prompt templates
+var templateFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"include": noInclude,
+}
+
+// renderPrompt validates the supplied arguments against the prompt definition, computes
+// the contexts the prompt declares and renders each message template over them.
+// allowInclude controls whether the templates may include other prompts.
+func renderPrompt(ctx context.Context, prompt Prompt, supplied map[string]string, allowInclude bool) (*mcp.GetPromptResult, error) {
+	data, err := resolveArguments(prompt.Arguments, supplied)
+	if err != nil {
+		return nil, fmt.Errorf("prompt %q: %w", prompt.Name, err)
+	}
+	data[policyKey] = prompt.policy
+
+	for _, name := range prompt.Context {
+		value, err := prompt.env.context(ctx, name, data)
+		if err != nil {
+			return nil, fmt.Errorf("prompt %q: %w", prompt.Name, err)
+		}
+		data[name] = value
+	}
+
+	funcs := template.FuncMap{"include": noInclude}
+	if allowInclude {
+		funcs["include"] = prompt.env.include(ctx)
+	}
+
+	result := &mcp.GetPromptResult{
+		Description: prompt.Description,
+		Messages:    make([]*mcp.PromptMessage, 0, len(prompt.Messages)),
+	}
+	for _, message := range prompt.Messages {
+		rendered, err := renderTemplate(prompt.Name, message.Content, data, funcs)
+		if err != nil {
+			return nil, err
+		}
+		result.Messages = append(result.Messages, &mcp.PromptMessage{
+			Role:    mcp.Role(message.Role),
+			Content: &mcp.TextContent{Text: rendered},
+		})
+	}
+	return result, nil
+}
+
+// noInclude stands in for include where other prompts cannot be included
+func noInclude(name string, _ ...any) (string, error) {
+	return "", fmt.Errorf("include %q: prompts cannot be included here", name)
+}
+
+// renderTemplate executes text as a template, failing on references to undeclared
+// arguments. funcs add to or replace the standard template functions.
+func renderTemplate(name, text string, data map[string]any, funcs ...template.FuncMap) (string, error) {
+	tmpl := template.New(name).Option("missingkey=error").Funcs(templateFuncs)
+	for _, f := range funcs {
+		tmpl = tmpl.Funcs(f)
+	}
+	tmpl, err := tmpl.Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing template for prompt %q: %w", name, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("rendering prompt %q: %w", name, err)
+	}
+	return sb.String(), nil
+}
+
+// withPolicy binds the prompt to a conventions policy. Argument defaults may be
+// templates over the policy, such as "{{.policy.DefaultBranch}}", and are resolved here.
+func (p Prompt) withPolicy(pol *policy.Policy) (Prompt, error) {
+	data := map[string]any{policyKey: pol}
+
+	arguments := make([]Argument, len(p.Arguments))
+	for i, arg := range p.Arguments {
+		if strings.Contains(arg.Default, "{{") {
+			value, err := renderTemplate(p.Name, arg.Default, data)
+			if err != nil {
+				return Prompt{}, fmt.Errorf("default of argument %q: %w", arg.Name, err)
+			}
+			arg.Default = value
+		}
+		arguments[i] = arg
+	}
+
+	p.Arguments = arguments
+	p.policy = pol
+	return p, nil
+}
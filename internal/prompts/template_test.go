@@ -0,0 +1,158 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	text, err := renderTemplate("example", "Fix #{{.issue_number}}{{if .scope}} in {{.scope}}{{end}}", map[string]any{
+		"issue_number": 7,
+		"scope":        "",
+	})
+	if err != nil {
+		t.Fatalf("renderTemplate returned error: %v", err)
+	}
+
+	if text != "Fix #7" {
+		t.Errorf("Expected 'Fix #7', got %q", text)
+	}
+}
+
+func TestRenderTemplateUndeclaredArgument(t *testing.T) {
+	_, err := renderTemplate("example", "{{.undeclared}}", map[string]any{})
+	if err == nil {
+		t.Fatal("Expected error for reference to undeclared argument")
+	}
+}
+
+func TestRenderTemplateParseError(t *testing.T) {
+	_, err := renderTemplate("example", "{{if .scope}", map[string]any{})
+	if err == nil {
+		t.Fatal("Expected error for malformed template")
+	}
+}
+
+func TestRenderPromptWithArguments(t *testing.T) {
+	pm := NewPromptManager()
+	params := &mcp.GetPromptParams{
+		Name: "development-workflow",
+		Arguments: map[string]string{
+			"issue_number":       "123",
+			"base_branch":        "develop",
+			"scope":              "api",
+			"language":           "Go",
+			"coverage_threshold": "80",
+		},
+	}
+
+	result, err := promptHandler(t, pm, "development-workflow")(context.Background(), nil, params)
+	if err != nil {
+		t.Fatalf("development-workflow handler returned error: %v", err)
+	}
+
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+
+	expected := []string{
+		"You are implementing issue #123 in the api area.",
+		"git pull origin develop",
+		"git checkout -b feature/123-branch-name",
+		"80% coverage",
+		"type(api): descriptive message",
+		"Follow Go best practices",
+	}
+	for _, keyword := range expected {
+		if !strings.Contains(text, keyword) {
+			t.Errorf("Expected text to contain %q", keyword)
+		}
+	}
+
+	if strings.Contains(text, "100% coverage") {
+		t.Error("Expected coverage threshold argument to replace the default")
+	}
+}
+
+func TestRenderPromptInvalidArgument(t *testing.T) {
+	pm := NewPromptManager()
+	params := &mcp.GetPromptParams{
+		Arguments: map[string]string{"coverage_threshold": "lots"},
+	}
+
+	_, err := promptHandler(t, pm, "development-workflow")(context.Background(), nil, params)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestRenderPromptNilParams(t *testing.T) {
+	pm := NewPromptManager()
+
+	result, err := promptHandler(t, pm, "git-best-practices")(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("git-best-practices handler returned error: %v", err)
+	}
+
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+	if !strings.Contains(text, "Create feature branch from main") {
+		t.Error("Expected default base branch to be rendered")
+	}
+	if strings.Contains(text, "issue #") {
+		t.Error("Expected issue section to be omitted without issue_number")
+	}
+}
+
+func TestRenderPromptFromPolicy(t *testing.T) {
+	pm := NewPromptManager()
+
+	p := policy.Default()
+	p.DefaultBranch = "trunk"
+	p.CoverageThreshold = 85
+	p.Branches.Protected = []string{"trunk", "production"}
+	p.Commits.SubjectMaxLength = 60
+	p.Commits.Scopes = []string{"api", "cli"}
+
+	changes, err := pm.SetPolicy(p)
+	if err != nil {
+		t.Fatalf("SetPolicy returned error: %v", err)
+	}
+	if len(changes.Updated) != len(pm.GetAllPrompts()) {
+		t.Errorf("Expected every prompt to be updated, got %d", len(changes.Updated))
+	}
+
+	workflow, _ := pm.GetPrompt("development-workflow")
+	for _, arg := range workflow.Arguments {
+		if arg.Name == "base_branch" && arg.Default != "trunk" {
+			t.Errorf("Expected base_branch default from policy, got %q", arg.Default)
+		}
+	}
+
+	tests := map[string][]string{
+		"development-workflow":     {"git pull origin trunk", "85% coverage", "NEVER work on trunk/production branch"},
+		"commit-message-format":    {"under 60 characters", "api, cli"},
+		"branch-naming-convention": {"protected branches: trunk, production"},
+	}
+	for name, expected := range tests {
+		result, err := promptHandler(t, pm, name)(context.Background(), nil, nil)
+		if err != nil {
+			t.Fatalf("%s handler returned error: %v", name, err)
+		}
+		text := result.Messages[0].Content.(*mcp.TextContent).Text
+		for _, keyword := range expected {
+			if !strings.Contains(text, keyword) {
+				t.Errorf("%s: expected text to contain %q", name, keyword)
+			}
+		}
+	}
+
+	if changes, _ := pm.SetPolicy(policy.Default()); len(changes.Updated) == 0 {
+		t.Error("Expected prompts to be updated when the policy changes back")
+	}
+	if changes, _ := pm.SetPolicy(policy.Default()); !changes.Empty() {
+		t.Errorf("Expected an identical policy to leave prompts unchanged, got %+v", changes)
+	}
+}
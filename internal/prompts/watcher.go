@@ -0,0 +1,125 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// PromptChanges describes how the prompt set changed during a reload
+type PromptChanges struct {
+	Added   []Prompt
+	Updated []Prompt
+	Removed []string
+}
+
+// Empty reports whether the reload left the prompt set unchanged
+func (c PromptChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Updated) == 0 && len(c.Removed) == 0
+}
+
+// Reload re-reads every prompt layer and atomically replaces the prompt set.
+// Files that fail to parse keep their last good version; their errors are returned
+// joined together alongside the changes that were applied.
+func (pm *PromptManager) Reload() (PromptChanges, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return pm.reload()
+}
+
+// reload re-reads every layer; the caller must hold the write lock
+func (pm *PromptManager) reload() (PromptChanges, error) {
+	var (
+		merged []Prompt
+		errs   []error
+	)
+	for _, layer := range pm.layers {
+		loaded, err := layer.load()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, prompt := range loaded {
+			bound, err := prompt.withPolicy(pm.policy)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %v", ErrInvalidPromptFile, prompt.Path, err))
+				continue
+			}
+			bound.env = pm.env
+			merged = mergePrompt(merged, bound)
+		}
+	}
+
+	changes := diffPrompts(pm.prompts, merged)
+	pm.prompts = merged
+	return changes, errors.Join(errs...)
+}
+
+// Watch polls the prompt layers every interval and reloads the prompt set whenever a
+// prompt file is added, removed or modified. onReload is called after each reload
+// with the applied changes and any file errors. Watch blocks until ctx is done.
+func (pm *PromptManager) Watch(ctx context.Context, interval time.Duration, onReload func(PromptChanges, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !pm.modified() {
+				continue
+			}
+
+			changes, err := pm.Reload()
+			onReload(changes, err)
+		}
+	}
+}
+
+// modified reports whether any layer's files changed since they were last loaded
+func (pm *PromptManager) modified() bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	for _, layer := range pm.layers {
+		if layer.signature() != layer.loaded {
+			return true
+		}
+	}
+	return false
+}
+
+// diffPrompts compares two prompt sets by name and definition
+func diffPrompts(previous, next []Prompt) PromptChanges {
+	var changes PromptChanges
+
+	old := make(map[string]Prompt, len(previous))
+	for _, prompt := range previous {
+		old[prompt.Name] = prompt
+	}
+
+	for _, prompt := range next {
+		before, ok := old[prompt.Name]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, prompt)
+		case !samePrompt(before, prompt):
+			changes.Updated = append(changes.Updated, prompt)
+		}
+		delete(old, prompt.Name)
+	}
+
+	for _, prompt := range previous {
+		if _, removed := old[prompt.Name]; removed {
+			changes.Removed = append(changes.Removed, prompt.Name)
+		}
+	}
+	return changes
+}
+
+// samePrompt reports whether two prompts have identical definitions, ignoring handlers
+func samePrompt(a, b Prompt) bool {
+	a.Handler, b.Handler = nil, nil
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,133 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writePromptFile writes a minimal prompt definition to dir
+func writePromptFile(t *testing.T, dir, name, description, body string) {
+	t.Helper()
+	data := "---\nname: " + name + "\ndescription: " + description + "\n---\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadReportsChanges(t *testing.T) {
+	dir := t.TempDir()
+	writePromptFile(t, dir, "release-notes", "Release notes", "Summarise changes.")
+	writePromptFile(t, dir, "hotfix-guide", "Hotfix guide", "Patch production.")
+
+	pm, err := LoadPromptManager(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptManager returned error: %v", err)
+	}
+
+	writePromptFile(t, dir, "release-notes", "Release notes", "Summarise user-facing changes.")
+	writePromptFile(t, dir, "onboarding", "Onboarding", "Welcome aboard.")
+	if err := os.Remove(filepath.Join(dir, "hotfix-guide.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := pm.Reload()
+	if err != nil {
+		t.Fatalf("Reload returned error: %v", err)
+	}
+
+	if len(changes.Added) != 1 || changes.Added[0].Name != "onboarding" {
+		t.Errorf("Expected onboarding to be added, got %+v", changes.Added)
+	}
+	if len(changes.Updated) != 1 || changes.Updated[0].Name != "release-notes" {
+		t.Errorf("Expected release-notes to be updated, got %+v", changes.Updated)
+	}
+	if len(changes.Removed) != 1 || changes.Removed[0] != "hotfix-guide" {
+		t.Errorf("Expected hotfix-guide to be removed, got %v", changes.Removed)
+	}
+
+	if _, ok := pm.GetPrompt("hotfix-guide"); ok {
+		t.Error("Expected removed prompt to be gone")
+	}
+
+	changes, err = pm.Reload()
+	if err != nil || !changes.Empty() {
+		t.Errorf("Expected no changes on unchanged reload, got %+v, %v", changes, err)
+	}
+}
+
+func TestReloadKeepsPreviousVersionOfMalformedFile(t *testing.T) {
+	dir := t.TempDir()
+	writePromptFile(t, dir, "release-notes", "Release notes", "Summarise changes.")
+
+	pm, err := LoadPromptManager(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptManager returned error: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "release-notes.md"), []byte("---\nname: [broken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writePromptFile(t, dir, "onboarding", "Onboarding", "Welcome {{.undeclared}}")
+
+	changes, err := pm.Reload()
+	if !errors.Is(err, ErrInvalidPromptFile) {
+		t.Fatalf("Expected ErrInvalidPromptFile, got %v", err)
+	}
+	if !changes.Empty() {
+		t.Errorf("Expected no changes when every edit is malformed, got %+v", changes)
+	}
+
+	prompt, ok := pm.GetPrompt("release-notes")
+	if !ok {
+		t.Fatal("Expected previous version of release-notes to be kept")
+	}
+	if prompt.Messages[0].Content != "Summarise changes." {
+		t.Errorf("Expected previous content, got %q", prompt.Messages[0].Content)
+	}
+	if _, ok := pm.GetPrompt("onboarding"); ok {
+		t.Error("Expected malformed new prompt to be rejected")
+	}
+}
+
+func TestWatchReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	writePromptFile(t, dir, "release-notes", "Release notes", "Summarise changes.")
+
+	pm, err := LoadPromptManager(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptManager returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type reload struct {
+		changes PromptChanges
+		err     error
+	}
+	reloads := make(chan reload, 1)
+	go pm.Watch(ctx, 10*time.Millisecond, func(changes PromptChanges, err error) {
+		select {
+		case reloads <- reload{changes, err}:
+		default:
+		}
+	})
+
+	writePromptFile(t, dir, "onboarding", "Onboarding", "Welcome aboard.")
+
+	select {
+	case r := <-reloads:
+		if r.err != nil {
+			t.Fatalf("Unexpected reload error: %v", r.err)
+		}
+		if len(r.changes.Added) != 1 || r.changes.Added[0].Name != "onboarding" {
+			t.Errorf("Expected onboarding to be added, got %+v", r.changes)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for prompt reload")
+	}
+}
@@ -0,0 +1,151 @@
+package pullrequests
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// headingPattern matches a Markdown ATX heading
+	headingPattern = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.+?)\s*#*\s*$`)
+
+	// commentPattern matches HTML comments, which templates use for instructions
+	commentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// Description is what a pull request description says, section by section
+type Description struct {
+	// Summary says what the change does
+	Summary string `json:"summary,omitempty"`
+	// Motivation says why the change is needed
+	Motivation string `json:"motivation,omitempty"`
+	// Testing says how the change was tested and how to try it
+	Testing string `json:"testing,omitempty"`
+	// Notes are anything else reviewers should know
+	Notes string `json:"notes,omitempty"`
+	// Issue is the number of the issue the pull request closes, or zero
+	Issue int `json:"issue,omitempty"`
+}
+
+// section is a part of the description and the template headings that ask for it
+type section struct {
+	heading *regexp.Regexp
+	text    func(d Description) string
+}
+
+// sections are the parts of a description by the title used in the built-in layout
+var sections = map[string]section{
+	"What":          {regexp.MustCompile(`(?i)\bwhat\b|description|summary|change|overview`), func(d Description) string { return d.Summary }},
+	"Why":           {regexp.MustCompile(`(?i)\bwhy\b|motivation|context|background|reason|rationale`), func(d Description) string { return d.Motivation }},
+	"How to Test":   {regexp.MustCompile(`(?i)test|verif|\bqa\b|how to (try|check|review)`), func(d Description) string { return d.Testing }},
+	"Notes":         {regexp.MustCompile(`(?i)note|additional|other|comment|screenshot`), func(d Description) string { return d.Notes }},
+	"Linked Issues": {regexp.MustCompile(`(?i)issue|ticket|related|link|closes|fixes|resolves`), func(d Description) string { return closingLine(d.Issue) }},
+}
+
+// layout is the order of the sections in the built-in layout
+var layout = []string{"What", "Why", "How to Test", "Notes", "Linked Issues"}
+
+// matchOrder is the order template headings are matched in, so that "How has this
+// been tested?" is testing and "Related issues" is not a summary
+var matchOrder = []string{"How to Test", "Linked Issues", "Why", "What", "Notes"}
+
+// Body renders the description into tmpl, or into the built-in layout of What, Why,
+// How to Test, Notes and Linked Issues sections when tmpl is nil. In a template, each
+// part goes below the first heading that asks for it, replacing the instructions in
+// HTML comments there; parts no heading asks for are added at the end. The body always
+// closes the linked issue.
+func (d Description) Body(tmpl *Template) string {
+	issue := d.Issue
+	if HasClosingKeyword(strings.Join([]string{d.Summary, d.Motivation, d.Testing, d.Notes}, "\n"), issue) {
+		// The issue is already closed by the text, so no section is needed
+		d.Issue = 0
+	}
+
+	filled := map[string]bool{}
+	var sb strings.Builder
+
+	if tmpl != nil {
+		sb.WriteString(d.fill(tmpl.Text, filled))
+	}
+
+	for _, title := range layout {
+		text := strings.TrimSpace(sections[title].text(d))
+		if text == "" || filled[title] {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "## %s\n\n%s\n", title, text)
+	}
+
+	body := strings.TrimSpace(sb.String()) + "\n"
+	if issue > 0 && !HasClosingKeyword(body, issue) {
+		body += "\n" + closingLine(issue) + "\n"
+	}
+	return body
+}
+
+// fill puts the parts of the description below the template headings that ask for
+// them, recording the sections it filled
+func (d Description) fill(text string, filled map[string]bool) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		out = append(out, lines[i])
+		match := headingPattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+
+		title, ok := d.sectionFor(match[1], filled)
+		if !ok {
+			continue
+		}
+		filled[title] = true
+
+		// The section runs to the next heading
+		end := i + 1
+		for end < len(lines) && !headingPattern.MatchString(lines[end]) {
+			end++
+		}
+		rest := strings.TrimSpace(commentPattern.ReplaceAllString(strings.Join(lines[i+1:end], "\n"), ""))
+
+		out = append(out, "", strings.TrimSpace(sections[title].text(d)))
+		if rest != "" {
+			out = append(out, "", rest)
+		}
+		out = append(out, "")
+		i = end - 1
+	}
+	return strings.Join(out, "\n")
+}
+
+// sectionFor returns the title of the part of the description that heading asks
+// for, unless that part is empty or already filled
+func (d Description) sectionFor(heading string, filled map[string]bool) (string, bool) {
+	for _, title := range matchOrder {
+		if sections[title].heading.MatchString(heading) {
+			ok := !filled[title] && strings.TrimSpace(sections[title].text(d)) != ""
+			return title, ok
+		}
+	}
+	return "", false
+}
+
+// closingLine links the issue with a closing keyword, or is empty without an issue
+func closingLine(issue int) string {
+	if issue <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("Fixes #%d", issue)
+}
+
+// HasClosingKeyword reports whether body closes issue with one of GitHub's closing
+// keywords, such as "Fixes #42" or "closes: owner/repo#42"
+func HasClosingKeyword(body string, issue int) bool {
+	pattern := regexp.MustCompile(fmt.Sprintf(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+(?:[\w.-]+/[\w.-]+)?#%d\b`, issue))
+	return pattern.MatchString(commentPattern.ReplaceAllString(body, ""))
+}
@@ -0,0 +1,67 @@
+package pullrequests
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBodyBuiltinLayout(t *testing.T) {
+	d := Description{Summary: "Retry failed uploads.", Motivation: "Uploads fail on flaky networks.", Testing: "go test ./...", Issue: 42}
+
+	want := "## What\n\nRetry failed uploads.\n\n## Why\n\nUploads fail on flaky networks.\n\n## How to Test\n\ngo test ./...\n\n" +
+		"## Linked Issues\n\nFixes #42\n"
+	if got := d.Body(nil); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	if got := (Description{Issue: 7}).Body(nil); got != "## Linked Issues\n\nFixes #7\n" {
+		t.Errorf("Expected only the linked issue, got:\n%s", got)
+	}
+}
+
+func TestBodyFillsTemplate(t *testing.T) {
+	tmpl := &Template{Text: "## Description\n<!-- Describe your change -->\n\n## How Has This Been Tested?\n\n<!--\nSteps\n-->\n\n" +
+		"## Checklist\n\n- [ ] Tests added\n\n## Related Issue\n\n"}
+	d := Description{Summary: "Retry failed uploads.", Motivation: "Uploads fail.", Testing: "Ran the upload tests.", Issue: 42}
+
+	body := d.Body(tmpl)
+	for _, want := range []string{
+		"## Description\n\nRetry failed uploads.\n\n## How Has This Been Tested?\n\nRan the upload tests.\n\n",
+		"## Checklist\n\n- [ ] Tests added\n",
+		"## Related Issue\n\nFixes #42\n",
+		"## Why\n\nUploads fail.\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected body to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "Describe your change") {
+		t.Errorf("Expected template instructions to be replaced, got:\n%s", body)
+	}
+}
+
+func TestBodyKeepsExistingClosingKeyword(t *testing.T) {
+	body := Description{Summary: "Retry uploads.\n\nCloses octo/hello#42", Issue: 42}.Body(&Template{Text: "## Summary\n"})
+	if strings.Count(strings.ToLower(body), "#42") != 1 {
+		t.Errorf("Expected a single reference to the issue, got:\n%s", body)
+	}
+}
+
+func TestHasClosingKeyword(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{"Fixes #42", true},
+		{"this resolves: octo/hello#42.", true},
+		{"CLOSED #42", true},
+		{"Refs #42", false},
+		{"Fixes #421", false},
+		{"<!-- Fixes #42 -->", false},
+	}
+	for _, tt := range tests {
+		if got := HasClosingKeyword(tt.body, 42); got != tt.want {
+			t.Errorf("HasClosingKeyword(%q) = %v, expected %v", tt.body, got, tt.want)
+		}
+	}
+}
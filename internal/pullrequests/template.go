@@ -0,0 +1,98 @@
+// Package pullrequests writes pull request descriptions from the repository's pull
+// request template and links them to the issue they close
+package pullrequests
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// templateFile is the name GitHub looks for, in any case, in templateDirs
+const templateFile = "pull_request_template.md"
+
+// templateDirs are searched for a single pull request template, in GitHub's order
+var templateDirs = []string{".github", ".", "docs"}
+
+// Template is a pull request template of a repository
+type Template struct {
+	// Path is relative to the repository root, or empty for the built-in template
+	Path string `json:"path,omitempty"`
+	Text string `json:"-"`
+}
+
+// FindTemplate returns the pull request template of the repository at dir. A name
+// selects one of several templates in a PULL_REQUEST_TEMPLATE directory. Without a
+// name, the single-file template is used; a repository without one has no template.
+func FindTemplate(dir, name string) (*Template, error) {
+	if name != "" {
+		return namedTemplate(dir, name)
+	}
+
+	for _, sub := range templateDirs {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.EqualFold(entry.Name(), templateFile) {
+				return readTemplate(dir, filepath.Join(sub, entry.Name()))
+			}
+		}
+	}
+	return nil, nil
+}
+
+// namedTemplate returns a template of a PULL_REQUEST_TEMPLATE directory by file name,
+// with or without its extension
+func namedTemplate(dir, name string) (*Template, error) {
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	var available []string
+	for _, sub := range templateDirs {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || !strings.EqualFold(entry.Name(), "pull_request_template") {
+				continue
+			}
+			templates, err := os.ReadDir(filepath.Join(dir, sub, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			for _, t := range templates {
+				file := t.Name()
+				if t.IsDir() || !strings.EqualFold(filepath.Ext(file), ".md") {
+					continue
+				}
+				if strings.EqualFold(file, name) || strings.EqualFold(strings.TrimSuffix(file, filepath.Ext(file)), name) {
+					return readTemplate(dir, filepath.Join(sub, entry.Name(), file))
+				}
+				available = append(available, file)
+			}
+		}
+	}
+	if len(available) == 0 {
+		return nil, fmt.Errorf("pull request template %q not found: the repository has no PULL_REQUEST_TEMPLATE directory", name)
+	}
+	return nil, fmt.Errorf("pull request template %q not found; available: %s", name, strings.Join(available, ", "))
+}
+
+// readTemplate reads the template at path relative to dir
+func readTemplate(dir, path string) (*Template, error) {
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Template{Path: filepath.ToSlash(filepath.Clean(path)), Text: string(data)}, nil
+}
@@ -0,0 +1,46 @@
+package pullrequests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+)
+
+func TestFindTemplate(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	if tmpl, err := FindTemplate(dir, ""); err != nil || tmpl != nil {
+		t.Fatalf("Expected no template, got %+v, %v", tmpl, err)
+	}
+
+	gittest.WriteFile(t, dir, "docs/pull_request_template.md", "docs")
+	gittest.WriteFile(t, dir, ".github/PULL_REQUEST_TEMPLATE.md", "github")
+	tmpl, err := FindTemplate(dir, "")
+	if err != nil {
+		t.Fatalf("FindTemplate returned error: %v", err)
+	}
+	if tmpl.Path != ".github/PULL_REQUEST_TEMPLATE.md" || tmpl.Text != "github" {
+		t.Errorf("Expected the .github template to take precedence, got %+v", tmpl)
+	}
+}
+
+func TestFindNamedTemplate(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.WriteFile(t, dir, ".github/PULL_REQUEST_TEMPLATE/feature.md", "feature")
+	gittest.WriteFile(t, dir, ".github/PULL_REQUEST_TEMPLATE/bugfix.md", "bugfix")
+
+	tmpl, err := FindTemplate(dir, "bugfix")
+	if err != nil {
+		t.Fatalf("FindTemplate returned error: %v", err)
+	}
+	if tmpl.Path != ".github/PULL_REQUEST_TEMPLATE/bugfix.md" || tmpl.Text != "bugfix" {
+		t.Errorf("Unexpected template %+v", tmpl)
+	}
+
+	if _, err := FindTemplate(dir, "release"); err == nil || !strings.Contains(err.Error(), "available: bugfix.md, feature.md") {
+		t.Errorf("Expected error listing the templates, got %v", err)
+	}
+	if _, err := FindTemplate(dir, "../secret"); err == nil {
+		t.Error("Expected error for a template name with a path")
+	}
+}
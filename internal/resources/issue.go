@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+	"github.com/yosida95/uritemplate/v3"
+)
+
+// IssueURITemplate addresses a GitHub issue
+const IssueURITemplate = "issue://{owner}/{repo}/{number}"
+
+var issueURI = uritemplate.MustNew(IssueURITemplate)
+
+// issueTemplate serves issues as Markdown for the agent to read and as JSON
+func (rm *ResourceManager) issueTemplate() Template {
+	return Template{
+		URITemplate: IssueURITemplate,
+		Name:        "issue",
+		Description: "A GitHub issue with its labels, assignees, milestone, linked pull requests and comments, " +
+			"as Markdown (text/markdown) and JSON (application/json)",
+		handler: func(ctx context.Context, _ *mcp.ServerSession, params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+			owner, repo, number, err := parseIssueURI(params.URI)
+			if err != nil {
+				return nil, err
+			}
+			if rm.deps.GitHub == nil {
+				return nil, errors.New("GitHub API is not configured")
+			}
+
+			issue, err := issues.Load(ctx, rm.deps.GitHub, owner, repo, number)
+			if errors.Is(err, github.ErrNotFound) {
+				return nil, mcp.ResourceNotFoundError(params.URI)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := json.MarshalIndent(issue, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+				{URI: params.URI, MIMEType: "text/markdown", Text: issue.Markdown()},
+				{URI: params.URI, MIMEType: "application/json", Text: string(data)},
+			}}, nil
+		},
+	}
+}
+
+// parseIssueURI extracts the owner, repository and issue number from an issue URI
+func parseIssueURI(uri string) (owner, repo string, number int, err error) {
+	values := issueURI.Match(uri)
+	if values == nil {
+		return "", "", 0, fmt.Errorf("invalid issue URI %q: expected %s", uri, IssueURITemplate)
+	}
+	owner, repo = values.Get("owner").String(), values.Get("repo").String()
+	number, err = strconv.Atoi(values.Get("number").String())
+	if err != nil || number <= 0 || owner == "" || repo == "" {
+		return "", "", 0, fmt.Errorf("invalid issue URI %q: expected %s with a positive number", uri, IssueURITemplate)
+	}
+	return owner, repo, number, nil
+}
@@ -0,0 +1,113 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+)
+
+// connect serves the resource templates of rm to an in-memory client
+func connect(t *testing.T, rm *ResourceManager) *mcp.ClientSession {
+	t.Helper()
+	return connectServer(t, rm, nil)
+}
+
+// connectServer is connect with server options
+func connectServer(t *testing.T, rm *ResourceManager, opts *mcp.ServerOptions) *mcp.ClientSession {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, opts)
+	for _, template := range rm.GetAllTemplates() {
+		template.Register(server)
+	}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport); err != nil {
+		t.Fatalf("server.Connect returned error: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func TestIssueResource(t *testing.T) {
+	s := githubtest.NewServer(t)
+	s.JSON("GET /repos/octo/hello/issues/42", http.StatusOK, map[string]any{
+		"number": 42, "title": "Crash on start", "state": "open", "user": map[string]any{"login": "alice"},
+	})
+	s.JSON("GET /repos/octo/hello/issues/42/timeline", http.StatusOK, []any{})
+	session := connect(t, NewResourceManager(Dependencies{GitHub: s.Client(t)}))
+	ctx := context.Background()
+
+	templates, err := session.ListResourceTemplates(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListResourceTemplates returned error: %v", err)
+	}
+	listed := slices.ContainsFunc(templates.ResourceTemplates, func(t *mcp.ResourceTemplate) bool { return t.URITemplate == IssueURITemplate })
+	if !listed {
+		t.Fatalf("Expected issue template to be listed, got %+v", templates.ResourceTemplates)
+	}
+
+	result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "issue://octo/hello/42"})
+	if err != nil {
+		t.Fatalf("ReadResource returned error: %v", err)
+	}
+	if len(result.Contents) != 2 {
+		t.Fatalf("Expected Markdown and JSON contents, got %d", len(result.Contents))
+	}
+
+	markdown, data := result.Contents[0], result.Contents[1]
+	if markdown.MIMEType != "text/markdown" || !strings.HasPrefix(markdown.Text, "# Crash on start (#42)") {
+		t.Errorf("Unexpected Markdown content %+v", markdown)
+	}
+	var issue issues.Issue
+	if err := json.Unmarshal([]byte(data.Text), &issue); err != nil {
+		t.Fatalf("Decoding JSON content: %v", err)
+	}
+	if data.MIMEType != "application/json" || issue.Owner != "octo" || issue.Number != 42 {
+		t.Errorf("Unexpected JSON content %+v", issue)
+	}
+}
+
+func TestIssueResourceErrors(t *testing.T) {
+	s := githubtest.NewServer(t)
+	session := connect(t, NewResourceManager(Dependencies{GitHub: s.Client(t)}))
+
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"issue://octo/hello/1", "not found"},
+		{"issue://octo/hello/abc", "positive number"},
+	}
+	for _, tt := range tests {
+		_, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: tt.uri})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ReadResource(%s): expected error containing %q, got %v", tt.uri, tt.want, err)
+		}
+	}
+}
+
+func TestParseIssueURI(t *testing.T) {
+	owner, repo, number, err := parseIssueURI("issue://octo/hello-world/7")
+	if err != nil || owner != "octo" || repo != "hello-world" || number != 7 {
+		t.Errorf("Unexpected result %q, %q, %d, %v", owner, repo, number, err)
+	}
+	for _, uri := range []string{"issue://octo/7", "issue://octo/hello/0", "pr://octo/hello/7"} {
+		if _, _, _, err := parseIssueURI(uri); err == nil {
+			t.Errorf("Expected error for %s", uri)
+		}
+	}
+}
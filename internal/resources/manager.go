@@ -0,0 +1,60 @@
+// Package resources provides the MCP resource templates of the server
+package resources
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// Template is an MCP resource template and the handler that reads matching resources
+type Template struct {
+	URITemplate string
+	Name        string
+	Description string
+
+	handler mcp.ResourceHandler
+}
+
+// Dependencies are the services shared by the resources
+type Dependencies struct {
+	// GitHub calls the GitHub REST API for GitHub resources
+	GitHub *github.Client
+
+	// Workflows tracks each session's progress through the development workflow
+	Workflows *workflow.Tracker
+}
+
+// ResourceManager manages all available resource templates
+type ResourceManager struct {
+	deps      Dependencies
+	templates []Template
+}
+
+// NewResourceManager creates a new resource manager with all resource templates backed by deps
+func NewResourceManager(deps Dependencies) *ResourceManager {
+	if deps.Workflows == nil {
+		deps.Workflows = workflow.NewTracker()
+	}
+
+	rm := &ResourceManager{deps: deps}
+	rm.templates = []Template{
+		rm.issueTemplate(),
+		rm.workflowTemplate(),
+	}
+	return rm
+}
+
+// GetAllTemplates returns all resource templates
+func (rm *ResourceManager) GetAllTemplates() []Template {
+	return rm.templates
+}
+
+// Register adds the resource template to server
+func (t Template) Register(server *mcp.Server) {
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: t.URITemplate,
+		Name:        t.Name,
+		Description: t.Description,
+	}, t.handler)
+}
@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+	"github.com/yosida95/uritemplate/v3"
+)
+
+// WorkflowURITemplate addresses the reading session's workflow for an issue, or its
+// active workflow as workflow://current
+const WorkflowURITemplate = "workflow://{issue}"
+
+// currentWorkflow names the active workflow in a workflow URI
+const currentWorkflow = "current"
+
+var workflowURI = uritemplate.MustNew(WorkflowURITemplate)
+
+// workflowTemplate serves the session's progress through the development workflow
+func (rm *ResourceManager) workflowTemplate() Template {
+	return Template{
+		URITemplate: WorkflowURITemplate,
+		Name:        "workflow",
+		Description: "This session's progress through the development workflow for an issue number, or for the workflow " +
+			"started last as workflow://current, as Markdown (text/markdown) and JSON (application/json)",
+		handler: func(_ context.Context, ss *mcp.ServerSession, params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+			issue, err := parseWorkflowURI(params.URI)
+			if err != nil {
+				return nil, err
+			}
+
+			// A workflow the session has not started does not exist for it
+			p, err := rm.deps.Workflows.Get(workflow.SessionID(ss), issue)
+			if err != nil {
+				return nil, mcp.ResourceNotFoundError(params.URI)
+			}
+
+			data, err := json.MarshalIndent(p, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+				{URI: params.URI, MIMEType: "text/markdown", Text: p.Markdown()},
+				{URI: params.URI, MIMEType: "application/json", Text: string(data)},
+			}}, nil
+		},
+	}
+}
+
+// parseWorkflowURI returns the issue of a workflow URI, zero for the current workflow
+func parseWorkflowURI(uri string) (int, error) {
+	values := workflowURI.Match(uri)
+	if values == nil {
+		return 0, fmt.Errorf("invalid workflow URI %q: expected %s", uri, WorkflowURITemplate)
+	}
+	issue := values.Get("issue").String()
+	if issue == currentWorkflow {
+		return 0, nil
+	}
+	number, err := strconv.Atoi(issue)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid workflow URI %q: expected an issue number or %q", uri, currentWorkflow)
+	}
+	return number, nil
+}
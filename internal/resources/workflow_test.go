@@ -0,0 +1,55 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+func TestWorkflowResource(t *testing.T) {
+	tracker := workflow.NewTracker()
+	sessions := make(chan *mcp.ServerSession, 1)
+	session := connectServer(t, NewResourceManager(Dependencies{Workflows: tracker}), &mcp.ServerOptions{
+		InitializedHandler: func(_ context.Context, ss *mcp.ServerSession, _ *mcp.InitializedParams) { sessions <- ss },
+	})
+	ctx := context.Background()
+
+	if _, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "workflow://current"}); err == nil {
+		t.Fatal("Expected no workflow before one is started")
+	}
+
+	sessionID := workflow.SessionID(<-sessions)
+	tracker.Start(sessionID, "", "", 42, false)
+	if _, err := tracker.Complete(sessionID, 42, workflow.StepCheckRepository, "clean repository"); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	tracker.Start("another-session", "", "", 7, false)
+
+	for _, uri := range []string{"workflow://current", "workflow://42"} {
+		result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		if err != nil {
+			t.Fatalf("ReadResource(%s) returned error: %v", uri, err)
+		}
+		markdown, data := result.Contents[0], result.Contents[1]
+		if markdown.MIMEType != "text/markdown" || !strings.Contains(markdown.Text, "- [x] 1. Check Git repository status — clean repository") {
+			t.Errorf("Unexpected Markdown content %+v", markdown)
+		}
+		var p workflow.Progress
+		if err := json.Unmarshal([]byte(data.Text), &p); err != nil {
+			t.Fatalf("Decoding JSON content: %v", err)
+		}
+		if p.Issue != 42 || p.Steps[0].Status != workflow.StatusDone {
+			t.Errorf("Unexpected JSON content %+v", p)
+		}
+	}
+
+	for _, uri := range []string{"workflow://7", "workflow://0", "workflow://next"} {
+		if _, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri}); err == nil {
+			t.Errorf("Expected an error for %s", uri)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package reviews tracks the review cycle of a pull request: its review threads and
+// which reviewers have approved it or requested changes
+package reviews
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+)
+
+// Thread states to filter by
+const (
+	StateUnresolved = "unresolved"
+	StateResolved   = "resolved"
+	StateAll        = "all"
+)
+
+// reviewRequired is the review decision of a pull request that still needs an approval
+const reviewRequired = "REVIEW_REQUIRED"
+
+// Summary is where the review of a pull request stands
+type Summary struct {
+	URL      string `json:"url"`
+	Decision string `json:"decision,omitempty"`
+
+	Approved         []string `json:"approved"`
+	ChangesRequested []string `json:"changes_requested"`
+	Commented        []string `json:"commented"`
+	Requested        []string `json:"requested"`
+
+	Threads    int `json:"threads"`
+	Unresolved int `json:"unresolved"`
+	// Outdated counts the unresolved threads on code that has since changed
+	Outdated int `json:"outdated"`
+
+	// Done is true when no thread is unresolved, no reviewer requests changes and no
+	// required review is missing; Blockers says what stands in the way otherwise
+	Done     bool     `json:"done"`
+	Blockers []string `json:"blockers"`
+}
+
+// Summarize combines the reviews and review threads of a pull request. Reviewers
+// whose latest review only comments are listed as commenters unless they approved
+// or requested changes earlier.
+func Summarize(status *github.ReviewStatus, threads []github.ReviewThread) Summary {
+	s := Summary{
+		URL:              status.URL,
+		Decision:         status.Decision,
+		Approved:         []string{},
+		ChangesRequested: []string{},
+		Commented:        []string{},
+		Requested:        slices.Clone(status.Requested),
+		Blockers:         []string{},
+	}
+
+	for _, review := range status.Opinionated {
+		switch review.State {
+		case github.ReviewApproved:
+			s.Approved = append(s.Approved, review.Author)
+		case github.ReviewChangesRequested:
+			s.ChangesRequested = append(s.ChangesRequested, review.Author)
+		}
+	}
+	for _, review := range status.Latest {
+		opinionated := slices.Contains(s.Approved, review.Author) || slices.Contains(s.ChangesRequested, review.Author)
+		if review.State == github.ReviewCommented && !opinionated {
+			s.Commented = append(s.Commented, review.Author)
+		}
+	}
+
+	for _, thread := range threads {
+		s.Threads++
+		if !thread.Resolved {
+			s.Unresolved++
+			if thread.Outdated {
+				s.Outdated++
+			}
+		}
+	}
+
+	if s.Unresolved > 0 {
+		s.Blockers = append(s.Blockers, fmt.Sprintf("%d unresolved review threads", s.Unresolved))
+	}
+	if len(s.ChangesRequested) > 0 {
+		s.Blockers = append(s.Blockers, "changes requested by "+strings.Join(s.ChangesRequested, ", "))
+	}
+	if s.Decision == reviewRequired {
+		s.Blockers = append(s.Blockers, "an approving review is required")
+	}
+	s.Done = len(s.Blockers) == 0
+	return s
+}
+
+// Text summarizes the review for the agent to read
+func (s Summary) Text() string {
+	var sb strings.Builder
+	state := "done"
+	if !s.Done {
+		state = "not done: " + strings.Join(s.Blockers, "; ")
+	}
+	fmt.Fprintf(&sb, "Review of %s: %s\n", s.URL, state)
+	if s.Decision != "" {
+		fmt.Fprintf(&sb, "- Decision: %s\n", s.Decision)
+	}
+	for _, group := range []struct {
+		label  string
+		logins []string
+	}{
+		{"Approved", s.Approved},
+		{"Changes requested", s.ChangesRequested},
+		{"Commented", s.Commented},
+		{"Awaiting review", s.Requested},
+	} {
+		if len(group.logins) > 0 {
+			fmt.Fprintf(&sb, "- %s: %s\n", group.label, strings.Join(group.logins, ", "))
+		}
+	}
+	fmt.Fprintf(&sb, "- Threads: %d unresolved (%d outdated) of %d\n", s.Unresolved, s.Outdated, s.Threads)
+	return sb.String()
+}
+
+// ValidState reports whether state is a thread state Filter accepts
+func ValidState(state string) bool {
+	return state == StateUnresolved || state == StateResolved || state == StateAll
+}
+
+// Filter returns the threads in state
+func Filter(threads []github.ReviewThread, state string) []github.ReviewThread {
+	kept := []github.ReviewThread{}
+	for _, thread := range threads {
+		if state == StateAll || thread.Resolved == (state == StateResolved) {
+			kept = append(kept, thread)
+		}
+	}
+	return kept
+}
+
+// Anchor is where a thread is in the diff, "path:line" or "path:start-end". Outdated
+// threads are anchored where they were first placed.
+func Anchor(thread github.ReviewThread) string {
+	start, end := thread.StartLine, thread.Line
+	if end == 0 {
+		start, end = thread.OriginalStartLine, thread.OriginalLine
+	}
+	switch {
+	case end == 0:
+		return thread.Path
+	case start > 0 && start != end:
+		return fmt.Sprintf("%s:%d-%d", thread.Path, start, end)
+	default:
+		return fmt.Sprintf("%s:%d", thread.Path, end)
+	}
+}
+
+// Markdown renders review threads with their diff hunks and comments
+func Markdown(threads []github.ReviewThread) string {
+	if len(threads) == 0 {
+		return "No review threads.\n"
+	}
+
+	var sb strings.Builder
+	for i, thread := range threads {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		state := StateUnresolved
+		if thread.Resolved {
+			state = StateResolved
+		}
+		if thread.Outdated {
+			state += ", outdated"
+		}
+		fmt.Fprintf(&sb, "## %s (%s)\n\nThread ID: `%s`\n", Anchor(thread), state, thread.ID)
+		if thread.DiffHunk != "" {
+			fmt.Fprintf(&sb, "\n```diff\n%s\n```\n", strings.TrimRight(thread.DiffHunk, "\n"))
+		}
+		sb.WriteString("\n")
+		for _, comment := range thread.Comments {
+			body := strings.ReplaceAll(strings.TrimSpace(comment.Body), "\n", "\n  ")
+			fmt.Fprintf(&sb, "- **%s**: %s\n", comment.Author, body)
+		}
+		if hidden := thread.TotalComments - len(thread.Comments); hidden > 0 {
+			fmt.Fprintf(&sb, "- ... %d more comments\n", hidden)
+		}
+	}
+	return sb.String()
+}
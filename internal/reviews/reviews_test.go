@@ -0,0 +1,96 @@
+package reviews
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+)
+
+// threads are a resolved thread, an unresolved multi-line thread and an unresolved
+// outdated thread
+var threads = []github.ReviewThread{
+	{ID: "T1", Path: "main.go", Line: 10, Resolved: true},
+	{ID: "T2", Path: "upload.go", StartLine: 40, Line: 42, DiffHunk: "@@ -38,3 +38,5 @@\n+retries := 1",
+		Comments: []github.ReviewComment{{Author: "alice", Body: "Retry three times?\nSee the issue."}}, TotalComments: 3},
+	{ID: "T3", Path: "parse.go", OriginalLine: 7, Outdated: true},
+}
+
+func TestSummarize(t *testing.T) {
+	status := &github.ReviewStatus{
+		URL:      "https://github.com/octo/hello/pull/9",
+		Decision: "CHANGES_REQUESTED",
+		Opinionated: []github.PullRequestReview{
+			{Author: "alice", State: github.ReviewChangesRequested},
+			{Author: "bob", State: github.ReviewApproved},
+		},
+		Latest: []github.PullRequestReview{
+			{Author: "alice", State: github.ReviewCommented},
+			{Author: "bob", State: github.ReviewApproved},
+			{Author: "carol", State: github.ReviewCommented},
+		},
+		Requested: []string{"octo/reviewers"},
+	}
+
+	s := Summarize(status, threads)
+	if !reflect.DeepEqual(s.Approved, []string{"bob"}) || !reflect.DeepEqual(s.ChangesRequested, []string{"alice"}) ||
+		!reflect.DeepEqual(s.Commented, []string{"carol"}) || !reflect.DeepEqual(s.Requested, []string{"octo/reviewers"}) {
+		t.Errorf("Unexpected reviewers %+v", s)
+	}
+	if s.Threads != 3 || s.Unresolved != 2 || s.Outdated != 1 {
+		t.Errorf("Unexpected thread counts %+v", s)
+	}
+	want := []string{"2 unresolved review threads", "changes requested by alice"}
+	if s.Done || !reflect.DeepEqual(s.Blockers, want) {
+		t.Errorf("Expected blockers %v, got %+v", want, s)
+	}
+	if text := s.Text(); !strings.Contains(text, "not done: 2 unresolved") || !strings.Contains(text, "- Approved: bob") {
+		t.Errorf("Unexpected text:\n%s", text)
+	}
+
+	done := Summarize(&github.ReviewStatus{Decision: "APPROVED", Opinionated: status.Opinionated[1:]}, threads[:1])
+	if !done.Done || len(done.Blockers) != 0 {
+		t.Errorf("Expected the review to be done, got %+v", done)
+	}
+	if required := Summarize(&github.ReviewStatus{Decision: "REVIEW_REQUIRED"}, nil); required.Done {
+		t.Errorf("Expected a missing required review to block, got %+v", required)
+	}
+}
+
+func TestFilterAndAnchor(t *testing.T) {
+	if got := Filter(threads, StateUnresolved); len(got) != 2 || got[0].ID != "T2" {
+		t.Errorf("Unexpected unresolved threads %+v", got)
+	}
+	if got := Filter(threads, StateResolved); len(got) != 1 || got[0].ID != "T1" {
+		t.Errorf("Unexpected resolved threads %+v", got)
+	}
+	if got := Filter(threads, StateAll); len(got) != 3 {
+		t.Errorf("Expected every thread, got %+v", got)
+	}
+
+	for i, want := range []string{"main.go:10", "upload.go:40-42", "parse.go:7"} {
+		if got := Anchor(threads[i]); got != want {
+			t.Errorf("Anchor(%s) = %q, expected %q", threads[i].ID, got, want)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	text := Markdown(threads[1:])
+	for _, want := range []string{
+		"## upload.go:40-42 (unresolved)",
+		"Thread ID: `T2`",
+		"```diff\n@@ -38,3 +38,5 @@\n+retries := 1\n```",
+		"- **alice**: Retry three times?\n  See the issue.",
+		"- ... 2 more comments",
+		"## parse.go:7 (unresolved, outdated)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	if Markdown(nil) != "No review threads.\n" {
+		t.Errorf("Unexpected text for no threads: %q", Markdown(nil))
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/auth"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// AuditEntry records who used a prompt, tool or resource, with which GitHub
+// credentials, and how it went
+type AuditEntry struct {
+	Time time.Time `json:"time"`
+	// User is the authenticated principal, or empty for sessions that did not authenticate
+	User        string `json:"user,omitempty"`
+	Session     string `json:"session"`
+	Credentials string `json:"github_credentials"`
+	Method      string `json:"method"`
+	Name        string `json:"name"`
+	// Outcome is "ok", "error" for a failed call or "tool_error" for a tool that reported an error
+	Outcome    string `json:"outcome"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// auditLog writes audit entries as JSON lines
+type auditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+	// file is the audit file w writes to, if any, closed on shutdown
+	file *os.File
+}
+
+// openAuditLog opens the file audit entries are appended to. Without one, entries go
+// to the server log.
+func (s *MCPServer) openAuditLog() (*auditLog, error) {
+	if s.auditFile == "" {
+		return &auditLog{}, nil
+	}
+	f, err := os.OpenFile(s.auditFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	log.Printf("Audit log: %s", s.auditFile)
+	return &auditLog{w: f, file: f}, nil
+}
+
+// close flushes the audit file to disk and closes it. Entries written afterwards go
+// to the server log.
+func (a *auditLog) close() error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Sync()
+	if closeErr := a.file.Close(); err == nil {
+		err = closeErr
+	}
+	a.w, a.file = nil, nil
+	if err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	return nil
+}
+
+// write records e
+func (a *auditLog) write(e AuditEntry) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("Failed to encode audit entry: %v", err)
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.w == nil {
+		log.Printf("Audit: %s", line)
+		return
+	}
+	if _, err := a.w.Write(append(line, '\n')); err != nil {
+		log.Printf("Failed to write audit entry: %v", err)
+	}
+}
+
+// auditCalls records every prompt, tool and resource used in the audit log,
+// attributed to the session's principal and GitHub credentials
+func (s *MCPServer) auditCalls(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+		var name string
+		switch params := params.(type) {
+		case *mcp.GetPromptParams:
+			name = params.Name
+		case *mcp.CallToolParamsFor[json.RawMessage]:
+			name = params.Name
+		case *mcp.ReadResourceParams:
+			name = params.URI
+		default:
+			return next(ctx, ss, method, params)
+		}
+
+		start := time.Now()
+		result, err := next(ctx, ss, method, params)
+		e := AuditEntry{
+			Time:        start.UTC(),
+			Session:     workflow.SessionID(ss),
+			Credentials: sessionCredentials(ctx),
+			Method:      method,
+			Name:        name,
+			Outcome:     "ok",
+			DurationMS:  time.Since(start).Milliseconds(),
+		}
+		if p, ok := auth.FromContext(ctx); ok {
+			e.User = p.ID()
+		}
+		if err != nil {
+			e.Outcome, e.Error = "error", err.Error()
+		} else if r, ok := result.(*mcp.CallToolResult); ok && r.IsError {
+			e.Outcome = "tool_error"
+		}
+		if s.audit != nil {
+			s.audit.write(e)
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/auth"
+)
+
+// loadVerifier loads the configured credentials. Without any, HTTP clients are not
+// authenticated and nil is returned.
+func (s *MCPServer) loadVerifier(mode string) (*auth.Verifier, error) {
+	if !s.auth.Enabled() {
+		if mode != TransportStdio {
+			log.Printf("HTTP clients are not authenticated; set auth.tokens_file or auth.jwks_file to require tokens")
+		}
+		return nil, nil
+	}
+	if mode == TransportStdio {
+		log.Printf("Ignoring authentication settings for the stdio transport")
+		return nil, nil
+	}
+
+	verifier, err := auth.NewVerifier(s.auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+	return verifier, nil
+}
+
+// toolReadOnly reports whether the tool named name only reads
+func (s *MCPServer) toolReadOnly(name string) bool {
+	if s.tools == nil {
+		return false
+	}
+	tool, ok := s.tools.GetTool(name)
+	return ok && tool.ReadOnly
+}
+
+// authorizeMethods hides the prompts, tools and resources that the scopes of an
+// authenticated session do not grant, and refuses calls to them. Sessions without
+// a principal, such as stdio sessions, see everything.
+func (s *MCPServer) authorizeMethods(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+		p, ok := auth.FromContext(ctx)
+		if !ok {
+			return next(ctx, ss, method, params)
+		}
+
+		var denied string
+		switch params := params.(type) {
+		case *mcp.GetPromptParams:
+			if !p.Scopes.Allows(auth.KindPrompt, params.Name, false) {
+				denied = "prompt " + params.Name
+			}
+		case *mcp.CallToolParamsFor[json.RawMessage]:
+			if !p.Scopes.Allows(auth.KindTool, params.Name, s.toolReadOnly(params.Name)) {
+				denied = "tool " + params.Name
+			}
+		case *mcp.ReadResourceParams:
+			if !p.Scopes.Allows(auth.KindResource, params.URI, false) {
+				denied = "resource " + params.URI
+			}
+		}
+		if denied != "" {
+			return nil, fmt.Errorf("%s is not granted to %s %s", denied, p.Method, p.Subject)
+		}
+
+		result, err := next(ctx, ss, method, params)
+		if err != nil {
+			return result, err
+		}
+		switch result := result.(type) {
+		case *mcp.ListPromptsResult:
+			result.Prompts = slices.DeleteFunc(result.Prompts, func(prompt *mcp.Prompt) bool {
+				return !p.Scopes.Allows(auth.KindPrompt, prompt.Name, false)
+			})
+		case *mcp.ListToolsResult:
+			result.Tools = slices.DeleteFunc(result.Tools, func(tool *mcp.Tool) bool {
+				return !p.Scopes.Allows(auth.KindTool, tool.Name, s.toolReadOnly(tool.Name))
+			})
+		case *mcp.ListResourcesResult:
+			if !p.Scopes.Allows(auth.KindResource, "", false) {
+				result.Resources = []*mcp.Resource{}
+			}
+		case *mcp.ListResourceTemplatesResult:
+			if !p.Scopes.Allows(auth.KindResource, "", false) {
+				result.ResourceTemplates = []*mcp.ResourceTemplate{}
+			}
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/auth"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/prompts"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/tools"
+)
+
+// bearerTransport adds a bearer token and, if set, a GitHub token to every request
+type bearerTransport struct {
+	token       string
+	githubToken string
+}
+
+func (b bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+b.token)
+	if b.githubToken != "" {
+		r.Header.Set(DefaultGitHubTokenHeader, b.githubToken)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// authorizedServer returns a server with the built-in prompts and tools, calling
+// GitHub with gh, whose sessions are limited to the scopes of their principal
+func authorizedServer(s *MCPServer, gh *github.Client) *mcp.Server {
+	s.prompts = prompts.NewPromptManager()
+	s.tools = tools.NewToolManager(tools.Dependencies{Policy: s.sessionPolicy, GitHub: gh})
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, nil)
+	server.AddReceivingMiddleware(s.auditCalls, s.authorizeMethods)
+	s.registerPrompts(server, s.prompts)
+	s.registerTools(server, s.tools)
+	return server
+}
+
+// toolNames returns the names of tools
+func toolNames(tools []*mcp.Tool) []string {
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestAuthorizeMethodsFollowsScopes(t *testing.T) {
+	s := NewMCPServer()
+	server := authorizedServer(s, nil)
+
+	ctx := context.Background()
+	principal := &auth.Principal{Subject: "alice", Scopes: auth.Scopes{"prompts:branch-naming-convention", auth.ScopeToolsRead}, Method: "token"}
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(auth.NewContext(ctx, principal), serverTransport); err != nil {
+		t.Fatalf("server.Connect returned error: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	defer session.Close()
+
+	listedPrompts, err := session.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPrompts returned error: %v", err)
+	}
+	if len(listedPrompts.Prompts) != 1 || listedPrompts.Prompts[0].Name != "branch-naming-convention" {
+		t.Errorf("Expected only the granted prompt, got %d prompts", len(listedPrompts.Prompts))
+	}
+
+	listedTools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools returned error: %v", err)
+	}
+	got := toolNames(listedTools.Tools)
+	if !strings.Contains(strings.Join(got, " "), "git_status") {
+		t.Errorf("Expected read-only tools to be listed, got %v", got)
+	}
+	for _, name := range got {
+		if !s.toolReadOnly(name) {
+			t.Errorf("Expected tool %s to be hidden from tools:read", name)
+		}
+	}
+
+	if _, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "work-on-issue", Arguments: map[string]string{"issue_number": "42"}}); err == nil || !strings.Contains(err.Error(), "prompt work-on-issue is not granted to token alice") {
+		t.Errorf("Expected an ungranted prompt to be refused, got %v", err)
+	}
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "commit_changes", Arguments: map[string]any{"message": "fix: x"}}); err == nil || !strings.Contains(err.Error(), "tool commit_changes is not granted") {
+		t.Errorf("Expected an ungranted tool to be refused, got %v", err)
+	}
+}
+
+func TestHTTPAuthentication(t *testing.T) {
+	tokens := filepath.Join(t.TempDir(), "tokens.yml")
+	if err := os.WriteFile(tokens, []byte("tokens:\n  - name: alice\n    token: alice-secret\n    scopes: [prompts:branch-naming-convention]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := NewMCPServer()
+	s.auth = auth.Config{TokensFile: tokens}
+	verifier, err := s.loadVerifier(TransportHTTP)
+	if err != nil {
+		t.Fatalf("loadVerifier returned error: %v", err)
+	}
+	s.verifier = verifier
+
+	handler, err := s.httpHandler(authorizedServer(s, nil), TransportHTTP)
+	if err != nil {
+		t.Fatalf("httpHandler returned error: %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp := sessionRequest(t, http.MethodPost, ts.URL+DefaultStreamablePath, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	if resp.StatusCode != http.StatusUnauthorized || !strings.Contains(resp.Header.Get("WWW-Authenticate"), auth.MetadataPath) {
+		t.Errorf("Expected 401 pointing at the resource metadata, got %s %q", resp.Status, resp.Header.Get("WWW-Authenticate"))
+	}
+
+	client := &http.Client{Transport: bearerTransport{token: "alice-secret"}}
+	session := connectHTTP(t, mcp.NewStreamableClientTransport(ts.URL+DefaultStreamablePath, &mcp.StreamableClientTransportOptions{HTTPClient: client}))
+	defer session.Close()
+	listed, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools returned error: %v", err)
+	}
+	if len(listed.Tools) != 0 {
+		t.Errorf("Expected no tools without a tools scope, got %v", toolNames(listed.Tools))
+	}
+
+	s.auth = auth.Config{TokensFile: filepath.Join(t.TempDir(), "missing.yml")}
+	if _, err := s.loadVerifier(TransportHTTP); err == nil || !strings.Contains(err.Error(), "failed to load credentials") {
+		t.Errorf("Expected a missing tokens file to be fatal, got %v", err)
+	}
+	if v, err := s.loadVerifier(TransportStdio); v != nil || err != nil {
+		t.Errorf("Expected authentication to be skipped for stdio, got %v, %v", v, err)
+	}
+}
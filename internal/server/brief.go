@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/prompts"
+)
+
+// issueBrief provides the brief context of the prompts of pm, such as work-on-issue,
+// against pm's policy. Without owner and repo arguments, the GitHub repository is
+// read from the origin remote of pm's repository, which is the session's own when
+// its roots include one.
+func (s *MCPServer) issueBrief(client *github.Client, pm *prompts.PromptManager) prompts.ContextProvider {
+	return func(ctx context.Context, args map[string]any) (any, error) {
+		number, _ := args["issue"].(int)
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+
+		if owner == "" || repo == "" {
+			if owner != "" || repo != "" {
+				return nil, errors.New("owner and repo must be given together")
+			}
+			var err error
+			if owner, repo, err = originRepository(ctx, pm.Repository()); err != nil {
+				return nil, err
+			}
+		}
+
+		issue, err := issues.Load(ctx, client, owner, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		return issues.NewBrief(issue, pm.Policy()), nil
+	}
+}
+
+// originRepository returns the GitHub repository named by the origin remote of the
+// repository at dir
+func originRepository(ctx context.Context, dir string) (string, string, error) {
+	if dir == "" {
+		return "", "", errors.New("owner and repo not given and no repository has been discovered")
+	}
+	repo, err := git.Open(ctx, dir)
+	if err != nil {
+		return "", "", err
+	}
+	remote, err := repo.RemoteURL(ctx, "origin")
+	if err != nil {
+		return "", "", err
+	}
+	owner, name, ok := github.ParseRemoteURL(remote)
+	if !ok {
+		return "", "", fmt.Errorf("owner and repo not given and the origin remote %q does not name a GitHub repository", remote)
+	}
+	return owner, name, nil
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/prompts"
+)
+
+func TestIssueBrief(t *testing.T) {
+	api := githubtest.NewServer(t)
+	api.JSON("GET /repos/octo/hello/issues/42", http.StatusOK, map[string]any{
+		"number": 42, "title": "Add retries", "state": "open", "labels": []map[string]any{{"name": "enhancement"}},
+		"body": "- [ ] Retry three times",
+	})
+	api.JSON("GET /repos/octo/hello/issues/42/timeline", http.StatusOK, []any{})
+
+	dir := gittest.NewRepository(t)
+	gittest.Run(t, dir, "remote", "add", "origin", "https://github.com/octo/hello.git")
+
+	s := NewMCPServer()
+	s.prompts = prompts.NewPromptManager()
+	p := policy.Default()
+	p.Branches.Prefixes = []policy.Convention{{Name: "feat", Description: "Features"}}
+	session, err := s.prompts.ForRepository(dir, p)
+	if err != nil {
+		t.Fatalf("ForRepository returned error: %v", err)
+	}
+	provide := s.issueBrief(api.Client(t), session)
+	ctx := context.Background()
+
+	value, err := provide(ctx, map[string]any{"issue": 42, "owner": "", "repo": ""})
+	if err != nil {
+		t.Fatalf("issueBrief returned error: %v", err)
+	}
+	brief := value.(*issues.Brief)
+	if brief.Issue.Owner != "octo" || brief.Branch != "feat/42-add-retries" || len(brief.AcceptanceCriteria) != 1 {
+		t.Errorf("Unexpected brief %+v", brief)
+	}
+
+	if _, err := s.issueBrief(api.Client(t), s.prompts)(ctx, map[string]any{"issue": 42, "owner": "", "repo": ""}); err == nil || !strings.Contains(err.Error(), "no repository") {
+		t.Errorf("Expected the server prompts not to see the session's repository, got %v", err)
+	}
+	if _, err := provide(ctx, map[string]any{"issue": 42, "owner": "octo", "repo": ""}); err == nil {
+		t.Error("Expected error for owner without repo")
+	}
+	_, err = provide(ctx, map[string]any{"issue": 1, "owner": "octo", "repo": "hello"})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/auth"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/config"
+)
+
+// Transports selected with transport.mode
+const (
+	TransportStdio = "stdio"
+	// TransportHTTP serves streamable HTTP and, for older clients, SSE
+	TransportHTTP       = "http"
+	TransportStreamable = "streamable"
+	TransportSSE        = "sse"
+)
+
+// Default paths of the HTTP transports. SSE keeps the root so that clients
+// configured before streamable HTTP was added still connect.
+const (
+	DefaultStreamablePath = config.DefaultStreamablePath
+	DefaultSSEPath        = config.DefaultSSEPath
+)
+
+// Default timeouts of the HTTP server. Reads and writes of whole requests are not
+// bounded by default because SSE and streamable HTTP streams stay open.
+const (
+	DefaultReadHeaderTimeout = config.DefaultReadHeaderTimeout
+	DefaultIdleTimeout       = config.DefaultIdleTimeout
+)
+
+// transportMode returns the transport to serve. Without transport.mode it is HTTP
+// when transport.address is set and stdio otherwise.
+func (s *MCPServer) transportMode() (string, error) {
+	transport := strings.ToLower(strings.TrimSpace(s.transport))
+	switch transport {
+	case "":
+		if s.httpAddr != "" {
+			return TransportHTTP, nil
+		}
+		return TransportStdio, nil
+	case TransportStdio:
+		return transport, nil
+	case TransportHTTP, TransportStreamable, TransportSSE:
+		if s.httpAddr == "" {
+			return "", fmt.Errorf("%s: %s needs transport.address", s.key("transport.mode"), transport)
+		}
+		return transport, nil
+	}
+	return "", fmt.Errorf("%s: unknown transport %q; use %s, %s, %s or %s", s.key("transport.mode"), s.transport, TransportStdio, TransportHTTP, TransportStreamable, TransportSSE)
+}
+
+// httpHandler serves server over the HTTP transports of mode: streamable HTTP at
+// the streamable path and SSE at the SSE path. With a verifier, every request must
+// carry a bearer token it accepts. Sessions act with the GitHub credentials of
+// their client.
+func (s *MCPServer) httpHandler(server *mcp.Server, mode string) (http.Handler, error) {
+	if err := s.checkPaths(mode); err != nil {
+		return nil, err
+	}
+	getServer := func(*http.Request) *mcp.Server { return server }
+	streamable := mode == TransportHTTP || mode == TransportStreamable
+	sse := mode == TransportHTTP || mode == TransportSSE
+
+	mux := http.NewServeMux()
+	if streamable {
+		mux.Handle(s.streamablePath, mcp.NewStreamableHTTPHandler(getServer, nil))
+	}
+	if sse {
+		mux.Handle(s.ssePath, mcp.NewSSEHandler(getServer))
+	}
+	handler := s.sessionHandler(mux)
+	if s.verifier == nil {
+		return handler, nil
+	}
+	return s.verifier.Handler(handler, auth.HandlerOptions{
+		Resource:             s.authResource,
+		AuthorizationServers: s.authServers,
+		ReadOnly:             s.toolReadOnly,
+	}), nil
+}
+
+// checkPaths checks that the paths of the HTTP transports of mode are distinct
+// absolute paths
+func (s *MCPServer) checkPaths(mode string) error {
+	var errs []error
+	for _, path := range []struct{ key, value string }{
+		{"transport.streamable_path", s.streamablePath},
+		{"transport.sse_path", s.ssePath},
+	} {
+		if !strings.HasPrefix(path.value, "/") {
+			errs = append(errs, fmt.Errorf("%s: %q must start with /", s.key(path.key), path.value))
+		}
+	}
+	if mode == TransportHTTP && s.streamablePath == s.ssePath {
+		errs = append(errs, fmt.Errorf("%s: streamable HTTP and SSE cannot share the path %s", s.key("transport.sse_path"), s.ssePath))
+	}
+	return errors.Join(errs...)
+}
+
+// httpServer returns the HTTP server that serves handler at the address, with the
+// configured timeouts and TLS
+func (s *MCPServer) httpServer(handler http.Handler) (*http.Server, error) {
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Server{
+		Addr:              s.httpAddr,
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: s.readHeaderTimeout,
+		ReadTimeout:       s.readTimeout,
+		WriteTimeout:      s.writeTimeout,
+		IdleTimeout:       s.idleTimeout,
+	}, nil
+}
+
+// tlsConfig loads the certificate the server presents and, for mutual TLS, the CAs
+// that client certificates must be signed by. Without a certificate the server
+// serves plain HTTP and nil is returned.
+func (s *MCPServer) tlsConfig() (*tls.Config, error) {
+	switch {
+	case s.tlsCertFile == "" && s.tlsKeyFile == "":
+		if s.tlsClientCAFile != "" {
+			return nil, fmt.Errorf("%s: needs tls.cert_file and tls.key_file", s.key("tls.client_ca_file"))
+		}
+		return nil, nil
+	case s.tlsCertFile == "":
+		return nil, fmt.Errorf("%s: needs tls.cert_file", s.key("tls.key_file"))
+	case s.tlsKeyFile == "":
+		return nil, fmt.Errorf("%s: needs tls.key_file", s.key("tls.cert_file"))
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.tlsCertFile, s.tlsKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s, %s: %w", s.key("tls.cert_file"), s.key("tls.key_file"), err)
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if s.tlsClientCAFile == "" {
+		return config, nil
+	}
+
+	pem, err := os.ReadFile(s.tlsClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.key("tls.client_ca_file"), err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%s: no PEM certificates in %s", s.key("tls.client_ca_file"), s.tlsClientCAFile)
+	}
+	config.ClientCAs = pool
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	return config, nil
+}
+
+// serve serves HTTP on ln until ctx is done and then shuts the server down gracefully
+func (s *MCPServer) serve(ctx context.Context, server *mcp.Server, httpServer *http.Server, ln net.Listener) error {
+	served := make(chan error, 1)
+	go func() {
+		if httpServer.TLSConfig != nil {
+			served <- httpServer.ServeTLS(ln, "", "")
+		} else {
+			served <- httpServer.Serve(ln)
+		}
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+		return s.shutdown(server, httpServer)
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/prompts"
+)
+
+// serveHTTP serves the built-in prompts over the HTTP transports of mode and returns
+// the server's URL
+func serveHTTP(t *testing.T, s *MCPServer, mode string) string {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, nil)
+	s.registerPrompts(server, prompts.NewPromptManager())
+	handler, err := s.httpHandler(server, mode)
+	if err != nil {
+		t.Fatalf("httpHandler returned error: %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+// connectHTTP connects a client over transport and checks that it can list prompts
+func connectHTTP(t *testing.T, transport mcp.Transport) *mcp.ClientSession {
+	t.Helper()
+
+	ctx := context.Background()
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, transport)
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	result, err := session.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPrompts returned error: %v", err)
+	}
+	if len(result.Prompts) == 0 {
+		t.Error("Expected prompts to be listed")
+	}
+	return session
+}
+
+func TestTransportMode(t *testing.T) {
+	tests := []struct {
+		transport, addr, want, err string
+	}{
+		{"", "", TransportStdio, ""},
+		{"", ":8080", TransportHTTP, ""},
+		{"stdio", ":8080", TransportStdio, ""},
+		{"Streamable", ":8080", TransportStreamable, ""},
+		{"sse", ":8080", TransportSSE, ""},
+		{"sse", "", "", "transport.mode: sse needs transport.address"},
+		{"websocket", ":8080", "", `transport.mode: unknown transport "websocket"`},
+	}
+	for _, tt := range tests {
+		s := &MCPServer{transport: tt.transport, httpAddr: tt.addr}
+		got, err := s.transportMode()
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("transportMode(%q, %q) returned %v, expected an error containing %q", tt.transport, tt.addr, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("transportMode(%q, %q) = %q, %v, expected %q", tt.transport, tt.addr, got, err, tt.want)
+		}
+	}
+}
+
+func TestHTTPTransports(t *testing.T) {
+	url := serveHTTP(t, NewMCPServer(), TransportHTTP)
+
+	streamable := connectHTTP(t, mcp.NewStreamableClientTransport(url+DefaultStreamablePath, nil))
+	id := streamable.ID()
+	if id == "" {
+		t.Fatal("Expected a streamable HTTP session ID")
+	}
+	sse := connectHTTP(t, mcp.NewSSEClientTransport(url+DefaultSSEPath, nil))
+	defer sse.Close()
+
+	// DELETE terminates the session
+	if resp := sessionRequest(t, http.MethodDelete, url+DefaultStreamablePath, id, ""); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204 for DELETE, got %s", resp.Status)
+	}
+	resp := sessionRequest(t, http.MethodPost, url+DefaultStreamablePath, id, `{"jsonrpc":"2.0","id":2,"method":"prompts/list"}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for a terminated session, got %s", resp.Status)
+	}
+	_ = streamable.Close()
+}
+
+// sessionRequest sends a streamable HTTP request for session id
+func sessionRequest(t *testing.T, method, url, id, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Mcp-Session-Id", id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s returned error: %v", method, err)
+	}
+	_ = resp.Body.Close()
+	return resp
+}
+
+func TestHTTPTransportSelection(t *testing.T) {
+	s := NewMCPServer()
+	s.streamablePath, s.ssePath = "/v1/mcp", "/v1/sse"
+
+	url := serveHTTP(t, s, TransportStreamable)
+	session := connectHTTP(t, mcp.NewStreamableClientTransport(url+"/v1/mcp", nil))
+	defer session.Close()
+	resp, err := http.Get(url + "/v1/sse")
+	if err != nil {
+		t.Fatalf("GET returned error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected no SSE endpoint when serving streamable HTTP only, got %s", resp.Status)
+	}
+
+	url = serveHTTP(t, s, TransportSSE)
+	session = connectHTTP(t, mcp.NewSSEClientTransport(url+"/v1/sse", nil))
+	defer session.Close()
+
+	s.ssePath = s.streamablePath
+	if _, err := s.httpHandler(mcp.NewServer(&mcp.Implementation{Name: "test"}, nil), TransportHTTP); err == nil {
+		t.Error("Expected an error for transports sharing a path")
+	}
+	s.ssePath = "sse"
+	if _, err := s.httpHandler(mcp.NewServer(&mcp.Implementation{Name: "test"}, nil), TransportSSE); err == nil || !strings.Contains(err.Error(), "transport.sse_path") {
+		t.Errorf("Expected an error naming transport.sse_path, got %v", err)
+	}
+}
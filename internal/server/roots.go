@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// rootsTimeout bounds how long the server waits for a client to list its roots
+const rootsTimeout = 10 * time.Second
+
+// discoverRepository asks the client for its roots and gives the session the prompt
+// overrides and conventions policy of the first root inside a Git repository that
+// lies within the workspaces. A session whose roots include no repository
+// goes back to the server's.
+func (s *MCPServer) discoverRepository(ss *mcp.ServerSession) {
+	generation := s.workspaces.begin(ss)
+
+	ctx, cancel := context.WithTimeout(context.Background(), rootsTimeout)
+	defer cancel()
+
+	result, err := ss.ListRoots(ctx, nil)
+	if err != nil {
+		log.Printf("Could not list client roots, keeping the session's repository: %v", err)
+		return
+	}
+
+	for _, root := range result.Roots {
+		dir, ok := git.FileURIPath(root.URI)
+		if !ok {
+			continue
+		}
+		repo := git.FindRoot(dir)
+		if _, ok := s.gitWorkspaces.Allow(repo); ok {
+			s.setRepository(ss, generation, repo)
+			return
+		}
+	}
+	s.workspaces.set(ss, generation, nil)
+}
+
+// setRepository layers the prompt overrides and conventions policy of the repository
+// at root over the server's for the session, unless a newer discovery has started
+func (s *MCPServer) setRepository(ss *mcp.ServerSession, generation uint64, root string) {
+	if current := s.workspaces.get(ss); current != nil && current.root == root {
+		return
+	}
+
+	p, err := s.loadPolicy(root)
+	if err != nil {
+		log.Printf("Rejected invalid policy of %s, using the server policy: %v", root, err)
+		if p, err = s.loadPolicy(""); err != nil {
+			p = policy.Default()
+		}
+	}
+	pm, err := s.prompts.ForRepository(root, p)
+	if err != nil {
+		log.Printf("Rejected invalid repository prompt files: %v", err)
+	}
+	s.provideContext(pm)
+
+	ctx, stop := context.WithCancel(context.Background())
+	ws := &workspace{root: root, prompts: pm, stop: stop}
+	if !s.workspaces.set(ss, generation, ws) {
+		stop()
+		return
+	}
+	log.Printf("Using repository %s for the session's prompts and policy", root)
+	go s.watchWorkspace(ctx, ws)
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/prompts"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/tools"
+)
+
+// newRepository creates a repository whose branch-naming-convention prompt is
+// described as description and whose policy allows only the commit scope scope
+func newRepository(t *testing.T, description, scope string) string {
+	t.Helper()
+
+	repo := t.TempDir()
+	promptDir := filepath.Join(repo, prompts.RepositoryPromptDir)
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(promptDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := "---\nname: branch-naming-convention\ndescription: " + description + "\n---\nUse <type>/<issue>-<slug> from master.\n"
+	if err := os.WriteFile(filepath.Join(promptDir, "branch-naming-convention.md"), []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, policy.RepositoryPolicyFile), []byte("commits:\n  scopes: ["+scope+"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestDiscoverRepositoryPerSession(t *testing.T) {
+	s := NewMCPServer()
+	s.prompts = prompts.NewPromptManager()
+
+	discovered := make(chan struct{}, 2)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, &mcp.ServerOptions{
+		InitializedHandler: func(_ context.Context, ss *mcp.ServerSession, _ *mcp.InitializedParams) {
+			go func() {
+				s.discoverRepository(ss)
+				discovered <- struct{}{}
+			}()
+		},
+	})
+	server.AddReceivingMiddleware(s.servePrompts)
+	s.registerPrompts(server, s.prompts)
+	s.registerTools(server, tools.NewToolManager(tools.Dependencies{Policy: s.sessionPolicy}))
+
+	ctx := context.Background()
+	connect := func(repo string) *mcp.ClientSession {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		if _, err := server.Connect(ctx, serverTransport); err != nil {
+			t.Fatalf("server.Connect returned error: %v", err)
+		}
+		client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+		client.AddRoots(&mcp.Root{URI: "file://" + filepath.ToSlash(filepath.Join(repo, "internal")), Name: "project"})
+		session, err := client.Connect(ctx, clientTransport)
+		if err != nil {
+			t.Fatalf("client.Connect returned error: %v", err)
+		}
+		t.Cleanup(func() { _ = session.Close() })
+		select {
+		case <-discovered:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for repository discovery")
+		}
+		return session
+	}
+	api := connect(newRepository(t, "API branch rules", "api"))
+	web := connect(newRepository(t, "Web branch rules", "web"))
+
+	for session, want := range map[*mcp.ClientSession]struct{ description, scope string }{
+		api: {"API branch rules", "api"},
+		web: {"Web branch rules", "web"},
+	} {
+		result, err := session.ListPrompts(ctx, nil)
+		if err != nil {
+			t.Fatalf("ListPrompts returned error: %v", err)
+		}
+		found := false
+		for _, prompt := range result.Prompts {
+			if prompt.Name != "branch-naming-convention" {
+				continue
+			}
+			found = true
+			if prompt.Description != want.description || prompt.Meta["source"] != string(prompts.SourceRepository) {
+				t.Errorf("Expected the %q repository prompt, got %q, %v", want.description, prompt.Description, prompt.Meta)
+			}
+			if _, ok := prompt.Meta["path"]; ok {
+				t.Errorf("Expected no path in _meta, got %v", prompt.Meta)
+			}
+		}
+		if !found {
+			t.Fatal("branch-naming-convention prompt not listed")
+		}
+
+		got, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "branch-naming-convention"})
+		if err != nil {
+			t.Fatalf("GetPrompt returned error: %v", err)
+		}
+		if text := got.Messages[0].Content.(*mcp.TextContent).Text; !strings.Contains(text, "from master") {
+			t.Errorf("Expected the repository prompt text, got %q", text)
+		}
+
+		result2, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "validate_commit_message",
+			Arguments: map[string]any{"message": "fix(" + want.scope + "): align buttons"},
+		})
+		if err != nil {
+			t.Fatalf("CallTool returned error: %v", err)
+		}
+		if text := result2.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "scope-enum") {
+			t.Errorf("Expected scope %q to be allowed by the session's policy, got %s", want.scope, text)
+		}
+	}
+
+	if prompt, _ := s.prompts.GetPrompt("branch-naming-convention"); prompt.Source != prompts.SourceBuiltin || s.prompts.Repository() != "" {
+		t.Errorf("Expected the server prompts to be left alone, got %s from %q", prompt.Source, s.prompts.Repository())
+	}
+}
+
+func TestDiscoverRepositoryWithinWorkspaces(t *testing.T) {
+	repo := newRepository(t, "API branch rules", "api")
+	tests := []struct {
+		name       string
+		workspaces tools.Workspaces
+		want       string
+	}{
+		{"inside", tools.Workspaces{Dirs: []string{filepath.Dir(repo)}, Required: true}, repo},
+		{"outside", tools.Workspaces{Dirs: []string{t.TempDir()}, Required: true}, ""},
+		{"none required", tools.Workspaces{Required: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMCPServer()
+			s.prompts = prompts.NewPromptManager()
+			s.gitWorkspaces = tt.workspaces
+
+			discovered := make(chan *mcp.ServerSession, 1)
+			server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, &mcp.ServerOptions{
+				InitializedHandler: func(_ context.Context, ss *mcp.ServerSession, _ *mcp.InitializedParams) {
+					go func() {
+						s.discoverRepository(ss)
+						discovered <- ss
+					}()
+				},
+			})
+			ctx := context.Background()
+			serverTransport, clientTransport := mcp.NewInMemoryTransports()
+			if _, err := server.Connect(ctx, serverTransport); err != nil {
+				t.Fatalf("server.Connect returned error: %v", err)
+			}
+			client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+			client.AddRoots(&mcp.Root{URI: "file://" + filepath.ToSlash(repo)})
+			session, err := client.Connect(ctx, clientTransport)
+			if err != nil {
+				t.Fatalf("client.Connect returned error: %v", err)
+			}
+			t.Cleanup(func() { _ = session.Close() })
+
+			select {
+			case ss := <-discovered:
+				root := ""
+				if ws := s.workspaces.get(ss); ws != nil {
+					root = ws.root
+				}
+				if root != tt.want {
+					t.Errorf("Expected repository %q, got %q", tt.want, root)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timed out waiting for repository discovery")
+			}
+		})
+	}
+}
+
+func TestOnlyLatestDiscoveryIsInstalled(t *testing.T) {
+	w := newWorkspaces()
+	ss := &mcp.ServerSession{}
+	stale := w.begin(ss)
+	latest := w.begin(ss)
+
+	stopped := false
+	if w.set(ss, stale, &workspace{root: "stale", stop: func() { stopped = true }}) {
+		t.Error("Expected a stale discovery not to be installed")
+	}
+	if !w.set(ss, latest, &workspace{root: "latest", stop: func() { stopped = true }}) || w.get(ss).root != "latest" {
+		t.Errorf("Expected the latest discovery to be installed, got %+v", w.get(ss))
+	}
+
+	w.end(ss)
+	if !stopped || w.get(ss) != nil {
+		t.Error("Expected the workspace to be stopped and forgotten when the session ends")
+	}
+	if w.set(ss, latest, &workspace{root: "late", stop: func() {}}) {
+		t.Error("Expected no workspace to be installed after the session ended")
+	}
+}
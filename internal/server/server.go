@@ -2,72 +2,575 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/auth"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/config"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
 	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/prompts"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/resources"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/store"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/tools"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
 )
 
 // MCPServer represents the MCP server instance
 type MCPServer struct {
-	server *mcp.Server
+	// config is the configuration the server was created with; errors name its keys
+	config *config.Config
+
+	server    *mcp.Server
+	prompts   *prompts.PromptManager
+	tools     *tools.ToolManager
+	resources *resources.ResourceManager
+
+	// workspaces holds the prompts and policy of each session whose roots include a
+	// repository; other sessions use prompts
+	workspaces *workspaces
+
+	// promptDir optionally points at a directory of prompt files that
+	// override or extend the built-in prompt library
+	promptDir string
+
+	// repositoryRoot is the repository whose .github/mcp-prompts directory is
+	// layered over the other prompts until a client reports its own roots
+	repositoryRoot string
+
+	// workspaceDirs are the directories client roots must lie within; gitWorkspaces
+	// applies them, requiring some on the HTTP transports, once started
+	workspaceDirs []string
+	gitWorkspaces tools.Workspaces
+
+	// promptPollInterval is how often promptDir is checked for changes
+	promptPollInterval time.Duration
+
+	// policyFile optionally points at a conventions policy that overrides the
+	// built-in defaults; a repository's .github/mcp-policy.yml overrides it in turn
+	policyFile string
+
+	// stateFile is where work is saved across restarts; StateFileOff disables saving
+	stateFile string
+
+	// transport selects stdio or the HTTP transports served at httpAddr
+	transport string
+	httpAddr  string
+
+	// streamablePath and ssePath are where the HTTP transports are served
+	streamablePath string
+	ssePath        string
+
+	// auth holds the credentials HTTP clients authenticate with. authResource and
+	// authServers are advertised in the resource metadata.
+	auth         auth.Config
+	authResource string
+	authServers  []string
+	verifier     *auth.Verifier
+
+	// github configures the GitHub API client with the server's own token;
+	// githubClient is the client, once started
+	github       github.Config
+	githubClient *github.Client
+
+	// githubCredentials selects whose GitHub credentials HTTP sessions act with: the
+	// server's, a token the client sends in githubTokenHeader or one exchanged for its
+	// bearer token at tokenExchange. credentials is the selection in effect.
+	githubCredentials string
+	githubTokenHeader string
+	tokenExchange     auth.ExchangeConfig
+	credentials       string
+	exchanger         *auth.Exchanger
+
+	// auditFile is where audit entries are appended; they are logged otherwise
+	auditFile string
+	audit     *auditLog
+
+	// tlsCertFile and tlsKeyFile turn on HTTPS; tlsClientCAFile also requires client
+	// certificates signed by its CAs
+	tlsCertFile     string
+	tlsKeyFile      string
+	tlsClientCAFile string
+
+	// Timeouts of the HTTP server; zero means none
+	readHeaderTimeout time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+
+	// shutdownTimeout is how long running calls may take to finish on shutdown;
+	// calls tracks the running calls
+	shutdownTimeout time.Duration
+	calls           *callTracker
 }
 
-// NewMCPServer creates a new MCP server instance
+// StateFileOff is the state.file value that turns off saving work across restarts
+const StateFileOff = "off"
+
+// NewMCPServer creates a new MCP server instance with the default configuration
 func NewMCPServer() *MCPServer {
-	return &MCPServer{}
+	return New(config.Default())
 }
 
-// Start initializes and starts the MCP server
+// New creates an MCP server with the configuration cfg
+func New(cfg *config.Config) *MCPServer {
+	repositoryRoot := cfg.Prompts.RepositoryRoot
+	if repositoryRoot == "" {
+		if wd, err := os.Getwd(); err == nil {
+			repositoryRoot = git.FindRoot(wd)
+		}
+	}
+
+	return &MCPServer{
+		config:             cfg,
+		promptDir:          cfg.Prompts.Dir,
+		repositoryRoot:     repositoryRoot,
+		workspaceDirs:      cfg.Git.Workspaces,
+		promptPollInterval: cfg.Prompts.PollInterval,
+		policyFile:         cfg.Policy.File,
+		stateFile:          cfg.State.File,
+		transport:          cfg.Transport.Mode,
+		httpAddr:           cfg.Transport.Address,
+		streamablePath:     cfg.Transport.StreamablePath,
+		ssePath:            cfg.Transport.SSEPath,
+		auth: auth.Config{
+			TokensFile: cfg.Auth.TokensFile,
+			JWKSFile:   cfg.Auth.JWKSFile,
+			Issuer:     cfg.Auth.Issuer,
+			Audience:   cfg.Auth.Audience,
+		},
+		authResource:      cfg.Auth.Resource,
+		authServers:       cfg.Auth.AuthorizationServers,
+		github:            github.Config{Token: cfg.GitHub.Token, BaseURL: cfg.GitHub.APIURL},
+		githubCredentials: cfg.GitHub.Credentials,
+		githubTokenHeader: cfg.GitHub.TokenHeader,
+		tokenExchange: auth.ExchangeConfig{
+			URL:          cfg.GitHub.TokenExchange.URL,
+			ClientID:     cfg.GitHub.TokenExchange.ClientID,
+			ClientSecret: cfg.GitHub.TokenExchange.ClientSecret,
+			Audience:     cfg.GitHub.TokenExchange.Audience,
+		},
+		auditFile:         cfg.Audit.Log,
+		tlsCertFile:       cfg.TLS.CertFile,
+		tlsKeyFile:        cfg.TLS.KeyFile,
+		tlsClientCAFile:   cfg.TLS.ClientCAFile,
+		readHeaderTimeout: cfg.Transport.ReadHeaderTimeout,
+		readTimeout:       cfg.Transport.ReadTimeout,
+		writeTimeout:      cfg.Transport.WriteTimeout,
+		idleTimeout:       cfg.Transport.IdleTimeout,
+		shutdownTimeout:   cfg.Server.ShutdownTimeout,
+		calls:             newCallTracker(),
+		workspaces:        newWorkspaces(),
+	}
+}
+
+// key names the configuration key in errors, with where it was set
+func (s *MCPServer) key(name string) string {
+	if s.config == nil {
+		return name
+	}
+	return s.config.Describe(name)
+}
+
+// Validate checks the configuration the way Start uses it, loading every file it
+// names, without serving and without changing s. Errors name the offending keys.
+func (s *MCPServer) Validate() error {
+	mode, err := s.transportMode()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if mode != TransportStdio {
+		if err := s.checkPaths(mode); err != nil {
+			errs = append(errs, err)
+		}
+		if _, err := s.tlsConfig(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if verifier, err := s.loadVerifier(mode); err != nil {
+		errs = append(errs, err)
+	} else if _, _, err := s.credentialsMode(mode, verifier); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := s.loadWorkspaces(mode); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := s.loadPolicy(""); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := s.loadPrompts(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := s.newGitHubClient(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := s.openStore(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Start initializes and starts the MCP server. It serves until ctx is done and then
+// shuts down gracefully.
 func (s *MCPServer) Start(ctx context.Context) error {
+	mode, err := s.transportMode()
+	if err != nil {
+		return err
+	}
+	if s.verifier, err = s.loadVerifier(mode); err != nil {
+		return err
+	}
+	if s.credentials, s.exchanger, err = s.credentialsMode(mode, s.verifier); err != nil {
+		return err
+	}
+	if s.gitWorkspaces, err = s.loadWorkspaces(mode); err != nil {
+		return err
+	}
+	if s.audit, err = s.openAuditLog(); err != nil {
+		return err
+	}
+
+	name := config.DefaultName
+	if s.config != nil {
+		name = s.config.Server.Name
+	}
+
+	// Tools record workflow progress that the workflow resource serves. Sessions
+	// drop theirs when they end; what was saved can be resumed.
+	workflows := workflow.NewTracker()
+
 	// Create server with proper implementation
-	server := mcp.NewServer(&mcp.Implementation{
-		Name:    "github-issue-developer",
-		Version: "1.0.0",
-	}, nil)
+	var server *mcp.Server
+	server = mcp.NewServer(&mcp.Implementation{
+		Name:    name,
+		Version: config.Version,
+	}, &mcp.ServerOptions{
+		InitializedHandler: func(_ context.Context, ss *mcp.ServerSession, _ *mcp.InitializedParams) {
+			go s.discoverRepository(ss)
+			go func() {
+				_ = ss.Wait()
+				workflows.End(workflow.SessionID(ss))
+				s.workspaces.end(ss)
+			}()
+		},
+		RootsListChangedHandler: func(_ context.Context, ss *mcp.ServerSession, _ *mcp.RootsListChangedParams) {
+			go s.discoverRepository(ss)
+		},
+	})
+	server.AddReceivingMiddleware(s.trackCalls, s.auditCalls, s.authorizeMethods, s.servePrompts)
+
+	// A broken policy file configured for the server is fatal, unlike repository policies
+	if _, err := s.loadPolicy(""); err != nil {
+		return err
+	}
 
 	// Register prompt handlers
-	s.registerPrompts(server)
+	promptManager, err := s.loadPrompts()
+	if err != nil {
+		return err
+	}
+	s.prompts = promptManager
+	if _, err := s.usePolicy(s.repositoryRoot); err != nil {
+		log.Printf("Rejected invalid policy, keeping previous policy: %v", err)
+	}
+	if s.repositoryRoot != "" {
+		if _, err := promptManager.SetRepository(s.repositoryRoot); err != nil {
+			log.Printf("Rejected invalid repository prompt files: %v", err)
+		}
+	}
+	s.registerPrompts(server, promptManager)
+
+	githubClient, err := s.newGitHubClient()
+	if err != nil {
+		return err
+	}
+	s.githubClient = githubClient
+	s.provideContext(promptManager)
+
+	state, err := s.openStore()
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		workflows.OnChange(func(p *workflow.Progress) {
+			if err := state.Save(p); err != nil {
+				log.Printf("Failed to save workflow: %v", err)
+			}
+		})
+	}
+
+	// Register tools
+	s.tools = tools.NewToolManager(tools.Dependencies{
+		Policy:     s.sessionPolicy,
+		Repository: func() string { return s.repositoryRoot },
+		Workspaces: s.gitWorkspaces,
+		GitHub:     githubClient,
+		Workflows:  workflows,
+		Store:      state,
+	})
+	s.registerTools(server, s.tools)
+
+	// Register resource templates
+	s.resources = resources.NewResourceManager(resources.Dependencies{GitHub: githubClient, Workflows: workflows})
+	s.registerResources(server, s.resources)
+
+	// Hot-reload the prompt directories while the server is running
+	go promptManager.Watch(ctx, s.promptPollInterval, func(changes prompts.PromptChanges, err error) {
+		s.applyPromptChanges(server, changes, err)
+	})
 
 	s.server = server
 
 	log.Println("Starting GitHub Issue Developer MCP Server...")
-	log.Println("Server Name: github-issue-developer")
-	log.Println("Version: 1.0.0")
-
-	// Check if HTTP address is provided via environment variable
-	httpAddr := os.Getenv("MCP_HTTP_ADDR")
-	if httpAddr != "" {
-		// Start HTTP server with SSE support
-		handler := mcp.NewSSEHandler(func(*http.Request) *mcp.Server {
-			return server
-		})
-		log.Printf("MCP server listening at %s", httpAddr)
-		return http.ListenAndServe(httpAddr, handler)
-	} else {
-		// Use stdio transport
-		transport := mcp.NewStdioTransport()
-		if err := server.Run(ctx, transport); err != nil {
-			return fmt.Errorf("failed to start server: %w", err)
+	log.Printf("Server Name: %s", name)
+	log.Printf("Version: %s", config.Version)
+	if s.config != nil && s.config.File() != "" {
+		log.Printf("Configuration file: %s", s.config.File())
+	}
+	if !s.gitWorkspaces.Enabled() {
+		log.Printf("Git tools disabled: %s lists no directories client roots may use", s.key("git.workspaces"))
+	}
+
+	if mode != TransportStdio {
+		handler, err := s.httpHandler(server, mode)
+		if err != nil {
+			return err
+		}
+		httpServer, err := s.httpServer(handler)
+		if err != nil {
+			return err
+		}
+		if mode != TransportSSE {
+			log.Printf("Streamable HTTP transport at %s", s.streamablePath)
+		}
+		if mode != TransportStreamable {
+			log.Printf("SSE transport at %s", s.ssePath)
 		}
+		ln, err := net.Listen("tcp", s.httpAddr)
+		if err != nil {
+			return err
+		}
+		switch {
+		case s.tlsClientCAFile != "":
+			log.Printf("MCP server listening at %s (HTTPS with client certificates)", s.httpAddr)
+		case httpServer.TLSConfig != nil:
+			log.Printf("MCP server listening at %s (HTTPS)", s.httpAddr)
+		default:
+			log.Printf("MCP server listening at %s", s.httpAddr)
+		}
+		return s.serve(ctx, server, httpServer, ln)
 	}
 
-	return nil
+	// Use stdio transport
+	ss, err := server.Connect(ctx, mcp.NewStdioTransport())
+	if err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+	closed := make(chan error, 1)
+	go func() { closed <- ss.Wait() }()
+	select {
+	case err := <-closed:
+		auditErr := s.audit.close()
+		if err != nil {
+			return errors.Join(fmt.Errorf("server stopped: %w", err), auditErr)
+		}
+		return auditErr
+	case <-ctx.Done():
+		return s.shutdown(server, nil)
+	}
 }
 
-// registerPrompts registers all available prompts with the server
-func (s *MCPServer) registerPrompts(server *mcp.Server) {
-	promptManager := prompts.NewPromptManager()
+// loadWorkspaces returns the workspaces client roots must lie within, which must be
+// existing directories. The HTTP transports require some for the git tools.
+func (s *MCPServer) loadWorkspaces(mode string) (tools.Workspaces, error) {
+	var errs []error
+	for _, dir := range s.workspaceDirs {
+		if info, err := os.Stat(dir); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.key("git.workspaces"), err))
+		} else if !info.IsDir() {
+			errs = append(errs, fmt.Errorf("%s: %s is not a directory", s.key("git.workspaces"), dir))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return tools.Workspaces{}, err
+	}
+	return tools.Workspaces{Dirs: s.workspaceDirs, Required: mode != TransportStdio}, nil
+}
+
+// loadPrompts builds the prompt manager from the built-in library and the user prompt
+// directory, which is prompts.dir if set or the user configuration directory otherwise
+func (s *MCPServer) loadPrompts() (*prompts.PromptManager, error) {
+	dir := s.promptDir
+	if dir == "" {
+		dir = defaultUserPromptDir()
+		if info, err := os.Stat(dir); dir == "" || err != nil || !info.IsDir() {
+			return prompts.NewPromptManager(), nil
+		}
+	}
+
+	promptManager, err := prompts.LoadPromptManager(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prompts from %s: %w", dir, err)
+	}
+	log.Printf("Loaded prompt overrides from %s", dir)
+	return promptManager, nil
+}
+
+// defaultUserPromptDir returns the user-global prompt directory, or an empty string
+// if the user configuration directory cannot be determined
+func defaultUserPromptDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "github-issue-developer", "prompts")
+}
+
+// openStore opens the file work is saved in across restarts: state.file if set,
+// or state.json in the user configuration directory. It returns nil when saving is off
+// or there is nowhere to save.
+func (s *MCPServer) openStore() (*store.Store, error) {
+	path := s.stateFile
+	switch path {
+	case StateFileOff:
+		return nil, nil
+	case "":
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			log.Printf("Not saving work across restarts: %v", err)
+			return nil, nil
+		}
+		path = filepath.Join(configDir, "github-issue-developer", "state.json")
+	}
+
+	state, err := store.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state file: %w", err)
+	}
+	log.Printf("Saving work in %s", path)
+	return state, nil
+}
+
+// loadPolicy returns the conventions policy for the repository at root: the built-in
+// defaults overlaid with policy.file and then the repository's policy file, if any
+func (s *MCPServer) loadPolicy(root string) (*policy.Policy, error) {
+	var files []string
+	if s.policyFile != "" {
+		files = append(files, s.policyFile)
+	}
+	if root != "" {
+		path := filepath.Join(root, policy.RepositoryPolicyFile)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+
+	p, err := policy.Load(files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy: %w", err)
+	}
+	return p, nil
+}
+
+// usePolicy loads the conventions policy for the repository at root and renders the
+// prompts against it. On error the previous policy stays in effect.
+func (s *MCPServer) usePolicy(root string) (prompts.PromptChanges, error) {
+	p, err := s.loadPolicy(root)
+	if err != nil {
+		return prompts.PromptChanges{}, err
+	}
+	return s.prompts.SetPolicy(p)
+}
+
+// provideContext installs the context providers of the prompts of pm, so that they
+// resolve the repository and policy of pm rather than the server's
+func (s *MCPServer) provideContext(pm *prompts.PromptManager) {
+	pm.SetContextProvider(prompts.ContextBrief, s.issueBrief(s.githubClient, pm))
+}
 
+// newGitHubClient creates the GitHub API client from github.token and github.api_url
+func (s *MCPServer) newGitHubClient() (*github.Client, error) {
+	client, err := github.NewClient(s.github)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.key("github.api_url"), err)
+	}
+	if client.Authenticated() {
+		log.Printf("GitHub API: %s", client.BaseURL())
+	} else {
+		log.Printf("GitHub API: %s (unauthenticated; set github.token or GITHUB_TOKEN for private repositories)", client.BaseURL())
+	}
+	return client, nil
+}
+
+// registerPrompts registers all available prompts with the server
+func (s *MCPServer) registerPrompts(server *mcp.Server, promptManager *prompts.PromptManager) {
 	// Register all prompts
 	for _, prompt := range promptManager.GetAllPrompts() {
-		server.AddPrompt(&mcp.Prompt{
-			Name:        prompt.Name,
-			Description: prompt.Description,
-		}, prompt.Handler)
-		log.Printf("Registered prompt: %s - %s", prompt.Name, prompt.Description)
+		addPrompt(server, prompt)
+		log.Printf("Registered prompt: %s - %s (%s)", prompt.Name, prompt.Description, prompt.Source)
+	}
+}
+
+// registerTools registers all available tools with the server
+func (s *MCPServer) registerTools(server *mcp.Server, toolManager *tools.ToolManager) {
+	for _, tool := range toolManager.GetAllTools() {
+		tool.Register(server)
+		log.Printf("Registered tool: %s", tool.Name)
+	}
+}
+
+// registerResources registers all available resource templates with the server
+func (s *MCPServer) registerResources(server *mcp.Server, resourceManager *resources.ResourceManager) {
+	for _, template := range resourceManager.GetAllTemplates() {
+		template.Register(server)
+		log.Printf("Registered resource template: %s", template.URITemplate)
+	}
+}
+
+// applyPromptChanges mirrors a prompt reload onto the live server. Adding and removing
+// prompts notifies connected sessions with notifications/prompts/list_changed.
+func (s *MCPServer) applyPromptChanges(server *mcp.Server, changes prompts.PromptChanges, err error) {
+	if err != nil {
+		log.Printf("Rejected invalid prompt files, keeping previous versions: %v", err)
+	}
+
+	for _, prompt := range changes.Added {
+		addPrompt(server, prompt)
+		log.Printf("Added prompt: %s - %s (%s)", prompt.Name, prompt.Description, prompt.Source)
+	}
+	for _, prompt := range changes.Updated {
+		addPrompt(server, prompt)
+		log.Printf("Updated prompt: %s - %s (%s)", prompt.Name, prompt.Description, prompt.Source)
+	}
+	if len(changes.Removed) > 0 {
+		server.RemovePrompts(changes.Removed...)
+		log.Printf("Removed prompts: %s", strings.Join(changes.Removed, ", "))
+	}
+}
+
+// addPrompt adds or replaces a prompt on the server
+func addPrompt(server *mcp.Server, prompt prompts.Prompt) {
+	server.AddPrompt(mcpPrompt(prompt), prompt.Handler)
+}
+
+// mcpPrompt returns the MCP representation of a prompt. The layer the prompt was
+// loaded from is advertised in its _meta so clients can see which definition is in
+// effect; the file is not, as it would reveal paths on the server.
+func mcpPrompt(prompt prompts.Prompt) *mcp.Prompt {
+	return &mcp.Prompt{
+		Meta:        mcp.Meta{"source": string(prompt.Source)},
+		Name:        prompt.Name,
+		Description: prompt.Description,
+		Arguments:   prompt.MCPArguments(),
 	}
 }
@@ -1,7 +1,19 @@
 package server
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/config"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/prompts"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/tools"
 )
 
 func TestNewMCPServer(t *testing.T) {
@@ -48,3 +60,354 @@ func TestServerStructure(t *testing.T) {
 		t.Error("Internal server should be nil before Start() is called")
 	}
 }
+
+func TestRegisteredPromptsAdvertiseArguments(t *testing.T) {
+	s := NewMCPServer()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, nil)
+	s.registerPrompts(server, prompts.NewPromptManager())
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport); err != nil {
+		t.Fatalf("server.Connect returned error: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	defer session.Close()
+
+	result, err := session.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPrompts returned error: %v", err)
+	}
+
+	arguments := make(map[string][]string)
+	for _, prompt := range result.Prompts {
+		for _, arg := range prompt.Arguments {
+			arguments[prompt.Name] = append(arguments[prompt.Name], arg.Name)
+		}
+	}
+
+	want := []string{"issue_number", "base_branch", "scope", "language", "coverage_threshold"}
+	if !slices.Equal(arguments["development-workflow"], want) {
+		t.Errorf("Expected development-workflow arguments %v, got %v", want, arguments["development-workflow"])
+	}
+
+	got, err := session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "branch-naming-convention",
+		Arguments: map[string]string{"issue_number": "42"},
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt returned error: %v", err)
+	}
+	if text := got.Messages[0].Content.(*mcp.TextContent).Text; !strings.Contains(text, "<type>/42-<short-description>") {
+		t.Errorf("Expected rendered issue number in prompt text, got %q", text)
+	}
+
+	if _, err := session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "branch-naming-convention",
+		Arguments: map[string]string{"issue_number": "not-a-number"},
+	}); err == nil {
+		t.Error("Expected GetPrompt to fail for invalid argument")
+	}
+}
+
+func TestRegisteredToolsFollowPromptPolicy(t *testing.T) {
+	s := NewMCPServer()
+	s.prompts = prompts.NewPromptManager()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, nil)
+	s.registerTools(server, tools.NewToolManager(tools.Dependencies{Policy: s.sessionPolicy}))
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport); err != nil {
+		t.Fatalf("server.Connect returned error: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	defer session.Close()
+
+	p := policy.Default()
+	p.Commits.Scopes = []string{"api"}
+	if _, err := s.prompts.SetPolicy(p); err != nil {
+		t.Fatalf("SetPolicy returned error: %v", err)
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "validate_commit_message",
+		Arguments: map[string]any{"message": "fix(ui): align buttons"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool returned error: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "scope-enum") {
+		t.Errorf("Expected scope violation from the current policy, got %s", text)
+	}
+}
+
+func TestLoadPromptsFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	file := "---\nname: release-notes\ndescription: Release note guidelines\n---\nSummarise user-facing changes.\n"
+	if err := os.WriteFile(filepath.Join(dir, "release-notes.md"), []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewMCPServer()
+	s.promptDir = dir
+
+	promptManager, err := s.loadPrompts()
+	if err != nil {
+		t.Fatalf("loadPrompts returned error: %v", err)
+	}
+	if _, ok := promptManager.GetPrompt("release-notes"); !ok {
+		t.Error("Expected prompt from directory to be loaded")
+	}
+
+	s.promptDir = filepath.Join(dir, "missing")
+	if _, err := s.loadPrompts(); err == nil {
+		t.Error("Expected error for missing prompt directory")
+	}
+}
+
+func TestLoadPolicyLayers(t *testing.T) {
+	userPolicy := filepath.Join(t.TempDir(), "policy.yml")
+	if err := os.WriteFile(userPolicy, []byte("default_branch: main\ncoverage_threshold: 90\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := t.TempDir()
+	repoPolicy := filepath.Join(repo, policy.RepositoryPolicyFile)
+	if err := os.MkdirAll(filepath.Dir(repoPolicy), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(repoPolicy, []byte("coverage_threshold: 75\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewMCPServer()
+	s.policyFile = userPolicy
+
+	p, err := s.loadPolicy(repo)
+	if err != nil {
+		t.Fatalf("loadPolicy returned error: %v", err)
+	}
+	if p.DefaultBranch != "main" || p.CoverageThreshold != 75 {
+		t.Errorf("Expected repository policy over user policy, got %q/%d", p.DefaultBranch, p.CoverageThreshold)
+	}
+
+	p, err = s.loadPolicy(t.TempDir())
+	if err != nil {
+		t.Fatalf("loadPolicy returned error: %v", err)
+	}
+	if p.CoverageThreshold != 90 {
+		t.Errorf("Expected user policy for repository without a policy file, got %d", p.CoverageThreshold)
+	}
+
+	s.policyFile = filepath.Join(t.TempDir(), "missing.yml")
+	if _, err := s.loadPolicy(""); err == nil {
+		t.Error("Expected error for missing policy file")
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	s := NewMCPServer()
+	s.stateFile = StateFileOff
+	if state, err := s.openStore(); state != nil || err != nil {
+		t.Errorf("Expected no store when saving is off, got %v, %v", state, err)
+	}
+
+	s.stateFile = filepath.Join(t.TempDir(), "state.json")
+	state, err := s.openStore()
+	if err != nil {
+		t.Fatalf("openStore returned error: %v", err)
+	}
+	if state.Path() != s.stateFile {
+		t.Errorf("Expected the store at %s, got %s", s.stateFile, state.Path())
+	}
+
+	if err := os.WriteFile(s.stateFile, []byte(`{"version": 99}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.openStore(); err == nil || !strings.Contains(err.Error(), "newer server") {
+		t.Errorf("Expected a state file from a newer server to be refused, got %v", err)
+	}
+}
+
+func TestApplyPromptChangesNotifiesSessions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		data := "---\nname: " + name + "\ndescription: " + name + " guidelines\n---\n" + body + "\n"
+		if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("release-notes", "Summarise changes.")
+
+	promptManager, err := prompts.LoadPromptManager(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptManager returned error: %v", err)
+	}
+
+	s := NewMCPServer()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, nil)
+	s.registerPrompts(server, promptManager)
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport); err != nil {
+		t.Fatalf("server.Connect returned error: %v", err)
+	}
+
+	notified := make(chan struct{}, 10)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, &mcp.ClientOptions{
+		PromptListChangedHandler: func(context.Context, *mcp.ClientSession, *mcp.PromptListChangedParams) {
+			notified <- struct{}{}
+		},
+	})
+	session, err := client.Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	defer session.Close()
+
+	if err := os.Remove(filepath.Join(dir, "release-notes.md")); err != nil {
+		t.Fatal(err)
+	}
+	write("onboarding", "Welcome aboard.")
+
+	changes, err := promptManager.Reload()
+	if err != nil {
+		t.Fatalf("Reload returned error: %v", err)
+	}
+	s.applyPromptChanges(server, changes, nil)
+
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for prompts/list_changed notification")
+	}
+
+	result, err := session.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPrompts returned error: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, prompt := range result.Prompts {
+		names[prompt.Name] = true
+	}
+	if !names["onboarding"] {
+		t.Error("Expected added prompt to be listed")
+	}
+	if names["release-notes"] {
+		t.Error("Expected removed prompt not to be listed")
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.Name = "team-developer"
+	cfg.Transport.Address = ":8080"
+	cfg.Prompts.PollInterval = 250 * time.Millisecond
+	cfg.GitHub.Token = "server-token"
+	cfg.GitHub.TokenExchange.URL = "https://idp.example.com/token"
+
+	s := New(cfg)
+	if s.httpAddr != ":8080" || s.promptPollInterval != 250*time.Millisecond || s.github.Token != "server-token" || s.tokenExchange.URL != "https://idp.example.com/token" {
+		t.Errorf("Expected the configuration to be applied, got %+v", s)
+	}
+	if s.streamablePath != DefaultStreamablePath || s.shutdownTimeout != DefaultShutdownTimeout {
+		t.Errorf("Expected defaults for unset settings, got %q, %s", s.streamablePath, s.shutdownTimeout)
+	}
+}
+
+func TestValidateNamesKeysAndSources(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yml")
+	if err := os.WriteFile(file, []byte(`transport:
+  mode: http
+  sse_path: sse
+tls:
+  cert_file: server.crt
+policy:
+  file: `+filepath.Join(dir, "missing.yml")+`
+state:
+  file: off
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"MCP_CONFIG_FILE": file, "MCP_HTTP_ADDR": "127.0.0.1:0", "GITHUB_API_URL": "ftp://github.example.com",
+		"MCP_GIT_WORKSPACES": filepath.Join(dir, "work")}
+	cfg, err := config.Load(config.Options{LookupEnv: func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	err = New(cfg).Validate()
+	if err == nil {
+		t.Fatal("Expected the configuration to be rejected")
+	}
+	for _, want := range []string{
+		`transport.sse_path (file ` + file + `:3): "sse" must start with /`,
+		"tls.cert_file (file " + file + ":5): needs tls.key_file",
+		"failed to load policy",
+		"github.api_url (env GITHUB_API_URL): invalid GitHub base URL",
+		"git.workspaces (env MCP_GIT_WORKSPACES): stat " + filepath.Join(dir, "work"),
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in:\n%v", want, err)
+		}
+	}
+
+	env = map[string]string{"MCP_TRANSPORT": "sse", "MCP_STATE_FILE": "off"}
+	if cfg, err = config.Load(config.Options{LookupEnv: func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}}); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if err := New(cfg).Validate(); err == nil || !strings.Contains(err.Error(), "transport.mode (env MCP_TRANSPORT): sse needs transport.address") {
+		t.Errorf("Expected an error naming transport.mode and its source, got %v", err)
+	}
+}
+
+func TestValidateLeavesServerUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	tokens := filepath.Join(dir, "tokens.yml")
+	if err := os.WriteFile(tokens, []byte("tokens:\n  - name: alice\n    token: alice-secret\n    scopes: [\"*\"]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"MCP_HTTP_ADDR": "127.0.0.1:0", "MCP_STATE_FILE": "off", "MCP_AUTH_TOKENS_FILE": tokens,
+		"MCP_GITHUB_CREDENTIALS": "exchange", "MCP_GITHUB_TOKEN_EXCHANGE_URL": "https://idp.example.com/token",
+		"MCP_PROMPTS_DIR": filepath.Join(dir, "prompts"),
+	}
+	if err := os.Mkdir(env["MCP_PROMPTS_DIR"], 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(config.Options{LookupEnv: func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	s := New(cfg)
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if s.verifier != nil || s.exchanger != nil || s.credentials != "" {
+		t.Errorf("Expected Validate to only check the configuration, got verifier %v, exchanger %v, credentials %q", s.verifier, s.exchanger, s.credentials)
+	}
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/auth"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/config"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+)
+
+// GitHub credentials of HTTP sessions, selected with github.credentials
+const (
+	// CredentialsServer acts with the server's own token for every session
+	CredentialsServer = "server"
+	// CredentialsForward acts with the token each client sends in a header
+	CredentialsForward = "forward"
+	// CredentialsExchange acts with a token exchanged for the client's bearer token
+	CredentialsExchange = "exchange"
+)
+
+// DefaultGitHubTokenHeader carries the client's GitHub token with CredentialsForward
+const DefaultGitHubTokenHeader = config.DefaultGitHubTokenHeader
+
+// sessionIDHeader is the streamable HTTP session header
+const sessionIDHeader = "Mcp-Session-Id"
+
+// credentialsKey is the key of the credentials a session acts with in its context
+type credentialsKey struct{}
+
+// sessionCredentials returns the GitHub credentials a session acts with, as audited
+func sessionCredentials(ctx context.Context) string {
+	if credentials, ok := ctx.Value(credentialsKey{}).(string); ok && credentials != "" {
+		return credentials
+	}
+	return CredentialsServer
+}
+
+// credentialsMode returns the GitHub credentials HTTP sessions act with, given the
+// verifier that authenticates them, and the token exchange they need, if any. Stdio
+// sessions always act with the server's token.
+func (s *MCPServer) credentialsMode(mode string, verifier *auth.Verifier) (string, *auth.Exchanger, error) {
+	credentials := strings.ToLower(strings.TrimSpace(s.githubCredentials))
+	switch credentials {
+	case "", CredentialsServer:
+		return CredentialsServer, nil, nil
+	case CredentialsForward, CredentialsExchange:
+	default:
+		return "", nil, fmt.Errorf("%s: unknown credentials %q; use %s, %s or %s", s.key("github.credentials"), s.githubCredentials, CredentialsServer, CredentialsForward, CredentialsExchange)
+	}
+	if mode == TransportStdio {
+		log.Printf("Ignoring github.credentials %s for the stdio transport", credentials)
+		return CredentialsServer, nil, nil
+	}
+
+	// Without authentication a session ID is all it takes to act with the GitHub
+	// credentials of whoever created the session
+	if verifier == nil {
+		return "", nil, fmt.Errorf("%s: %s needs auth.tokens_file or auth.jwks_file", s.key("github.credentials"), credentials)
+	}
+	if credentials == CredentialsForward {
+		return credentials, nil, nil
+	}
+	if s.tokenExchange.URL == "" {
+		return "", nil, fmt.Errorf("%s: %s needs github.token_exchange.url", s.key("github.credentials"), credentials)
+	}
+	exchanger, err := auth.NewExchanger(s.tokenExchange)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", s.key("github.token_exchange.url"), err)
+	}
+	return credentials, exchanger, nil
+}
+
+// sessionHandler gives every HTTP session the GitHub credentials of its client and
+// binds it to the principal that created it so that nobody else can use its session
+// ID. The credentials are taken from every request of the session, so a client can
+// rotate its GitHub token or bearer token without reconnecting.
+func (s *MCPServer) sessionHandler(next http.Handler) http.Handler {
+	sessions := &httpSessions{sessions: map[string]*httpSession{}}
+	header := s.githubTokenHeader
+	if header == "" {
+		header = DefaultGitHubTokenHeader
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var source github.TokenSource
+		switch s.credentials {
+		case CredentialsForward:
+			token := strings.TrimSpace(r.Header.Get(header))
+			if token == "" {
+				http.Error(w, fmt.Sprintf("missing %s header with the GitHub token of the session", header), http.StatusBadRequest)
+				return
+			}
+			source = github.StaticToken(token)
+		case CredentialsExchange:
+			bearer, _ := auth.BearerToken(r)
+			source = func(ctx context.Context) (string, error) {
+				return s.exchanger.Exchange(ctx, bearer)
+			}
+		}
+		var owner string
+		if p, ok := auth.FromContext(r.Context()); ok {
+			owner = p.ID()
+		}
+
+		id := r.Header.Get(sessionIDHeader)
+		if id == "" {
+			id = r.URL.Query().Get("sessionid")
+		}
+		if id != "" {
+			if session, ok := sessions.get(id); ok {
+				if session.owner != owner {
+					log.Printf("Refused session %s of %s to %s", id, session.owner, owner)
+					http.Error(w, "session not found", http.StatusNotFound)
+					return
+				}
+				session.setTokenSource(source)
+			}
+			sw := &sessionWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+			if r.Method == http.MethodDelete && sw.status == http.StatusNoContent {
+				sessions.unbind(id)
+			}
+			return
+		}
+
+		// The context of the request that creates a session is the context of all of
+		// its calls, so the session's credentials are looked up on every GitHub request
+		session := &httpSession{owner: owner, source: source}
+		ctx := context.WithValue(r.Context(), credentialsKey{}, s.credentials)
+		if source != nil {
+			ctx = github.WithTokenSource(ctx, session.token)
+		}
+		sw := &sessionWriter{ResponseWriter: w, bind: func(id string) { sessions.bind(id, session) }}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+		// SSE sessions end with their stream
+		if sw.streamID != "" {
+			sessions.unbind(sw.streamID)
+		}
+	})
+}
+
+// httpSession is the principal an HTTP session is bound to and the GitHub
+// credentials of its latest request
+type httpSession struct {
+	owner string
+
+	mu     sync.Mutex
+	source github.TokenSource
+}
+
+func (s *httpSession) setTokenSource(source github.TokenSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.source = source
+}
+
+// token returns a GitHub token from the session's current credentials
+func (s *httpSession) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	source := s.source
+	s.mu.Unlock()
+	return source(ctx)
+}
+
+// httpSessions maps session IDs to their sessions
+type httpSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+}
+
+func (h *httpSessions) bind(id string, session *httpSession) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessions[id] = session
+}
+
+func (h *httpSessions) unbind(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sessions, id)
+}
+
+func (h *httpSessions) get(id string) (*httpSession, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	session, ok := h.sessions[id]
+	return session, ok
+}
+
+// maxEndpointEvent bounds how much of an SSE stream is read looking for its endpoint event
+const maxEndpointEvent = 4096
+
+// sessionWriter passes a response through, reporting the session ID it assigns to
+// bind before the client can see it: the Mcp-Session-Id header of streamable HTTP
+// or the sessionid of the endpoint URL that starts an SSE stream
+type sessionWriter struct {
+	http.ResponseWriter
+	bind   func(id string)
+	status int
+
+	// event buffers the first event of an SSE stream until it is complete, and
+	// streamID is the ID of the SSE session whose stream this is
+	event    []byte
+	streamID string
+}
+
+func (w *sessionWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		if id := w.Header().Get(sessionIDHeader); id != "" && w.bind != nil {
+			w.bind(id)
+			w.bind = nil
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.bind != nil && strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+		w.event = append(w.event, b...)
+		if id, done := endpointSessionID(w.event); done || len(w.event) > maxEndpointEvent {
+			if id != "" {
+				w.streamID = id
+				w.bind(id)
+			}
+			w.bind, w.event = nil, nil
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// endpointSessionID returns the session ID of the endpoint URL in the first event of
+// an SSE stream, and whether that event is complete
+func endpointSessionID(stream []byte) (string, bool) {
+	stream = bytes.ReplaceAll(stream, []byte("\r\n"), []byte("\n"))
+	event, _, ok := bytes.Cut(stream, []byte("\n\n"))
+	if !ok {
+		return "", false
+	}
+
+	var name, data string
+	for line := range strings.SplitSeq(string(event), "\n") {
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			name = value
+		case "data":
+			data = value
+		}
+	}
+	if name != "endpoint" {
+		return "", true
+	}
+	endpoint, err := url.Parse(data)
+	if err != nil {
+		return "", true
+	}
+	return endpoint.Query().Get("sessionid"), true
+}
+
+// Flush sends buffered data to the client, which server-sent events need
+func (w *sessionWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/auth"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+)
+
+// serveTeam serves the prompts and tools over HTTP to alice and bob, who
+// authenticate with the tokens alice-secret and bob-secret, and returns the
+// server's URL. GitHub calls go to gh.
+func serveTeam(t *testing.T, s *MCPServer, gh *githubtest.Server) string {
+	t.Helper()
+
+	tokens := filepath.Join(t.TempDir(), "tokens.yml")
+	content := "tokens:\n  - name: alice\n    token: alice-secret\n    scopes: [\"*\"]\n  - name: bob\n    token: bob-secret\n    scopes: [\"*\"]\n"
+	if err := os.WriteFile(tokens, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s.auth = auth.Config{TokensFile: tokens}
+	verifier, err := s.loadVerifier(TransportHTTP)
+	if err != nil {
+		t.Fatalf("loadVerifier returned error: %v", err)
+	}
+	s.verifier = verifier
+	if s.credentials, s.exchanger, err = s.credentialsMode(TransportHTTP, verifier); err != nil {
+		t.Fatalf("credentialsMode returned error: %v", err)
+	}
+
+	handler, err := s.httpHandler(authorizedServer(s, gh.Client(t)), TransportHTTP)
+	if err != nil {
+		t.Fatalf("httpHandler returned error: %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+// requestAs sends an HTTP request authenticated with token for session id
+func requestAs(t *testing.T, token, method, url, id, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Content-Type", "application/json")
+	if id != "" {
+		req.Header.Set("Mcp-Session-Id", id)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s returned error: %v", method, err)
+	}
+	_ = resp.Body.Close()
+	return resp
+}
+
+func TestSessionsActWithTheirOwnGitHubCredentials(t *testing.T) {
+	exchange := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		githubtest.WriteJSON(w, http.StatusOK, map[string]any{
+			"access_token": "gho_for_" + strings.TrimSuffix(r.PostForm.Get("subject_token"), "-secret"),
+			"expires_in":   3600,
+		})
+	}))
+	defer exchange.Close()
+
+	tests := []struct {
+		credentials, githubToken, want string
+	}{
+		{CredentialsServer, "", "Bearer " + githubtest.Token},
+		{CredentialsForward, "gho_forwarded", "Bearer gho_forwarded"},
+		{CredentialsExchange, "", "Bearer gho_for_alice"},
+	}
+	for _, tt := range tests {
+		gh := githubtest.NewServer(t)
+		var got string
+		gh.HandleFunc("GET /repos/octo/hello/issues/1", func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("Authorization")
+			githubtest.WriteJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		})
+
+		var audit bytes.Buffer
+		s := NewMCPServer()
+		s.githubCredentials = tt.credentials
+		s.tokenExchange = auth.ExchangeConfig{URL: exchange.URL}
+		s.audit = &auditLog{w: &audit}
+		url := serveTeam(t, s, gh)
+
+		client := &http.Client{Transport: bearerTransport{token: "alice-secret", githubToken: tt.githubToken}}
+		session := connectHTTP(t, mcp.NewStreamableClientTransport(url+DefaultStreamablePath, &mcp.StreamableClientTransportOptions{HTTPClient: client}))
+		_, _ = session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "get_issue",
+			Arguments: map[string]any{"owner": "octo", "repo": "hello", "number": 1},
+		})
+		_ = session.Close()
+		if got != tt.want {
+			t.Errorf("%s: expected GitHub to be called with %q, got %q", tt.credentials, tt.want, got)
+		}
+
+		var entry AuditEntry
+		for line := range strings.SplitSeq(strings.TrimSpace(audit.String()), "\n") {
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("Unmarshal returned error: %v", err)
+			}
+		}
+		if entry.User != "token:alice" || entry.Credentials != tt.credentials || entry.Method != "tools/call" || entry.Name != "get_issue" || entry.Outcome == "ok" || entry.Session == "" {
+			t.Errorf("%s: unexpected audit entry %+v", tt.credentials, entry)
+		}
+	}
+}
+
+// rotatingTransport authenticates as token and forwards the current GitHub token
+type rotatingTransport struct {
+	token       string
+	githubToken atomic.Pointer[string]
+}
+
+func (t *rotatingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return bearerTransport{token: t.token, githubToken: *t.githubToken.Load()}.RoundTrip(r)
+}
+
+func TestRotatedCredentialsAreUsedMidSession(t *testing.T) {
+	gh := githubtest.NewServer(t)
+	var got atomic.Value
+	gh.HandleFunc("GET /repos/octo/hello/issues/1", func(w http.ResponseWriter, r *http.Request) {
+		got.Store(r.Header.Get("Authorization"))
+		githubtest.WriteJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	})
+
+	s := NewMCPServer()
+	s.githubCredentials = CredentialsForward
+	url := serveTeam(t, s, gh)
+
+	transport := &rotatingTransport{token: "alice-secret"}
+	first := "gho_before"
+	transport.githubToken.Store(&first)
+	session := connectHTTP(t, mcp.NewStreamableClientTransport(url+DefaultStreamablePath, &mcp.StreamableClientTransportOptions{HTTPClient: &http.Client{Transport: transport}}))
+	defer session.Close()
+
+	for _, token := range []string{"gho_before", "gho_after"} {
+		transport.githubToken.Store(&token)
+		_, _ = session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "get_issue",
+			Arguments: map[string]any{"owner": "octo", "repo": "hello", "number": 1},
+		})
+		if got.Load() != "Bearer "+token {
+			t.Errorf("Expected GitHub to be called with %s, got %v", token, got.Load())
+		}
+	}
+}
+
+func TestEndpointSessionID(t *testing.T) {
+	tests := []struct {
+		stream string
+		id     string
+		done   bool
+	}{
+		{"event: endpoint\ndata: /?sessionid=abc123\n\n", "abc123", true},
+		{"event: endpoint\r\ndata: /sse?x=1&sessionid=A-b_9\r\n\r\nevent: message\n", "A-b_9", true},
+		{"event: endpoint\ndata: /?sessionid=ab", "", false},
+		{"event: message\ndata: {\"sessionid=evil\"}\n\n", "", true},
+	}
+	for _, tt := range tests {
+		id, done := endpointSessionID([]byte(tt.stream))
+		if id != tt.id || done != tt.done {
+			t.Errorf("endpointSessionID(%q) = %q, %v, expected %q, %v", tt.stream, id, done, tt.id, tt.done)
+		}
+	}
+}
+
+func TestForwardedCredentialsAreRequired(t *testing.T) {
+	s := NewMCPServer()
+	s.githubCredentials = CredentialsForward
+	url := serveTeam(t, s, githubtest.NewServer(t))
+
+	resp := requestAs(t, "alice-secret", http.MethodPost, url+DefaultStreamablePath, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a GitHub token, got %s", resp.Status)
+	}
+}
+
+func TestSessionsAreBoundToTheirPrincipal(t *testing.T) {
+	url := serveTeam(t, NewMCPServer(), githubtest.NewServer(t))
+
+	client := &http.Client{Transport: bearerTransport{token: "alice-secret"}}
+	session := connectHTTP(t, mcp.NewStreamableClientTransport(url+DefaultStreamablePath, &mcp.StreamableClientTransportOptions{HTTPClient: client}))
+	defer session.Close()
+
+	list := `{"jsonrpc":"2.0","id":9,"method":"prompts/list"}`
+	if resp := requestAs(t, "bob-secret", http.MethodPost, url+DefaultStreamablePath, session.ID(), list); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected bob to be refused alice's session, got %s", resp.Status)
+	}
+	if resp := requestAs(t, "bob-secret", http.MethodDelete, url+DefaultStreamablePath, session.ID(), ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected bob not to end alice's session, got %s", resp.Status)
+	}
+	if _, err := session.ListPrompts(context.Background(), nil); err != nil {
+		t.Errorf("Expected alice's session to survive, got %v", err)
+	}
+
+	// The SSE session ID arrives in the endpoint event of alice's stream
+	req, err := http.NewRequest(http.MethodGet, url+DefaultSSEPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer alice-secret")
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET returned error: %v", err)
+	}
+	defer func() { _ = stream.Body.Close() }()
+	var endpoint string
+	for scanner := bufio.NewScanner(stream.Body); endpoint == "" && scanner.Scan(); {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			endpoint = data
+		}
+	}
+	if !strings.Contains(endpoint, "sessionid=") {
+		t.Fatalf("Expected an endpoint event, got %q", endpoint)
+	}
+
+	if resp := requestAs(t, "bob-secret", http.MethodPost, url+endpoint, "", list); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected bob to be refused alice's SSE session, got %s", resp.Status)
+	}
+	if resp := requestAs(t, "alice-secret", http.MethodPost, url+endpoint, "", list); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected alice to use her SSE session, got %s", resp.Status)
+	}
+}
+
+func TestCredentialsMode(t *testing.T) {
+	verifier := &auth.Verifier{}
+	tests := []struct {
+		credentials string
+		mode        string
+		verifier    *auth.Verifier
+		url         string
+		want, err   string
+	}{
+		{"", TransportHTTP, nil, "", CredentialsServer, ""},
+		{"Forward", TransportHTTP, verifier, "", CredentialsForward, ""},
+		{"forward", TransportHTTP, nil, "", "", "forward needs auth.tokens_file"},
+		{"exchange", TransportStdio, nil, "", CredentialsServer, ""},
+		{"exchange", TransportHTTP, nil, "https://idp.example.com/token", "", "needs auth.tokens_file"},
+		{"exchange", TransportHTTP, verifier, "", "", "needs github.token_exchange.url"},
+		{"exchange", TransportHTTP, verifier, "idp.example.com", "", "github.token_exchange.url: invalid token exchange URL"},
+		{"exchange", TransportHTTP, verifier, "https://idp.example.com/token", CredentialsExchange, ""},
+		{"session", TransportHTTP, nil, "", "", `github.credentials: unknown credentials "session"`},
+	}
+	for _, tt := range tests {
+		s := &MCPServer{githubCredentials: tt.credentials, tokenExchange: auth.ExchangeConfig{URL: tt.url}}
+		got, exchanger, err := s.credentialsMode(tt.mode, tt.verifier)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("credentialsMode(%q, %q) returned %v, expected an error containing %q", tt.credentials, tt.mode, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("credentialsMode(%q, %q) = %q, %v, expected %q", tt.credentials, tt.mode, got, err, tt.want)
+		}
+		if (exchanger != nil) != (got == CredentialsExchange) {
+			t.Errorf("credentialsMode(%q, %q) returned exchanger %v", tt.credentials, tt.mode, exchanger)
+		}
+	}
+}
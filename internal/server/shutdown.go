@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/config"
+)
+
+// DefaultShutdownTimeout is how long running calls may take to finish after a
+// shutdown starts before they are cancelled
+const DefaultShutdownTimeout = config.DefaultShutdownTimeout
+
+// sessionCloseGrace is how long sessions are given to close once their calls have
+// been cancelled
+const sessionCloseGrace = 2 * time.Second
+
+// connectionCloseGrace is how long HTTP connections are given to close once their
+// sessions have closed
+const connectionCloseGrace = 2 * time.Second
+
+// errShuttingDown refuses calls that arrive after a shutdown has started
+var errShuttingDown = errors.New("the server is shutting down")
+
+// callTracker follows the calls sessions are running so that a shutdown can wait
+// for them and cancel them at its deadline
+type callTracker struct {
+	mu       sync.Mutex
+	stopping bool
+	running  int
+	idle     chan struct{}
+
+	// ctx is the parent of every call's context; cancel cancels the running calls
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// newCallTracker creates a tracker without running calls
+func newCallTracker() *callTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &callTracker{ctx: ctx, cancel: cancel}
+}
+
+// start records a call, or refuses it once the shutdown has started
+func (c *callTracker) start() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopping {
+		return false
+	}
+	c.running++
+	return true
+}
+
+// done records the end of a call
+func (c *callTracker) done() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running--
+	if c.running == 0 && c.idle != nil {
+		close(c.idle)
+		c.idle = nil
+	}
+}
+
+// drain refuses new calls and waits until the running calls finish or ctx is done.
+// It returns the number of calls still running.
+func (c *callTracker) drain(ctx context.Context) int {
+	c.mu.Lock()
+	c.stopping = true
+	if c.running == 0 {
+		c.mu.Unlock()
+		return 0
+	}
+	idle := make(chan struct{})
+	c.idle = idle
+	c.mu.Unlock()
+
+	select {
+	case <-idle:
+		return 0
+	case <-ctx.Done():
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.running
+}
+
+// trackCalls runs every request with a context that is cancelled when a shutdown
+// reaches its deadline, and refuses requests once a shutdown has started.
+// Notifications, such as cancellations, are still delivered.
+func (s *MCPServer) trackCalls(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+		if strings.HasPrefix(method, "notifications/") {
+			return next(ctx, ss, method, params)
+		}
+		if !s.calls.start() {
+			return nil, errShuttingDown
+		}
+		defer s.calls.done()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(s.calls.ctx, cancel)
+		defer stop()
+		return next(ctx, ss, method, params)
+	}
+}
+
+// shutdown stops the server gracefully. It stops accepting connections and calls,
+// lets running calls finish until the shutdown timeout, cancels those still
+// running and then closes every session and the audit log. Connections are given their own grace
+// period after that, so streams that end with their sessions are not cut off.
+func (s *MCPServer) shutdown(server *mcp.Server, httpServer *http.Server) error {
+	log.Printf("Shutting down; waiting up to %s for running calls", s.shutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	stopped := make(chan error, 1)
+	if httpServer != nil {
+		// Idle connections close now; streams end when their sessions close below
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), s.shutdownTimeout+sessionCloseGrace+connectionCloseGrace)
+		defer cancelShutdown()
+		go func() { stopped <- httpServer.Shutdown(shutdownCtx) }()
+	} else {
+		stopped <- nil
+	}
+
+	if running := s.calls.drain(ctx); running > 0 {
+		log.Printf("Cancelling %d calls still running at the shutdown deadline", running)
+	}
+	s.calls.cancel()
+
+	closeCtx, cancelClose := context.WithTimeout(context.Background(), sessionCloseGrace)
+	defer cancelClose()
+	closeSessions(closeCtx, server)
+
+	// Entries of the calls drained above are on disk before the server exits
+	auditErr := s.audit.close()
+	if auditErr != nil {
+		log.Printf("%v", auditErr)
+	}
+
+	if err := <-stopped; err != nil {
+		log.Printf("Closing connections still open after the shutdown grace period")
+		return errors.Join(httpServer.Close(), auditErr)
+	}
+	log.Println("Server stopped")
+	return auditErr
+}
+
+// closeSessions closes every session of server, giving up when ctx is done
+func closeSessions(ctx context.Context, server *mcp.Server) {
+	var wg sync.WaitGroup
+	for ss := range server.Sessions() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = ss.Close()
+		}()
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-ctx.Done():
+		log.Printf("Some sessions did not close in time")
+	}
+}
@@ -0,0 +1,361 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// waitTool is a tool that runs for the duration it is given or until its context
+// is cancelled
+type waitTool struct {
+	started   chan struct{}
+	cancelled chan bool
+}
+
+// newWaitServer returns a server with the wait tool whose calls s tracks
+func newWaitServer(s *MCPServer) (*mcp.Server, *waitTool) {
+	w := &waitTool{started: make(chan struct{}, 1), cancelled: make(chan bool, 1)}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, nil)
+	server.AddReceivingMiddleware(s.trackCalls)
+	type waitInput struct {
+		Duration string `json:"duration"`
+	}
+	mcp.AddTool(server, &mcp.Tool{Name: "wait"}, func(ctx context.Context, _ *mcp.ServerSession, params *mcp.CallToolParamsFor[waitInput]) (*mcp.CallToolResultFor[any], error) {
+		d, _ := time.ParseDuration(params.Arguments.Duration)
+		w.started <- struct{}{}
+		select {
+		case <-time.After(d):
+			w.cancelled <- false
+			return &mcp.CallToolResultFor[any]{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+		case <-ctx.Done():
+			w.cancelled <- true
+			return nil, ctx.Err()
+		}
+	})
+	return server, w
+}
+
+// call connects to server in memory and starts the wait tool, returning once it runs
+func (w *waitTool) call(t *testing.T, server *mcp.Server, duration string) *mcp.ClientSession {
+	t.Helper()
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport); err != nil {
+		t.Fatalf("server.Connect returned error: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	go func() {
+		_, _ = session.CallTool(ctx, &mcp.CallToolParams{Name: "wait", Arguments: map[string]any{"duration": duration}})
+	}()
+	<-w.started
+	return session
+}
+
+func TestShutdownLetsRunningCallsFinish(t *testing.T) {
+	s := NewMCPServer()
+	s.shutdownTimeout = 5 * time.Second
+	server, w := newWaitServer(s)
+	session := w.call(t, server, "100ms")
+	defer session.Close()
+
+	if err := s.shutdown(server, nil); err != nil {
+		t.Fatalf("shutdown returned error: %v", err)
+	}
+	if <-w.cancelled {
+		t.Error("Expected the call to finish before the deadline")
+	}
+	if s.calls.start() {
+		t.Error("Expected new calls to be refused after the shutdown")
+	}
+}
+
+func TestShutdownClosesAuditLogAfterDrainedCalls(t *testing.T) {
+	s := NewMCPServer()
+	s.shutdownTimeout = 5 * time.Second
+	s.auditFile = filepath.Join(t.TempDir(), "audit.log")
+	var err error
+	if s.audit, err = s.openAuditLog(); err != nil {
+		t.Fatalf("openAuditLog returned error: %v", err)
+	}
+	server, w := newWaitServer(s)
+	server.AddReceivingMiddleware(s.auditCalls)
+	session := w.call(t, server, "100ms")
+	defer session.Close()
+
+	if err := s.shutdown(server, nil); err != nil {
+		t.Fatalf("shutdown returned error: %v", err)
+	}
+	data, err := os.ReadFile(s.auditFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"name":"wait"`) {
+		t.Errorf("Expected the drained call in the audit log, got %q", data)
+	}
+	if s.audit.file != nil {
+		t.Error("Expected the audit log to be closed")
+	}
+	if err := s.audit.close(); err != nil {
+		t.Errorf("Expected closing twice to do nothing, got %v", err)
+	}
+}
+
+func TestShutdownCancelsCallsAtTheDeadline(t *testing.T) {
+	s := NewMCPServer()
+	s.shutdownTimeout = 50 * time.Millisecond
+	server, w := newWaitServer(s)
+	session := w.call(t, server, "1h")
+	defer session.Close()
+
+	start := time.Now()
+	if err := s.shutdown(server, nil); err != nil {
+		t.Fatalf("shutdown returned error: %v", err)
+	}
+	if !<-w.cancelled {
+		t.Error("Expected the call to be cancelled at the deadline")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Expected the shutdown to keep to its deadline, took %s", elapsed)
+	}
+}
+
+func TestServeShutsDownWhenDone(t *testing.T) {
+	s := NewMCPServer()
+	server, _ := newWaitServer(s)
+	handler, err := s.httpHandler(server, TransportHTTP)
+	if err != nil {
+		t.Fatalf("httpHandler returned error: %v", err)
+	}
+	httpServer, err := s.httpServer(handler)
+	if err != nil {
+		t.Fatalf("httpServer returned error: %v", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- s.serve(ctx, server, httpServer, ln) }()
+
+	// An SSE session keeps its stream open until the shutdown closes it
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(context.Background(), mcp.NewSSEClientTransport("http://"+ln.Addr().String()+DefaultSSEPath, nil))
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	defer session.Close()
+
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("serve returned error: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected serve to return after the shutdown")
+	}
+	if _, err := http.Get("http://" + ln.Addr().String() + DefaultStreamablePath); err == nil {
+		t.Error("Expected the listener to be closed")
+	}
+}
+
+func TestShutdownGivesStreamsTimeAfterTheDeadline(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	s := NewMCPServer()
+	s.shutdownTimeout = 50 * time.Millisecond
+	server, w := newWaitServer(s)
+	handler, err := s.httpHandler(server, TransportSSE)
+	if err != nil {
+		t.Fatalf("httpHandler returned error: %v", err)
+	}
+	httpServer, err := s.httpServer(handler)
+	if err != nil {
+		t.Fatalf("httpServer returned error: %v", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- s.serve(ctx, server, httpServer, ln) }()
+
+	// The call outlives the drain; its stream must still end gracefully
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(context.Background(), mcp.NewSSEClientTransport("http://"+ln.Addr().String()+DefaultSSEPath, nil))
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	defer session.Close()
+	go func() {
+		_, _ = session.CallTool(context.Background(), &mcp.CallToolParams{Name: "wait", Arguments: map[string]any{"duration": "1h"}})
+	}()
+	<-w.started
+
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("serve returned error: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected serve to return after the shutdown")
+	}
+	if !<-w.cancelled {
+		t.Error("Expected the call to be cancelled at the deadline")
+	}
+	if strings.Contains(logs.String(), "Closing connections") {
+		t.Errorf("Expected connections to close within their grace period:\n%s", logs.String())
+	}
+}
+
+// writeCertificate writes a certificate for 127.0.0.1 and its key, signed by parent
+// or self-signed, and returns them with the file names
+func writeCertificate(t *testing.T, dir, name string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return cert, key, certFile, keyFile
+}
+
+func TestServeWithMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caFile, _ := writeCertificate(t, dir, "ca", true, nil, nil)
+	_, _, certFile, keyFile := writeCertificate(t, dir, "server", false, ca, caKey)
+	_, _, clientCertFile, clientKeyFile := writeCertificate(t, dir, "client", false, ca, caKey)
+
+	s := NewMCPServer()
+	s.tlsCertFile, s.tlsKeyFile, s.tlsClientCAFile = certFile, keyFile, caFile
+	server, _ := newWaitServer(s)
+	handler, err := s.httpHandler(server, TransportHTTP)
+	if err != nil {
+		t.Fatalf("httpHandler returned error: %v", err)
+	}
+	httpServer, err := s.httpServer(handler)
+	if err != nil {
+		t.Fatalf("httpServer returned error: %v", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.serve(ctx, server, httpServer, ln) }()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "https://" + ln.Addr().String() + DefaultStreamablePath
+	for _, certs := range [][]tls.Certificate{nil, {clientCert}} {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs}}}
+		resp, err := client.Get(url)
+		if certs == nil {
+			if err == nil {
+				_ = resp.Body.Close()
+				t.Error("Expected a client without a certificate to be refused")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("GET with a client certificate returned error: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected the streamable HTTP handler to answer, got %s", resp.Status)
+		}
+	}
+}
+
+func TestTLSConfigNamesMissingSettings(t *testing.T) {
+	tests := []struct {
+		cert, key, clientCA, err string
+	}{
+		{"", "", "", ""},
+		{"server.crt", "", "", "tls.cert_file: needs tls.key_file"},
+		{"", "server.key", "", "tls.key_file: needs tls.cert_file"},
+		{"", "", "ca.crt", "tls.client_ca_file: needs tls.cert_file and tls.key_file"},
+		{"missing.crt", "missing.key", "", "tls.cert_file, tls.key_file"},
+	}
+	for _, tt := range tests {
+		s := &MCPServer{tlsCertFile: tt.cert, tlsKeyFile: tt.key, tlsClientCAFile: tt.clientCA}
+		config, err := s.tlsConfig()
+		if tt.err == "" {
+			if config != nil || err != nil {
+				t.Errorf("Expected plain HTTP, got %v, %v", config, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("tlsConfig(%q, %q, %q) returned %v, expected an error containing %q", tt.cert, tt.key, tt.clientCA, err, tt.err)
+		}
+	}
+}
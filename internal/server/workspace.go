@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/prompts"
+)
+
+// workspace is the repository a session discovered among its roots, with the prompts
+// and conventions policy layered for it
+type workspace struct {
+	root    string
+	prompts *prompts.PromptManager
+
+	// stop ends the polling of the workspace's prompt directories
+	stop context.CancelFunc
+}
+
+// sessionWorkspace is the workspace of a session. generation counts the discoveries
+// started for the session; only the latest one may install its workspace, so a slow
+// discovery cannot replace the result of a newer one.
+type sessionWorkspace struct {
+	generation uint64
+	current    *workspace
+}
+
+// workspaces holds the workspaces of the sessions. Sessions without one use the
+// server's prompts and policy.
+type workspaces struct {
+	mu       sync.Mutex
+	sessions map[*mcp.ServerSession]*sessionWorkspace
+}
+
+// newWorkspaces returns an empty set of workspaces
+func newWorkspaces() *workspaces {
+	return &workspaces{sessions: map[*mcp.ServerSession]*sessionWorkspace{}}
+}
+
+// begin starts a discovery for the session and returns its generation
+func (w *workspaces) begin(ss *mcp.ServerSession) uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entry, ok := w.sessions[ss]
+	if !ok {
+		entry = &sessionWorkspace{}
+		w.sessions[ss] = entry
+	}
+	entry.generation++
+	return entry.generation
+}
+
+// get returns the workspace of the session, or nil if it has none
+func (w *workspaces) get(ss *mcp.ServerSession) *workspace {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if entry, ok := w.sessions[ss]; ok {
+		return entry.current
+	}
+	return nil
+}
+
+// set installs ws, which may be nil, as the workspace of the session if no newer
+// discovery has started and the session has not ended. It reports whether ws was
+// installed; the workspace it replaces is stopped.
+func (w *workspaces) set(ss *mcp.ServerSession, generation uint64, ws *workspace) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entry, ok := w.sessions[ss]
+	if !ok || entry.generation != generation {
+		return false
+	}
+	if entry.current != nil {
+		entry.current.stop()
+	}
+	entry.current = ws
+	return true
+}
+
+// end forgets the workspace of a session that has ended
+func (w *workspaces) end(ss *mcp.ServerSession) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if entry, ok := w.sessions[ss]; ok && entry.current != nil {
+		entry.current.stop()
+	}
+	delete(w.sessions, ss)
+}
+
+// sessionPrompts returns the prompts of the session's workspace, or the server's
+func (s *MCPServer) sessionPrompts(ss *mcp.ServerSession) *prompts.PromptManager {
+	if ws := s.workspaces.get(ss); ws != nil {
+		return ws.prompts
+	}
+	return s.prompts
+}
+
+// sessionPolicy returns the conventions policy of the session's workspace, or the
+// server's
+func (s *MCPServer) sessionPolicy(ss *mcp.ServerSession) *policy.Policy {
+	if pm := s.sessionPrompts(ss); pm != nil {
+		return pm.Policy()
+	}
+	return policy.Default()
+}
+
+// servePrompts lists and renders the prompts of the session's workspace for sessions
+// that have one. The prompts registered on the server are those of sessions without.
+func (s *MCPServer) servePrompts(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+	return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+		ws := s.workspaces.get(ss)
+		if ws == nil {
+			return next(ctx, ss, method, params)
+		}
+
+		switch method {
+		case "prompts/list":
+			result := &mcp.ListPromptsResult{Prompts: []*mcp.Prompt{}}
+			for _, prompt := range ws.prompts.GetAllPrompts() {
+				result.Prompts = append(result.Prompts, mcpPrompt(prompt))
+			}
+			return result, nil
+		case "prompts/get":
+			params, _ := params.(*mcp.GetPromptParams)
+			if params == nil {
+				return nil, errors.New("prompts/get needs the name of a prompt")
+			}
+			prompt, ok := ws.prompts.GetPrompt(params.Name)
+			if !ok {
+				return nil, fmt.Errorf("unknown prompt %q", params.Name)
+			}
+			return prompt.Handler(ctx, ss, params)
+		}
+		return next(ctx, ss, method, params)
+	}
+}
+
+// watchWorkspace polls the prompt directories of a workspace until it is stopped.
+// Sessions are not notified of the changes; they see them the next time they list.
+func (s *MCPServer) watchWorkspace(ctx context.Context, ws *workspace) {
+	ws.prompts.Watch(ctx, s.promptPollInterval, func(changes prompts.PromptChanges, err error) {
+		if err != nil {
+			log.Printf("Rejected invalid prompt files of %s, keeping previous versions: %v", ws.root, err)
+		}
+		if !changes.Empty() {
+			log.Printf("Reloaded prompts of %s", ws.root)
+		}
+	})
+}
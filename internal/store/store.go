@@ -0,0 +1,290 @@
+// Package store keeps the work in progress across server restarts: the workflow of
+// each issue with the branch, pull request and last checks its tools observed. It
+// is saved as a versioned JSON file.
+package store
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// SchemaVersion is the version of the file format written by this server
+const SchemaVersion = 1
+
+// MaxRecords bounds how many pieces of work are kept; the least recently saved are dropped
+const MaxRecords = 100
+
+// ErrNotFound is returned when no saved work matches a query
+var ErrNotFound = errors.New("no saved work matches")
+
+// migrations upgrade the JSON of a file from the version they are keyed by to the
+// next one. Version 1 is the first, so there is nothing to migrate yet.
+var migrations = map[int]func(json.RawMessage) (json.RawMessage, error){}
+
+// Record is the saved state of the work on an issue. The branch, pull request and
+// last checks are the facts of the workflow. Owner is the user the work belongs
+// to, empty for work done without authentication.
+type Record struct {
+	Owner      string            `json:"owner,omitempty"`
+	Repository string            `json:"repository,omitempty"`
+	Issue      int               `json:"issue,omitempty"`
+	Workflow   workflow.Progress `json:"workflow"`
+	SavedAt    time.Time         `json:"saved_at"`
+}
+
+// matches reports whether the record is the work q asks for
+func (r Record) matches(q Query) bool {
+	facts := r.Workflow.Facts
+	return r.Owner == q.Owner &&
+		(q.Repository == "" || strings.EqualFold(r.Repository, q.Repository)) &&
+		(q.Issue == 0 || r.Issue == q.Issue) &&
+		(q.Branch == "" || facts.Branch == q.Branch) &&
+		(q.PullRequest == 0 || facts.PullRequest == q.PullRequest)
+}
+
+// Query selects saved work of Owner. Its other fields match any record when empty.
+type Query struct {
+	Owner       string
+	Repository  string
+	Issue       int
+	Branch      string
+	PullRequest int
+}
+
+// file is the layout of the state file
+type file struct {
+	Version int      `json:"version"`
+	Records []Record `json:"records"`
+}
+
+// Waiting for the lock of the state file. A lock older than lockStale was left by a
+// server that stopped while saving and is broken.
+const (
+	lockTimeout = 10 * time.Second
+	lockRetry   = 10 * time.Millisecond
+	lockStale   = 30 * time.Second
+)
+
+// Store is the state file at a path. Every change rereads the file while holding a
+// lock file next to it, so servers sharing it do not drop each other's work.
+type Store struct {
+	path string
+	mu   sync.Mutex
+
+	// now returns the current time; tests replace it
+	now func() time.Time
+}
+
+// Open opens the state file at path, which is created when work is first saved. An
+// existing file that cannot be read, or was written by a newer server, is an error.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, now: time.Now}
+	if _, err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Path returns the path of the state file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Save saves the workflow, replacing the saved work of the same owner on the same issue
+// of the same repository
+func (s *Store) Save(p *workflow.Progress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	saved := *p
+	// Sessions do not outlive the server, so their IDs mean nothing to the next one
+	saved.Session = ""
+	record := Record{Owner: p.Owner, Repository: p.Repository, Issue: p.Issue, Workflow: saved, SavedAt: s.now()}
+	records = slices.DeleteFunc(records, func(r Record) bool {
+		return r.Owner == record.Owner && strings.EqualFold(r.Repository, record.Repository) && r.Issue == record.Issue
+	})
+	records = append([]Record{record}, records...)
+	if len(records) > MaxRecords {
+		records = records[:MaxRecords]
+	}
+	return s.write(records)
+}
+
+// Find returns the most recently saved work matching q
+func (s *Store) Find(q Query) (*Record, error) {
+	records, err := s.Records()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if r.matches(q) {
+			return &r, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Records returns all saved work, most recently saved first
+func (s *Store) Records() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+// lock takes the lock file of the state file, waiting while another server holds it,
+// and returns the function that releases it. The lock file holds a token of its own,
+// so that a server whose lock was broken as stale does not release the next one.
+func (s *Store) lock() (func(), error) {
+	path := s.path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating state directory: %w", err)
+	}
+	token := fmt.Sprintf("%d %s\n", os.Getpid(), rand.Text())
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, err = f.WriteString(token)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(path)
+				return nil, fmt.Errorf("locking state file: %w", err)
+			}
+			return func() {
+				removeLock(path, func(_ fs.FileInfo, owner []byte) bool { return string(owner) == token })
+			}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("locking state file: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil && stale(info) {
+			removeLock(path, func(info fs.FileInfo, _ []byte) bool { return stale(info) })
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("locking state file: %s is held by another server; remove it if none is running", path)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// stale reports whether a lock file was left by a server that stopped while saving
+func stale(info fs.FileInfo) bool {
+	return time.Since(info.ModTime()) > lockStale
+}
+
+// removeLock removes the lock file at path if remove reports true for it. The file is
+// first renamed to a name of its own, so that it is looked at as it is then: when
+// another server has replaced a stale lock by its own in the meantime, that lock is
+// the one looked at, and it is linked back into place instead of removed.
+func removeLock(path string, remove func(info fs.FileInfo, owner []byte) bool) {
+	aside := path + "." + rand.Text()
+	if err := os.Rename(path, aside); err != nil {
+		return
+	}
+	info, statErr := os.Stat(aside)
+	owner, readErr := os.ReadFile(aside)
+	if statErr != nil || readErr != nil || !remove(info, owner) {
+		_ = os.Link(aside, path)
+	}
+	_ = os.Remove(aside)
+}
+
+// load reads the records, migrating files written with an older schema; s.mu must be
+// held unless s is not shared yet
+func (s *Store) load() ([]Record, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("state file %s: %w", s.path, err)
+	}
+	switch {
+	case header.Version < 1:
+		return nil, fmt.Errorf("state file %s: missing schema version", s.path)
+	case header.Version > SchemaVersion:
+		return nil, fmt.Errorf("state file %s: schema version %d was written by a newer server; this server reads up to version %d",
+			s.path, header.Version, SchemaVersion)
+	}
+	for version := header.Version; version < SchemaVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("state file %s: no migration from schema version %d", s.path, version)
+		}
+		if data, err = migrate(data); err != nil {
+			return nil, fmt.Errorf("state file %s: migrating from schema version %d: %w", s.path, version, err)
+		}
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("state file %s: %w", s.path, err)
+	}
+	slices.SortStableFunc(f.Records, func(a, b Record) int { return b.SavedAt.Compare(a.SavedAt) })
+	return f.Records, nil
+}
+
+// write replaces the state file with records. The file is written next to it and
+// renamed into place, so a crash leaves either the old or the new state.
+func (s *Store) write(records []Record) error {
+	data, err := json.MarshalIndent(file{Version: SchemaVersion, Records: records}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// openStore opens a store in a temporary directory whose clock advances a minute per save
+func openStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "state", "state.json"))
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return s
+}
+
+// save saves a workflow with facts
+func save(t *testing.T, s *Store, repository string, issue int, facts workflow.Facts) {
+	t.Helper()
+
+	p := workflow.Progress{Session: "session-1", Repository: repository, Issue: issue, Facts: facts}
+	if err := s.Save(&p); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+}
+
+func TestSaveAndFind(t *testing.T) {
+	s := openStore(t)
+	save(t, s, "octo/hello", 42, workflow.Facts{Branch: "feature/42-login"})
+	save(t, s, "octo/hello", 7, workflow.Facts{Branch: "fix/7-crash", PullRequest: 9, ChecksState: workflow.ChecksFailure})
+	save(t, s, "octo/other", 42, workflow.Facts{})
+	save(t, s, "octo/hello", 42, workflow.Facts{Branch: "feature/42-login", PullRequest: 10})
+
+	records, err := s.Records()
+	if err != nil {
+		t.Fatalf("Records returned error: %v", err)
+	}
+	if len(records) != 3 || records[0].Issue != 42 || records[0].Workflow.Facts.PullRequest != 10 {
+		t.Fatalf("Expected the last save to replace the first and come first, got %+v", records)
+	}
+	if records[0].Workflow.Session != "" {
+		t.Errorf("Expected the session not to be saved, got %q", records[0].Workflow.Session)
+	}
+
+	tests := []struct {
+		query Query
+		issue int
+		repo  string
+	}{
+		{Query{Repository: "octo/hello"}, 42, "octo/hello"},
+		{Query{Issue: 42}, 42, "octo/hello"},
+		{Query{Repository: "Octo/Other", Issue: 42}, 42, "octo/other"},
+		{Query{Branch: "fix/7-crash"}, 7, "octo/hello"},
+		{Query{PullRequest: 9}, 7, "octo/hello"},
+	}
+	for _, tt := range tests {
+		r, err := s.Find(tt.query)
+		if err != nil {
+			t.Errorf("Find(%+v) returned error: %v", tt.query, err)
+			continue
+		}
+		if r.Issue != tt.issue || r.Repository != tt.repo {
+			t.Errorf("Find(%+v) = %s#%d, expected %s#%d", tt.query, r.Repository, r.Issue, tt.repo, tt.issue)
+		}
+	}
+	if _, err := s.Find(Query{Issue: 3}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSavedWorkBelongsToItsOwner(t *testing.T) {
+	s := openStore(t)
+	save(t, s, "octo/hello", 42, workflow.Facts{Branch: "feature/42-local"})
+	for _, owner := range []string{"token:alice", "jwt:bob"} {
+		p := workflow.Progress{Owner: owner, Repository: "octo/hello", Issue: 42, Facts: workflow.Facts{Branch: "feature/42-" + owner[strings.Index(owner, ":")+1:]}}
+		if err := s.Save(&p); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+	}
+
+	if records, _ := s.Records(); len(records) != 3 {
+		t.Errorf("Expected one record per owner, got %d", len(records))
+	}
+	for owner, branch := range map[string]string{"": "feature/42-local", "token:alice": "feature/42-alice", "jwt:bob": "feature/42-bob"} {
+		r, err := s.Find(Query{Owner: owner, Issue: 42})
+		if err != nil || r.Workflow.Facts.Branch != branch {
+			t.Errorf("Find for %q returned %+v, %v, expected branch %s", owner, r, err, branch)
+		}
+	}
+	if _, err := s.Find(Query{Owner: "token:carol"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected no work for another owner, got %v", err)
+	}
+}
+
+func TestStateSurvivesReopening(t *testing.T) {
+	s := openStore(t)
+	save(t, s, "octo/hello", 42, workflow.Facts{Branch: "feature/42-login", ChecksState: workflow.ChecksSuccess, ChecksSHA: "abc123"})
+
+	reopened, err := Open(s.Path())
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	r, err := reopened.Find(Query{Issue: 42})
+	if err != nil {
+		t.Fatalf("Find returned error: %v", err)
+	}
+	if r.Workflow.Facts.ChecksSHA != "abc123" || r.SavedAt.IsZero() {
+		t.Errorf("Unexpected record %+v", r)
+	}
+
+	data, err := os.ReadFile(s.Path())
+	if err != nil {
+		t.Fatalf("ReadFile returned error: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "{\n  \"version\": 1,") {
+		t.Errorf("Expected the schema version first, got:\n%s", data)
+	}
+}
+
+func TestSaveKeepsMostRecentRecords(t *testing.T) {
+	s := openStore(t)
+	for issue := 1; issue <= MaxRecords+5; issue++ {
+		save(t, s, "octo/hello", issue, workflow.Facts{})
+	}
+
+	records, err := s.Records()
+	if err != nil {
+		t.Fatalf("Records returned error: %v", err)
+	}
+	if len(records) != MaxRecords || records[0].Issue != MaxRecords+5 || records[MaxRecords-1].Issue != 6 {
+		t.Errorf("Expected issues %d down to 6, got %d records from #%d", MaxRecords+5, len(records), records[0].Issue)
+	}
+}
+
+func TestOpenRejectsUnreadableFiles(t *testing.T) {
+	tests := map[string]string{
+		`{"version": 2, "records": []}`: "schema version 2 was written by a newer server",
+		`{"records": []}`:               "missing schema version",
+		`{"version": 1, "records": {}}`: "cannot unmarshal",
+		`not json`:                      "invalid character",
+	}
+	for content, want := range tests {
+		path := filepath.Join(t.TempDir(), "state.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Open(path)
+		if err == nil || !strings.Contains(err.Error(), want) || !strings.Contains(err.Error(), path) {
+			t.Errorf("Open(%s) returned %v, expected an error containing %q and the path", content, err, want)
+		}
+	}
+}
+
+func TestServersSharingTheFileKeepEachOthersWork(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	var wg sync.WaitGroup
+	for issue := 1; issue <= 8; issue++ {
+		s, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open returned error: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Save(&workflow.Progress{Repository: "octo/hello", Issue: issue}); err != nil {
+				t.Errorf("Save returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if records, err := s.Records(); err != nil || len(records) != 8 {
+		t.Errorf("Expected the work of every server, got %d records, %v", len(records), err)
+	}
+	if _, err := os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the lock to be released, got %v", err)
+	}
+}
+
+func TestSaveBreaksStaleLock(t *testing.T) {
+	s := openStore(t)
+	lock := s.Path() + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lock), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lock, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+	save(t, s, "octo/hello", 42, workflow.Facts{})
+}
+
+func TestServersBreakStaleLockOnce(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	lock := path + ".lock"
+	if err := os.WriteFile(lock, []byte("stopped\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for issue := 1; issue <= 8; issue++ {
+		s, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open returned error: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range 5 {
+				if err := s.Save(&workflow.Progress{Repository: "octo/hello", Issue: issue*10 + n}); err != nil {
+					t.Errorf("Save returned error: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if records, err := s.Records(); err != nil || len(records) != 40 {
+		t.Errorf("Expected the work of every server, got %d records, %v", len(records), err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "state.json" {
+		t.Errorf("Expected only the state file to be left, got %v", entries)
+	}
+}
+
+func TestBreakingStaleLockSparesFreshOne(t *testing.T) {
+	dir := t.TempDir()
+	lock := filepath.Join(dir, "state.json.lock")
+	if err := os.WriteFile(lock, []byte("other server\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A server that saw a stale lock before the other server replaced it
+	removeLock(lock, func(info os.FileInfo, _ []byte) bool { return stale(info) })
+	if owner, err := os.ReadFile(lock); err != nil || string(owner) != "other server\n" {
+		t.Errorf("Expected the fresh lock to be kept, got %q, %v", owner, err)
+	}
+
+	// A server whose own lock was broken releasing the one that replaced it
+	removeLock(lock, func(_ os.FileInfo, owner []byte) bool { return string(owner) == "stopped server\n" })
+	if _, err := os.Stat(lock); err != nil {
+		t.Errorf("Expected another server's lock not to be released, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected only the lock file to be left, got %v", entries)
+	}
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/branches"
+)
+
+// ValidateBranchNameInput is the input of the validate_branch_name tool
+type ValidateBranchNameInput struct {
+	Name        string `json:"name" jsonschema:"the proposed branch name, e.g. feature/42-add-login"`
+	IssueNumber int    `json:"issue_number,omitempty" jsonschema:"issue the branch must reference as <issue-number>- in its description"`
+}
+
+// SuggestBranchNameInput is the input of the suggest_branch_name tool
+type SuggestBranchNameInput struct {
+	IssueNumber int      `json:"issue_number" jsonschema:"number of the issue the branch is for"`
+	Title       string   `json:"title" jsonschema:"title of the issue"`
+	Labels      []string `json:"labels,omitempty" jsonschema:"labels of the issue; bug maps to bugfix/, documentation to docs/ and so on"`
+}
+
+// validateBranchNameTool checks a branch name against the branch conventions of the policy
+func (tm *ToolManager) validateBranchNameTool() Tool {
+	return newTool("validate_branch_name",
+		"Check a proposed branch name against the branch naming conventions: an allowed <type>/ prefix, "+
+			"lowercase words separated by hyphens, at most <type>/<scope>/<short-description>, the length limit, "+
+			"protected branches and the issue number. Returns violations with suggested fixes and a corrected name.",
+		true,
+		func(_ context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ValidateBranchNameInput]) (*mcp.CallToolResultFor[branches.Result], error) {
+			result := branches.Validate(params.Arguments.Name, tm.deps.Policy(ss), branches.Options{
+				IssueNumber: params.Arguments.IssueNumber,
+			})
+			return structuredResult(result)
+		})
+}
+
+// suggestBranchNameTool derives a compliant branch name from an issue
+func (tm *ToolManager) suggestBranchNameTool() Tool {
+	return newTool("suggest_branch_name",
+		"Derive a branch name of the form <type>/<issue-number>-<short-description> from an issue number, title "+
+			"and labels. The type is mapped from the labels (bug becomes bugfix/) and the title is slugified "+
+			"without stop words and capped to the length limit.",
+		true,
+		func(_ context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[SuggestBranchNameInput]) (*mcp.CallToolResultFor[branches.Suggestion], error) {
+			args := params.Arguments
+			if args.IssueNumber <= 0 {
+				return nil, errors.New("issue_number must be a positive integer")
+			}
+			if strings.TrimSpace(args.Title) == "" {
+				return nil, errors.New("title is required")
+			}
+
+			return structuredResult(branches.Suggest(args.IssueNumber, args.Title, args.Labels, tm.deps.Policy(ss)))
+		})
+}
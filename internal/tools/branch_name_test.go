@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/branches"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+func TestValidateBranchNameTool(t *testing.T) {
+	session := connect(t, NewToolManager(Dependencies{}))
+
+	var result branches.Result
+	callTool(t, session, "validate_branch_name", map[string]any{"name": "Feat/Add_Login", "issue_number": 42}, &result)
+
+	if result.Valid {
+		t.Error("Expected branch name to be invalid")
+	}
+	found := make(map[string]bool)
+	for _, v := range result.Violations {
+		found[v.Rule] = true
+	}
+	for _, rule := range []string{branches.RulePrefix, branches.RuleCase, branches.RuleCharacters, branches.RuleIssueNumber} {
+		if !found[rule] {
+			t.Errorf("Expected %s violation, got %+v", rule, result.Violations)
+		}
+	}
+	if result.SuggestedName != "feature/42-add-login" {
+		t.Errorf("Expected suggested name feature/42-add-login, got %q", result.SuggestedName)
+	}
+
+	callTool(t, session, "validate_branch_name", map[string]any{"name": "bugfix/42-fix-login"}, &result)
+	if !result.Valid || result.Branch == nil || result.Branch.IssueNumber != 42 {
+		t.Errorf("Expected valid branch for issue 42, got %+v", result)
+	}
+}
+
+func TestSuggestBranchNameTool(t *testing.T) {
+	p := policy.Default()
+	p.Branches.MaxLength = 30
+	session := connect(t, NewToolManager(Dependencies{Policy: func(*mcp.ServerSession) *policy.Policy { return p }}))
+
+	var suggestion branches.Suggestion
+	callTool(t, session, "suggest_branch_name", map[string]any{
+		"issue_number": 128,
+		"title":        "Crash when the config file is missing",
+		"labels":       []string{"bug", "priority: high"},
+	}, &suggestion)
+
+	if suggestion.Name != "bugfix/128-crash-config-file" {
+		t.Errorf("Expected bugfix/128-crash-config-file, got %q", suggestion.Name)
+	}
+	if suggestion.Prefix != "bugfix" {
+		t.Errorf("Expected bugfix prefix, got %q", suggestion.Prefix)
+	}
+}
+
+func TestSuggestBranchNameToolRequiresIssue(t *testing.T) {
+	session := connect(t, NewToolManager(Dependencies{}))
+
+	if result := callTool(t, session, "suggest_branch_name", map[string]any{"issue_number": 0, "title": "Something"}, nil); !result.IsError {
+		t.Error("Expected error result without an issue number")
+	}
+	if result := callTool(t, session, "suggest_branch_name", map[string]any{"issue_number": 3, "title": " "}, nil); !result.IsError {
+		t.Error("Expected error result without a title")
+	}
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/checks"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// Limits of the wait_for_checks timeout
+const (
+	defaultChecksTimeout = 10 * time.Minute
+	maxChecksTimeout     = time.Hour
+)
+
+// WaitForChecksInput is the input of the wait_for_checks tool
+type WaitForChecksInput struct {
+	Owner          string `json:"owner,omitempty" jsonschema:"repository owner (default: from the origin remote of the repository)"`
+	Repo           string `json:"repo,omitempty" jsonschema:"repository name (default: from the origin remote of the repository)"`
+	Ref            string `json:"ref,omitempty" jsonschema:"commit SHA, branch or tag whose checks to wait for (default: HEAD of the repository)"`
+	PullRequest    int    `json:"pull_request,omitempty" jsonschema:"pull request whose head commit's checks to wait for, instead of ref"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"how long to wait at most (default: 600, at most 3600)"`
+	Path           string `json:"path,omitempty" jsonschema:"directory inside the repository; must be within the client's roots. Defaults to the first root inside a repository"`
+}
+
+// ChecksResult is the outcome of wait_for_checks
+type ChecksResult struct {
+	checks.Summary
+	TimedOut bool   `json:"timed_out"`
+	Waited   string `json:"waited"`
+}
+
+// waitForChecksTool waits for the CI of a commit or pull request to finish
+func (tm *ToolManager) waitForChecksTool() Tool {
+	return newTool("wait_for_checks",
+		"Wait for the GitHub Actions workflow runs and other checks of a commit or pull request to finish, polling with backoff. "+
+			"Sends progress notifications as jobs finish and stops when the request is cancelled. Returns a pass/fail summary per job; "+
+			"state is success, failure, pending (timed out) or none (no checks reported).",
+		true,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[WaitForChecksInput]) (*mcp.CallToolResultFor[ChecksResult], error) {
+			args := params.Arguments
+			if tm.deps.GitHub == nil {
+				return nil, ErrNoGitHub
+			}
+			if args.PullRequest < 0 || args.TimeoutSeconds < 0 {
+				return nil, errors.New("pull_request and timeout_seconds must not be negative")
+			}
+			if args.PullRequest > 0 && args.Ref != "" {
+				return nil, errors.New("give either ref or pull_request, not both")
+			}
+
+			owner, repo, err := tm.githubRepository(ctx, ss, args.Owner, args.Repo, args.Path)
+			if err != nil {
+				return nil, err
+			}
+			sha, err := tm.checksCommit(ctx, ss, owner, repo, args.Ref, args.PullRequest, args.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			opts := tm.checksWait
+			opts.Timeout = min(time.Duration(args.TimeoutSeconds)*time.Second, maxChecksTimeout)
+			if args.TimeoutSeconds == 0 {
+				opts.Timeout = defaultChecksTimeout
+			}
+			if token := params.GetProgressToken(); token != nil && ss != nil {
+				opts.OnUpdate = func(s *checks.Summary, finished []checks.Job) {
+					_ = ss.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+						ProgressToken: token,
+						Progress:      float64(s.Completed),
+						Total:         float64(s.Total),
+						Message:       progressMessage(s, finished),
+					})
+				}
+			}
+
+			start := time.Now()
+			summary, timedOut, err := checks.Wait(ctx, tm.deps.GitHub, owner, repo, sha, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			tm.recordChecks(ctx, ss, owner+"/"+repo, args, summary)
+
+			out := ChecksResult{Summary: *summary, TimedOut: timedOut, Waited: time.Since(start).Round(time.Second).String()}
+			text := summary.Text()
+			if timedOut {
+				text += fmt.Sprintf("Timed out after %s with checks still running; call wait_for_checks again to keep waiting.\n", opts.Timeout)
+			}
+			return &mcp.CallToolResultFor[ChecksResult]{
+				Content:           []mcp.Content{&mcp.TextContent{Text: text}},
+				StructuredContent: out,
+			}, nil
+		})
+}
+
+// recordChecks records the outcome of the checks in the session's active workflow
+// if they belong to its work: the pull request it opened or the head of its branch
+func (tm *ToolManager) recordChecks(ctx context.Context, ss *mcp.ServerSession, repository string, args WaitForChecksInput, summary *checks.Summary) {
+	session := workflow.SessionID(ss)
+	p, err := tm.deps.Workflows.Get(session, 0)
+	if err != nil || (p.Repository != "" && !strings.EqualFold(p.Repository, repository)) {
+		return
+	}
+	branch, head := tm.workHead(ctx, ss, args.Path, p.Facts)
+	switch {
+	case args.PullRequest > 0 && args.PullRequest != p.Facts.PullRequest:
+		return
+	case args.PullRequest == 0 && (head == "" || head != summary.SHA):
+		return
+	}
+
+	_ = tm.deps.Workflows.Update(session, p.Issue, func(f *workflow.Facts) {
+		f.ChecksState, f.ChecksSHA = summary.State, summary.SHA
+		if f.Branch == "" {
+			f.Branch = branch
+		}
+		if head != "" {
+			f.Head = head
+		}
+	})
+}
+
+// checksCommit resolves the commit whose checks to look at: the head of pullRequest,
+// the commit ref names or the HEAD of the local repository at path
+func (tm *ToolManager) checksCommit(ctx context.Context, ss *mcp.ServerSession, owner, repo, ref string, pullRequest int, path string) (string, error) {
+	if pullRequest > 0 {
+		pr, err := tm.deps.GitHub.GetPullRequest(ctx, owner, repo, pullRequest)
+		if err != nil {
+			return "", err
+		}
+		return pr.Head.SHA, nil
+	}
+
+	if ref = strings.TrimSpace(ref); ref != "" {
+		commit, err := tm.deps.GitHub.GetCommit(ctx, owner, repo, ref)
+		if err != nil {
+			return "", err
+		}
+		return commit.SHA, nil
+	}
+
+	local, err := tm.openRepository(ctx, ss, path)
+	if err != nil {
+		return "", fmt.Errorf("ref and pull_request not given and no repository to take HEAD from: %w", err)
+	}
+	return local.RevParse(ctx, "HEAD")
+}
+
+// progressMessage describes the jobs that finished, or the checks found on the first poll
+func progressMessage(s *checks.Summary, finished []checks.Job) string {
+	if len(finished) == 0 {
+		return fmt.Sprintf("waiting for %d of %d checks of %.12s", s.Total-s.Completed, s.Total, s.SHA)
+	}
+	parts := make([]string, 0, len(finished))
+	for _, job := range finished {
+		parts = append(parts, fmt.Sprintf("%s: %s", job.Name, job.Conclusion))
+	}
+	return fmt.Sprintf("%s (%d of %d checks finished)", strings.Join(parts, ", "), s.Completed, s.Total)
+}
@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/checks"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+)
+
+const checksSHA = "0123456789abcdef0123456789abcdef01234567"
+
+// serveCheckRuns serves pull request 9 of octo/hello whose head has the given jobs,
+// one more of which finishes with its conclusion on every poll
+func serveCheckRuns(s *githubtest.Server, jobs [][2]string) {
+	serveCheckRunsAt(s, checksSHA, jobs)
+}
+
+// serveCheckRunsAt serves the jobs of serveCheckRuns for the commit sha
+func serveCheckRunsAt(s *githubtest.Server, sha string, jobs [][2]string) {
+	var mu sync.Mutex
+	polls := 0
+	prefix := "GET /repos/octo/hello/commits/" + sha
+	s.JSON("GET /repos/octo/hello/pulls/9", http.StatusOK, map[string]any{"number": 9, "head": map[string]any{"sha": sha}})
+	s.JSON(prefix+"/check-suites", http.StatusOK, map[string]any{"check_suites": []any{}})
+	s.JSON("GET /repos/octo/hello/actions/runs", http.StatusOK, map[string]any{"workflow_runs": []map[string]any{{"id": 100, "name": "CI", "check_suite_id": 10}}})
+	s.HandleFunc(prefix+"/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		runs := []map[string]any{}
+		for i, job := range jobs {
+			run := map[string]any{"id": i + 1, "name": job[0], "status": "in_progress", "check_suite": map[string]any{"id": 10}}
+			if i < polls {
+				run["status"], run["conclusion"] = "completed", job[1]
+			}
+			runs = append(runs, run)
+		}
+		polls++
+		githubtest.WriteJSON(w, http.StatusOK, map[string]any{"check_runs": runs})
+	})
+}
+
+// fastChecks makes wait_for_checks poll without delay
+func fastChecks(tm *ToolManager) *ToolManager {
+	tm.checksWait.Backoff = checks.Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Factor: 2}
+	return tm
+}
+
+func TestWaitForChecks(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveCheckRuns(s, [][2]string{{"build", "success"}, {"lint", "failure"}})
+
+	var mu sync.Mutex
+	var progress []*mcp.ProgressNotificationParams
+	session := connectClient(t, fastChecks(NewToolManager(Dependencies{GitHub: s.Client(t)})), &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, _ *mcp.ClientSession, params *mcp.ProgressNotificationParams) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress = append(progress, params)
+		},
+	}, gittest.NewRepository(t))
+
+	// SetProgressToken does not store the token while Meta is nil, so set Meta directly
+	params := &mcp.CallToolParams{
+		Meta:      mcp.Meta{"progressToken": "checks-1"},
+		Name:      "wait_for_checks",
+		Arguments: map[string]any{"owner": "octo", "repo": "hello", "pull_request": 9},
+	}
+	result, err := session.CallTool(context.Background(), params)
+	if err != nil || result.IsError {
+		t.Fatalf("wait_for_checks failed: %v %+v", err, result)
+	}
+
+	var out ChecksResult
+	data, _ := json.Marshal(result.StructuredContent)
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.State != checks.StateFailure || out.SHA != checksSHA || out.Passed != 1 || out.Failed != 1 || out.TimedOut {
+		t.Errorf("Unexpected result %+v", out)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "- CI / lint: FAIL (failure)") {
+		t.Errorf("Expected per-job summary, got:\n%s", text)
+	}
+
+	// Notifications are delivered asynchronously
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(progress)
+		mu.Unlock()
+		if n == 3 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(progress) != 3 {
+		t.Fatalf("Expected progress for the start and each finished job, got %d notifications", len(progress))
+	}
+	if p := progress[2]; p.ProgressToken != "checks-1" || p.Progress != 2 || p.Total != 2 || !strings.HasPrefix(p.Message, "lint: failure") {
+		t.Errorf("Unexpected progress %+v", p)
+	}
+}
+
+func TestWaitForChecksCancellation(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveCheckRuns(s, [][2]string{{"build", "success"}})
+	tm := NewToolManager(Dependencies{GitHub: s.Client(t)})
+	tm.checksWait.Backoff = checks.Backoff{Initial: time.Hour, Max: time.Hour, Factor: 1}
+	session := connect(t, tm)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "wait_for_checks", Arguments: map[string]any{"owner": "octo", "repo": "hello", "pull_request": 9}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the call to end with the client's context, got %v", err)
+	}
+}
+
+func TestWaitForChecksResolvesHead(t *testing.T) {
+	s := githubtest.NewServer(t)
+	dir := gittest.NewRepository(t)
+	head := gittest.Run(t, dir, "rev-parse", "HEAD")
+	prefix := "GET /repos/octo/hello/commits/" + head
+	s.JSON(prefix+"/check-suites", http.StatusOK, map[string]any{"check_suites": []any{}})
+	s.JSON(prefix+"/check-runs", http.StatusOK, map[string]any{"check_runs": []map[string]any{{"id": 1, "name": "build", "status": "completed", "conclusion": "success"}}})
+	s.JSON("GET /repos/octo/hello/actions/runs", http.StatusOK, map[string]any{"workflow_runs": []any{}})
+	session := connect(t, fastChecks(NewToolManager(Dependencies{GitHub: s.Client(t)})), dir)
+
+	var out ChecksResult
+	callTool(t, session, "wait_for_checks", map[string]any{"owner": "octo", "repo": "hello"}, &out)
+	if out.SHA != head || out.State != checks.StateSuccess {
+		t.Errorf("Expected the checks of HEAD, got %+v", out)
+	}
+
+	result := callTool(t, session, "wait_for_checks", map[string]any{"owner": "octo", "repo": "hello", "ref": "main", "pull_request": 1}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "not both") {
+		t.Errorf("Expected error for ref and pull_request, got %q", text)
+	}
+}
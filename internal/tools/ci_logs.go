@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/checks"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/cilogs"
+)
+
+// Limits of the log excerpt of each failed job
+const (
+	defaultExcerptLines = 100
+	maxExcerptLines     = 500
+)
+
+// CIFailureLogsInput is the input of the get_ci_failure_logs tool
+type CIFailureLogsInput struct {
+	Owner       string `json:"owner,omitempty" jsonschema:"repository owner (default: from the origin remote of the repository)"`
+	Repo        string `json:"repo,omitempty" jsonschema:"repository name (default: from the origin remote of the repository)"`
+	RunID       int64  `json:"run_id,omitempty" jsonschema:"workflow run whose failed jobs to analyze"`
+	JobID       int64  `json:"job_id,omitempty" jsonschema:"single job to analyze, failed or not"`
+	Ref         string `json:"ref,omitempty" jsonschema:"commit SHA, branch or tag whose failed runs to analyze (default: HEAD of the repository)"`
+	PullRequest int    `json:"pull_request,omitempty" jsonschema:"pull request whose head commit's failed runs to analyze, instead of ref"`
+	MaxLines    int    `json:"max_lines,omitempty" jsonschema:"lines of the failing step's log to return per job (default: 100, at most 500)"`
+	Path        string `json:"path,omitempty" jsonschema:"directory inside the repository; must be within the client's roots. Defaults to the first root inside a repository"`
+}
+
+// CIFailureLogs is the outcome of get_ci_failure_logs
+type CIFailureLogs struct {
+	SHA  string              `json:"sha,omitempty"`
+	Jobs []cilogs.JobFailure `json:"jobs"`
+	// Other lists failed checks that are not GitHub Actions jobs and have no logs to read
+	Other []string `json:"other,omitempty"`
+}
+
+// getCIFailureLogsTool extracts the failures from the logs of failed GitHub Actions jobs
+func (tm *ToolManager) getCIFailureLogsTool() Tool {
+	return newTool("get_ci_failure_logs",
+		"Download the logs of failed GitHub Actions jobs, of a workflow run, a single job, or the runs of a commit or pull request, "+
+			"and extract what failed: Go test failures, compile errors, golangci-lint issues and panics with file and line references, "+
+			"plus the excerpt of the failing step's log without timestamps or color codes.",
+		true,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CIFailureLogsInput]) (*mcp.CallToolResultFor[CIFailureLogs], error) {
+			args := params.Arguments
+			if tm.deps.GitHub == nil {
+				return nil, ErrNoGitHub
+			}
+			if args.RunID < 0 || args.JobID < 0 || args.PullRequest < 0 || args.MaxLines < 0 {
+				return nil, errors.New("run_id, job_id, pull_request and max_lines must not be negative")
+			}
+			if (args.RunID != 0 || args.JobID != 0) && (args.Ref != "" || args.PullRequest != 0) {
+				return nil, errors.New("give run_id or job_id, or ref or pull_request, not both")
+			}
+			if args.PullRequest > 0 && args.Ref != "" {
+				return nil, errors.New("give either ref or pull_request, not both")
+			}
+			lines := min(args.MaxLines, maxExcerptLines)
+			if lines == 0 {
+				lines = defaultExcerptLines
+			}
+
+			owner, repo, err := tm.githubRepository(ctx, ss, args.Owner, args.Repo, args.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			out := CIFailureLogs{Jobs: []cilogs.JobFailure{}}
+			runs := []int64{args.RunID}
+			switch {
+			case args.JobID != 0 && args.RunID == 0:
+				job, err := tm.deps.GitHub.GetWorkflowJob(ctx, owner, repo, args.JobID)
+				if err != nil {
+					return nil, err
+				}
+				runs, out.SHA = []int64{job.RunID}, job.HeadSHA
+			case args.RunID == 0:
+				out.SHA, err = tm.checksCommit(ctx, ss, owner, repo, args.Ref, args.PullRequest, args.Path)
+				if err != nil {
+					return nil, err
+				}
+				summary, err := checks.Snapshot(ctx, tm.deps.GitHub, owner, repo, out.SHA)
+				if err != nil {
+					return nil, err
+				}
+				runs, out.Other = failedRuns(summary)
+			}
+
+			for _, runID := range runs {
+				jobs, err := cilogs.Analyze(ctx, tm.deps.GitHub, owner, repo, runID, args.JobID, lines)
+				if err != nil {
+					return nil, err
+				}
+				out.Jobs = append(out.Jobs, jobs...)
+			}
+
+			text := cilogs.Markdown(out.Jobs)
+			if len(out.Other) > 0 {
+				text += fmt.Sprintf("\nFailed checks without GitHub Actions logs: %s\n", strings.Join(out.Other, ", "))
+			}
+			return &mcp.CallToolResultFor[CIFailureLogs]{
+				Content:           []mcp.Content{&mcp.TextContent{Text: text}},
+				StructuredContent: out,
+			}, nil
+		})
+}
+
+// failedRuns returns the workflow runs with failed jobs in a checks summary, and the
+// names of failed checks that belong to no workflow run
+func failedRuns(summary *checks.Summary) ([]int64, []string) {
+	var runs []int64
+	var other []string
+	for _, job := range summary.Jobs {
+		switch {
+		case !job.Failed():
+		case job.RunID == 0:
+			other = append(other, job.Name)
+		case !slices.Contains(runs, job.RunID):
+			runs = append(runs, job.RunID)
+		}
+	}
+	return runs, other
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+)
+
+const lintLog = `2026-10-01T10:00:00.0000000Z ##[group]Run golangci-lint run
+2026-10-01T10:00:05.0000000Z upload.go:30:9: Error return value of ` + "`f.Close`" + ` is not checked (errcheck)
+2026-10-01T10:00:05.0000000Z ##[error]Process completed with exit code 1.`
+
+// serveFailedRun serves the checks of checksSHA, where job 2 of run 100 failed with
+// lintLog and a check of another app failed too; the run's log archive has expired
+func serveFailedRun(s *githubtest.Server) {
+	prefix := "GET /repos/octo/hello/commits/" + checksSHA
+	s.JSON("GET /repos/octo/hello/pulls/9", http.StatusOK, map[string]any{"number": 9, "head": map[string]any{"sha": checksSHA}})
+	s.JSON(prefix+"/check-suites", http.StatusOK, map[string]any{"check_suites": []any{}})
+	s.JSON("GET /repos/octo/hello/actions/runs", http.StatusOK, map[string]any{"workflow_runs": []map[string]any{{"id": 100, "name": "CI", "check_suite_id": 10}}})
+	s.JSON(prefix+"/check-runs", http.StatusOK, map[string]any{"check_runs": []map[string]any{
+		{"id": 1, "name": "build", "status": "completed", "conclusion": "success", "check_suite": map[string]any{"id": 10}},
+		{"id": 2, "name": "lint", "status": "completed", "conclusion": "failure", "check_suite": map[string]any{"id": 10}},
+		{"id": 3, "name": "coverage", "status": "completed", "conclusion": "failure", "check_suite": map[string]any{"id": 20}},
+	}})
+	s.JSON("GET /repos/octo/hello/actions/runs/100/jobs", http.StatusOK, map[string]any{"jobs": []map[string]any{
+		{"id": 1, "run_id": 100, "name": "build", "workflow_name": "CI", "conclusion": "success"},
+		{"id": 2, "run_id": 100, "name": "lint", "workflow_name": "CI", "conclusion": "failure", "head_sha": checksSHA,
+			"steps": []map[string]any{{"name": "Run golangci-lint", "number": 4, "conclusion": "failure"}}},
+	}})
+	s.JSON("GET /repos/octo/hello/actions/jobs/2", http.StatusOK, map[string]any{"id": 2, "run_id": 100, "name": "lint", "head_sha": checksSHA})
+	s.JSON("GET /repos/octo/hello/actions/runs/100/logs", http.StatusGone, map[string]any{"message": "Gone"})
+	s.HandleFunc("GET /repos/octo/hello/actions/jobs/2/logs", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(lintLog))
+	})
+}
+
+func TestGetCIFailureLogs(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveFailedRun(s)
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}))
+
+	var out CIFailureLogs
+	result := callTool(t, session, "get_ci_failure_logs", map[string]any{"owner": "octo", "repo": "hello", "pull_request": 9}, &out)
+	if out.SHA != checksSHA || len(out.Jobs) != 1 || len(out.Other) != 1 || out.Other[0] != "coverage" {
+		t.Fatalf("Unexpected result %+v", out)
+	}
+	job := out.Jobs[0]
+	if job.Job != "lint" || job.Step != "Run golangci-lint" || len(job.Failures) != 1 {
+		t.Fatalf("Unexpected job %+v", job)
+	}
+	if f := job.Failures[0]; f.Linter != "errcheck" || f.Locations[0].String() != "upload.go:30:9" {
+		t.Errorf("Unexpected failure %+v", f)
+	}
+	if strings.Contains(job.Excerpt, "2026-10-01T") {
+		t.Errorf("Expected timestamps to be removed, got %q", job.Excerpt)
+	}
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"## CI / lint", "upload.go:30:9 **lint (errcheck)**", "without GitHub Actions logs: coverage"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestGetCIFailureLogsForJob(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveFailedRun(s)
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}))
+
+	var out CIFailureLogs
+	callTool(t, session, "get_ci_failure_logs", map[string]any{"owner": "octo", "repo": "hello", "job_id": 2}, &out)
+	if out.SHA != checksSHA || len(out.Jobs) != 1 || out.Jobs[0].RunID != 100 {
+		t.Errorf("Unexpected result %+v", out)
+	}
+
+	result := callTool(t, session, "get_ci_failure_logs", map[string]any{"owner": "octo", "repo": "hello", "run_id": 100, "ref": "main"}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "not both") {
+		t.Errorf("Expected an error for conflicting arguments, got %q", text)
+	}
+}
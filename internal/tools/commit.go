@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/commits"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git"
+)
+
+// CommitChangesInput is the input of the commit_changes tool
+type CommitChangesInput struct {
+	Type        string           `json:"type" jsonschema:"commit type from the conventions policy, e.g. feat or fix"`
+	Scope       string           `json:"scope,omitempty" jsonschema:"optional scope, e.g. api"`
+	Description string           `json:"description" jsonschema:"imperative summary without a trailing period, e.g. add login endpoint"`
+	Body        string           `json:"body,omitempty" jsonschema:"what changed and why; wrapped to the policy's body width"`
+	Breaking    bool             `json:"breaking,omitempty" jsonschema:"mark the commit as a breaking change with ! in the header"`
+	Footers     []commits.Footer `json:"footers,omitempty" jsonschema:"trailers such as {token: Refs, value: #42} or {token: BREAKING CHANGE, value: ...}"`
+	Files       []string         `json:"files" jsonschema:"paths relative to the repository root to stage and commit; nothing else is committed"`
+	IssueNumber int              `json:"issue_number,omitempty" jsonschema:"issue the commit must reference in a footer such as Refs #42"`
+	Path        string           `json:"path,omitempty" jsonschema:"directory inside the repository; must be within the client's roots. Defaults to the first root inside a repository"`
+}
+
+// CommitChanges reports the commit made by commit_changes
+type CommitChanges struct {
+	SHA      string              `json:"sha"`
+	Branch   string              `json:"branch"`
+	Message  string              `json:"message"`
+	Files    []git.FileChange    `json:"files"`
+	Warnings []commits.Violation `json:"warnings"`
+}
+
+// commitChangesTool stages an explicit list of files and commits them with a
+// Conventional Commits message checked against the conventions policy
+func (tm *ToolManager) commitChangesTool() Tool {
+	return newTool("commit_changes",
+		"Stage the given files and commit them with a Conventional Commits message rendered from type, scope, "+
+			"description, body and footers. The message is validated with the same rules as validate_commit_message "+
+			"and rejected if it has errors. Refuses to commit on protected branches, on a detached HEAD or when the "+
+			"files have no changes. Returns the commit SHA.",
+		false,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CommitChangesInput]) (*mcp.CallToolResultFor[CommitChanges], error) {
+			args := params.Arguments
+			pol := tm.deps.Policy(ss)
+
+			switch {
+			case strings.TrimSpace(args.Type) == "":
+				return nil, errors.New("type is required")
+			case strings.TrimSpace(args.Description) == "":
+				return nil, errors.New("description is required")
+			case len(args.Files) == 0:
+				return nil, errors.New("files is required: list the paths to commit")
+			}
+
+			message := commits.Message{
+				Type:        strings.TrimSpace(args.Type),
+				Scope:       strings.TrimSpace(args.Scope),
+				Breaking:    args.Breaking,
+				Description: strings.TrimSpace(args.Description),
+				Body:        args.Body,
+				Footers:     args.Footers,
+			}.Render(pol.Commits.BodyWrap)
+
+			result := commits.Validate(message, pol, commits.Options{IssueNumber: args.IssueNumber})
+			if err := checkCommitMessage(result, len(args.Footers)); err != nil {
+				return nil, err
+			}
+
+			repo, err := tm.openRepository(ctx, ss, args.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			branch, err := repo.CurrentBranch(ctx)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case branch == "":
+				return nil, errors.New("refusing to commit on a detached HEAD; switch to a work branch first")
+			case pol.IsProtectedBranch(branch):
+				return nil, fmt.Errorf("refusing to commit on protected branch %q; switch to a work branch first", branch)
+			}
+
+			files, err := repositoryPaths(repo, args.Files)
+			if err != nil {
+				return nil, err
+			}
+			if err := repo.Add(ctx, files...); err != nil {
+				return nil, err
+			}
+
+			status, err := repo.Status(ctx)
+			if err != nil {
+				return nil, err
+			}
+			staged := []git.FileChange{}
+			for _, change := range status.Staged {
+				if covered(files, change.Path) || (change.OriginalPath != "" && covered(files, change.OriginalPath)) {
+					staged = append(staged, change)
+				}
+			}
+			if len(staged) == 0 {
+				return nil, fmt.Errorf("no staged changes in %s: nothing to commit", strings.Join(files, ", "))
+			}
+
+			sha, err := repo.Commit(ctx, message, files...)
+			if err != nil {
+				return nil, err
+			}
+
+			warnings := []commits.Violation{}
+			for _, v := range result.Violations {
+				if v.Severity == commits.SeverityWarning {
+					warnings = append(warnings, v)
+				}
+			}
+			return structuredResult(CommitChanges{
+				SHA:      sha,
+				Branch:   branch,
+				Message:  message,
+				Files:    staged,
+				Warnings: warnings,
+			})
+		})
+}
+
+// checkCommitMessage rejects a rendered message with error violations or footers
+// that did not survive rendering
+func checkCommitMessage(result commits.Result, footers int) error {
+	if result.Valid && result.Commit != nil && len(result.Commit.Footers) != footers {
+		return errors.New("invalid footers: each footer needs a token such as Refs or BREAKING CHANGE and a value")
+	}
+	if result.Valid {
+		return nil
+	}
+
+	var messages []string
+	for _, v := range result.Violations {
+		if v.Severity == commits.SeverityError {
+			messages = append(messages, fmt.Sprintf("%s: %s", v.Rule, v.Message))
+		}
+	}
+	err := "invalid commit message: " + strings.Join(messages, "; ")
+	if result.SuggestedMessage != "" {
+		err += fmt.Sprintf("\nsuggested message:\n%s", result.SuggestedMessage)
+	}
+	return errors.New(err)
+}
+
+// repositoryPaths cleans paths relative to the repository root, refusing any that
+// escape it
+func repositoryPaths(repo *git.Repository, paths []string) ([]string, error) {
+	cleaned := make([]string, 0, len(paths))
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return nil, errors.New("files must not contain empty paths")
+		}
+		if filepath.IsAbs(path) || !within(repo.Dir, filepath.Join(repo.Dir, path)) {
+			return nil, fmt.Errorf("%w: %s is not relative to the repository root", ErrOutsideRoots, path)
+		}
+		cleaned = append(cleaned, filepath.ToSlash(filepath.Clean(path)))
+	}
+	return cleaned, nil
+}
+
+// covered reports whether path is one of paths or inside one of them
+func covered(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == "." || p == path || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
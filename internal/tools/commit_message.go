@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/commits"
+)
+
+// ValidateCommitMessageInput is the input of the validate_commit_message tool
+type ValidateCommitMessageInput struct {
+	Message     string `json:"message" jsonschema:"the full commit message: subject line, optional body and footers"`
+	IssueNumber int    `json:"issue_number,omitempty" jsonschema:"issue the commit must reference in a footer such as Refs #42"`
+}
+
+// validateCommitMessageTool checks a commit message against the Conventional Commits
+// rules of the conventions policy
+func (tm *ToolManager) validateCommitMessageTool() Tool {
+	return newTool("validate_commit_message",
+		"Parse a commit message as a Conventional Commit and check it against the commit conventions "+
+			"(allowed types and scopes, subject length, imperative mood, blank line after the subject, body wrapping, "+
+			"issue references). Returns the parsed commit, violations with suggested fixes and a corrected message.",
+		true,
+		func(_ context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ValidateCommitMessageInput]) (*mcp.CallToolResultFor[commits.Result], error) {
+			if strings.TrimSpace(params.Arguments.Message) == "" {
+				return nil, errors.New("message is required")
+			}
+
+			result := commits.Validate(params.Arguments.Message, tm.deps.Policy(ss), commits.Options{
+				IssueNumber: params.Arguments.IssueNumber,
+			})
+			return structuredResult(result)
+		})
+}
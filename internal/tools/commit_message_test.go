@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/commits"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+func TestValidateCommitMessageTool(t *testing.T) {
+	session := connect(t, NewToolManager(Dependencies{}))
+
+	var result commits.Result
+	callTool(t, session, "validate_commit_message", map[string]any{
+		"message":      "feature(api): Added retries.",
+		"issue_number": 12,
+	}, &result)
+
+	if result.Valid {
+		t.Error("Expected message to be invalid")
+	}
+	if result.Commit == nil || result.Commit.Type != "feature" || result.Commit.Scope != "api" {
+		t.Errorf("Expected parsed commit, got %+v", result.Commit)
+	}
+
+	found := make(map[string]bool)
+	for _, v := range result.Violations {
+		found[v.Rule] = true
+	}
+	for _, rule := range []string{commits.RuleTypeEnum, commits.RuleSubjectFullStop, commits.RuleSubjectImperative, commits.RuleIssueReference} {
+		if !found[rule] {
+			t.Errorf("Expected %s violation, got %+v", rule, result.Violations)
+		}
+	}
+
+	if want := "feat(api): Add retries\n\nRefs #12"; result.SuggestedMessage != want {
+		t.Errorf("Expected suggested message %q, got %q", want, result.SuggestedMessage)
+	}
+}
+
+func TestValidateCommitMessageToolUsesPolicy(t *testing.T) {
+	p := policy.Default()
+	p.Commits.SubjectMaxLength = 20
+	session := connect(t, NewToolManager(Dependencies{Policy: func(*mcp.ServerSession) *policy.Policy { return p }}))
+
+	var result commits.Result
+	callTool(t, session, "validate_commit_message", map[string]any{"message": "fix: handle empty responses"}, &result)
+
+	if result.Valid || len(result.Violations) != 1 || result.Violations[0].Rule != commits.RuleSubjectMaxLength {
+		t.Errorf("Expected subject length violation from policy, got %+v", result.Violations)
+	}
+}
+
+func TestValidateCommitMessageToolRequiresMessage(t *testing.T) {
+	session := connect(t, NewToolManager(Dependencies{}))
+
+	result := callTool(t, session, "validate_commit_message", map[string]any{}, nil)
+	if !result.IsError {
+		t.Error("Expected error result without a message")
+	}
+}
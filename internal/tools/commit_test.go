@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+)
+
+func TestCommitChanges(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.Run(t, dir, "switch", "-q", "-c", "feature/42-add-login")
+	gittest.WriteFile(t, dir, "login.go", "package main\n")
+	gittest.WriteFile(t, dir, "scratch.txt", "notes\n")
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	var out CommitChanges
+	callTool(t, session, "commit_changes", map[string]any{
+		"type":         "feat",
+		"scope":        "auth",
+		"description":  "add login endpoint",
+		"body":         "Users can sign in with their GitHub account instead of creating a separate password.",
+		"footers":      []map[string]any{{"token": "Refs", "value": "#42"}},
+		"files":        []string{"login.go"},
+		"issue_number": 42,
+	}, &out)
+
+	want := "feat(auth): add login endpoint\n\n" +
+		"Users can sign in with their GitHub account instead of creating a\nseparate password.\n\n" +
+		"Refs #42"
+	if out.Message != want {
+		t.Errorf("Expected message %q, got %q", want, out.Message)
+	}
+	if head := gittest.Run(t, dir, "rev-parse", "HEAD"); out.SHA != head {
+		t.Errorf("Expected SHA %s, got %s", head, out.SHA)
+	}
+	if got := gittest.Run(t, dir, "log", "-1", "--format=%B"); got != want {
+		t.Errorf("Expected commit message %q, got %q", want, got)
+	}
+	if out.Branch != "feature/42-add-login" || len(out.Files) != 1 || out.Files[0].Path != "login.go" {
+		t.Errorf("Unexpected result %+v", out)
+	}
+	if untracked := gittest.Run(t, dir, "status", "--porcelain"); untracked != "?? scratch.txt" {
+		t.Errorf("Expected scratch.txt to be left alone, got %q", untracked)
+	}
+}
+
+func TestCommitChangesRefusals(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.WriteFile(t, dir, "login.go", "package main\n")
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	valid := map[string]any{"type": "feat", "description": "add login endpoint", "files": []string{"login.go"}}
+	with := func(key string, value any) map[string]any {
+		args := map[string]any{}
+		for k, v := range valid {
+			args[k] = v
+		}
+		args[key] = value
+		return args
+	}
+
+	result := callTool(t, session, "commit_changes", valid, nil)
+	if text := errorText(t, result); !strings.Contains(text, `protected branch "main"`) {
+		t.Errorf("Expected protected branch refusal, got %q", text)
+	}
+
+	gittest.Run(t, dir, "switch", "-q", "-c", "feature/1-login")
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing files", with("files", []string{}), "files is required"},
+		{"unknown type", with("type", "feature"), "type-enum"},
+		{"missing issue reference", with("issue_number", 1), "issue-reference"},
+		{"invalid footer", with("footers", []map[string]any{{"token": "Fixes owner", "value": "x"}}), "invalid footers"},
+		{"outside repository", with("files", []string{"../other.go"}), "not relative to the repository root"},
+		{"no changes", with("files", []string{"README.md"}), "nothing to commit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, session, "commit_changes", tt.args, nil)
+			if text := errorText(t, result); !strings.Contains(text, tt.want) {
+				t.Errorf("Expected error containing %q, got %q", tt.want, text)
+			}
+		})
+	}
+
+	if count := gittest.Run(t, dir, "rev-list", "--count", "HEAD"); count != "1" {
+		t.Errorf("Expected no commits to be made, got %s", count)
+	}
+}
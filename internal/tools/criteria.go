@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+)
+
+// How the checklist comment was posted, reported in AcceptanceCriteria.Posted
+const (
+	ChecklistCreated   = "created"
+	ChecklistUpdated   = "updated"
+	ChecklistUnchanged = "unchanged"
+)
+
+// AcceptanceCriteriaInput is the input of the get_acceptance_criteria tool
+type AcceptanceCriteriaInput struct {
+	Owner  string `json:"owner,omitempty" jsonschema:"repository owner (default: from the origin remote of the repository)"`
+	Repo   string `json:"repo,omitempty" jsonschema:"repository name (default: from the origin remote of the repository)"`
+	Number int    `json:"number" jsonschema:"issue number"`
+	Done   []int  `json:"done,omitempty" jsonschema:"numbers of the criteria to tick off, counting from 1"`
+	Post   bool   `json:"post,omitempty" jsonschema:"post the checklist as an issue comment, updating an earlier checklist comment"`
+	Path   string `json:"path,omitempty" jsonschema:"directory inside the repository providing the origin remote and issue forms; within the client's roots"`
+}
+
+// AcceptanceCriteria is the checklist of an issue's acceptance criteria
+type AcceptanceCriteria struct {
+	Issue      int                `json:"issue"`
+	URL        string             `json:"url"`
+	Criteria   []issues.Criterion `json:"criteria"`
+	Remaining  int                `json:"remaining"`
+	Checklist  string             `json:"checklist"`
+	Posted     string             `json:"posted,omitempty"`
+	CommentURL string             `json:"comment_url,omitempty"`
+}
+
+// getAcceptanceCriteriaTool extracts an issue's acceptance criteria as a checklist that
+// the agent ticks off as it works and can keep up to date in an issue comment
+func (tm *ToolManager) getAcceptanceCriteriaTool() Tool {
+	return newTool("get_acceptance_criteria",
+		"Extract the acceptance criteria of a GitHub issue from its \"Acceptance Criteria\" section, issue form responses or task lists "+
+			"and return them as a checklist. Progress recorded in an earlier checklist comment is kept; done ticks off further criteria "+
+			"and post creates or updates the checklist comment on the issue.",
+		false,
+		func(ctx context.Context, ss *mcp.ServerSession,
+			params *mcp.CallToolParamsFor[AcceptanceCriteriaInput]) (*mcp.CallToolResultFor[AcceptanceCriteria], error) {
+			args := params.Arguments
+			if args.Number <= 0 {
+				return nil, errors.New("number must be a positive issue number")
+			}
+			if tm.deps.GitHub == nil {
+				return nil, ErrNoGitHub
+			}
+
+			owner, repo, err := tm.githubRepository(ctx, ss, args.Owner, args.Repo, args.Path)
+			if err != nil {
+				return nil, err
+			}
+			headings, err := tm.issueFormHeadings(ctx, ss, args.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			issue, err := issues.Load(ctx, tm.deps.GitHub, owner, repo, args.Number)
+			if err != nil {
+				return nil, err
+			}
+
+			// Only the checklist posted with the same credentials records progress
+			var login string
+			if user, err := tm.deps.GitHub.GetAuthenticatedUser(ctx); err == nil {
+				login = user.Login
+			} else if args.Post {
+				return nil, fmt.Errorf("cannot post the checklist without knowing the authenticated user: %w", err)
+			}
+
+			criteria := issues.AcceptanceCriteria(issue.Body, headings...)
+			comment, posted := issues.ChecklistComment(issue.Comments, login)
+			if posted {
+				criteria = issues.MergeProgress(criteria, issues.AcceptanceCriteria(comment.Body))
+			}
+			for _, n := range args.Done {
+				if n < 1 || n > len(criteria) {
+					return nil, fmt.Errorf("done: criterion %d does not exist; the issue has %d acceptance criteria", n, len(criteria))
+				}
+				criteria[n-1].Done = true
+			}
+
+			out := AcceptanceCriteria{Issue: issue.Number, URL: issue.URL, Criteria: criteria, Checklist: issues.Checklist(criteria)}
+			for _, c := range criteria {
+				if !c.Done {
+					out.Remaining++
+				}
+			}
+
+			if args.Post {
+				switch {
+				case !posted:
+					created, err := tm.deps.GitHub.CreateIssueComment(ctx, owner, repo, issue.Number, out.Checklist)
+					if err != nil {
+						return nil, err
+					}
+					out.Posted, out.CommentURL = ChecklistCreated, created.HTMLURL
+				case strings.TrimSpace(comment.Body) != strings.TrimSpace(out.Checklist):
+					updated, err := tm.deps.GitHub.UpdateIssueComment(ctx, owner, repo, comment.ID, out.Checklist)
+					if err != nil {
+						return nil, err
+					}
+					out.Posted, out.CommentURL = ChecklistUpdated, updated.HTMLURL
+				default:
+					out.Posted, out.CommentURL = ChecklistUnchanged, comment.URL
+				}
+			}
+
+			return &mcp.CallToolResultFor[AcceptanceCriteria]{
+				Content:           []mcp.Content{&mcp.TextContent{Text: out.Checklist}},
+				StructuredContent: out,
+			}, nil
+		})
+}
+
+// issueFormHeadings returns the headings under which the issue forms of the local
+// repository place acceptance criteria. Without a local repository there are none.
+func (tm *ToolManager) issueFormHeadings(ctx context.Context, ss *mcp.ServerSession, path string) ([]string, error) {
+	local, err := tm.openRepository(ctx, ss, path)
+	if err != nil {
+		return nil, nil
+	}
+	forms, err := issues.LoadIssueForms(local.Dir)
+	if err != nil {
+		return nil, err
+	}
+	return issues.FormCriteriaHeadings(forms), nil
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+)
+
+// serveIssueThread registers octo/hello#5 with body and a comment thread that can be
+// added to and edited, and returns the thread
+func serveIssueThread(s *githubtest.Server, body string) *[]map[string]any {
+	var mu sync.Mutex
+	comments := []map[string]any{}
+
+	s.HandleFunc("GET /repos/octo/hello/issues/5", func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		githubtest.WriteJSON(w, http.StatusOK, map[string]any{
+			"number": 5, "title": "Retry uploads", "state": "open", "body": body,
+			"html_url": "https://github.com/octo/hello/issues/5", "comments": len(comments),
+		})
+	})
+	s.JSON("GET /repos/octo/hello/issues/5/timeline", http.StatusOK, []any{})
+	s.JSON("GET /user", http.StatusOK, map[string]any{"login": "agent"})
+	s.HandleFunc("GET /repos/octo/hello/issues/5/comments", func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		githubtest.WriteJSON(w, http.StatusOK, comments)
+	})
+	s.HandleFunc("POST /repos/octo/hello/issues/5/comments", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var in map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		id := len(comments) + 1
+		comment := map[string]any{"id": id, "user": map[string]any{"login": "agent"}, "body": in["body"], "html_url": "https://github.com/octo/hello/issues/5#issuecomment-" + strconv.Itoa(id)}
+		comments = append(comments, comment)
+		githubtest.WriteJSON(w, http.StatusCreated, comment)
+	})
+	s.HandleFunc("PATCH /repos/octo/hello/issues/comments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var in map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		if id < 1 || id > len(comments) {
+			githubtest.WriteJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		comments[id-1]["body"] = in["body"]
+		githubtest.WriteJSON(w, http.StatusOK, comments[id-1])
+	})
+	return &comments
+}
+
+func TestGetAcceptanceCriteria(t *testing.T) {
+	s := githubtest.NewServer(t)
+	comments := serveIssueThread(s, "### Summary\n\nRetry failed uploads\n\n### Done when\n\n- [ ] Retries three times\n- [ ] Logs each attempt\n")
+
+	dir := gittest.NewRepository(t)
+	gittest.Run(t, dir, "remote", "add", "origin", "https://github.com/octo/hello.git")
+	gittest.WriteFile(t, dir, issues.IssueFormDir+"/feature.yml",
+		"name: Feature\nbody:\n  - type: textarea\n    id: acceptance\n    attributes:\n      label: Done when\n")
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}), dir)
+
+	var out AcceptanceCriteria
+	callTool(t, session, "get_acceptance_criteria", map[string]any{"number": 5}, &out)
+	if len(out.Criteria) != 2 || out.Remaining != 2 || out.Posted != "" || len(*comments) != 0 {
+		t.Fatalf("Unexpected result %+v", out)
+	}
+
+	callTool(t, session, "get_acceptance_criteria", map[string]any{"number": 5, "done": []int{1}, "post": true}, &out)
+	if out.Posted != ChecklistCreated || out.Remaining != 1 || !strings.HasSuffix(out.CommentURL, "issuecomment-1") {
+		t.Fatalf("Expected checklist comment to be created, got %+v", out)
+	}
+
+	// Progress is read back from the comment
+	callTool(t, session, "get_acceptance_criteria", map[string]any{"number": 5, "post": true}, &out)
+	if out.Posted != ChecklistUnchanged || !out.Criteria[0].Done {
+		t.Errorf("Expected unchanged checklist with recorded progress, got %+v", out)
+	}
+
+	callTool(t, session, "get_acceptance_criteria", map[string]any{"number": 5, "done": []int{2}, "post": true}, &out)
+	if out.Posted != ChecklistUpdated || out.Remaining != 0 || len(*comments) != 1 {
+		t.Errorf("Expected checklist comment to be updated, got %+v", out)
+	}
+	if body := (*comments)[0]["body"].(string); !strings.Contains(body, "- [x] Logs each attempt") {
+		t.Errorf("Expected updated comment, got:\n%s", body)
+	}
+
+	// A checklist posted by someone else does not tick off criteria
+	*comments = append(*comments, map[string]any{"id": 2, "user": map[string]any{"login": "mallory"}, "body": issues.ChecklistMarker + "\n- [ ] Retries three times\n"})
+	callTool(t, session, "get_acceptance_criteria", map[string]any{"number": 5}, &out)
+	if !out.Criteria[0].Done {
+		t.Errorf("Expected the checklist of another user to be ignored, got %+v", out)
+	}
+
+	result := callTool(t, session, "get_acceptance_criteria", map[string]any{"number": 5, "done": []int{3}}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "criterion 3 does not exist") {
+		t.Errorf("Expected error for unknown criterion, got %q", text)
+	}
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git"
+)
+
+const (
+	// defaultLogLimit is the number of commits git_log returns when no limit is given
+	defaultLogLimit = 10
+	// maxLogLimit caps the number of commits git_log returns
+	maxLogLimit = 100
+)
+
+// RepositoryInput selects the repository a git tool works on
+type RepositoryInput struct {
+	Path string `json:"path,omitempty" jsonschema:"directory inside the repository; must be within the client's roots. Defaults to the first root inside a repository"`
+}
+
+// LogInput is the input of the git_log tool
+type LogInput struct {
+	Path  string `json:"path,omitempty" jsonschema:"directory inside the repository; must be within the client's roots. Defaults to the first root inside a repository"`
+	Limit int    `json:"limit,omitempty" jsonschema:"maximum number of commits to return (default 10, at most 100)"`
+}
+
+// RepositoryInfo describes the repository found at a path
+type RepositoryInfo struct {
+	IsRepository bool   `json:"is_repository"`
+	Root         string `json:"root,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+	Detached     bool   `json:"detached"`
+	Head         string `json:"head,omitempty"`
+	OriginURL    string `json:"origin_url,omitempty"`
+}
+
+// StashList is the output of the git_stash_list tool
+type StashList struct {
+	Stashes []git.Stash `json:"stashes"`
+}
+
+// CommitLog is the output of the git_log tool
+type CommitLog struct {
+	Commits []git.Commit `json:"commits"`
+}
+
+// gitRepositoryTool detects whether a path is inside a repository and reports its current branch
+func (tm *ToolManager) gitRepositoryTool() Tool {
+	return newTool("git_repository",
+		"Detect whether a path inside the client's roots is a Git repository and report its root, current branch "+
+			"(or detached HEAD), HEAD commit and origin URL.",
+		true,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[RepositoryInput]) (*mcp.CallToolResultFor[RepositoryInfo], error) {
+			repo, err := tm.openRepository(ctx, ss, params.Arguments.Path)
+			if errors.Is(err, git.ErrNotRepository) {
+				return structuredResult(RepositoryInfo{})
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			status, err := repo.Status(ctx)
+			if err != nil {
+				return nil, err
+			}
+			origin, err := repo.RemoteURL(ctx, "origin")
+			if err != nil {
+				return nil, err
+			}
+			return structuredResult(RepositoryInfo{
+				IsRepository: true,
+				Root:         repo.Dir,
+				Branch:       status.Branch,
+				Detached:     status.Detached,
+				Head:         status.Head,
+				OriginURL:    origin,
+			})
+		})
+}
+
+// gitStatusTool reports the branch, ahead/behind counts and changed files
+func (tm *ToolManager) gitStatusTool() Tool {
+	return newTool("git_status",
+		"Report the current branch, its upstream and ahead/behind counts versus origin, and the staged, unstaged, "+
+			"untracked and conflicted files of a repository within the client's roots.",
+		true,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[RepositoryInput]) (*mcp.CallToolResultFor[git.Status], error) {
+			repo, err := tm.openRepository(ctx, ss, params.Arguments.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			status, err := repo.Status(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return structuredResult(*status)
+		})
+}
+
+// gitStashListTool lists the stashes of a repository
+func (tm *ToolManager) gitStashListTool() Tool {
+	return newTool("git_stash_list",
+		"List the stash entries of a repository within the client's roots, newest first.",
+		true,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[RepositoryInput]) (*mcp.CallToolResultFor[StashList], error) {
+			repo, err := tm.openRepository(ctx, ss, params.Arguments.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			stashes, err := repo.Stashes(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return structuredResult(StashList{Stashes: stashes})
+		})
+}
+
+// gitLogTool lists the recent commits of the current branch
+func (tm *ToolManager) gitLogTool() Tool {
+	return newTool("git_log",
+		"List the most recent commits reachable from HEAD in a repository within the client's roots, newest first.",
+		true,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[LogInput]) (*mcp.CallToolResultFor[CommitLog], error) {
+			limit := params.Arguments.Limit
+			switch {
+			case limit < 0:
+				return nil, errors.New("limit must not be negative")
+			case limit == 0:
+				limit = defaultLogLimit
+			case limit > maxLogLimit:
+				limit = maxLogLimit
+			}
+
+			repo, err := tm.openRepository(ctx, ss, params.Arguments.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			commits, err := repo.Log(ctx, limit)
+			if err != nil {
+				return nil, err
+			}
+			return structuredResult(CommitLog{Commits: commits})
+		})
+}
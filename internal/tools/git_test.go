@@ -0,0 +1,210 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+)
+
+func TestGitRepositoryTool(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	remote := gittest.NewRemote(t, dir)
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	var info RepositoryInfo
+	callTool(t, session, "git_repository", map[string]any{}, &info)
+	if !info.IsRepository || info.Root != dir || info.Branch != "main" || info.Head == "" || info.OriginURL != remote {
+		t.Errorf("Unexpected repository info %+v", info)
+	}
+
+	plain := filepath.Join(dir, "vendor-copy")
+	if err := os.Mkdir(plain, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	callTool(t, session, "git_repository", map[string]any{"path": plain}, &info)
+	if !info.IsRepository {
+		t.Error("Expected nested directory to belong to the repository")
+	}
+}
+
+func TestGitRepositoryToolOutsideRepository(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	var info RepositoryInfo
+	callTool(t, session, "git_repository", map[string]any{"path": dir}, &info)
+	if info.IsRepository {
+		t.Errorf("Expected no repository, got %+v", info)
+	}
+}
+
+func TestGitStatusTool(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.WriteFile(t, dir, "staged.go", "package main\n")
+	gittest.Run(t, dir, "add", "staged.go")
+	gittest.WriteFile(t, dir, "README.md", "# Changed\n")
+	gittest.WriteFile(t, dir, "new.txt", "new\n")
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	var status git.Status
+	callTool(t, session, "git_status", map[string]any{"path": dir}, &status)
+
+	if status.Branch != "main" || status.Clean {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if len(status.Staged) != 1 || status.Staged[0].Path != "staged.go" {
+		t.Errorf("Unexpected staged files %+v", status.Staged)
+	}
+	if len(status.Unstaged) != 1 || status.Unstaged[0].Path != "README.md" {
+		t.Errorf("Unexpected unstaged files %+v", status.Unstaged)
+	}
+	if len(status.Untracked) != 1 || status.Untracked[0] != "new.txt" {
+		t.Errorf("Unexpected untracked files %+v", status.Untracked)
+	}
+}
+
+func TestGitStashListAndLogTools(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	for _, subject := range []string{"feat: one", "feat: two", "feat: three"} {
+		gittest.Run(t, dir, "commit", "-q", "--allow-empty", "-m", subject)
+	}
+	gittest.WriteFile(t, dir, "README.md", "# Changed\n")
+	gittest.Run(t, dir, "stash", "push", "-q", "-m", "wip")
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	var stashes StashList
+	callTool(t, session, "git_stash_list", map[string]any{}, &stashes)
+	if len(stashes.Stashes) != 1 || !strings.HasSuffix(stashes.Stashes[0].Message, "wip") {
+		t.Errorf("Unexpected stashes %+v", stashes)
+	}
+
+	var log CommitLog
+	callTool(t, session, "git_log", map[string]any{"limit": 2}, &log)
+	if len(log.Commits) != 2 || log.Commits[0].Subject != "feat: three" {
+		t.Errorf("Unexpected log %+v", log)
+	}
+
+	callTool(t, session, "git_log", map[string]any{}, &log)
+	if len(log.Commits) != 4 {
+		t.Errorf("Expected the whole history within the default limit, got %d commits", len(log.Commits))
+	}
+
+	if result := callTool(t, session, "git_log", map[string]any{"limit": -1}, nil); !result.IsError {
+		t.Error("Expected error for negative limit")
+	}
+}
+
+func TestGitToolsRestrictedToRoots(t *testing.T) {
+	inside := gittest.NewRepository(t)
+	outside := gittest.NewRepository(t)
+	session := connect(t, NewToolManager(Dependencies{}), inside)
+
+	result := callTool(t, session, "git_status", map[string]any{"path": outside}, nil)
+	if !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, ErrOutsideRoots.Error()) {
+		t.Errorf("Expected path outside the roots to be rejected, got %+v", result.Content)
+	}
+
+	link := filepath.Join(inside, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatal(err)
+	}
+	if result := callTool(t, session, "git_status", map[string]any{"path": link}, nil); !result.IsError {
+		t.Error("Expected symbolic link out of the roots to be rejected")
+	}
+
+	if result := callTool(t, session, "git_status", map[string]any{"path": filepath.Join(inside, "..", filepath.Base(outside))}, nil); !result.IsError {
+		t.Error("Expected relative escape out of the roots to be rejected")
+	}
+	if result := callTool(t, session, "git_status", map[string]any{"path": filepath.Join("..", filepath.Base(outside))}, nil); !result.IsError {
+		t.Error("Expected relative path out of the roots to be rejected")
+	}
+	var status git.Status
+	callTool(t, session, "git_status", map[string]any{"path": "."}, &status)
+	if status.Branch != "main" {
+		t.Errorf("Expected relative path to resolve against the root, got %+v", status)
+	}
+}
+
+func TestGitToolsRejectRepositoryBeyondRoot(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	sub := filepath.Join(dir, "docs")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	session := connect(t, NewToolManager(Dependencies{}), sub)
+
+	for _, args := range []map[string]any{{}, {"path": sub}} {
+		result := callTool(t, session, "git_status", args, nil)
+		if !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, ErrOutsideRoots.Error()) {
+			t.Errorf("Expected repository beyond the root to be rejected for %v, got %+v", args, result.Content)
+		}
+	}
+}
+
+func TestGitToolsFallBackToServerRepository(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	session := connect(t, NewToolManager(Dependencies{Repository: func() string { return dir }}))
+
+	var status git.Status
+	callTool(t, session, "git_status", map[string]any{}, &status)
+	if status.Branch != "main" {
+		t.Errorf("Expected status of the server repository, got %+v", status)
+	}
+
+	session = connect(t, NewToolManager(Dependencies{}))
+	if result := callTool(t, session, "git_status", map[string]any{}, nil); !result.IsError {
+		t.Error("Expected error without roots or server repository")
+	}
+}
+
+func TestGitToolsRestrictedToWorkspaces(t *testing.T) {
+	workspace := t.TempDir()
+	inside := gittest.NewRepository(t)
+	moved := filepath.Join(workspace, "app")
+	if err := os.Rename(inside, moved); err != nil {
+		t.Fatal(err)
+	}
+	outside := gittest.NewRepository(t)
+	workspaces := Workspaces{Dirs: []string{workspace}, Required: true}
+	session := connect(t, NewToolManager(Dependencies{Workspaces: workspaces}), "/", outside, moved)
+
+	var repo RepositoryInfo
+	callTool(t, session, "git_repository", map[string]any{}, &repo)
+	if want, _ := filepath.EvalSymlinks(moved); repo.Root != want {
+		t.Errorf("Expected the root within the workspaces, got %+v", repo)
+	}
+	result := callTool(t, session, "git_status", map[string]any{"path": outside}, nil)
+	if !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, ErrOutsideRoots.Error()) {
+		t.Errorf("Expected a root outside the workspaces to be ignored, got %+v", result.Content)
+	}
+
+	session = connect(t, NewToolManager(Dependencies{Workspaces: Workspaces{Required: true}, Repository: func() string { return outside }}), outside)
+	result = callTool(t, session, "git_status", map[string]any{}, nil)
+	if !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, ErrNoWorkspaces.Error()) {
+		t.Errorf("Expected the git tools to be disabled without workspaces, got %+v", result.Content)
+	}
+}
+
+func TestWithin(t *testing.T) {
+	root := filepath.FromSlash("/work/app")
+	tests := map[string]bool{
+		"/work/app":          true,
+		"/work/app/internal": true,
+		"/work/application":  false,
+		"/work":              false,
+		"/work/app/../other": false,
+	}
+	for path, want := range tests {
+		if got := within(root, filepath.Clean(filepath.FromSlash(path))); got != want {
+			t.Errorf("within(%q, %q) = %v, want %v", root, path, got, want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+)
+
+// ErrNoGitHub is returned by GitHub tools when the server has no GitHub client
+var ErrNoGitHub = errors.New("GitHub API is not configured")
+
+// GetIssueInput is the input of the get_issue tool
+type GetIssueInput struct {
+	Owner  string `json:"owner,omitempty" jsonschema:"repository owner (default: from the origin remote of the repository)"`
+	Repo   string `json:"repo,omitempty" jsonschema:"repository name (default: from the origin remote of the repository)"`
+	Number int    `json:"number" jsonschema:"issue number"`
+	Path   string `json:"path,omitempty" jsonschema:"directory inside the repository whose origin remote names the GitHub repository; must be within the client's roots"`
+}
+
+// getIssueTool loads an issue with its discussion for the agent to work from
+func (tm *ToolManager) getIssueTool() Tool {
+	return newTool("get_issue",
+		"Load a GitHub issue with its title, body, labels, assignees, milestone, linked pull requests and comment thread. "+
+			"Returns the issue as structured JSON and as Markdown. Owner and repo default to the origin remote of the repository.",
+		true,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GetIssueInput]) (*mcp.CallToolResultFor[issues.Issue], error) {
+			args := params.Arguments
+			if args.Number <= 0 {
+				return nil, errors.New("number must be a positive issue number")
+			}
+			if tm.deps.GitHub == nil {
+				return nil, ErrNoGitHub
+			}
+
+			owner, repo, err := tm.githubRepository(ctx, ss, args.Owner, args.Repo, args.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			issue, err := issues.Load(ctx, tm.deps.GitHub, owner, repo, args.Number)
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.CallToolResultFor[issues.Issue]{
+				Content:           []mcp.Content{&mcp.TextContent{Text: issue.Markdown()}},
+				StructuredContent: *issue,
+			}, nil
+		})
+}
+
+// githubRepository returns owner and repo, or if both are empty, the GitHub
+// repository named by the origin remote of the repository at path
+func (tm *ToolManager) githubRepository(ctx context.Context, ss *mcp.ServerSession, owner, repo, path string) (string, string, error) {
+	owner, repo = strings.TrimSpace(owner), strings.TrimSpace(repo)
+	switch {
+	case owner != "" && repo != "":
+		return owner, repo, nil
+	case owner != "" || repo != "":
+		return "", "", errors.New("owner and repo must be given together")
+	}
+
+	local, err := tm.openRepository(ctx, ss, path)
+	if err != nil {
+		return "", "", fmt.Errorf("owner and repo not given and no repository to read them from: %w", err)
+	}
+	remote, err := local.RemoteURL(ctx, defaultRemote)
+	if err != nil {
+		return "", "", err
+	}
+	owner, repo, ok := github.ParseRemoteURL(remote)
+	if !ok {
+		return "", "", fmt.Errorf("owner and repo not given and the %s remote %q does not name a GitHub repository", defaultRemote, remote)
+	}
+	return owner, repo, nil
+}
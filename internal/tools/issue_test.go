@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/issues"
+)
+
+// serveIssue registers an issue without comments or linked pull requests
+func serveIssue(s *githubtest.Server, owner, repo string, number int, title string) {
+	prefix := fmt.Sprintf("GET /repos/%s/%s/issues/%d", owner, repo, number)
+	s.JSON(prefix, http.StatusOK, map[string]any{
+		"number":     number,
+		"title":      title,
+		"body":       "Details",
+		"state":      "open",
+		"user":       map[string]any{"login": "alice"},
+		"labels":     []map[string]any{{"name": "bug"}},
+		"created_at": "2026-10-01T09:30:00Z",
+		"updated_at": "2026-10-01T09:30:00Z",
+	})
+	s.JSON(prefix+"/timeline", http.StatusOK, []any{})
+}
+
+func TestGetIssue(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveIssue(s, "octo", "hello", 42, "Crash on start")
+
+	dir := gittest.NewRepository(t)
+	gittest.Run(t, dir, "remote", "add", "origin", "git@github.com:octo/hello.git")
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}), dir)
+
+	var issue issues.Issue
+	result := callTool(t, session, "get_issue", map[string]any{"number": 42}, &issue)
+	if result.IsError {
+		t.Fatalf("get_issue failed: %s", errorText(t, result))
+	}
+	if issue.Owner != "octo" || issue.Repo != "hello" || issue.Title != "Crash on start" || len(issue.Labels) != 1 {
+		t.Errorf("Unexpected issue %+v", issue)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.HasPrefix(text, "# Crash on start (#42)\n") {
+		t.Errorf("Expected Markdown content, got %q", text)
+	}
+
+	serveIssue(s, "other", "repo", 7, "Elsewhere")
+	callTool(t, session, "get_issue", map[string]any{"owner": "other", "repo": "repo", "number": 7}, &issue)
+	if issue.Title != "Elsewhere" {
+		t.Errorf("Expected explicit owner and repo to be used, got %+v", issue)
+	}
+}
+
+func TestGetIssueErrors(t *testing.T) {
+	s := githubtest.NewServer(t)
+	dir := gittest.NewRepository(t)
+
+	tests := []struct {
+		name string
+		deps Dependencies
+		args map[string]any
+		want string
+	}{
+		{"no client", Dependencies{}, map[string]any{"owner": "octo", "repo": "hello", "number": 1}, "not configured"},
+		{"invalid number", Dependencies{GitHub: s.Client(t)}, map[string]any{"number": 0}, "positive issue number"},
+		{"owner without repo", Dependencies{GitHub: s.Client(t)}, map[string]any{"owner": "octo", "number": 1}, "given together"},
+		{"no origin", Dependencies{GitHub: s.Client(t)}, map[string]any{"number": 1}, "does not name a GitHub repository"},
+		{"not found", Dependencies{GitHub: s.Client(t)}, map[string]any{"owner": "octo", "repo": "hello", "number": 1}, "404"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := connect(t, NewToolManager(tt.deps), dir)
+			result := callTool(t, session, "get_issue", tt.args, nil)
+			if text := errorText(t, result); !strings.Contains(text, tt.want) {
+				t.Errorf("Expected error containing %q, got %q", tt.want, text)
+			}
+		})
+	}
+}
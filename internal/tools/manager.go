@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"encoding/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/checks"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/store"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// Tool represents a single MCP tool and the function that adds it to a server
+type Tool struct {
+	Name        string
+	Description string
+	ReadOnly    bool
+
+	register func(server *mcp.Server, tool *mcp.Tool)
+}
+
+// Dependencies are the services shared by the tools
+type Dependencies struct {
+	// Policy returns the conventions policy in effect for a session
+	Policy func(ss *mcp.ServerSession) *policy.Policy
+
+	// Repository returns the server's repository, used by git tools when the client
+	// reports no roots
+	Repository func() string
+
+	// Workspaces limits the client roots that git tools work in
+	Workspaces Workspaces
+
+	// GitHub calls the GitHub REST API on behalf of the GitHub tools
+	GitHub *github.Client
+
+	// Workflows tracks each session's progress through the development workflow
+	Workflows *workflow.Tracker
+
+	// Store holds the work saved by earlier sessions; without it work cannot be resumed
+	Store *store.Store
+}
+
+// ToolManager manages all available tools
+type ToolManager struct {
+	deps  Dependencies
+	tools []Tool
+
+	// checksWait is how wait_for_checks polls; tests shorten it
+	checksWait checks.WaitOptions
+}
+
+// NewToolManager creates a new tool manager with all tools backed by deps
+func NewToolManager(deps Dependencies) *ToolManager {
+	if deps.Policy == nil {
+		deps.Policy = func(*mcp.ServerSession) *policy.Policy { return policy.Default() }
+	}
+	if deps.Workflows == nil {
+		deps.Workflows = workflow.NewTracker()
+	}
+
+	tm := &ToolManager{
+		deps:       deps,
+		checksWait: checks.WaitOptions{Backoff: checks.DefaultBackoff, StartupGrace: checks.DefaultStartupGrace},
+	}
+	tm.tools = []Tool{
+		tm.validateCommitMessageTool(),
+		tm.validateBranchNameTool(),
+		tm.suggestBranchNameTool(),
+		tm.gitRepositoryTool(),
+		tm.gitStatusTool(),
+		tm.gitStashListTool(),
+		tm.gitLogTool(),
+		tm.createOrSwitchBranchTool(),
+		tm.commitChangesTool(),
+		tm.getIssueTool(),
+		tm.getAcceptanceCriteriaTool(),
+		tm.createPullRequestTool(),
+		tm.waitForChecksTool(),
+		tm.getCIFailureLogsTool(),
+		tm.listReviewThreadsTool(),
+		tm.replyToReviewThreadTool(),
+		tm.resolveReviewThreadTool(),
+		tm.getReviewSummaryTool(),
+		tm.startWorkflowTool(),
+		tm.getWorkflowStatusTool(),
+		tm.completeWorkflowStepTool(),
+		tm.resumeWorkTool(),
+	}
+	return tm
+}
+
+// GetAllTools returns all registered tools
+func (tm *ToolManager) GetAllTools() []Tool {
+	return tm.tools
+}
+
+// GetTool returns the tool with the given name
+func (tm *ToolManager) GetTool(name string) (Tool, bool) {
+	for _, tool := range tm.tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
+// Register adds the tool to server
+func (t Tool) Register(server *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        t.Name,
+		Description: t.Description,
+	}
+	if t.ReadOnly {
+		tool.Annotations = &mcp.ToolAnnotations{ReadOnlyHint: true}
+	}
+	t.register(server, tool)
+}
+
+// newTool creates a tool whose input and output schemas are inferred from the handler types
+func newTool[In, Out any](name, description string, readOnly bool, handler mcp.ToolHandlerFor[In, Out]) Tool {
+	return Tool{
+		Name:        name,
+		Description: description,
+		ReadOnly:    readOnly,
+		register: func(server *mcp.Server, tool *mcp.Tool) {
+			mcp.AddTool(server, tool, handler)
+		},
+	}
+}
+
+// structuredResult returns out as structured content, together with its JSON
+// encoding as text for clients that do not read structured content
+func structuredResult[Out any](out Out) (*mcp.CallToolResultFor[Out], error) {
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.CallToolResultFor[Out]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(data)}},
+		StructuredContent: out,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// connect registers the tools of tm on a new server and returns a connected client
+// session whose client reports the given directories as its roots
+func connect(t *testing.T, tm *ToolManager, roots ...string) *mcp.ClientSession {
+	t.Helper()
+	return connectClient(t, tm, nil, roots...)
+}
+
+// connectClient is connect with client options
+func connectClient(t *testing.T, tm *ToolManager, opts *mcp.ClientOptions, roots ...string) *mcp.ClientSession {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, nil)
+	for _, tool := range tm.GetAllTools() {
+		tool.Register(server)
+	}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport); err != nil {
+		t.Fatalf("server.Connect returned error: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v0.0.1"}, opts)
+	for _, root := range roots {
+		client.AddRoots(&mcp.Root{URI: "file://" + filepath.ToSlash(root)})
+	}
+	session, err := client.Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("client.Connect returned error: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// callTool calls a tool and decodes its structured content into out. It returns the raw result.
+func callTool(t *testing.T, session *mcp.ClientSession, name string, args map[string]any, out any) *mcp.CallToolResult {
+	t.Helper()
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("CallTool(%s) returned error: %v", name, err)
+	}
+	if out != nil && !result.IsError {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("Decoding structured content of %s: %v", name, err)
+		}
+	}
+	return result
+}
+
+func TestNewToolManager(t *testing.T) {
+	tm := NewToolManager(Dependencies{})
+	if tm == nil {
+		t.Fatal("NewToolManager() returned nil")
+	}
+	if len(tm.GetAllTools()) == 0 {
+		t.Error("Expected tools to be registered")
+	}
+}
+
+func TestToolsAreListed(t *testing.T) {
+	tm := NewToolManager(Dependencies{})
+	session := connect(t, tm)
+
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools returned error: %v", err)
+	}
+	if len(result.Tools) != len(tm.GetAllTools()) {
+		t.Fatalf("Expected %d tools, got %d", len(tm.GetAllTools()), len(result.Tools))
+	}
+
+	for _, listed := range result.Tools {
+		tool, ok := tm.GetTool(listed.Name)
+		if !ok {
+			t.Errorf("Unexpected tool %s", listed.Name)
+			continue
+		}
+		if listed.Description == "" || listed.InputSchema == nil {
+			t.Errorf("Tool %s is missing a description or input schema", listed.Name)
+		}
+		if tool.ReadOnly && (listed.Annotations == nil || !listed.Annotations.ReadOnlyHint) {
+			t.Errorf("Expected read-only hint on %s", listed.Name)
+		}
+	}
+}
+
+func TestGetToolUnknown(t *testing.T) {
+	if _, ok := NewToolManager(Dependencies{}).GetTool("nonexistent"); ok {
+		t.Error("Expected unknown tool lookup to fail")
+	}
+}
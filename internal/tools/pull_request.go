@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/pullrequests"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// builtinTemplate is reported as the template of descriptions written without a repository template
+const builtinTemplate = "built-in"
+
+// CreatePullRequestInput is the input of the create_pull_request tool
+type CreatePullRequestInput struct {
+	Owner      string   `json:"owner,omitempty" jsonschema:"repository owner (default: from the origin remote of the repository)"`
+	Repo       string   `json:"repo,omitempty" jsonschema:"repository name (default: from the origin remote of the repository)"`
+	Title      string   `json:"title" jsonschema:"pull request title"`
+	Head       string   `json:"head,omitempty" jsonschema:"branch with the changes, or owner:branch for a fork (default: the current branch)"`
+	Base       string   `json:"base,omitempty" jsonschema:"branch the changes are merged into (default: the policy's default branch)"`
+	Issue      int      `json:"issue,omitempty" jsonschema:"issue the pull request closes; the description always contains \"Fixes #<issue>\""`
+	Summary    string   `json:"summary,omitempty" jsonschema:"what the change does"`
+	Motivation string   `json:"motivation,omitempty" jsonschema:"why the change is needed"`
+	Testing    string   `json:"testing,omitempty" jsonschema:"how the change was tested and how reviewers can try it"`
+	Notes      string   `json:"notes,omitempty" jsonschema:"anything else reviewers should know"`
+	Template   string   `json:"template,omitempty" jsonschema:"name of a template in the repository's PULL_REQUEST_TEMPLATE directory"`
+	Draft      bool     `json:"draft,omitempty" jsonschema:"open the pull request as a draft"`
+	Reviewers  []string `json:"reviewers,omitempty" jsonschema:"users to request reviews from, and teams as org/team-slug"`
+	Path       string   `json:"path,omitempty" jsonschema:"directory inside the repository; must be within the client's roots. Defaults to the first root inside a repository"`
+}
+
+// PullRequestCreated reports the pull request create_pull_request opened
+type PullRequestCreated struct {
+	Number             int      `json:"number"`
+	URL                string   `json:"url"`
+	Title              string   `json:"title"`
+	Head               string   `json:"head"`
+	Base               string   `json:"base"`
+	Draft              bool     `json:"draft"`
+	Body               string   `json:"body"`
+	Template           string   `json:"template"`
+	RequestedReviewers []string `json:"requested_reviewers"`
+	Warnings           []string `json:"warnings"`
+}
+
+// createPullRequestTool opens a pull request with a description written from the
+// repository's template that closes the linked issue
+func (tm *ToolManager) createPullRequestTool() Tool {
+	return newTool("create_pull_request",
+		"Open a pull request for a pushed work branch. The description is written from summary, motivation, testing and notes into "+
+			"the repository's PULL_REQUEST_TEMPLATE, or a built-in template, and always closes the linked issue with \"Fixes #N\". "+
+			"Supports draft pull requests and review requests. Refuses to open a pull request from a protected branch.",
+		false,
+		func(ctx context.Context, ss *mcp.ServerSession,
+			params *mcp.CallToolParamsFor[CreatePullRequestInput]) (*mcp.CallToolResultFor[PullRequestCreated], error) {
+			args := params.Arguments
+			pol := tm.deps.Policy(ss)
+
+			title := strings.TrimSpace(args.Title)
+			if title == "" {
+				return nil, errors.New("title is required")
+			}
+			if args.Issue < 0 {
+				return nil, errors.New("issue must be a positive issue number")
+			}
+			if tm.deps.GitHub == nil {
+				return nil, ErrNoGitHub
+			}
+
+			owner, repo, err := tm.githubRepository(ctx, ss, args.Owner, args.Repo, args.Path)
+			if err != nil {
+				return nil, err
+			}
+			local, localErr := tm.openRepository(ctx, ss, args.Path)
+
+			head := strings.TrimSpace(args.Head)
+			if head == "" {
+				if localErr != nil {
+					return nil, fmt.Errorf("head not given and no repository to take the current branch from: %w", localErr)
+				}
+				if head, err = local.CurrentBranch(ctx); err != nil {
+					return nil, err
+				}
+				if head == "" {
+					return nil, errors.New("HEAD is detached; switch to a work branch or give head")
+				}
+			}
+			base := strings.TrimSpace(args.Base)
+			if base == "" {
+				base = pol.DefaultBranch
+			}
+
+			_, branch, fork := strings.Cut(head, ":")
+			if !fork {
+				branch = head
+			}
+			if pol.IsProtectedBranch(branch) {
+				return nil, fmt.Errorf("refusing to open a pull request from protected branch %q; create a work branch with create_or_switch_branch", branch)
+			}
+			if !fork && branch == base {
+				return nil, fmt.Errorf("head and base are both %q", base)
+			}
+
+			out := PullRequestCreated{Title: title, Head: head, Base: base, Template: builtinTemplate, Warnings: []string{}}
+			if reason := tm.deps.Workflows.Unreached(workflow.SessionID(ss), workflow.StepCreatePullRequest); reason != "" {
+				return nil, errors.New("the workflow has not reached the pull request yet: " + reason)
+			}
+			var tmpl *pullrequests.Template
+			if local != nil {
+				if !fork {
+					warning, err := checkPushed(ctx, local, branch)
+					if err != nil {
+						return nil, err
+					}
+					if warning != "" {
+						out.Warnings = append(out.Warnings, warning)
+					}
+				}
+				if tmpl, err = pullrequests.FindTemplate(local.Dir, strings.TrimSpace(args.Template)); err != nil {
+					return nil, err
+				}
+			} else if args.Template != "" {
+				return nil, fmt.Errorf("template %q given but no repository to read it from: %w", args.Template, localErr)
+			}
+			if tmpl != nil {
+				out.Template = tmpl.Path
+			}
+
+			description := pullrequests.Description{
+				Summary:    args.Summary,
+				Motivation: args.Motivation,
+				Testing:    args.Testing,
+				Notes:      args.Notes,
+				Issue:      args.Issue,
+			}
+			out.Body = description.Body(tmpl)
+
+			pr, err := tm.deps.GitHub.CreatePullRequest(ctx, owner, repo, github.NewPullRequest{
+				Title: title, Head: head, Base: base, Body: out.Body, Draft: args.Draft,
+			})
+			if err != nil {
+				return nil, err
+			}
+			out.Number, out.URL, out.Draft = pr.Number, pr.HTMLURL, pr.Draft
+			tm.recordFacts(ss, func(f *workflow.Facts) { f.Branch, f.PullRequest, f.PullRequestURL = branch, pr.Number, pr.HTMLURL })
+
+			out.RequestedReviewers = []string{}
+			if users, teams := splitReviewers(args.Reviewers); len(users)+len(teams) > 0 {
+				// The pull request exists now, so a failed review request is reported rather than returned
+				if _, err := tm.deps.GitHub.RequestReviewers(ctx, owner, repo, pr.Number, users, teams); err != nil {
+					out.Warnings = append(out.Warnings, fmt.Sprintf("could not request reviews: %v", err))
+				} else {
+					out.RequestedReviewers = append(out.RequestedReviewers, args.Reviewers...)
+				}
+			}
+			return structuredResult(out)
+		})
+}
+
+// checkPushed refuses branches that are not on origin and warns when the local branch
+// differs from what was pushed. Origin is asked directly, since remote-tracking
+// branches are only as fresh as the last fetch.
+func checkPushed(ctx context.Context, repo *git.Repository, branch string) (string, error) {
+	remote, err := repo.RemoteBranchHead(ctx, defaultRemote, branch)
+	if err != nil {
+		return "", fmt.Errorf("checking whether branch %q has been pushed to %s: %w", branch, defaultRemote, err)
+	}
+	if remote == "" {
+		return "", fmt.Errorf("branch %q has not been pushed to %s; push it before opening a pull request", branch, defaultRemote)
+	}
+	local, err := repo.RevParse(ctx, "refs/heads/"+branch)
+	if err != nil || local == remote {
+		return "", nil
+	}
+	return fmt.Sprintf("local branch %s differs from %s/%s; push it so reviewers see the latest commits", branch, defaultRemote, branch), nil
+}
+
+// splitReviewers separates users from teams given as org/team-slug
+func splitReviewers(reviewers []string) ([]string, []string) {
+	var users, teams []string
+	for _, r := range reviewers {
+		r = strings.TrimPrefix(strings.TrimSpace(r), "@")
+		if r == "" {
+			continue
+		}
+		if _, team, ok := strings.Cut(r, "/"); ok {
+			teams = append(teams, team)
+		} else {
+			users = append(users, r)
+		}
+	}
+	return users, teams
+}
@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+)
+
+// servePullRequests accepts new pull requests and review requests for octo/hello and
+// records the request bodies
+func servePullRequests(s *githubtest.Server) (*map[string]any, *map[string][]string) {
+	created := map[string]any{}
+	reviews := map[string][]string{}
+	s.HandleFunc("POST /repos/octo/hello/pulls", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		githubtest.WriteJSON(w, http.StatusCreated, map[string]any{
+			"number": 9, "html_url": "https://github.com/octo/hello/pull/9", "draft": created["draft"],
+		})
+	})
+	s.HandleFunc("POST /repos/octo/hello/pulls/9/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&reviews)
+		githubtest.WriteJSON(w, http.StatusCreated, map[string]any{"number": 9})
+	})
+	return &created, &reviews
+}
+
+// pushedBranch creates a repository with origin and a work branch pushed to it
+func pushedBranch(t *testing.T) string {
+	dir, _ := pushedBranchAndRemote(t)
+	return dir
+}
+
+// pushedBranchAndRemote is pushedBranch that also returns the path of origin
+func pushedBranchAndRemote(t *testing.T) (string, string) {
+	dir := gittest.NewRepository(t)
+	remote := gittest.NewRemote(t, dir)
+	gittest.Run(t, dir, "switch", "-q", "-c", "feature/5-retry-uploads")
+	gittest.WriteFile(t, dir, "upload.go", "package upload\n")
+	gittest.Run(t, dir, "add", "upload.go")
+	gittest.Run(t, dir, "commit", "-q", "-m", "feat: retry uploads")
+	gittest.Run(t, dir, "push", "-q", "origin", "feature/5-retry-uploads")
+	return dir, remote
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	s := githubtest.NewServer(t)
+	created, reviews := servePullRequests(s)
+	dir := pushedBranch(t)
+	gittest.WriteFile(t, dir, ".github/pull_request_template.md", "## Description\n\n<!-- What does it do? -->\n\n## Checklist\n\n- [ ] Tests\n")
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}), dir)
+
+	var out PullRequestCreated
+	result := callTool(t, session, "create_pull_request", map[string]any{
+		"owner": "octo", "repo": "hello", "title": "feat: retry uploads", "issue": 5, "summary": "Retries failed uploads.",
+		"draft": true, "reviewers": []string{"@alice", "octo/reviewers"},
+	}, &out)
+	if result.IsError {
+		t.Fatalf("create_pull_request failed: %s", errorText(t, result))
+	}
+
+	if out.Number != 9 || !out.Draft || out.Head != "feature/5-retry-uploads" || out.Base != "main" || out.Template != ".github/pull_request_template.md" {
+		t.Errorf("Unexpected result %+v", out)
+	}
+	body := (*created)["body"].(string)
+	if !strings.Contains(body, "## Description\n\nRetries failed uploads.\n") || !strings.Contains(body, "- [ ] Tests") ||
+		!strings.Contains(body, "Fixes #5") {
+		t.Errorf("Unexpected body:\n%s", body)
+	}
+	if (*created)["head"] != "feature/5-retry-uploads" || (*created)["draft"] != true {
+		t.Errorf("Unexpected request %+v", *created)
+	}
+	if strings.Join((*reviews)["reviewers"], ",") != "alice" || strings.Join((*reviews)["team_reviewers"], ",") != "reviewers" {
+		t.Errorf("Unexpected review request %+v", *reviews)
+	}
+	if len(out.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", out.Warnings)
+	}
+}
+
+func TestCreatePullRequestWarnsAboutUnpushedCommits(t *testing.T) {
+	s := githubtest.NewServer(t)
+	servePullRequests(s)
+	dir := pushedBranch(t)
+	gittest.WriteFile(t, dir, "upload.go", "package upload\n\n// Retries is the number of attempts\nconst Retries = 3\n")
+	gittest.Run(t, dir, "commit", "-q", "-am", "feat: add retry count")
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}), dir)
+
+	var out PullRequestCreated
+	callTool(t, session, "create_pull_request", map[string]any{"owner": "octo", "repo": "hello", "title": "feat: retry uploads"}, &out)
+	if out.Template != "built-in" || len(out.Warnings) != 1 || !strings.Contains(out.Warnings[0], "differs from origin") {
+		t.Errorf("Expected a warning about unpushed commits, got %+v", out)
+	}
+}
+
+func TestCreatePullRequestIgnoresStaleRemoteTrackingBranches(t *testing.T) {
+	s := githubtest.NewServer(t)
+	created, _ := servePullRequests(s)
+	dir, remote := pushedBranchAndRemote(t)
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}), dir)
+	args := map[string]any{"owner": "octo", "repo": "hello", "title": "feat: retry uploads"}
+
+	other := gittest.Clone(t, remote)
+	gittest.Run(t, other, "switch", "-q", "feature/5-retry-uploads")
+	gittest.Run(t, other, "commit", "-q", "--allow-empty", "-m", "feat: pushed elsewhere")
+	gittest.Run(t, other, "push", "-q", "origin", "feature/5-retry-uploads")
+	var out PullRequestCreated
+	callTool(t, session, "create_pull_request", args, &out)
+	if len(out.Warnings) != 1 || !strings.Contains(out.Warnings[0], "differs from origin") {
+		t.Errorf("Expected a warning about the branch moving on origin, got %+v", out)
+	}
+
+	*created = map[string]any{}
+	gittest.Run(t, other, "push", "-q", "origin", "--delete", "feature/5-retry-uploads")
+	result := callTool(t, session, "create_pull_request", args, nil)
+	if text := errorText(t, result); !strings.Contains(text, "has not been pushed") {
+		t.Errorf("Expected a branch deleted on origin to be refused, got %q", text)
+	}
+	if len(*created) != 0 {
+		t.Errorf("Expected no pull request to be opened, got %v", *created)
+	}
+}
+
+func TestCreatePullRequestRefusals(t *testing.T) {
+	s := githubtest.NewServer(t)
+	servePullRequests(s)
+	dir := pushedBranch(t)
+	gittest.Run(t, dir, "switch", "-q", "-c", "feature/6-local-only")
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"no title", map[string]any{"owner": "octo", "repo": "hello"}, "title is required"},
+		{"protected head", map[string]any{"owner": "octo", "repo": "hello", "title": "t", "head": "main"}, "protected branch \"main\""},
+		{"protected fork head", map[string]any{"owner": "octo", "repo": "hello", "title": "t", "head": "alice:main"}, "protected branch \"main\""},
+		{"same as base", map[string]any{"owner": "octo", "repo": "hello", "title": "t", "head": "feature/5-retry-uploads", "base": "feature/5-retry-uploads"}, "both"},
+		{"not pushed", map[string]any{"owner": "octo", "repo": "hello", "title": "t"}, "has not been pushed"},
+		{"unknown template", map[string]any{"owner": "octo", "repo": "hello", "title": "t", "head": "feature/5-retry-uploads", "template": "x"}, "not found"},
+	}
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}), dir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, session, "create_pull_request", tt.args, nil)
+			if text := errorText(t, result); !strings.Contains(text, tt.want) {
+				t.Errorf("Expected error containing %q, got %q", tt.want, text)
+			}
+		})
+	}
+}
+
+func TestCreatePullRequestFollowsWorkflow(t *testing.T) {
+	s := githubtest.NewServer(t)
+	created, _ := servePullRequests(s)
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}), pushedBranch(t))
+
+	callTool(t, session, "start_workflow", map[string]any{"issue": 5}, nil)
+	result := callTool(t, session, "create_pull_request", map[string]any{"owner": "octo", "repo": "hello", "title": "feat: retry uploads"}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "has not reached the pull request") {
+		t.Errorf("Expected the pull request to wait for the workflow, got %q", text)
+	}
+	if len(*created) != 0 {
+		t.Errorf("Expected no pull request to be opened, got %v", *created)
+	}
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git"
+)
+
+// ErrOutsideRoots is returned when a tool is asked to work on a path outside the client's roots
+var ErrOutsideRoots = errors.New("path is outside the client's roots")
+
+// ErrNoWorkspaces is returned by the git tools when workspaces are required but none
+// are configured
+var ErrNoWorkspaces = errors.New("the git tools are disabled over HTTP until git.workspaces lists the directories client roots may use")
+
+// Workspaces limits the directories of the server's machine that the git tools and
+// the server may use as client roots
+type Workspaces struct {
+	// Dirs are the directories roots must lie within. Without any, every root is
+	// allowed unless Required is set.
+	Dirs []string
+
+	// Required refuses every root while Dirs is empty. It is set for the HTTP
+	// transports, whose clients may not share the server's filesystem and must not
+	// reach other users' checkouts by declaring them as roots.
+	Required bool
+}
+
+// Enabled reports whether any root may be used
+func (w Workspaces) Enabled() bool {
+	return len(w.Dirs) > 0 || !w.Required
+}
+
+// Allow returns dir with symbolic links resolved if it lies within one of the
+// workspaces, or anywhere if there are none and they are not required
+func (w Workspaces) Allow(dir string) (string, bool) {
+	if !w.Enabled() || dir == "" {
+		return "", false
+	}
+	resolved, err := resolvePath(dir)
+	if err != nil {
+		return "", false
+	}
+	if len(w.Dirs) == 0 {
+		return resolved, true
+	}
+	for _, workspace := range w.Dirs {
+		if workspace, err := resolvePath(workspace); err == nil && within(workspace, resolved) {
+			return resolved, true
+		}
+	}
+	return "", false
+}
+
+// rootsTimeout bounds how long a tool waits for the client to list its roots
+const rootsTimeout = 10 * time.Second
+
+// openRepository resolves the repository a tool works on. An empty path selects the
+// first client root inside a repository. An explicit path must lie within one of the
+// client's roots; relative paths are resolved against each root in turn. The
+// repository's top-level directory must lie within the same root, so that a root
+// inside a larger repository does not expose the rest of it. Clients that report no
+// roots are limited to the server's repository. Roots outside the workspaces are
+// ignored.
+func (tm *ToolManager) openRepository(ctx context.Context, ss *mcp.ServerSession, path string) (*git.Repository, error) {
+	if !tm.deps.Workspaces.Enabled() {
+		return nil, ErrNoWorkspaces
+	}
+	roots := tm.allowedRoots(ctx, ss)
+	if len(roots) == 0 {
+		return nil, errors.New("no repository available: the client reported no roots within the workspaces and the server has no repository")
+	}
+
+	if path == "" {
+		var outside error
+		for _, root := range roots {
+			repo, err := openWithin(ctx, root, root)
+			if err == nil {
+				return repo, nil
+			}
+			if errors.Is(err, ErrOutsideRoots) && outside == nil {
+				outside = err
+			}
+		}
+		if outside != nil {
+			return nil, outside
+		}
+		return nil, fmt.Errorf("%w: none of the client's roots is inside a repository", git.ErrNotRepository)
+	}
+
+	for _, root := range roots {
+		candidate := path
+		if !filepath.IsAbs(candidate) {
+			candidate = filepath.Join(root, candidate)
+		}
+		resolved, err := resolvePath(candidate)
+		if err != nil {
+			if filepath.IsAbs(path) {
+				return nil, err
+			}
+			continue
+		}
+		if within(root, resolved) {
+			return openWithin(ctx, root, resolved)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrOutsideRoots, path)
+}
+
+// openWithin opens the repository containing path, which must lie within root
+// together with the repository's top-level directory
+func openWithin(ctx context.Context, root, path string) (*git.Repository, error) {
+	repo, err := git.Open(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	toplevel, err := resolvePath(repo.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if !within(root, toplevel) {
+		return nil, fmt.Errorf("%w: the repository at %s extends beyond the root %s", ErrOutsideRoots, repo.Dir, root)
+	}
+	return repo, nil
+}
+
+// allowedRoots returns the local directories of the client's file roots that lie
+// within the workspaces, or the server's repository if there are none and it lies
+// within them too
+func (tm *ToolManager) allowedRoots(ctx context.Context, ss *mcp.ServerSession) []string {
+	var roots []string
+	if ss != nil {
+		ctx, cancel := context.WithTimeout(ctx, rootsTimeout)
+		defer cancel()
+
+		if result, err := ss.ListRoots(ctx, nil); err == nil {
+			for _, root := range result.Roots {
+				dir, ok := git.FileURIPath(root.URI)
+				if !ok {
+					continue
+				}
+				if dir, ok := tm.deps.Workspaces.Allow(dir); ok {
+					roots = append(roots, dir)
+				}
+			}
+		}
+	}
+
+	if len(roots) == 0 && tm.deps.Repository != nil {
+		if repo := tm.deps.Repository(); repo != "" {
+			if dir, ok := tm.deps.Workspaces.Allow(repo); ok {
+				roots = append(roots, dir)
+			}
+		}
+	}
+	return roots
+}
+
+// resolvePath returns the absolute path with symbolic links resolved, so that links
+// cannot be used to escape a root
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+// within reports whether path is root or one of its descendants
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
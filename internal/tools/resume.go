@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/store"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// ErrNoStore is returned by resume_work when the server does not save work
+var ErrNoStore = errors.New("saved work is not available: the server keeps no state file")
+
+// ResumeWorkInput is the input of the resume_work tool
+type ResumeWorkInput struct {
+	Issue       int    `json:"issue,omitempty" jsonschema:"issue whose work to resume"`
+	Branch      string `json:"branch,omitempty" jsonschema:"branch whose work to resume"`
+	PullRequest int    `json:"pull_request,omitempty" jsonschema:"pull request whose work to resume"`
+	Owner       string `json:"owner,omitempty" jsonschema:"repository owner (default: from the origin remote of the repository)"`
+	Repo        string `json:"repo,omitempty" jsonschema:"repository name (default: from the origin remote of the repository)"`
+	Path        string `json:"path,omitempty" jsonschema:"directory inside the repository; must be within the client's roots. Defaults to the first root inside a repository"`
+}
+
+// ResumedWork is the saved work that resume_work picked up, with its workflow now
+// belonging to the session. The workflow forgets the checks and review, which have to
+// be observed again; SavedFacts has them as they were saved.
+type ResumedWork struct {
+	store.Record
+	SavedFacts    workflow.Facts `json:"saved_facts"`
+	CurrentBranch string         `json:"current_branch,omitempty"`
+}
+
+// resumeWorkTool picks up work saved by an earlier session
+func (tm *ToolManager) resumeWorkTool() Tool {
+	return newTool("resume_work",
+		"Resume work saved by an earlier session or before a server restart: the issue, its branch and pull request, "+
+			"the last known checks and the workflow progress, which becomes this session's active workflow. "+
+			"Finds the work by issue, branch or pull request; by default the work on the checked-out branch, "+
+			"or else the most recent work in the repository.",
+		false,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ResumeWorkInput]) (*mcp.CallToolResultFor[ResumedWork], error) {
+			args := params.Arguments
+			if tm.deps.Store == nil {
+				return nil, ErrNoStore
+			}
+			if args.Issue < 0 || args.PullRequest < 0 {
+				return nil, errors.New("issue and pull_request must not be negative")
+			}
+
+			repository, err := tm.workRepository(ctx, ss, args.Owner, args.Repo, args.Path)
+			if err != nil {
+				return nil, err
+			}
+			var current string
+			if local, err := tm.openRepository(ctx, ss, args.Path); err == nil {
+				current, _ = local.CurrentBranch(ctx)
+			}
+
+			query := store.Query{Owner: workOwner(ctx), Repository: repository, Issue: args.Issue, Branch: strings.TrimSpace(args.Branch), PullRequest: args.PullRequest}
+			record, err := tm.findWork(query, current)
+			if err != nil {
+				return nil, err
+			}
+
+			saved := record.Workflow.Facts
+			record.Workflow = *tm.deps.Workflows.Resume(workflow.SessionID(ss), &record.Workflow)
+			out := ResumedWork{Record: *record, SavedFacts: saved, CurrentBranch: current}
+			return &mcp.CallToolResultFor[ResumedWork]{
+				Content:           []mcp.Content{&mcp.TextContent{Text: out.Markdown()}},
+				StructuredContent: out,
+			}, nil
+		})
+}
+
+// findWork looks up the saved work for query. A query that names no work prefers the
+// work on the checked-out branch.
+func (tm *ToolManager) findWork(query store.Query, current string) (*store.Record, error) {
+	if query.Issue == 0 && query.Branch == "" && query.PullRequest == 0 && current != "" {
+		onBranch := query
+		onBranch.Branch = current
+		if record, err := tm.deps.Store.Find(onBranch); !errors.Is(err, store.ErrNotFound) {
+			return record, err
+		}
+	}
+
+	record, err := tm.deps.Store.Find(query)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("%w %s; start the work with start_workflow", err, describeQuery(query))
+	}
+	return record, err
+}
+
+// describeQuery names what a query looks for
+func describeQuery(q store.Query) string {
+	var parts []string
+	if q.Issue > 0 {
+		parts = append(parts, fmt.Sprintf("issue #%d", q.Issue))
+	}
+	if q.Branch != "" {
+		parts = append(parts, "branch "+q.Branch)
+	}
+	if q.PullRequest > 0 {
+		parts = append(parts, fmt.Sprintf("pull request #%d", q.PullRequest))
+	}
+	if q.Repository != "" {
+		parts = append(parts, "in "+q.Repository)
+	}
+	if len(parts) == 0 {
+		return "any work"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Markdown summarizes the resumed work and what to check before carrying on
+func (w ResumedWork) Markdown() string {
+	var sb strings.Builder
+	facts := w.SavedFacts
+
+	title := "Resuming work"
+	if w.Issue > 0 {
+		title += fmt.Sprintf(" on issue #%d", w.Issue)
+	}
+	if w.Repository != "" {
+		title += " in " + w.Repository
+	}
+	fmt.Fprintf(&sb, "# %s\n\nSaved %s.\n\n", title, w.SavedAt.UTC().Format(time.RFC3339))
+
+	switch {
+	case facts.Branch == "":
+		sb.WriteString("- **Branch:** none recorded\n")
+	case w.CurrentBranch == "" || w.CurrentBranch == facts.Branch:
+		fmt.Fprintf(&sb, "- **Branch:** %s\n", facts.Branch)
+	default:
+		fmt.Fprintf(&sb, "- **Branch:** %s, but %s is checked out; switch with create_or_switch_branch\n", facts.Branch, w.CurrentBranch)
+	}
+	if facts.PullRequest > 0 {
+		fmt.Fprintf(&sb, "- **Pull request:** #%d %s\n", facts.PullRequest, facts.PullRequestURL)
+	}
+	if facts.ChecksState != "" {
+		fmt.Fprintf(&sb, "- **Last checks:** %s for %.12s; they may have changed since, run wait_for_checks again\n", facts.ChecksState, facts.ChecksSHA)
+	}
+	if review := facts.Review; review != nil {
+		line := fmt.Sprintf("- **Last review:** %d unresolved threads", review.Unresolved)
+		if len(review.Approved) > 0 {
+			line += ", approved by " + strings.Join(review.Approved, ", ")
+		}
+		if len(review.ChangesRequested) > 0 {
+			line += ", changes requested by " + strings.Join(review.ChangesRequested, ", ")
+		}
+		sb.WriteString(line + "; run get_review_summary again\n")
+	}
+
+	sb.WriteString("\n" + w.Workflow.Markdown())
+	return sb.String()
+}
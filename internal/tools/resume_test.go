@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/store"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// persistentTools returns a tool manager whose workflows are saved in the state file
+// at path, as if the server had just started
+func persistentTools(t *testing.T, path string) *ToolManager {
+	t.Helper()
+
+	state, err := store.Open(path)
+	if err != nil {
+		t.Fatalf("store.Open returned error: %v", err)
+	}
+	workflows := workflow.NewTracker()
+	workflows.OnChange(func(p *workflow.Progress) {
+		if err := state.Save(p); err != nil {
+			t.Errorf("Save returned error: %v", err)
+		}
+	})
+	return NewToolManager(Dependencies{Workflows: workflows, Store: state})
+}
+
+func TestResumeWorkAfterRestart(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.Run(t, dir, "remote", "add", "origin", "https://github.com/octo/hello.git")
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	session := connect(t, persistentTools(t, path), dir)
+	var p workflow.Progress
+	callTool(t, session, "start_workflow", map[string]any{"issue": 42}, &p)
+	if p.Repository != "octo/hello" {
+		t.Errorf("Expected the repository to be read from origin, got %q", p.Repository)
+	}
+	callTool(t, session, "create_or_switch_branch", map[string]any{"name": "feature/42-add-login"}, nil)
+	callTool(t, session, "complete_workflow_step", map[string]any{"step": "1"}, nil)
+	callTool(t, session, "complete_workflow_step", map[string]any{"step": "2"}, nil)
+
+	// A new server only has what was saved
+	session = connect(t, persistentTools(t, path), dir)
+	result := callTool(t, session, "get_workflow_status", map[string]any{}, nil)
+	errorText(t, result)
+
+	var out ResumedWork
+	result = callTool(t, session, "resume_work", map[string]any{}, &out)
+	if out.Issue != 42 || out.Repository != "octo/hello" || out.CurrentBranch != "feature/42-add-login" {
+		t.Fatalf("Unexpected resumed work %+v", out)
+	}
+	if next := out.Workflow.Next(); next == nil || next.Number != 3 {
+		t.Errorf("Expected the workflow to continue at step 3, got %+v", next)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"# Resuming work on issue #42 in octo/hello", "- **Branch:** feature/42-add-login\n", "**Next:** 3."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	callTool(t, session, "get_workflow_status", map[string]any{}, &p)
+	if p.Issue != 42 || p.Facts.Branch != "feature/42-add-login" {
+		t.Errorf("Expected the resumed workflow to be active, got %+v", p)
+	}
+
+	gittest.Run(t, dir, "switch", "-q", "main")
+	result = callTool(t, session, "resume_work", map[string]any{"issue": 42}, &out)
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "but main is checked out") {
+		t.Errorf("Expected a hint to switch branches, got:\n%s", text)
+	}
+
+	result = callTool(t, session, "resume_work", map[string]any{"issue": 3}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "no saved work matches issue #3 in octo/hello") {
+		t.Errorf("Expected a not found error, got %q", text)
+	}
+}
+
+func TestResumeWorkWithoutStore(t *testing.T) {
+	session := connect(t, NewToolManager(Dependencies{}))
+	result := callTool(t, session, "resume_work", map[string]any{"issue": 42}, nil)
+	if text := errorText(t, result); text != ErrNoStore.Error() {
+		t.Errorf("Expected %q, got %q", ErrNoStore, text)
+	}
+}
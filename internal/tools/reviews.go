@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/reviews"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// ReviewThreadsInput is the input of the list_review_threads tool
+type ReviewThreadsInput struct {
+	Owner       string `json:"owner,omitempty" jsonschema:"repository owner (default: from the origin remote of the repository)"`
+	Repo        string `json:"repo,omitempty" jsonschema:"repository name (default: from the origin remote of the repository)"`
+	PullRequest int    `json:"pull_request" jsonschema:"pull request number"`
+	State       string `json:"state,omitempty" jsonschema:"threads to list: unresolved (default), resolved or all"`
+	Path        string `json:"path,omitempty" jsonschema:"directory inside the repository whose origin remote names the GitHub repository; must be within the client's roots"`
+}
+
+// ReviewThreads is the outcome of list_review_threads
+type ReviewThreads struct {
+	PullRequest int                   `json:"pull_request"`
+	State       string                `json:"state"`
+	Threads     []github.ReviewThread `json:"threads"`
+	Total       int                   `json:"total"`
+	Unresolved  int                   `json:"unresolved"`
+}
+
+// ReviewSummaryInput is the input of the get_review_summary tool
+type ReviewSummaryInput struct {
+	Owner       string `json:"owner,omitempty" jsonschema:"repository owner (default: from the origin remote of the repository)"`
+	Repo        string `json:"repo,omitempty" jsonschema:"repository name (default: from the origin remote of the repository)"`
+	PullRequest int    `json:"pull_request" jsonschema:"pull request number"`
+	Path        string `json:"path,omitempty" jsonschema:"directory inside the repository whose origin remote names the GitHub repository; must be within the client's roots"`
+}
+
+// ReplyToThreadInput is the input of the reply_to_review_thread tool
+type ReplyToThreadInput struct {
+	ThreadID string `json:"thread_id" jsonschema:"ID of the review thread, as listed by list_review_threads"`
+	Body     string `json:"body" jsonschema:"reply in Markdown"`
+}
+
+// ResolveThreadInput is the input of the resolve_review_thread tool
+type ResolveThreadInput struct {
+	ThreadID string `json:"thread_id" jsonschema:"ID of the review thread, as listed by list_review_threads"`
+	Reply    string `json:"reply,omitempty" jsonschema:"reply to post before resolving, such as how the comment was addressed"`
+}
+
+// ThreadReply is the outcome of reply_to_review_thread and resolve_review_thread
+type ThreadReply struct {
+	Thread *github.ReviewThread  `json:"thread,omitempty"`
+	Reply  *github.ReviewComment `json:"reply,omitempty"`
+}
+
+// listReviewThreadsTool lists the review threads of a pull request
+func (tm *ToolManager) listReviewThreadsTool() Tool {
+	return newTool("list_review_threads",
+		"List the review threads of a pull request with their resolved and outdated state, file and line anchors, diff hunks and comments. "+
+			"Lists unresolved threads unless state is resolved or all. Thread IDs are used to reply to and resolve threads.",
+		true,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ReviewThreadsInput]) (*mcp.CallToolResultFor[ReviewThreads], error) {
+			args := params.Arguments
+			state := strings.ToLower(strings.TrimSpace(args.State))
+			if state == "" {
+				state = reviews.StateUnresolved
+			}
+			if !reviews.ValidState(state) {
+				return nil, fmt.Errorf("state must be %s, %s or %s", reviews.StateUnresolved, reviews.StateResolved, reviews.StateAll)
+			}
+			owner, repo, err := tm.reviewedPullRequest(ctx, ss, args.Owner, args.Repo, args.Path, args.PullRequest)
+			if err != nil {
+				return nil, err
+			}
+
+			threads, err := tm.deps.GitHub.ListReviewThreads(ctx, owner, repo, args.PullRequest)
+			if err != nil {
+				return nil, err
+			}
+			out := ReviewThreads{PullRequest: args.PullRequest, State: state, Threads: reviews.Filter(threads, state), Total: len(threads)}
+			out.Unresolved = len(reviews.Filter(threads, reviews.StateUnresolved))
+
+			text := fmt.Sprintf("%d of %d review threads are unresolved.\n\n", out.Unresolved, out.Total) + reviews.Markdown(out.Threads)
+			return &mcp.CallToolResultFor[ReviewThreads]{
+				Content:           []mcp.Content{&mcp.TextContent{Text: text}},
+				StructuredContent: out,
+			}, nil
+		})
+}
+
+// getReviewSummaryTool reports which reviewers approved or requested changes and
+// whether the review is done
+func (tm *ToolManager) getReviewSummaryTool() Tool {
+	return newTool("get_review_summary",
+		"Summarize the review of a pull request: the review decision, who approved, requested changes, commented or has yet to review, "+
+			"and how many review threads are unresolved. done is true when no thread is unresolved, nobody requests changes "+
+			"and no required approval is missing.",
+		true,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ReviewSummaryInput]) (*mcp.CallToolResultFor[reviews.Summary], error) {
+			args := params.Arguments
+			owner, repo, err := tm.reviewedPullRequest(ctx, ss, args.Owner, args.Repo, args.Path, args.PullRequest)
+			if err != nil {
+				return nil, err
+			}
+
+			status, err := tm.deps.GitHub.GetReviewStatus(ctx, owner, repo, args.PullRequest)
+			if err != nil {
+				return nil, err
+			}
+			threads, err := tm.deps.GitHub.ListReviewThreads(ctx, owner, repo, args.PullRequest)
+			if err != nil {
+				return nil, err
+			}
+
+			summary := reviews.Summarize(status, threads)
+			// Only the review of the workflow's own pull request counts
+			if p, err := tm.deps.Workflows.Get(workflow.SessionID(ss), 0); err == nil && p.Facts.PullRequest == args.PullRequest &&
+				(p.Repository == "" || strings.EqualFold(p.Repository, owner+"/"+repo)) {
+				_ = tm.deps.Workflows.Update(p.Session, p.Issue, func(f *workflow.Facts) {
+					f.PullRequestURL = summary.URL
+					f.Review = &workflow.Review{
+						Unresolved:       summary.Unresolved,
+						Approved:         summary.Approved,
+						ChangesRequested: summary.ChangesRequested,
+						Done:             summary.Done,
+					}
+				})
+			}
+			return &mcp.CallToolResultFor[reviews.Summary]{
+				Content:           []mcp.Content{&mcp.TextContent{Text: summary.Text()}},
+				StructuredContent: summary,
+			}, nil
+		})
+}
+
+// replyToReviewThreadTool replies to a review thread
+func (tm *ToolManager) replyToReviewThreadTool() Tool {
+	return newTool("reply_to_review_thread",
+		"Reply to a review thread of a pull request, for example to answer a question or explain how a comment was addressed.",
+		false,
+		func(ctx context.Context, _ *mcp.ServerSession, params *mcp.CallToolParamsFor[ReplyToThreadInput]) (*mcp.CallToolResultFor[ThreadReply], error) {
+			args := params.Arguments
+			if strings.TrimSpace(args.ThreadID) == "" || strings.TrimSpace(args.Body) == "" {
+				return nil, errors.New("thread_id and body are required")
+			}
+			if tm.deps.GitHub == nil {
+				return nil, ErrNoGitHub
+			}
+
+			reply, err := tm.deps.GitHub.ReplyToReviewThread(ctx, strings.TrimSpace(args.ThreadID), args.Body)
+			if err != nil {
+				return nil, err
+			}
+			return structuredResult(ThreadReply{Reply: reply})
+		})
+}
+
+// resolveReviewThreadTool marks a review thread as resolved, optionally replying first
+func (tm *ToolManager) resolveReviewThreadTool() Tool {
+	return newTool("resolve_review_thread",
+		"Mark a review thread of a pull request as resolved once its comments have been addressed, optionally replying first.",
+		false,
+		func(ctx context.Context, _ *mcp.ServerSession, params *mcp.CallToolParamsFor[ResolveThreadInput]) (*mcp.CallToolResultFor[ThreadReply], error) {
+			args := params.Arguments
+			threadID := strings.TrimSpace(args.ThreadID)
+			if threadID == "" {
+				return nil, errors.New("thread_id is required")
+			}
+			if tm.deps.GitHub == nil {
+				return nil, ErrNoGitHub
+			}
+
+			var out ThreadReply
+			if strings.TrimSpace(args.Reply) != "" {
+				reply, err := tm.deps.GitHub.ReplyToReviewThread(ctx, threadID, args.Reply)
+				if err != nil {
+					return nil, err
+				}
+				out.Reply = reply
+			}
+			thread, err := tm.deps.GitHub.ResolveReviewThread(ctx, threadID)
+			if err != nil {
+				return nil, err
+			}
+			out.Thread = thread
+			return structuredResult(out)
+		})
+}
+
+// reviewedPullRequest validates the pull request of a review tool and resolves its repository
+func (tm *ToolManager) reviewedPullRequest(ctx context.Context, ss *mcp.ServerSession, owner, repo, path string, number int) (string, string, error) {
+	if number <= 0 {
+		return "", "", errors.New("pull_request must be a positive pull request number")
+	}
+	if tm.deps.GitHub == nil {
+		return "", "", ErrNoGitHub
+	}
+	return tm.githubRepository(ctx, ss, owner, repo, path)
+}
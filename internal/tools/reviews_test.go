@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/reviews"
+)
+
+// reviewThread is a review thread as returned by GraphQL
+func reviewThread(id, path string, line int, resolved bool) map[string]any {
+	return map[string]any{
+		"id": id, "path": path, "line": line, "originalLine": line, "isResolved": resolved, "isOutdated": false, "diffSide": "RIGHT",
+		"comments": map[string]any{"totalCount": 1, "nodes": []map[string]any{
+			{"id": "C-" + id, "databaseId": 1, "author": map[string]any{"login": "alice"}, "body": "Please fix " + path, "diffHunk": "@@ -1 +1 @@"},
+		}},
+	}
+}
+
+// serveReviews serves the reviews of pull request 9 of octo/hello over two pages of
+// threads and records the mutations it receives
+func serveReviews(t *testing.T, s *githubtest.Server) *[]string {
+	var mu sync.Mutex
+	var mutations []string
+	s.GraphQL(t, func(query string, variables map[string]any) any {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.Contains(query, "addPullRequestReviewThreadReply"):
+			mutations = append(mutations, "reply "+variables["thread"].(string)+": "+variables["body"].(string))
+			return map[string]any{"addPullRequestReviewThreadReply": map[string]any{"comment": map[string]any{
+				"id": "C-reply", "author": map[string]any{"login": "agent"}, "body": variables["body"],
+			}}}
+		case strings.Contains(query, "resolveReviewThread"):
+			mutations = append(mutations, "resolve "+variables["thread"].(string))
+			return map[string]any{"resolveReviewThread": map[string]any{"thread": reviewThread(variables["thread"].(string), "upload.go", 42, true)}}
+		case strings.Contains(query, "reviewDecision"):
+			return map[string]any{"repository": map[string]any{"pullRequest": map[string]any{
+				"url":            "https://github.com/octo/hello/pull/9",
+				"reviewDecision": "CHANGES_REQUESTED",
+				"latestOpinionatedReviews": map[string]any{"nodes": []map[string]any{
+					{"author": map[string]any{"login": "alice"}, "state": "CHANGES_REQUESTED"},
+					{"author": map[string]any{"login": "bob"}, "state": "APPROVED"},
+				}},
+				"latestReviews": map[string]any{"nodes": []map[string]any{}},
+				"reviewRequests": map[string]any{"nodes": []map[string]any{
+					{"requestedReviewer": map[string]any{"slug": "reviewers", "organization": map[string]any{"login": "octo"}}},
+				}},
+			}}}
+		}
+
+		if variables["owner"] != "octo" || variables["repo"] != "hello" || variables["number"] != float64(9) {
+			t.Errorf("Unexpected variables %v", variables)
+		}
+		page := map[string]any{"pageInfo": map[string]any{"hasNextPage": true, "endCursor": "page2"},
+			"nodes": []map[string]any{reviewThread("T1", "main.go", 10, true), reviewThread("T2", "upload.go", 42, false)}}
+		if variables["cursor"] == "page2" {
+			page = map[string]any{"pageInfo": map[string]any{"hasNextPage": false},
+				"nodes": []map[string]any{reviewThread("T3", "parse.go", 7, false)}}
+		}
+		return map[string]any{"repository": map[string]any{"pullRequest": map[string]any{"reviewThreads": page}}}
+	})
+	return &mutations
+}
+
+func TestListReviewThreads(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveReviews(t, s)
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}))
+
+	var out ReviewThreads
+	result := callTool(t, session, "list_review_threads", map[string]any{"owner": "octo", "repo": "hello", "pull_request": 9}, &out)
+	if out.Total != 3 || out.Unresolved != 2 || len(out.Threads) != 2 || out.Threads[0].ID != "T2" || out.Threads[1].ID != "T3" {
+		t.Fatalf("Unexpected threads %+v", out)
+	}
+	if thread := out.Threads[0]; thread.Line != 42 || thread.DiffHunk != "@@ -1 +1 @@" || thread.Comments[0].Author != "alice" {
+		t.Errorf("Unexpected thread %+v", thread)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.HasPrefix(text, "2 of 3 review threads are unresolved.") || !strings.Contains(text, "## upload.go:42 (unresolved)") {
+		t.Errorf("Unexpected text:\n%s", text)
+	}
+
+	callTool(t, session, "list_review_threads", map[string]any{"owner": "octo", "repo": "hello", "pull_request": 9, "state": "all"}, &out)
+	if len(out.Threads) != 3 {
+		t.Errorf("Expected every thread, got %+v", out.Threads)
+	}
+
+	result = callTool(t, session, "list_review_threads", map[string]any{"owner": "octo", "repo": "hello", "pull_request": 9, "state": "open"}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "state must be") {
+		t.Errorf("Expected an error for an unknown state, got %q", text)
+	}
+}
+
+func TestResolveReviewThread(t *testing.T) {
+	s := githubtest.NewServer(t)
+	mutations := serveReviews(t, s)
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}))
+
+	var out ThreadReply
+	callTool(t, session, "resolve_review_thread", map[string]any{"thread_id": "T2", "reply": "Now retries three times."}, &out)
+	if out.Thread == nil || !out.Thread.Resolved || out.Reply == nil || out.Reply.Author != "agent" {
+		t.Errorf("Unexpected result %+v", out)
+	}
+
+	callTool(t, session, "reply_to_review_thread", map[string]any{"thread_id": "T3", "body": "Done in the next commit."}, &out)
+	want := []string{"reply T2: Now retries three times.", "resolve T2", "reply T3: Done in the next commit."}
+	if strings.Join(*mutations, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected mutations %q, got %q", want, *mutations)
+	}
+
+	result := callTool(t, session, "reply_to_review_thread", map[string]any{"thread_id": "T3", "body": " "}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "required") {
+		t.Errorf("Expected an error for an empty reply, got %q", text)
+	}
+}
+
+func TestGetReviewSummary(t *testing.T) {
+	s := githubtest.NewServer(t)
+	serveReviews(t, s)
+	session := connect(t, NewToolManager(Dependencies{GitHub: s.Client(t)}))
+
+	var out reviews.Summary
+	result := callTool(t, session, "get_review_summary", map[string]any{"owner": "octo", "repo": "hello", "pull_request": 9}, &out)
+	if out.Done || out.Unresolved != 2 || out.Approved[0] != "bob" || out.ChangesRequested[0] != "alice" || out.Requested[0] != "octo/reviewers" {
+		t.Errorf("Unexpected summary %+v", out)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "- Changes requested: alice") {
+		t.Errorf("Unexpected text:\n%s", text)
+	}
+}
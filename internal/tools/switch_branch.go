@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/branches"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// defaultRemote is the remote branches are fetched from and tracked against
+const defaultRemote = "origin"
+
+// Actions reported by create_or_switch_branch
+const (
+	BranchCreated   = "created"
+	BranchSwitched  = "switched"
+	BranchTracked   = "tracked"
+	BranchUnchanged = "unchanged"
+)
+
+// CreateOrSwitchBranchInput is the input of the create_or_switch_branch tool
+type CreateOrSwitchBranchInput struct {
+	Name        string `json:"name" jsonschema:"branch to switch to or create, e.g. feature/42-add-login"`
+	Base        string `json:"base,omitempty" jsonschema:"branch a new branch is created from (default: the policy's default branch)"`
+	FastForward bool   `json:"fast_forward,omitempty" jsonschema:"fetch the base branch from origin and fast-forward it before creating the new branch"`
+	Path        string `json:"path,omitempty" jsonschema:"directory inside the repository; must be within the client's roots. Defaults to the first root inside a repository"`
+}
+
+// BranchSwitch reports what create_or_switch_branch did
+type BranchSwitch struct {
+	Branch         string   `json:"branch"`
+	Action         string   `json:"action"`
+	PreviousBranch string   `json:"previous_branch,omitempty"`
+	Base           string   `json:"base,omitempty"`
+	StartPoint     string   `json:"start_point,omitempty"`
+	FastForwarded  bool     `json:"fast_forwarded"`
+	Steps          []string `json:"steps"`
+}
+
+// createOrSwitchBranchTool moves the agent onto a conventional work branch
+func (tm *ToolManager) createOrSwitchBranchTool() Tool {
+	return newTool("create_or_switch_branch",
+		"Switch to a work branch, creating it from the base branch if it does not exist locally or on origin. "+
+			"Refuses protected branches such as main and master and names that break the branch conventions. "+
+			"Optionally fast-forwards the base branch from origin first.",
+		false,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CreateOrSwitchBranchInput]) (*mcp.CallToolResultFor[BranchSwitch], error) {
+			args := params.Arguments
+			pol := tm.deps.Policy(ss)
+
+			name := strings.TrimSpace(args.Name)
+			if err := checkWorkBranch(name, pol); err != nil {
+				return nil, err
+			}
+
+			repo, err := tm.openRepository(ctx, ss, args.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			out, err := switchBranch(ctx, repo, pol, name, args)
+			if err != nil {
+				return nil, stillOnBranchError(ctx, repo, pol, err)
+			}
+			tm.recordFacts(ss, func(f *workflow.Facts) { f.Branch = out.Branch })
+			return structuredResult(*out)
+		})
+}
+
+// checkWorkBranch refuses protected branches and names that break the branch conventions
+func checkWorkBranch(name string, pol *policy.Policy) error {
+	if pol.IsProtectedBranch(name) {
+		return fmt.Errorf("refusing to work on protected branch %q; create a feature branch instead", name)
+	}
+
+	result := branches.Validate(name, pol, branches.Options{})
+	if result.Valid {
+		return nil
+	}
+	messages := make([]string, 0, len(result.Violations))
+	for _, v := range result.Violations {
+		messages = append(messages, v.Message)
+	}
+	err := fmt.Sprintf("invalid branch name %q: %s", name, strings.Join(messages, "; "))
+	if result.SuggestedName != "" {
+		err += fmt.Sprintf(" (suggested: %s)", result.SuggestedName)
+	}
+	return errors.New(err)
+}
+
+// switchBranch switches to name, tracking it from origin or creating it from the base if needed
+func switchBranch(ctx context.Context, repo *git.Repository, pol *policy.Policy, name string, args CreateOrSwitchBranchInput) (*BranchSwitch, error) {
+	previous, err := repo.CurrentBranch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &BranchSwitch{Branch: name, PreviousBranch: previous, Steps: []string{}}
+
+	switch {
+	case previous == name:
+		out.Action = BranchUnchanged
+		out.Steps = append(out.Steps, fmt.Sprintf("already on %s", name))
+		return out, nil
+	case repo.BranchExists(ctx, name):
+		if err := repo.Switch(ctx, name); err != nil {
+			return nil, err
+		}
+		out.Action = BranchSwitched
+		out.Steps = append(out.Steps, fmt.Sprintf("switched to existing branch %s", name))
+		return out, nil
+	case repo.RemoteBranchExists(ctx, defaultRemote, name):
+		if err := repo.TrackBranch(ctx, defaultRemote, name); err != nil {
+			return nil, err
+		}
+		out.Action = BranchTracked
+		out.Steps = append(out.Steps, fmt.Sprintf("created %s tracking %s/%s", name, defaultRemote, name))
+		return out, nil
+	}
+
+	base := strings.TrimSpace(args.Base)
+	if base == "" {
+		base = pol.DefaultBranch
+	}
+	if err := repo.CheckBranchName(ctx, base); err != nil {
+		return nil, fmt.Errorf("base branch: %w", err)
+	}
+	out.Base = base
+
+	if args.FastForward {
+		if repo.BranchExists(ctx, base) {
+			moved, err := repo.FastForward(ctx, defaultRemote, base)
+			if err != nil {
+				return nil, fmt.Errorf("could not fast-forward %s from %s: %w", base, defaultRemote, err)
+			}
+			out.FastForwarded = moved
+			if moved {
+				out.Steps = append(out.Steps, fmt.Sprintf("fast-forwarded %s from %s", base, defaultRemote))
+			} else {
+				out.Steps = append(out.Steps, fmt.Sprintf("%s is up to date with %s", base, defaultRemote))
+			}
+		} else {
+			if err := repo.Fetch(ctx, defaultRemote, base); err != nil {
+				return nil, fmt.Errorf("could not fetch %s from %s: %w", base, defaultRemote, err)
+			}
+			out.Steps = append(out.Steps, fmt.Sprintf("fetched %s from %s", base, defaultRemote))
+		}
+	}
+
+	// Full refs, so that a tag named like the base cannot stand in for it
+	startPoint, ref := base, "refs/heads/"+base
+	if !repo.BranchExists(ctx, base) {
+		if !repo.RemoteBranchExists(ctx, defaultRemote, base) {
+			return nil, fmt.Errorf("base branch %q does not exist locally or on %s", base, defaultRemote)
+		}
+		startPoint, ref = defaultRemote+"/"+base, "refs/remotes/"+defaultRemote+"/"+base
+	}
+	if out.StartPoint, err = repo.RevParse(ctx, ref); err != nil {
+		return nil, err
+	}
+
+	if err := repo.CreateBranch(ctx, name, out.StartPoint); err != nil {
+		return nil, err
+	}
+	out.Action = BranchCreated
+	out.Steps = append(out.Steps, fmt.Sprintf("created %s from %s at %.12s", name, startPoint, out.StartPoint))
+	return out, nil
+}
+
+// stillOnBranchError adds the branch the repository was left on to err, warning when
+// it is protected
+func stillOnBranchError(ctx context.Context, repo *git.Repository, pol *policy.Policy, err error) error {
+	current, branchErr := repo.CurrentBranch(ctx)
+	if branchErr != nil || current == "" {
+		return err
+	}
+	if pol.IsProtectedBranch(current) {
+		return fmt.Errorf("%w; still on protected branch %q: do not commit here", err, current)
+	}
+	return fmt.Errorf("%w; still on branch %q", err, current)
+}
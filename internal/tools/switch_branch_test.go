@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/policy"
+)
+
+// errorText returns the text of an error result
+func errorText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if !result.IsError {
+		t.Fatalf("Expected error result, got %+v", result.StructuredContent)
+	}
+	return result.Content[0].(*mcp.TextContent).Text
+}
+
+func TestCreateOrSwitchBranchCreatesFromBase(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	var out BranchSwitch
+	callTool(t, session, "create_or_switch_branch", map[string]any{"name": "feature/42-add-login"}, &out)
+
+	if out.Action != BranchCreated || out.Base != "main" || out.PreviousBranch != "main" || out.StartPoint == "" {
+		t.Errorf("Unexpected result %+v", out)
+	}
+	if branch := gittest.Run(t, dir, "branch", "--show-current"); branch != "feature/42-add-login" {
+		t.Errorf("Expected new branch to be checked out, got %q", branch)
+	}
+
+	callTool(t, session, "create_or_switch_branch", map[string]any{"name": "feature/42-add-login"}, &out)
+	if out.Action != BranchUnchanged {
+		t.Errorf("Expected no-op on the current branch, got %+v", out)
+	}
+
+	gittest.Run(t, dir, "switch", "-q", "main")
+	callTool(t, session, "create_or_switch_branch", map[string]any{"name": "feature/42-add-login"}, &out)
+	if out.Action != BranchSwitched {
+		t.Errorf("Expected switch to existing branch, got %+v", out)
+	}
+}
+
+func TestCreateOrSwitchBranchIgnoresTagsNamedLikeTheBase(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.Run(t, dir, "tag", "main")
+	gittest.Run(t, dir, "commit", "-q", "--allow-empty", "-m", "feat: after the tag")
+	head := gittest.Run(t, dir, "rev-parse", "refs/heads/main")
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	var out BranchSwitch
+	callTool(t, session, "create_or_switch_branch", map[string]any{"name": "feature/43-add-logout"}, &out)
+	if out.StartPoint != head {
+		t.Errorf("Expected start point %s of the main branch, got %+v", head, out)
+	}
+	if sha := gittest.Run(t, dir, "rev-parse", "HEAD"); sha != head {
+		t.Errorf("Expected the new branch at %s, got %s", head, sha)
+	}
+}
+
+func TestCreateOrSwitchBranchFastForwardsBase(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	remote := gittest.NewRemote(t, dir)
+	other := gittest.Clone(t, remote)
+	gittest.Run(t, other, "commit", "-q", "--allow-empty", "-m", "feat: upstream change")
+	gittest.Run(t, other, "push", "-q", "origin", "main")
+	upstream := gittest.Run(t, other, "rev-parse", "HEAD")
+
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	var out BranchSwitch
+	callTool(t, session, "create_or_switch_branch", map[string]any{"name": "bugfix/7-crash", "fast_forward": true}, &out)
+
+	if !out.FastForwarded || out.StartPoint != upstream {
+		t.Errorf("Expected branch from fast-forwarded main %s, got %+v", upstream, out)
+	}
+}
+
+func TestCreateOrSwitchBranchTracksRemote(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	remote := gittest.NewRemote(t, dir)
+	other := gittest.Clone(t, remote)
+	gittest.Run(t, other, "switch", "-q", "-c", "feature/9-shared")
+	gittest.Run(t, other, "push", "-q", "origin", "feature/9-shared")
+	gittest.Run(t, dir, "fetch", "-q", "origin")
+
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	var out BranchSwitch
+	callTool(t, session, "create_or_switch_branch", map[string]any{"name": "feature/9-shared"}, &out)
+	if out.Action != BranchTracked {
+		t.Errorf("Expected remote branch to be tracked, got %+v", out)
+	}
+}
+
+func TestCreateOrSwitchBranchRefusals(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	p := policy.Default()
+	p.Branches.Protected = append(p.Branches.Protected, "release")
+	p.Branches.Prefixes = append(p.Branches.Prefixes, policy.Convention{Name: "release"})
+	session := connect(t, NewToolManager(Dependencies{Policy: func(*mcp.ServerSession) *policy.Policy { return p }}), dir)
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"protected default", map[string]any{"name": "main"}, "protected branch"},
+		{"protected from policy", map[string]any{"name": "release"}, "protected branch"},
+		{"invalid name", map[string]any{"name": "Add Login"}, "suggested: feature/add-login"},
+		{"missing base", map[string]any{"name": "feature/1-x", "base": "develop"}, `still on protected branch "main"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := errorText(t, callTool(t, session, "create_or_switch_branch", tt.args, nil))
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("Expected error containing %q, got %q", tt.want, text)
+			}
+		})
+	}
+
+	if branch := gittest.Run(t, dir, "branch", "--show-current"); branch != "main" {
+		t.Errorf("Expected refusals to leave the branch unchanged, got %q", branch)
+	}
+}
+
+func TestCreateOrSwitchBranchRejectsOptionAsBase(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.NewRemote(t, dir)
+	session := connect(t, NewToolManager(Dependencies{}), dir)
+
+	pwned := filepath.Join(t.TempDir(), "pwned")
+	args := map[string]any{"name": "feature/1-x", "base": "--upload-pack=touch " + pwned, "fast_forward": true}
+	text := errorText(t, callTool(t, session, "create_or_switch_branch", args, nil))
+	if !strings.Contains(text, "invalid branch name") {
+		t.Errorf("Expected the base to be rejected, got %q", text)
+	}
+	if _, err := os.Stat(pwned); err == nil {
+		t.Error("Expected the upload-pack command not to run")
+	}
+}
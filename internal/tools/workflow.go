@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/auth"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+// StartWorkflowInput is the input of the start_workflow tool
+type StartWorkflowInput struct {
+	Issue   int    `json:"issue,omitempty" jsonschema:"issue the work is for; omit for work without an issue"`
+	Restart bool   `json:"restart,omitempty" jsonschema:"start over even if the workflow for the issue has already been started"`
+	Owner   string `json:"owner,omitempty" jsonschema:"repository owner (default: from the origin remote of the repository)"`
+	Repo    string `json:"repo,omitempty" jsonschema:"repository name (default: from the origin remote of the repository)"`
+	Path    string `json:"path,omitempty" jsonschema:"directory inside the repository; must be within the client's roots. Defaults to the first root inside a repository"`
+}
+
+// WorkflowStatusInput is the input of the get_workflow_status tool
+type WorkflowStatusInput struct {
+	Issue int `json:"issue,omitempty" jsonschema:"issue whose workflow to show (default: the workflow started last)"`
+}
+
+// CompleteStepInput is the input of the complete_workflow_step tool
+type CompleteStepInput struct {
+	Step  string `json:"step" jsonschema:"ID or number of the step, such as commit or 8"`
+	Note  string `json:"note,omitempty" jsonschema:"what was done, kept with the step"`
+	Issue int    `json:"issue,omitempty" jsonschema:"issue whose workflow the step belongs to (default: the workflow started last)"`
+}
+
+// startWorkflowTool starts tracking the development workflow for an issue
+func (tm *ToolManager) startWorkflowTool() Tool {
+	return newTool("start_workflow",
+		"Start tracking the 14-step development workflow for an issue in this session, or return it if already started. "+
+			"Steps are completed in order with complete_workflow_step; CI, pull request and review steps are only accepted "+
+			"once wait_for_checks, create_pull_request and get_review_summary have shown them to be done. "+
+			"The progress is saved so that resume_work can pick it up after a restart.",
+		false,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[StartWorkflowInput]) (*mcp.CallToolResultFor[workflow.Progress], error) {
+			args := params.Arguments
+			if args.Issue < 0 {
+				return nil, errors.New("issue must be a positive issue number")
+			}
+			repository, err := tm.workRepository(ctx, ss, args.Owner, args.Repo, args.Path)
+			if err != nil {
+				return nil, err
+			}
+			p := tm.deps.Workflows.Start(workflow.SessionID(ss), workOwner(ctx), repository, args.Issue, args.Restart)
+			return progressResult(p)
+		})
+}
+
+// getWorkflowStatusTool shows the progress through the development workflow
+func (tm *ToolManager) getWorkflowStatusTool() Tool {
+	return newTool("get_workflow_status",
+		"Show the progress through the development workflow: which steps are done, the next step with guidance and "+
+			"what blocks it, and the facts observed so far (branch, checks, pull request and review).",
+		true,
+		func(_ context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[WorkflowStatusInput]) (*mcp.CallToolResultFor[workflow.Progress], error) {
+			p, err := tm.deps.Workflows.Get(workflow.SessionID(ss), params.Arguments.Issue)
+			if err != nil {
+				return nil, err
+			}
+			return progressResult(p)
+		})
+}
+
+// completeWorkflowStepTool completes a step of the development workflow
+func (tm *ToolManager) completeWorkflowStepTool() Tool {
+	return newTool("complete_workflow_step",
+		"Mark a step of the development workflow as done. Steps out of order, and CI, pull request and review steps whose "+
+			"conditions have not been observed, are rejected with an explanation of what to do first.",
+		false,
+		func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CompleteStepInput]) (*mcp.CallToolResultFor[workflow.Progress], error) {
+			args := params.Arguments
+			step, ok := workflow.FindStep(args.Step)
+			if !ok {
+				return nil, fmt.Errorf("unknown workflow step %q; steps are numbered 1 to %d", args.Step, len(workflow.Steps))
+			}
+			session := workflow.SessionID(ss)
+			current, err := tm.deps.Workflows.Get(session, args.Issue)
+			if err != nil {
+				return nil, err
+			}
+			// The guards compare the checks with where the branch is now
+			if _, head := tm.workHead(ctx, ss, "", current.Facts); head != "" {
+				_ = tm.deps.Workflows.Update(session, current.Issue, func(f *workflow.Facts) { f.Head = head })
+			}
+			p, err := tm.deps.Workflows.Complete(session, current.Issue, step.ID, args.Note)
+			if err != nil {
+				return nil, err
+			}
+			return progressResult(p)
+		})
+}
+
+// progressResult returns the progress as Markdown and structured content
+func progressResult(p *workflow.Progress) (*mcp.CallToolResultFor[workflow.Progress], error) {
+	return &mcp.CallToolResultFor[workflow.Progress]{
+		Content:           []mcp.Content{&mcp.TextContent{Text: p.Markdown()}},
+		StructuredContent: *p,
+	}, nil
+}
+
+// workRepository names the repository the work is in as owner/repo. Without owner
+// and repo it is read from the origin remote and left empty when there is none.
+func (tm *ToolManager) workRepository(ctx context.Context, ss *mcp.ServerSession, owner, repo, path string) (string, error) {
+	resolvedOwner, resolvedRepo, err := tm.githubRepository(ctx, ss, owner, repo, path)
+	switch {
+	case err == nil:
+		return resolvedOwner + "/" + resolvedRepo, nil
+	case strings.TrimSpace(owner) != "" || strings.TrimSpace(repo) != "":
+		return "", err
+	}
+	return "", nil
+}
+
+// workOwner identifies the user work done with ctx belongs to: the authenticated
+// principal, or nobody for sessions that did not authenticate
+func workOwner(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return p.ID()
+	}
+	return ""
+}
+
+// recordFacts updates the facts of the session's active workflow
+func (tm *ToolManager) recordFacts(ss *mcp.ServerSession, update func(*workflow.Facts)) {
+	tm.deps.Workflows.Record(workflow.SessionID(ss), update)
+}
+
+// workHead returns the branch a workflow works on and the commit it points at in the
+// local repository at path. Without a recorded branch the current branch is the work
+// branch unless it is protected. The commit is empty when it cannot be resolved.
+func (tm *ToolManager) workHead(ctx context.Context, ss *mcp.ServerSession, path string, facts workflow.Facts) (string, string) {
+	local, err := tm.openRepository(ctx, ss, path)
+	if err != nil {
+		return facts.Branch, ""
+	}
+	branch := facts.Branch
+	if branch == "" {
+		current, err := local.CurrentBranch(ctx)
+		if err != nil || current == "" || tm.deps.Policy(ss).IsProtectedBranch(current) {
+			return "", ""
+		}
+		branch = current
+	}
+	head, err := local.RevParse(ctx, "refs/heads/"+branch)
+	if err != nil {
+		return branch, ""
+	}
+	return branch, head
+}
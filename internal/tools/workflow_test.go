@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/git/gittest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/github/githubtest"
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/workflow"
+)
+
+func TestWorkflowTools(t *testing.T) {
+	dir := gittest.NewRepository(t)
+	gittest.Run(t, dir, "switch", "-q", "-c", "feature/42-login")
+	head := gittest.Run(t, dir, "rev-parse", "HEAD")
+	s := githubtest.NewServer(t)
+	serveCheckRunsAt(s, head, [][2]string{{"build", "success"}, {"lint", "failure"}})
+	session := connect(t, fastChecks(NewToolManager(Dependencies{GitHub: s.Client(t)})), dir)
+
+	result := callTool(t, session, "get_workflow_status", map[string]any{}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "start_workflow") {
+		t.Errorf("Expected a hint to start the workflow, got %q", text)
+	}
+
+	var p workflow.Progress
+	callTool(t, session, "start_workflow", map[string]any{"issue": 42}, &p)
+	if p.Issue != 42 || len(p.Steps) != 14 {
+		t.Fatalf("Unexpected progress %+v", p)
+	}
+
+	result = callTool(t, session, "complete_workflow_step", map[string]any{"step": "create_pull_request"}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "step 1 (check_repository) comes first") {
+		t.Errorf("Expected the step to be rejected as out of order, got %q", text)
+	}
+	for number := 1; number <= 8; number++ {
+		callTool(t, session, "complete_workflow_step", map[string]any{"step": strconv.Itoa(number)}, &p)
+	}
+
+	result = callTool(t, session, "complete_workflow_step", map[string]any{"step": "push_and_monitor"}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "no checks have been observed") {
+		t.Errorf("Expected the step to wait for checks, got %q", text)
+	}
+
+	// Checks of a pull request the workflow did not open are not its checks
+	callTool(t, session, "wait_for_checks", map[string]any{"owner": "octo", "repo": "hello", "pull_request": 9}, nil)
+	result = callTool(t, session, "complete_workflow_step", map[string]any{"step": "push_and_monitor"}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "no checks have been observed") {
+		t.Errorf("Expected checks of another pull request to be ignored, got %q", text)
+	}
+
+	// wait_for_checks records the failed checks of the branch head in the workflow
+	callTool(t, session, "wait_for_checks", map[string]any{"owner": "octo", "repo": "hello"}, nil)
+	callTool(t, session, "complete_workflow_step", map[string]any{"step": "push_and_monitor", "note": "pushed"}, &p)
+	result = callTool(t, session, "complete_workflow_step", map[string]any{"step": "fix_ci"}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "ended in failure") {
+		t.Errorf("Expected fixing CI to need passing checks, got %q", text)
+	}
+
+	result = callTool(t, session, "get_workflow_status", map[string]any{"issue": 42}, &p)
+	if p.Facts.ChecksState != workflow.ChecksFailure || p.Facts.ChecksSHA != head || p.Facts.Branch != "feature/42-login" {
+		t.Errorf("Expected the checks to be recorded, got %+v", p.Facts)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "**Next:** 10. Fix and re-push if CI fails") || !strings.Contains(text, "get_ci_failure_logs") {
+		t.Errorf("Unexpected status:\n%s", text)
+	}
+
+	// Checks of an earlier commit say nothing about the branch head
+	gittest.Run(t, dir, "commit", "-q", "--allow-empty", "-m", "fix: lint")
+	result = callTool(t, session, "complete_workflow_step", map[string]any{"step": "fix_ci"}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "the branch is now at") {
+		t.Errorf("Expected checks of an earlier commit to be stale, got %q", text)
+	}
+
+	result = callTool(t, session, "complete_workflow_step", map[string]any{"step": "deploy"}, nil)
+	if text := errorText(t, result); !strings.Contains(text, "unknown workflow step") {
+		t.Errorf("Expected an unknown step error, got %q", text)
+	}
+}
@@ -0,0 +1,258 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Statuses of a step
+const (
+	StatusPending = "pending"
+	StatusDone    = "done"
+	StatusSkipped = "skipped"
+)
+
+// Check states recorded in Facts, as reported by wait_for_checks
+const (
+	ChecksSuccess = "success"
+	ChecksFailure = "failure"
+	ChecksPending = "pending"
+	// ChecksNone means the repository reports no checks, which does not hold up the workflow
+	ChecksNone = "none"
+)
+
+// Facts are what the tools have observed about the work. Guards decide on them
+// rather than on what the agent claims.
+type Facts struct {
+	Branch      string `json:"branch,omitempty"`
+	ChecksState string `json:"checks_state,omitempty"`
+	ChecksSHA   string `json:"checks_sha,omitempty"`
+	// Head is the commit the branch pointed at when it was last looked at
+	Head           string  `json:"head,omitempty"`
+	PullRequest    int     `json:"pull_request,omitempty"`
+	PullRequestURL string  `json:"pull_request_url,omitempty"`
+	Review         *Review `json:"review,omitempty"`
+}
+
+// Review is the state of the pull request's review when it was last summarized
+type Review struct {
+	Unresolved       int      `json:"unresolved"`
+	Approved         []string `json:"approved"`
+	ChangesRequested []string `json:"changes_requested"`
+	Done             bool     `json:"done"`
+}
+
+// checksGreen reports whether the last checks passed or the repository has none
+func (f Facts) checksGreen() bool {
+	return f.ChecksState == ChecksSuccess || f.ChecksState == ChecksNone
+}
+
+// checksStale reports whether the last checks ran on a commit other than the head
+// of the branch
+func (f Facts) checksStale() bool {
+	return f.ChecksSHA != "" && f.Head != "" && f.ChecksSHA != f.Head
+}
+
+// StepState is a step and how far it has got
+type StepState struct {
+	Step
+	Status      string     `json:"status"`
+	Note        string     `json:"note,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Progress is the state of the workflow for an issue in a session. Repository is
+// the GitHub repository the issue belongs to, as owner/repo, when it is known.
+// Owner identifies the authenticated user the work belongs to; it is empty for
+// sessions that did not authenticate, such as stdio sessions.
+type Progress struct {
+	Session    string      `json:"session"`
+	Owner      string      `json:"owner,omitempty"`
+	Repository string      `json:"repository,omitempty"`
+	Issue      int         `json:"issue,omitempty"`
+	Steps      []StepState `json:"steps"`
+	Facts      Facts       `json:"facts"`
+	StartedAt  time.Time   `json:"started_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// TransitionError explains why a step cannot be completed yet
+type TransitionError struct {
+	Step   Step
+	Reason string
+}
+
+// Error describes the rejected step and the reason
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot complete step %d (%s) yet: %s", e.Step.Number, e.Step.ID, e.Reason)
+}
+
+// newProgress starts the workflow with every step pending
+func newProgress(session, owner, repository string, issue int, now time.Time) *Progress {
+	p := &Progress{Session: session, Owner: owner, Repository: repository, Issue: issue, Steps: make([]StepState, len(Steps)), StartedAt: now, UpdatedAt: now}
+	for i, step := range Steps {
+		p.Steps[i] = StepState{Step: step, Status: StatusPending}
+	}
+	return p
+}
+
+// Next returns the first step that is still pending, or nil when the workflow is complete
+func (p *Progress) Next() *StepState {
+	for i := range p.Steps {
+		if p.Steps[i].Status == StatusPending {
+			return &p.Steps[i]
+		}
+	}
+	return nil
+}
+
+// Complete marks a step done after checking that the steps before it are done and
+// that its guard allows it. Completing a step twice changes nothing.
+func (p *Progress) Complete(id, note string, now time.Time) error {
+	i := slices.IndexFunc(p.Steps, func(s StepState) bool { return s.ID == id })
+	if i < 0 {
+		return fmt.Errorf("unknown workflow step %q", id)
+	}
+	state := &p.Steps[i]
+	if state.Status != StatusPending {
+		return nil
+	}
+	if reason := p.Unreached(id); reason != "" {
+		return &TransitionError{Step: state.Step, Reason: reason}
+	}
+	if reason := p.guard(state.ID); reason != "" {
+		return &TransitionError{Step: state.Step, Reason: reason}
+	}
+
+	p.mark(i, StatusDone, note, now)
+	// Passing checks leave nothing to fix
+	if state.ID == StepPushAndMonitor && p.Facts.checksGreen() {
+		p.mark(i+1, StatusSkipped, "checks passed", now)
+	}
+	return nil
+}
+
+// Unreached explains which earlier step has to be completed before step id, or
+// returns an empty string when every step before it is done
+func (p *Progress) Unreached(id string) string {
+	next := p.Next()
+	i := slices.IndexFunc(p.Steps, func(s StepState) bool { return s.ID == id })
+	if next == nil || i < 0 || next.Number >= p.Steps[i].Number {
+		return ""
+	}
+	return fmt.Sprintf("step %d (%s) comes first: %s. %s", next.Number, next.ID, next.Title, next.Guidance)
+}
+
+// mark sets the status of step i
+func (p *Progress) mark(i int, status, note string, now time.Time) {
+	p.Steps[i].Status, p.Steps[i].Note, p.Steps[i].CompletedAt = status, strings.TrimSpace(note), &now
+	p.UpdatedAt = now
+}
+
+// guard returns why step id cannot be completed on the recorded facts, or an empty string
+func (p *Progress) guard(id string) string {
+	f := p.Facts
+	switch id {
+	case StepPushAndMonitor, StepFixCI, StepCreatePullRequest, StepAddressReview, StepAwaitApproval, StepComplete:
+		if f.checksStale() {
+			return fmt.Sprintf("the last checks ran on %.12s but the branch is now at %.12s; push it and run wait_for_checks",
+				f.ChecksSHA, f.Head)
+		}
+	}
+	switch id {
+	case StepPushAndMonitor:
+		switch f.ChecksState {
+		case "":
+			return "no checks have been observed; push the branch and run wait_for_checks"
+		case ChecksPending:
+			return "the checks are still running; run wait_for_checks again until they finish"
+		}
+	case StepFixCI:
+		if !f.checksGreen() {
+			return fmt.Sprintf("the last checks of %.12s ended in %s; fix the failures shown by get_ci_failure_logs, "+
+				"push and run wait_for_checks until they pass", f.ChecksSHA, f.ChecksState)
+		}
+	case StepCreatePullRequest:
+		if !f.checksGreen() {
+			return "the checks have not passed; a pull request is only opened once they do"
+		}
+		if f.PullRequest == 0 {
+			return "no pull request has been opened; open it with create_pull_request"
+		}
+	case StepAddressReview:
+		switch {
+		case f.Review == nil:
+			return "the review has not been checked; run get_review_summary"
+		case f.Review.Unresolved > 0:
+			return fmt.Sprintf("%d review threads are unresolved; address them and resolve them with resolve_review_thread, "+
+				"then run get_review_summary again", f.Review.Unresolved)
+		case len(f.Review.ChangesRequested) > 0:
+			return "changes are requested by " + strings.Join(f.Review.ChangesRequested, ", ") + "; address them and ask for a new review"
+		case !f.checksGreen():
+			return "the checks of the latest push have not passed; run wait_for_checks"
+		}
+	case StepAwaitApproval, StepComplete:
+		switch {
+		case f.Review == nil || len(f.Review.Approved) == 0:
+			return "the pull request has not been approved; ask the user to review it and confirm with get_review_summary"
+		case !f.Review.Done:
+			return "the review is not done; run get_review_summary to see what is missing"
+		case !f.checksGreen():
+			return "the checks of the latest push have not passed; run wait_for_checks"
+		}
+	}
+	return ""
+}
+
+// clone returns a deep copy that can be handed out while p keeps changing
+func (p *Progress) clone() *Progress {
+	c := *p
+	c.Steps = slices.Clone(p.Steps)
+	if p.Facts.Review != nil {
+		review := *p.Facts.Review
+		review.Approved = slices.Clone(review.Approved)
+		review.ChangesRequested = slices.Clone(review.ChangesRequested)
+		c.Facts.Review = &review
+	}
+	return &c
+}
+
+// Markdown renders the progress as a checklist with the next step to take
+func (p *Progress) Markdown() string {
+	var sb strings.Builder
+	title := "Development workflow"
+	if p.Issue > 0 {
+		title += fmt.Sprintf(" for issue #%d", p.Issue)
+	}
+	if p.Repository != "" {
+		title += " in " + p.Repository
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+
+	for _, s := range p.Steps {
+		box := " "
+		if s.Status != StatusPending {
+			box = "x"
+		}
+		line := fmt.Sprintf("- [%s] %d. %s", box, s.Number, s.Title)
+		switch {
+		case s.Status == StatusSkipped:
+			line += " (skipped: " + s.Note + ")"
+		case s.Note != "":
+			line += " — " + s.Note
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	if next := p.Next(); next != nil {
+		fmt.Fprintf(&sb, "\n**Next:** %d. %s (`%s`). %s\n", next.Number, next.Title, next.ID, next.Guidance)
+		if reason := p.guard(next.ID); reason != "" {
+			fmt.Fprintf(&sb, "**Blocked:** %s\n", reason)
+		}
+	} else {
+		sb.WriteString("\n**The workflow is complete.**\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+// Package workflow tracks an agent's progress through the development workflow: the
+// 14 steps from checking the repository to the approved pull request, with guards
+// that keep steps in order and hold back the later ones until CI and review allow
+package workflow
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Step IDs of the development workflow
+const (
+	StepCheckRepository   = "check_repository"
+	StepHandleUncommitted = "handle_uncommitted"
+	StepLeaveProtected    = "leave_protected_branch"
+	StepChooseBranch      = "choose_branch"
+	StepSyncBranch        = "sync_branch"
+	StepImplement         = "implement"
+	StepRunTests          = "run_tests"
+	StepCommit            = "commit"
+	StepPushAndMonitor    = "push_and_monitor"
+	StepFixCI             = "fix_ci"
+	StepCreatePullRequest = "create_pull_request"
+	StepAddressReview     = "address_review"
+	StepAwaitApproval     = "await_approval"
+	StepComplete          = "complete"
+)
+
+// Step is a step of the development workflow
+type Step struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	// Guidance says how to carry out the step and what completes it
+	Guidance string `json:"guidance"`
+}
+
+// Steps are the steps of the development workflow in order
+var Steps = []Step{
+	{StepCheckRepository, 1, "Check Git repository status",
+		"Confirm the working directory is a Git repository with git_repository."},
+	{StepHandleUncommitted, 2, "Handle uncommitted changes",
+		"Inspect git_status and have the user commit or stash uncommitted changes."},
+	{StepLeaveProtected, 3, "Ensure not on a protected branch",
+		"Never work on a protected branch; plan to switch to a work branch."},
+	{StepChooseBranch, 4, "Ask the user for the branch preference",
+		"Continue on the current branch, switch to an existing one or create a new one, as the user prefers."},
+	{StepSyncBranch, 5, "Pull latest and create or switch the branch",
+		"Use suggest_branch_name and create_or_switch_branch with fast_forward to start from the latest base."},
+	{StepImplement, 6, "Make the changes and write tests",
+		"Implement the issue's acceptance criteria (get_acceptance_criteria) with tests meeting the coverage threshold."},
+	{StepRunTests, 7, "Run the tests locally",
+		"Run the test suite and fix failures before committing."},
+	{StepCommit, 8, "Commit with a descriptive message",
+		"Commit with commit_changes using a Conventional Commits message that references the issue."},
+	{StepPushAndMonitor, 9, "Push and monitor GitHub Actions",
+		"Push the branch and wait for the checks with wait_for_checks until they finish."},
+	{StepFixCI, 10, "Fix and re-push if CI fails",
+		"Read get_ci_failure_logs, fix the failures, commit, push and wait_for_checks again until the checks pass. Skipped when they passed."},
+	{StepCreatePullRequest, 11, "Create the pull request",
+		"Open the pull request with create_pull_request once the checks pass."},
+	{StepAddressReview, 12, "Address the review comments and iterate",
+		"Work through list_review_threads, reply to and resolve each thread, push fixes and check get_review_summary."},
+	{StepAwaitApproval, 13, "Wait for the user's approval",
+		"Remind the user to review the pull request and confirm the approval with get_review_summary."},
+	{StepComplete, 14, "Task complete",
+		"The task is done once checks pass, every review thread is resolved and the pull request is approved."},
+}
+
+// FindStep looks up a step by its ID or number
+func FindStep(idOrNumber string) (Step, bool) {
+	idOrNumber = strings.TrimSpace(idOrNumber)
+	number, err := strconv.Atoi(idOrNumber)
+	for _, step := range Steps {
+		if step.ID == idOrNumber || (err == nil && step.Number == number) {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
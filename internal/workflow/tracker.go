@@ -0,0 +1,215 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ErrNoWorkflow is returned when a session has not started the workflow it refers to
+var ErrNoWorkflow = errors.New("no workflow has been started; start one with start_workflow")
+
+// key identifies the workflow of an issue in a session
+type key struct {
+	session string
+	issue   int
+}
+
+// Tracker holds the workflow progress of every session, by issue. The workflow a
+// session started last is its active one, which facts are recorded in.
+type Tracker struct {
+	mu       sync.Mutex
+	progress map[key]*Progress
+	active   map[string]int
+
+	// onChange is called with a copy of every workflow that changes. The copies wait
+	// in pending until t.mu is released, and reporting keeps them in order.
+	onChange  func(*Progress)
+	pending   []*Progress
+	reporting sync.Mutex
+
+	// now returns the current time; tests replace it
+	now func() time.Time
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{progress: map[key]*Progress{}, active: map[string]int{}, now: time.Now}
+}
+
+// SessionID identifies an MCP session. Transports without session IDs, such as
+// stdio, are identified by the session itself.
+func SessionID(ss *mcp.ServerSession) string {
+	if ss == nil {
+		return ""
+	}
+	if id := ss.ID(); id != "" {
+		return id
+	}
+	return fmt.Sprintf("session-%p", ss)
+}
+
+// OnChange sets the function called with a copy of a workflow whenever it is started,
+// resumed or changed. It is called in the order the changes are made, after the
+// tracker is unlocked, so that slow saves do not hold up other sessions.
+func (t *Tracker) OnChange(fn func(*Progress)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onChange = fn
+}
+
+// Start starts the workflow for issue of repository in session, on behalf of owner,
+// and makes it the active one. A workflow already started is kept unless restart is set.
+func (t *Tracker) Start(session, owner, repository string, issue int, restart bool) *Progress {
+	defer t.report()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	k := key{session, issue}
+	p, ok := t.progress[k]
+	if !ok || restart {
+		p = newProgress(session, owner, repository, issue, t.now())
+		t.progress[k] = p
+		t.changed(p)
+	}
+	t.active[session] = issue
+	return p.clone()
+}
+
+// Resume makes a copy of a workflow saved by an earlier session the active workflow
+// of session, replacing any workflow the session has for the same issue. The checks
+// and the review may have changed since, so they have to be observed again.
+func (t *Tracker) Resume(session string, saved *Progress) *Progress {
+	defer t.report()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := saved.clone()
+	p.Session, p.UpdatedAt = session, t.now()
+	p.Facts.ChecksState, p.Facts.ChecksSHA, p.Facts.Head, p.Facts.Review = "", "", "", nil
+	t.progress[key{session, p.Issue}] = p
+	t.active[session] = p.Issue
+	t.changed(p)
+	return p.clone()
+}
+
+// Get returns the workflow for issue in session, or the session's active workflow
+// when issue is zero
+func (t *Tracker) Get(session string, issue int) (*Progress, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, err := t.lookup(session, issue)
+	if err != nil {
+		return nil, err
+	}
+	return p.clone(), nil
+}
+
+// Complete completes a step of the workflow for issue in session, or of the active
+// workflow when issue is zero. A *TransitionError explains a rejected step.
+func (t *Tracker) Complete(session string, issue int, step, note string) (*Progress, error) {
+	defer t.report()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, err := t.lookup(session, issue)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Complete(step, note, t.now()); err != nil {
+		return nil, err
+	}
+	t.changed(p)
+	return p.clone(), nil
+}
+
+// Record updates the facts of the session's active workflow, if it has one
+func (t *Tracker) Record(session string, update func(*Facts)) {
+	_ = t.Update(session, 0, update)
+}
+
+// Update updates the facts of the workflow for issue in session, or of the active
+// workflow when issue is zero
+func (t *Tracker) Update(session string, issue int, update func(*Facts)) error {
+	defer t.report()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, err := t.lookup(session, issue)
+	if err != nil {
+		return err
+	}
+	update(&p.Facts)
+	p.UpdatedAt = t.now()
+	t.changed(p)
+	return nil
+}
+
+// End forgets the workflows of a session that has ended. Saved workflows can still
+// be resumed by another session.
+func (t *Tracker) End(session string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for k := range t.progress {
+		if k.session == session {
+			delete(t.progress, k)
+		}
+	}
+	delete(t.active, session)
+}
+
+// Unreached explains which step of the session's active workflow has to be completed
+// before step id. It returns an empty string when every step before it is done or
+// the session has no workflow.
+func (t *Tracker) Unreached(session, id string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p, err := t.lookup(session, 0); err == nil {
+		return p.Unreached(id)
+	}
+	return ""
+}
+
+// changed queues a change of p for onChange; t.mu must be held
+func (t *Tracker) changed(p *Progress) {
+	if t.onChange != nil {
+		t.pending = append(t.pending, p.clone())
+	}
+}
+
+// report passes the queued changes to onChange; t.mu must not be held
+func (t *Tracker) report() {
+	t.reporting.Lock()
+	defer t.reporting.Unlock()
+
+	t.mu.Lock()
+	pending, onChange := t.pending, t.onChange
+	t.pending = nil
+	t.mu.Unlock()
+	for _, p := range pending {
+		onChange(p)
+	}
+}
+
+// lookup finds a workflow; t.mu must be held
+func (t *Tracker) lookup(session string, issue int) (*Progress, error) {
+	if issue == 0 {
+		active, ok := t.active[session]
+		if !ok {
+			return nil, ErrNoWorkflow
+		}
+		issue = active
+	}
+	p, ok := t.progress[key{session, issue}]
+	if !ok {
+		return nil, fmt.Errorf("no workflow has been started for issue #%d; start one with start_workflow", issue)
+	}
+	return p, nil
+}
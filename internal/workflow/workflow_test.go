@@ -0,0 +1,278 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// completeThrough completes the steps up to and including number
+func completeThrough(t *testing.T, tr *Tracker, number int) {
+	t.Helper()
+	for _, step := range Steps[:number] {
+		if _, err := tr.Complete("s1", 0, step.ID, ""); err != nil {
+			t.Fatalf("Complete(%s) returned error: %v", step.ID, err)
+		}
+	}
+}
+
+func TestFindStep(t *testing.T) {
+	for _, name := range []string{"commit", "8", " 8 "} {
+		if step, ok := FindStep(name); !ok || step.ID != StepCommit {
+			t.Errorf("FindStep(%q) = %+v, %v", name, step, ok)
+		}
+	}
+	if _, ok := FindStep("15"); ok {
+		t.Error("Expected no step 15")
+	}
+	for i, step := range Steps {
+		if step.Number != i+1 {
+			t.Errorf("Expected step %s to be number %d", step.ID, i+1)
+		}
+	}
+}
+
+func TestCompleteRejectsOutOfOrder(t *testing.T) {
+	tr := NewTracker()
+	tr.Start("s1", "", "", 42, false)
+
+	_, err := tr.Complete("s1", 0, StepCommit, "")
+	var transition *TransitionError
+	if !errors.As(err, &transition) || transition.Step.ID != StepCommit {
+		t.Fatalf("Expected a TransitionError, got %v", err)
+	}
+	want := "cannot complete step 8 (commit) yet: step 1 (check_repository) comes first"
+	if !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("Expected %q, got %q", want, err)
+	}
+
+	completeThrough(t, tr, 8)
+	if _, err := tr.Complete("s1", 42, StepCommit, "again"); err != nil {
+		t.Errorf("Expected completing a step twice to be accepted, got %v", err)
+	}
+	if _, err := tr.Complete("s1", 7, StepCheckRepository, ""); err == nil || !strings.Contains(err.Error(), "issue #7") {
+		t.Errorf("Expected an error for a workflow that was not started, got %v", err)
+	}
+	if _, err := tr.Complete("s2", 0, StepCheckRepository, ""); !errors.Is(err, ErrNoWorkflow) {
+		t.Errorf("Expected ErrNoWorkflow for another session, got %v", err)
+	}
+}
+
+func TestChecksGuardCIAndPullRequest(t *testing.T) {
+	tr := NewTracker()
+	tr.Start("s1", "", "", 42, false)
+	completeThrough(t, tr, 8)
+
+	if _, err := tr.Complete("s1", 0, StepPushAndMonitor, ""); err == nil || !strings.Contains(err.Error(), "wait_for_checks") {
+		t.Fatalf("Expected pushing to wait for the checks, got %v", err)
+	}
+	tr.Record("s1", func(f *Facts) { f.ChecksState, f.ChecksSHA = ChecksFailure, "0123456789abcdef" })
+	if _, err := tr.Complete("s1", 0, StepPushAndMonitor, "pushed"); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+
+	_, err := tr.Complete("s1", 0, StepFixCI, "")
+	if err == nil || !strings.Contains(err.Error(), "checks of 0123456789ab ended in failure") {
+		t.Fatalf("Expected fixing CI to need passing checks, got %v", err)
+	}
+	if _, err := tr.Complete("s1", 0, StepCreatePullRequest, ""); err == nil || !strings.Contains(err.Error(), "step 10 (fix_ci) comes first") {
+		t.Fatalf("Expected the pull request to wait for CI, got %v", err)
+	}
+
+	tr.Record("s1", func(f *Facts) { f.ChecksState = ChecksSuccess })
+	if _, err := tr.Complete("s1", 0, StepFixCI, "fixed lint"); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if _, err := tr.Complete("s1", 0, StepCreatePullRequest, ""); err == nil || !strings.Contains(err.Error(), "create_pull_request") {
+		t.Fatalf("Expected the pull request to be observed, got %v", err)
+	}
+	tr.Record("s1", func(f *Facts) { f.PullRequest, f.Head = 9, "fedcba9876543210" })
+	if _, err := tr.Complete("s1", 0, StepCreatePullRequest, ""); err == nil || !strings.Contains(err.Error(), "checks ran on 0123456789ab but the branch is now at fedcba987654") {
+		t.Fatalf("Expected checks of another commit to be stale, got %v", err)
+	}
+	tr.Record("s1", func(f *Facts) { f.ChecksSHA = f.Head })
+	if _, err := tr.Complete("s1", 0, StepCreatePullRequest, ""); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+}
+
+func TestPassingChecksSkipFixCI(t *testing.T) {
+	tr := NewTracker()
+	tr.Start("s1", "", "", 0, false)
+	completeThrough(t, tr, 8)
+	tr.Record("s1", func(f *Facts) { f.ChecksState = ChecksNone })
+
+	p, err := tr.Complete("s1", 0, StepPushAndMonitor, "")
+	if err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if fix := p.Steps[9]; fix.Status != StatusSkipped || fix.Note != "checks passed" {
+		t.Errorf("Expected fix_ci to be skipped, got %+v", fix)
+	}
+	if next := p.Next(); next.ID != StepCreatePullRequest {
+		t.Errorf("Expected create_pull_request next, got %+v", next)
+	}
+}
+
+func TestReviewGuards(t *testing.T) {
+	tr := NewTracker()
+	tr.Start("s1", "", "", 42, false)
+	tr.Record("s1", func(f *Facts) { f.ChecksState, f.PullRequest = ChecksSuccess, 9 })
+	completeThrough(t, tr, 11)
+
+	reasons := []struct {
+		review *Review
+		step   string
+		want   string
+	}{
+		{nil, StepAddressReview, "run get_review_summary"},
+		{&Review{Unresolved: 2}, StepAddressReview, "2 review threads are unresolved"},
+		{&Review{ChangesRequested: []string{"alice"}}, StepAddressReview, "changes are requested by alice"},
+		{&Review{Done: true}, StepAwaitApproval, "has not been approved"},
+	}
+	for _, r := range reasons {
+		tr.Record("s1", func(f *Facts) { f.Review = r.review })
+		if r.step == StepAwaitApproval {
+			if _, err := tr.Complete("s1", 0, StepAddressReview, ""); err != nil {
+				t.Fatalf("Complete returned error: %v", err)
+			}
+		}
+		if _, err := tr.Complete("s1", 0, r.step, ""); err == nil || !strings.Contains(err.Error(), r.want) {
+			t.Errorf("Expected %q for %+v, got %v", r.want, r.review, err)
+		}
+	}
+
+	tr.Record("s1", func(f *Facts) { f.Review = &Review{Approved: []string{"bob"}, Done: true} })
+	completeThrough(t, tr, 14)
+	p, _ := tr.Get("s1", 42)
+	if p.Next() != nil || !strings.Contains(p.Markdown(), "The workflow is complete") {
+		t.Errorf("Expected the workflow to be complete:\n%s", p.Markdown())
+	}
+}
+
+func TestTrackerSessions(t *testing.T) {
+	tr := NewTracker()
+	now := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	tr.now = func() time.Time { return now }
+
+	tr.Start("s1", "", "", 42, false)
+	if _, err := tr.Complete("s1", 0, StepCheckRepository, ""); err != nil {
+		t.Fatal(err)
+	}
+	tr.Start("s1", "", "", 7, false)
+	if p, _ := tr.Get("s1", 0); p.Issue != 7 {
+		t.Errorf("Expected the workflow started last to be active, got #%d", p.Issue)
+	}
+	if p := tr.Start("s1", "", "", 42, false); p.Steps[0].Status != StatusDone {
+		t.Error("Expected starting again to keep the progress")
+	}
+	if p := tr.Start("s1", "", "", 42, true); p.Steps[0].Status != StatusPending || !p.StartedAt.Equal(now) {
+		t.Errorf("Expected restarting to start over, got %+v", p.Steps[0])
+	}
+
+	p, _ := tr.Get("s1", 42)
+	p.Steps[0].Status = StatusDone
+	if again, _ := tr.Get("s1", 42); again.Steps[0].Status != StatusPending {
+		t.Error("Expected Get to return a copy")
+	}
+	if tr.Unreached("s1", StepCommit) == "" || tr.Unreached("s2", StepCommit) != "" {
+		t.Error("Expected Unreached to explain the missing steps of active workflows only")
+	}
+}
+
+func TestTrackerReportsChangesAndResumes(t *testing.T) {
+	tr := NewTracker()
+	var changes []*Progress
+	tr.OnChange(func(p *Progress) { changes = append(changes, p) })
+
+	tr.Start("s1", "", "octo/hello", 42, false)
+	tr.Start("s1", "", "octo/hello", 42, false)
+	completeThrough(t, tr, 2)
+	tr.Record("s1", func(f *Facts) { f.Branch = "feature/42-login" })
+	tr.Record("s2", func(f *Facts) { f.Branch = "ignored" })
+	if len(changes) != 4 {
+		t.Fatalf("Expected a change for the start, two steps and the fact, got %d", len(changes))
+	}
+	last := changes[3]
+	if last.Repository != "octo/hello" || last.Facts.Branch != "feature/42-login" || last.Steps[1].Status != StatusDone {
+		t.Errorf("Unexpected change %+v", last)
+	}
+
+	last.Session = ""
+	last.Facts.ChecksState, last.Facts.ChecksSHA, last.Facts.PullRequest = ChecksSuccess, "0123456789abcdef", 9
+	last.Facts.Review = &Review{Approved: []string{"bob"}, Done: true}
+	p := tr.Resume("s2", last)
+	if p.Session != "s2" || len(changes) != 5 {
+		t.Errorf("Expected the resumed workflow to belong to s2 and be reported, got %q", p.Session)
+	}
+	if f := p.Facts; f.ChecksState != "" || f.ChecksSHA != "" || f.Review != nil || f.Branch != "feature/42-login" || f.PullRequest != 9 {
+		t.Errorf("Expected the checks and review to be forgotten on resume, got %+v", f)
+	}
+	if p, err := tr.Get("s2", 0); err != nil || p.Issue != 42 || p.Next().ID != Steps[2].ID {
+		t.Errorf("Expected the resumed workflow to be active and continue at step 3, got %+v, %v", p, err)
+	}
+	if _, err := tr.Complete("s2", 0, Steps[2].ID, ""); err != nil {
+		t.Errorf("Complete returned error: %v", err)
+	}
+	if p, _ := tr.Get("s1", 42); p.Steps[2].Status != StatusPending {
+		t.Error("Expected the original session's workflow to be unaffected")
+	}
+}
+
+func TestChangesAreReportedOutsideTheLock(t *testing.T) {
+	tr := NewTracker()
+	var reported []int
+	tr.OnChange(func(p *Progress) {
+		// A save that reads the tracker would deadlock if the tracker were locked
+		if _, err := tr.Get(p.Session, p.Issue); err != nil {
+			t.Errorf("Get returned error: %v", err)
+		}
+		reported = append(reported, p.Issue)
+	})
+
+	tr.Start("s1", "", "", 42, false)
+	tr.Start("s1", "", "", 43, false)
+	tr.Record("s1", func(f *Facts) { f.Branch = "feature/43-logout" })
+	if len(reported) != 3 || reported[0] != 42 || reported[2] != 43 {
+		t.Errorf("Expected every change to be reported in order, got %v", reported)
+	}
+}
+
+func TestEndForgetsSession(t *testing.T) {
+	tr := NewTracker()
+	tr.Start("s1", "", "", 42, false)
+	tr.Start("s1", "", "", 43, false)
+	tr.Start("s2", "", "", 42, false)
+
+	tr.End("s1")
+	if _, err := tr.Get("s1", 0); !errors.Is(err, ErrNoWorkflow) {
+		t.Errorf("Expected the ended session to have no workflow, got %v", err)
+	}
+	if _, err := tr.Get("s1", 42); err == nil {
+		t.Error("Expected the ended session's workflows to be dropped")
+	}
+	if _, err := tr.Get("s2", 42); err != nil {
+		t.Errorf("Expected other sessions to keep their workflows, got %v", err)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tr := NewTracker()
+	tr.Start("s1", "", "", 42, false)
+	completeThrough(t, tr, 8)
+	p, _ := tr.Get("s1", 0)
+
+	text := p.Markdown()
+	for _, want := range []string{
+		"# Development workflow for issue #42",
+		"- [x] 8. Commit with a descriptive message",
+		"- [ ] 9. Push and monitor GitHub Actions",
+		"**Next:** 9. Push and monitor GitHub Actions (`push_and_monitor`).",
+		"**Blocked:** no checks have been observed",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
@@ -2,20 +2,102 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/config"
 	"github.com/shibbirmcc/github-issue-developer-mcp-server/internal/server"
 )
 
 func main() {
-	ctx := context.Background()
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(configCommand(os.Args[2:]))
+	}
+
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	flags := config.RegisterFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [flags]\n       %s config validate|print [--effective] [flags]\n\nFlags:\n", os.Args[0], os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(os.Args[1:])
+
+	cfg, err := config.Load(config.Options{Flags: flags, LookupEnv: os.LookupEnv})
+	if err != nil {
+		log.Printf("Error loading configuration:\n%v", err)
+		os.Exit(1)
+	}
+
+	// SIGINT and SIGTERM shut the server down gracefully; a second signal stops it at once
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
 
 	// Create and start the MCP server
-	mcpServer := server.NewMCPServer()
+	mcpServer := server.New(cfg)
 
 	if err := mcpServer.Start(ctx); err != nil {
 		log.Printf("Error starting MCP server: %v", err)
 		os.Exit(1)
 	}
 }
+
+// configCommand runs "config validate", which checks the configuration and every
+// file it names, or "config print", which prints the defaults or, with --effective,
+// the configuration in effect. It returns the exit code.
+func configCommand(args []string) int {
+	usage := fmt.Sprintf("Usage: %s config validate|print [--effective] [flags]", os.Args[0])
+	if len(args) == 0 || (args[0] != "validate" && args[0] != "print") {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("config "+args[0], flag.ContinueOnError)
+	flags := config.RegisterFlags(fs)
+	effective := false
+	if args[0] == "print" {
+		fs.BoolVar(&effective, "effective", false, "print the configuration in effect, noting where each setting was set")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if args[0] == "print" && !effective {
+		if err := config.Default().Write(os.Stdout, false); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	cfg, err := config.Load(config.Options{Flags: flags, LookupEnv: os.LookupEnv})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if effective {
+		if err := cfg.Write(os.Stdout, true); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	if err := server.New(cfg).Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if cfg.File() != "" {
+		fmt.Printf("Configuration is valid (%s)\n", cfg.File())
+	} else {
+		fmt.Println("Configuration is valid")
+	}
+	return 0
+}